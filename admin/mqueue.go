@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common/mqueue"
+	"github.com/botlabs-gg/yagpdb/v2/web"
+	"goji.io/pat"
+)
+
+const deadLettersPerPage = 50
+
+func (p *Plugin) handleGetMqueue(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	_, tmpl := web.GetBaseCPContextData(r.Context())
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 0 {
+		page = 0
+	}
+
+	letters, total, err := mqueue.GetDeadLetters(page*deadLettersPerPage, deadLettersPerPage)
+	if err != nil {
+		return tmpl, errors.WithStackIf(err)
+	}
+
+	tmpl["DeadLetters"] = letters
+	tmpl["TotalDeadLetters"] = total
+	tmpl["Page"] = page
+	if page > 0 {
+		tmpl["PrevPage"] = page - 1
+	}
+	if (page+1)*deadLettersPerPage < total {
+		tmpl["NextPage"] = page + 1
+	}
+
+	return tmpl, nil
+}
+
+func (p *Plugin) handleReplayDeadLetter(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	_, tmpl := web.GetBaseCPContextData(r.Context())
+
+	id, _ := strconv.ParseInt(pat.Param(r, "id"), 10, 64)
+	_, err := mqueue.ReplayDeadLetter(id)
+	if err == mqueue.ErrDeadLetterNotFound {
+		return tmpl.AddAlerts(web.ErrorAlert("Unknown dead letter")), nil
+	} else if err != nil {
+		return tmpl, errors.WithStackIf(err)
+	}
+
+	return tmpl.AddAlerts(web.SucessAlert("Queued #", id, " again")), nil
+}
+
+func (p *Plugin) handleDeleteDeadLetter(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	_, tmpl := web.GetBaseCPContextData(r.Context())
+
+	id, _ := strconv.ParseInt(pat.Param(r, "id"), 10, 64)
+	err := mqueue.DeleteDeadLetter(id)
+	if err != nil {
+		return tmpl, errors.WithStackIf(err)
+	}
+
+	return tmpl.AddAlerts(web.SucessAlert("Deleted #", id)), nil
+}
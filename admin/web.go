@@ -28,10 +28,14 @@ var PageHTMLPanel string
 //go:embed assets/bot_admin_config.html
 var PageHTMLConfig string
 
+//go:embed assets/bot_admin_mqueue.html
+var PageHTMLMqueue string
+
 // InitWeb implements web.Plugin
 func (p *Plugin) InitWeb() {
 	web.AddHTMLTemplate("admin/assets/bot_admin_panel.html", PageHTMLPanel)
 	web.AddHTMLTemplate("admin/assets/bot_admin_config.html", PageHTMLConfig)
+	web.AddHTMLTemplate("admin/assets/bot_admin_mqueue.html", PageHTMLMqueue)
 
 	mux := goji.SubMux()
 	web.RootMux.Handle(pat.New("/admin/*"), mux)
@@ -69,6 +73,11 @@ func (p *Plugin) InitWeb() {
 	getConfigHandler := web.ControllerHandler(p.handleGetConfig, "bot_admin_config")
 	mux.Handle(pat.Get("/config"), getConfigHandler)
 	mux.Handle(pat.Post("/config/edit/:key"), web.ControllerPostHandler(p.handleEditConfig, getConfigHandler, nil))
+
+	getMqueueHandler := web.ControllerHandler(p.handleGetMqueue, "bot_admin_mqueue")
+	mux.Handle(pat.Get("/mqueue"), getMqueueHandler)
+	mux.Handle(pat.Post("/mqueue/:id/replay"), web.ControllerPostHandler(p.handleReplayDeadLetter, getMqueueHandler, nil))
+	mux.Handle(pat.Post("/mqueue/:id/delete"), web.ControllerPostHandler(p.handleDeleteDeadLetter, getMqueueHandler, nil))
 }
 
 type Host struct {
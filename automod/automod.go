@@ -2,6 +2,7 @@ package automod
 
 import (
 	"context"
+	"embed"
 	"encoding/json"
 	"strconv"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/automod/models"
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	"github.com/botlabs-gg/yagpdb/v2/premium"
 	"github.com/karlseguin/ccache"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
@@ -18,6 +20,9 @@ import (
 
 //go:generate sqlboiler --no-hooks psql
 
+//go:embed locales
+var locales embed.FS
+
 var (
 	RegexCache *ccache.Cache
 	logger     = common.GetPluginLogger(&Plugin{})
@@ -38,6 +43,7 @@ func RegisterPlugin() {
 	RegexCache = ccache.New(ccache.Configure())
 
 	common.InitSchemas("automod_v2", DBSchemas...)
+	i18n.RegisterCatalogs(locales, "locales")
 
 	p := &Plugin{}
 	common.RegisterPlugin(p)
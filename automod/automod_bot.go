@@ -20,6 +20,7 @@ import (
 	schEventsModels "github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2/models"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/botlabs-gg/yagpdb/v2/outboundwebhooks"
 	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
@@ -525,6 +526,17 @@ func (p *Plugin) RulesetRulesTriggeredCondsPassed(ruleset *ParsedRuleset, trigge
 		}
 	}
 
+	for _, v := range loggedModels {
+		outboundwebhooks.Emit(v.GuildID, outboundwebhooks.EventAutomodTriggered, &outboundwebhooks.AutomodTriggeredData{
+			RulesetName: v.RulesetName,
+			RuleID:      v.RuleID.Int64,
+			RuleName:    v.RuleName,
+			User:        outboundwebhooks.UserData(&ctxData.MS.User),
+			ChannelID:   v.ChannelID,
+			ChannelName: v.ChannelName,
+		})
+	}
+
 	tx, err := common.PQ.BeginTx(context.Background(), nil)
 	if err != nil {
 		logger.WithError(err).Error("failed creating transaction")
@@ -84,6 +84,15 @@ func (p *Plugin) InitWeb() {
 	muxer.Handle(pat.Post("/list/:listID/update"), web.ControllerPostHandler(p.handlePostAutomodUpdateList, getIndexHandler, UpdateListData{}))
 	muxer.Handle(pat.Post("/list/:listID/delete"), web.ControllerPostHandler(p.handlePostAutomodDeleteList, getIndexHandler, nil))
 
+	// Discord's native automod rules
+	getNativeHandler := web.ControllerHandler(p.handleGetNativeRules, "automod_index")
+	muxer.Handle(pat.Get("/native"), getNativeHandler)
+	muxer.Handle(pat.Get("/native/"), getNativeHandler)
+	muxer.Handle(pat.Post("/native/new"), web.ControllerPostHandler(p.handlePostNativeRuleCreate, getNativeHandler, NativeRuleData{}))
+	muxer.Handle(pat.Post("/native/:ruleID/update"), web.ControllerPostHandler(p.handlePostNativeRuleUpdate, getNativeHandler, NativeRuleData{}))
+	muxer.Handle(pat.Post("/native/:ruleID/delete"), web.ControllerPostHandler(p.handlePostNativeRuleDelete, getNativeHandler, nil))
+	muxer.Handle(pat.Post("/native/:ruleID/import"), web.ControllerPostHandler(p.handlePostNativeRuleImport, getNativeHandler, nil))
+
 	// Ruleset specific handlers
 	rulesetMuxer := goji.SubMux()
 	muxer.Handle(pat.New("/ruleset/:rulesetID"), rulesetMuxer)
@@ -815,6 +824,18 @@ func WebLoadRuleSettings(r *http.Request, tmpl web.TemplateData, ruleset *models
 	tmpl["RSPartData"] = parsedRSData
 }
 
+var _ web.PluginWithPublicAPI = (*Plugin)(nil)
+
+func (p *Plugin) PublicAPIResources() []*web.APIResource {
+	return []*web.APIResource{
+		{
+			Path:     "/automod",
+			Scope:    "automod",
+			DataKeys: []string{"AutomodRulesets", "AutomodLists", "CurrentRuleset", "AutomodLogEntries"},
+		},
+	}
+}
+
 var _ web.PluginWithServerHomeWidget = (*Plugin)(nil)
 
 func (p *Plugin) LoadServerHomeWidget(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
@@ -10,6 +10,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/automod/models"
 	"github.com/botlabs-gg/yagpdb/v2/bot"
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
 	schEventsModels "github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2/models"
 	"github.com/botlabs-gg/yagpdb/v2/common/templates"
@@ -21,6 +22,15 @@ import (
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 )
 
+// punishReason returns the reason used for punishments, it's included in the punishment DM so it's in the language of the server
+func (t *TriggeredRuleData) punishReason(customReason string) string {
+	if customReason == "" {
+		customReason = t.ConstructReason(true)
+	}
+
+	return i18n.GuildTf(t.GS.ID, "Automoderator:\n%s", customReason)
+}
+
 type Effect interface {
 	Apply(ctxData *TriggeredRuleData, settings interface{}) error
 	IsRoleEffect() bool
@@ -281,12 +291,7 @@ func (kick *KickUserEffect) IsRoleEffect() bool {
 func (kick *KickUserEffect) Apply(ctxData *TriggeredRuleData, settings interface{}) error {
 	settingsCast := settings.(*KickUserEffectData)
 
-	reason := "Automoderator:\n"
-	if settingsCast.CustomReason != "" {
-		reason += settingsCast.CustomReason
-	} else {
-		reason += ctxData.ConstructReason(true)
-	}
+	reason := ctxData.punishReason(settingsCast.CustomReason)
 
 	err := moderation.KickUser(nil, ctxData.GS.ID, ctxData.CS, ctxData.Message, common.BotUser, reason, &ctxData.MS.User, -1, false)
 	return err
@@ -355,12 +360,7 @@ func (ban *BanUserEffect) IsRoleEffect() bool {
 func (ban *BanUserEffect) Apply(ctxData *TriggeredRuleData, settings interface{}) error {
 	settingsCast := settings.(*BanUserEffectData)
 
-	reason := "Automoderator:\n"
-	if settingsCast.CustomReason != "" {
-		reason += settingsCast.CustomReason
-	} else {
-		reason += ctxData.ConstructReason(true)
-	}
+	reason := ctxData.punishReason(settingsCast.CustomReason)
 
 	duration := time.Duration(settingsCast.Duration) * time.Minute
 	err := moderation.BanUserWithDuration(nil, ctxData.GS.ID, ctxData.CS, ctxData.Message, common.BotUser, reason, &ctxData.MS.User, duration, settingsCast.MessageDeleteDays, false)
@@ -422,12 +422,7 @@ func (mute *MuteUserEffect) IsRoleEffect() bool {
 func (mute *MuteUserEffect) Apply(ctxData *TriggeredRuleData, settings interface{}) error {
 	settingsCast := settings.(*MuteUserEffectData)
 
-	reason := "Automoderator:\n"
-	if settingsCast.CustomReason != "" {
-		reason += settingsCast.CustomReason
-	} else {
-		reason += ctxData.ConstructReason(true)
-	}
+	reason := ctxData.punishReason(settingsCast.CustomReason)
 
 	err := moderation.MuteUnmuteUser(nil, true, ctxData.GS.ID, ctxData.CS, ctxData.Message, common.BotUser, reason, ctxData.MS, settingsCast.Duration, false)
 	return err
@@ -495,12 +490,7 @@ func (timeout *TimeoutUserEffect) Apply(ctxData *TriggeredRuleData, settings int
 
 	settingsCast := settings.(*TimeoutUserEffectData)
 
-	reason := "Automoderator:\n"
-	if settingsCast.CustomReason != "" {
-		reason += settingsCast.CustomReason
-	} else {
-		reason += ctxData.ConstructReason(true)
-	}
+	reason := ctxData.punishReason(settingsCast.CustomReason)
 
 	duration := time.Duration(settingsCast.Duration) * time.Minute
 	err := moderation.TimeoutUser(nil, ctxData.GS.ID, ctxData.CS, ctxData.Message, common.BotUser, reason, &ctxData.MS.User, duration, false)
@@ -554,12 +544,7 @@ func (warn *WarnUserEffect) IsRoleEffect() bool {
 func (warn *WarnUserEffect) Apply(ctxData *TriggeredRuleData, settings interface{}) error {
 	settingsCast := settings.(*WarnUserEffectData)
 
-	reason := "Automoderator:\n"
-	if settingsCast.CustomReason != "" {
-		reason += settingsCast.CustomReason
-	} else {
-		reason += ctxData.ConstructReason(true)
-	}
+	reason := ctxData.punishReason(settingsCast.CustomReason)
 
 	err := moderation.WarnUser(nil, ctxData.GS.ID, ctxData.CS, ctxData.Message, common.BotUser, &ctxData.MS.User, reason, false)
 	return err
@@ -0,0 +1,737 @@
+package automod
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/automod/models"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/cplogs"
+	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/common/pubsub"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/web"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"goji.io/pat"
+)
+
+// Management of Discord's native automod rules, these live on Discord and are only proxied by the dashboard.
+
+// Limits discord puts on native rules
+const (
+	NativeMaxKeywords      = 1000
+	NativeMaxKeywordLength = 60
+	NativeMaxRegexPatterns = 10
+	NativeMaxRegexLength   = 260
+	NativeMaxAllowList     = 100
+	// keyword preset rules allow a longer allow list
+	NativeMaxPresetAllowList = 1000
+	NativeMaxMentionLimit    = 50
+	NativeMaxExemptRoles     = 20
+	NativeMaxExemptChans     = 50
+	// in minutes
+	NativeMaxTimeout = 40320
+)
+
+var (
+	panelLogKeyNewNativeRule      = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "automodv2_new_native_rule", FormatString: "Updated automod: Created a Discord AutoMod rule"})
+	panelLogKeyUpdatedNativeRule  = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "automodv2_updated_native_rule", FormatString: "Updated automod: Updated a Discord AutoMod rule"})
+	panelLogKeyRemovedNativeRule  = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "automodv2_removed_native_rule", FormatString: "Updated automod: Removed a Discord AutoMod rule"})
+	panelLogKeyImportedNativeRule = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "automodv2_imported_native_rule", FormatString: "Updated automod: Imported a Discord AutoMod rule as a ruleset"})
+)
+
+// NativeTriggerTypes are the trigger types that can be managed from the dashboard
+var NativeTriggerTypes = []*NativeTriggerType{
+	{Type: discordgo.AutoModerationEventTriggerKeyword, Name: "Keywords"},
+	{Type: discordgo.AutoModerationEventTriggerSpam, Name: "Spam"},
+	{Type: discordgo.AutoModerationEventTriggerKeywordPreset, Name: "Keyword presets"},
+	{Type: discordgo.AutoModerationEventTriggerMentionSpam, Name: "Mention spam"},
+}
+
+type NativeTriggerType struct {
+	Type discordgo.AutoModerationRuleTriggerType
+	Name string
+}
+
+func nativeTriggerTypeName(t discordgo.AutoModerationRuleTriggerType) string {
+	for _, v := range NativeTriggerTypes {
+		if v.Type == t {
+			return v.Name
+		}
+	}
+
+	return "Unknown (" + strconv.Itoa(int(t)) + ")"
+}
+
+var NativeKeywordPresets = []*NativeKeywordPreset{
+	{Preset: discordgo.AutoModerationKeywordPresetProfanity, Name: "Profanity"},
+	{Preset: discordgo.AutoModerationKeywordPresetSexualContent, Name: "Sexual content"},
+	{Preset: discordgo.AutoModerationKeywordPresetSlurs, Name: "Slurs"},
+}
+
+type NativeKeywordPreset struct {
+	Preset discordgo.AutoModerationKeywordPreset
+	Name   string
+}
+
+// NativeRuleData is the dashboard form for a native rule, the trigger type can only be set when creating it
+type NativeRuleData struct {
+	Name        string `valid:",1,100"`
+	TriggerType int
+	Enabled     bool
+
+	Keywords      string `valid:",0,70000"`
+	RegexPatterns string `valid:",0,3000"`
+	AllowList     string `valid:",0,7000"`
+	Presets       []int
+
+	MentionLimit          int
+	MentionRaidProtection bool
+
+	BlockMessage       bool
+	BlockCustomMessage string `valid:",0,150"`
+	AlertChannel       int64  `valid:"channel,true"`
+	// in minutes, 0 to not time out
+	TimeoutDuration int
+
+	ExemptRoles    []int64 `valid:"role,true"`
+	ExemptChannels []int64 `valid:"channel,true"`
+}
+
+// NativeRuleView is a rule as shown on the dashboard, with the form prefilled from it
+type NativeRuleView struct {
+	Rule        *discordgo.AutoModerationRule
+	TriggerName string
+	Form        *NativeRuleData
+	// empty if the rule can be imported as a ruleset
+	ImportProblem string
+}
+
+func (d *NativeRuleData) HasPreset(p discordgo.AutoModerationKeywordPreset) bool {
+	for _, v := range d.Presets {
+		if v == int(p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitNativeList(input string) []string {
+	var result []string
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			result = append(result, line)
+		}
+	}
+
+	return result
+}
+
+func checkNativeList(name string, list []string, maxEntries, maxLength int) error {
+	if len(list) > maxEntries {
+		return fmt.Errorf("%s can have at most %d entries", name, maxEntries)
+	}
+
+	for _, v := range list {
+		if len([]rune(v)) > maxLength {
+			return fmt.Errorf("%s entries can be at most %d characters, %q is too long", name, maxLength, v)
+		}
+	}
+
+	return nil
+}
+
+// toRule converts the form into a native rule, validating it against discord's limits
+func (d *NativeRuleData) toRule(triggerType discordgo.AutoModerationRuleTriggerType) (*discordgo.AutoModerationRule, error) {
+	enabled := d.Enabled
+	rule := &discordgo.AutoModerationRule{
+		Name:            d.Name,
+		EventType:       discordgo.AutoModerationEventMessageSend,
+		TriggerType:     triggerType,
+		TriggerMetadata: &discordgo.AutoModerationTriggerMetadata{},
+		Actions:         []discordgo.AutoModerationAction{},
+		Enabled:         &enabled,
+		ExemptRoles:     d.ExemptRoles,
+		ExemptChannels:  d.ExemptChannels,
+	}
+
+	if rule.ExemptRoles == nil {
+		rule.ExemptRoles = discordgo.IDSlice{}
+	}
+	if rule.ExemptChannels == nil {
+		rule.ExemptChannels = discordgo.IDSlice{}
+	}
+
+	if len(rule.ExemptRoles) > NativeMaxExemptRoles {
+		return nil, fmt.Errorf("At most %d roles can be exempt", NativeMaxExemptRoles)
+	}
+	if len(rule.ExemptChannels) > NativeMaxExemptChans {
+		return nil, fmt.Errorf("At most %d channels can be exempt", NativeMaxExemptChans)
+	}
+
+	allowList := splitNativeList(d.AllowList)
+	switch triggerType {
+	case discordgo.AutoModerationEventTriggerKeyword:
+		keywords := splitNativeList(d.Keywords)
+		patterns := splitNativeList(d.RegexPatterns)
+		if len(keywords) == 0 && len(patterns) == 0 {
+			return nil, errors.New("Keyword rules need at least one keyword or regex pattern")
+		}
+
+		if err := checkNativeList("Keywords", keywords, NativeMaxKeywords, NativeMaxKeywordLength); err != nil {
+			return nil, err
+		}
+		if err := checkNativeList("Regex patterns", patterns, NativeMaxRegexPatterns, NativeMaxRegexLength); err != nil {
+			return nil, err
+		}
+		if err := checkNativeList("The allow list", allowList, NativeMaxAllowList, NativeMaxKeywordLength); err != nil {
+			return nil, err
+		}
+
+		rule.TriggerMetadata.KeywordFilter = keywords
+		rule.TriggerMetadata.RegexPatterns = patterns
+		rule.TriggerMetadata.AllowList = &allowList
+	case discordgo.AutoModerationEventTriggerKeywordPreset:
+		for _, p := range d.Presets {
+			known := false
+			for _, v := range NativeKeywordPresets {
+				if int(v.Preset) == p {
+					known = true
+				}
+			}
+			if !known {
+				return nil, fmt.Errorf("Unknown keyword preset %d", p)
+			}
+			rule.TriggerMetadata.Presets = append(rule.TriggerMetadata.Presets, discordgo.AutoModerationKeywordPreset(p))
+		}
+
+		if len(rule.TriggerMetadata.Presets) == 0 {
+			return nil, errors.New("Select at least one keyword preset")
+		}
+
+		if err := checkNativeList("The allow list", allowList, NativeMaxPresetAllowList, NativeMaxKeywordLength); err != nil {
+			return nil, err
+		}
+		rule.TriggerMetadata.AllowList = &allowList
+	case discordgo.AutoModerationEventTriggerMentionSpam:
+		if d.MentionLimit < 1 || d.MentionLimit > NativeMaxMentionLimit {
+			return nil, fmt.Errorf("The mention limit has to be between 1 and %d", NativeMaxMentionLimit)
+		}
+
+		rule.TriggerMetadata.MentionTotalLimit = d.MentionLimit
+		rule.TriggerMetadata.MentionRaidProtectionEnabled = d.MentionRaidProtection
+	case discordgo.AutoModerationEventTriggerSpam:
+		// no metadata
+	default:
+		return nil, errors.New("Unsupported trigger type")
+	}
+
+	if d.BlockMessage {
+		action := discordgo.AutoModerationAction{Type: discordgo.AutoModerationRuleActionBlockMessage}
+		if d.BlockCustomMessage != "" {
+			action.Metadata = &discordgo.AutoModerationActionMetadata{CustomMessage: d.BlockCustomMessage}
+		}
+		rule.Actions = append(rule.Actions, action)
+	}
+
+	if d.AlertChannel != 0 {
+		rule.Actions = append(rule.Actions, discordgo.AutoModerationAction{
+			Type:     discordgo.AutoModerationRuleActionSendAlertMessage,
+			Metadata: &discordgo.AutoModerationActionMetadata{ChannelID: d.AlertChannel},
+		})
+	}
+
+	if d.TimeoutDuration != 0 {
+		if triggerType != discordgo.AutoModerationEventTriggerKeyword && triggerType != discordgo.AutoModerationEventTriggerMentionSpam {
+			return nil, errors.New("Only keyword and mention spam rules can time out members")
+		}
+
+		if d.TimeoutDuration < 0 || d.TimeoutDuration > NativeMaxTimeout {
+			return nil, fmt.Errorf("The timeout duration has to be between 1 and %d minutes", NativeMaxTimeout)
+		}
+
+		rule.Actions = append(rule.Actions, discordgo.AutoModerationAction{
+			Type:     discordgo.AutoModerationRuleActionTimeout,
+			Metadata: &discordgo.AutoModerationActionMetadata{Duration: d.TimeoutDuration * 60},
+		})
+	}
+
+	if len(rule.Actions) == 0 {
+		return nil, errors.New("Rules need at least one action")
+	}
+
+	return rule, nil
+}
+
+// nativeRuleEditParams converts a rule from toRule into the body of a edit,
+// so emptied lists clear them on discord instead of being left out
+func nativeRuleEditParams(rule *discordgo.AutoModerationRule) *discordgo.AutoModerationRuleEditParams {
+	params := &discordgo.AutoModerationRuleEditParams{
+		Name:           rule.Name,
+		EventType:      rule.EventType,
+		Actions:        rule.Actions,
+		Enabled:        rule.Enabled,
+		ExemptRoles:    rule.ExemptRoles,
+		ExemptChannels: rule.ExemptChannels,
+	}
+
+	md := rule.TriggerMetadata
+	switch rule.TriggerType {
+	case discordgo.AutoModerationEventTriggerKeyword:
+		keywords, patterns := md.KeywordFilter, md.RegexPatterns
+		if keywords == nil {
+			keywords = []string{}
+		}
+		if patterns == nil {
+			patterns = []string{}
+		}
+
+		params.TriggerMetadata = &discordgo.AutoModerationTriggerMetadataEditParams{
+			KeywordFilter: &keywords,
+			RegexPatterns: &patterns,
+			AllowList:     md.AllowList,
+		}
+	case discordgo.AutoModerationEventTriggerKeywordPreset:
+		params.TriggerMetadata = &discordgo.AutoModerationTriggerMetadataEditParams{
+			Presets:   md.Presets,
+			AllowList: md.AllowList,
+		}
+	case discordgo.AutoModerationEventTriggerMentionSpam:
+		raidProtection := md.MentionRaidProtectionEnabled
+		params.TriggerMetadata = &discordgo.AutoModerationTriggerMetadataEditParams{
+			MentionTotalLimit:            md.MentionTotalLimit,
+			MentionRaidProtectionEnabled: &raidProtection,
+		}
+	}
+
+	return params
+}
+
+// nativeRuleFormData fills in the dashboard form from a existing rule
+func nativeRuleFormData(rule *discordgo.AutoModerationRule) *NativeRuleData {
+	d := &NativeRuleData{
+		Name:           rule.Name,
+		TriggerType:    int(rule.TriggerType),
+		Enabled:        rule.Enabled != nil && *rule.Enabled,
+		ExemptRoles:    rule.ExemptRoles,
+		ExemptChannels: rule.ExemptChannels,
+	}
+
+	if md := rule.TriggerMetadata; md != nil {
+		d.Keywords = strings.Join(md.KeywordFilter, "\n")
+		d.RegexPatterns = strings.Join(md.RegexPatterns, "\n")
+		if md.AllowList != nil {
+			d.AllowList = strings.Join(*md.AllowList, "\n")
+		}
+		for _, p := range md.Presets {
+			d.Presets = append(d.Presets, int(p))
+		}
+		d.MentionLimit = md.MentionTotalLimit
+		d.MentionRaidProtection = md.MentionRaidProtectionEnabled
+	}
+
+	for _, a := range rule.Actions {
+		switch a.Type {
+		case discordgo.AutoModerationRuleActionBlockMessage:
+			d.BlockMessage = true
+			if a.Metadata != nil {
+				d.BlockCustomMessage = a.Metadata.CustomMessage
+			}
+		case discordgo.AutoModerationRuleActionSendAlertMessage:
+			if a.Metadata != nil {
+				d.AlertChannel = a.Metadata.ChannelID
+			}
+		case discordgo.AutoModerationRuleActionTimeout:
+			if a.Metadata != nil {
+				d.TimeoutDuration = a.Metadata.Duration / 60
+			}
+		}
+	}
+
+	return d
+}
+
+// rulesets can only match whole words, so discord's wildcards are dropped when importing keywords
+var nativeWildcardReplacer = strings.NewReplacer("*", "")
+
+// nativeRuleImportProblem returns why a rule can't be imported as a ruleset, or a empty string if it can
+func nativeRuleImportProblem(rule *discordgo.AutoModerationRule) string {
+	switch rule.TriggerType {
+	case discordgo.AutoModerationEventTriggerKeyword, discordgo.AutoModerationEventTriggerMentionSpam:
+	default:
+		return "Only keyword and mention spam rules can be imported, rulesets have no equivalent of Discord's " + strings.ToLower(nativeTriggerTypeName(rule.TriggerType)) + " filter"
+	}
+
+	if rule.TriggerMetadata == nil {
+		return "The rule has no trigger settings"
+	}
+
+	return ""
+}
+
+// nativeRuleParts converts a native rule into the parts of a ruleset rule.
+// listID is the list holding the keywords of keyword rules.
+func nativeRuleParts(rule *discordgo.AutoModerationRule, listID int64) ([]*models.AutomodRuleDatum, error) {
+	var parts []*models.AutomodRuleDatum
+	addPart := func(typeID int, settings interface{}) error {
+		part, ok := RulePartMap[typeID]
+		if !ok {
+			return fmt.Errorf("unknown rule part %d", typeID)
+		}
+
+		// parts without settings are stored as a empty object, same as the dashboard does
+		encoded := []byte("{}")
+		if settings != nil {
+			var err error
+			encoded, err = json.Marshal(settings)
+			if err != nil {
+				return err
+			}
+		}
+
+		parts = append(parts, &models.AutomodRuleDatum{
+			Kind:     int(part.Kind()),
+			TypeID:   typeID,
+			Settings: encoded,
+		})
+		return nil
+	}
+
+	md := rule.TriggerMetadata
+	switch rule.TriggerType {
+	case discordgo.AutoModerationEventTriggerKeyword:
+		if listID != 0 {
+			if err := addPart(5, &WorldListTriggerData{ListID: listID}); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, p := range md.RegexPatterns {
+			if _, err := regexp.Compile(p); err != nil {
+				return nil, fmt.Errorf("regex %q is not supported by rulesets: %v", p, err)
+			}
+
+			if err := addPart(15, &BaseRegexTriggerData{Regex: p}); err != nil {
+				return nil, err
+			}
+		}
+	case discordgo.AutoModerationEventTriggerMentionSpam:
+		if err := addPart(2, &MentionsTriggerData{Treshold: md.MentionTotalLimit}); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rule.ExemptRoles) > 0 {
+		if err := addPart(200, &MemberRolesConditionData{Roles: rule.ExemptRoles}); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rule.ExemptChannels) > 0 {
+		if err := addPart(202, &ChannelsConditionData{Channels: rule.ExemptChannels}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range rule.Actions {
+		var err error
+		switch a.Type {
+		case discordgo.AutoModerationRuleActionBlockMessage:
+			err = addPart(300, nil)
+		case discordgo.AutoModerationRuleActionSendAlertMessage:
+			if a.Metadata != nil && a.Metadata.ChannelID != 0 {
+				err = addPart(313, &SendChannelMessageEffectData{
+					CustomReason: "Triggered the imported Discord AutoMod rule " + rule.Name,
+					LogChannel:   a.Metadata.ChannelID,
+				})
+			}
+		case discordgo.AutoModerationRuleActionTimeout:
+			if a.Metadata != nil && a.Metadata.Duration > 0 {
+				err = addPart(314, &TimeoutUserEffectData{
+					Duration:     max(1, a.Metadata.Duration/60),
+					CustomReason: "Triggered the imported Discord AutoMod rule " + rule.Name,
+				})
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return parts, nil
+}
+
+// nativeKeywordList returns the keywords of the rule as a ruleset word list.
+// Word lists match single words, so keywords with spaces are returned as skipped
+// instead of being split up, which would make the filter match a lot more.
+func nativeKeywordList(rule *discordgo.AutoModerationRule) (words []string, skipped []string) {
+	for _, k := range rule.TriggerMetadata.KeywordFilter {
+		word := strings.TrimSpace(nativeWildcardReplacer.Replace(k))
+		if word == "" {
+			continue
+		}
+
+		if strings.ContainsFunc(word, unicode.IsSpace) {
+			skipped = append(skipped, k)
+			continue
+		}
+
+		words = append(words, word)
+	}
+
+	return words, skipped
+}
+
+func nativeRuleError(tmpl web.TemplateData, action string, err error) (web.TemplateData, error) {
+	if code, msg := common.DiscordError(err); code != 0 || msg != "" {
+		return tmpl.AddAlerts(web.ErrorAlert(fmt.Sprintf("Failed %s the Discord AutoMod rule: %s", action, msg))), nil
+	}
+
+	return tmpl, err
+}
+
+func (p *Plugin) handleGetNativeRules(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	g, tmpl := web.GetBaseCPContextData(r.Context())
+
+	tmpl["InNative"] = true
+	tmpl["NativeTriggerTypes"] = NativeTriggerTypes
+	tmpl["NativeKeywordPresets"] = NativeKeywordPresets
+	tmpl["NativeNewRule"] = &NativeRuleData{Enabled: true, BlockMessage: true, MentionLimit: 20}
+
+	rules, err := common.BotSession.AutoModerationRules(g.ID)
+	if err != nil {
+		web.CtxLogger(r.Context()).WithError(err).Error("failed retrieving native automod rules")
+		_, msg := common.DiscordError(err)
+		tmpl.AddAlerts(web.ErrorAlert("Failed retrieving the Discord AutoMod rules, make sure the bot has the Manage Server permission: ", msg))
+		return p.handleGetAutomodIndex(w, r)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	views := make([]*NativeRuleView, 0, len(rules))
+	for _, rule := range rules {
+		views = append(views, &NativeRuleView{
+			Rule:          rule,
+			TriggerName:   nativeTriggerTypeName(rule.TriggerType),
+			Form:          nativeRuleFormData(rule),
+			ImportProblem: nativeRuleImportProblem(rule),
+		})
+	}
+	tmpl["NativeRules"] = views
+
+	return p.handleGetAutomodIndex(w, r)
+}
+
+func (p *Plugin) handlePostNativeRuleCreate(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	g, tmpl := web.GetBaseCPContextData(r.Context())
+	data := r.Context().Value(common.ContextKeyParsedForm).(*NativeRuleData)
+
+	rule, err := data.toRule(discordgo.AutoModerationRuleTriggerType(data.TriggerType))
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert(err.Error())), nil
+	}
+
+	_, err = common.BotSession.AutoModerationRuleCreate(g.ID, rule, "Created from the control panel")
+	if err != nil {
+		return nativeRuleError(tmpl, "creating", err)
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyNewNativeRule))
+	return tmpl, nil
+}
+
+func (p *Plugin) handlePostNativeRuleUpdate(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	g, tmpl := web.GetBaseCPContextData(r.Context())
+	data := r.Context().Value(common.ContextKeyParsedForm).(*NativeRuleData)
+
+	ruleID, _ := strconv.ParseInt(pat.Param(r, "ruleID"), 10, 64)
+	current, err := common.BotSession.AutoModerationRule(g.ID, ruleID)
+	if err != nil {
+		return nativeRuleError(tmpl, "retrieving", err)
+	}
+
+	// the trigger type of a existing rule can't be changed
+	rule, err := data.toRule(current.TriggerType)
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert(err.Error())), nil
+	}
+
+	_, err = common.BotSession.AutoModerationRuleEdit(g.ID, ruleID, nativeRuleEditParams(rule), "Updated from the control panel")
+	if err != nil {
+		return nativeRuleError(tmpl, "updating", err)
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyUpdatedNativeRule))
+	return tmpl, nil
+}
+
+func (p *Plugin) handlePostNativeRuleDelete(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	g, tmpl := web.GetBaseCPContextData(r.Context())
+
+	ruleID, _ := strconv.ParseInt(pat.Param(r, "ruleID"), 10, 64)
+	err := common.BotSession.AutoModerationRuleDelete(g.ID, ruleID, "Deleted from the control panel")
+	if err != nil {
+		return nativeRuleError(tmpl, "deleting", err)
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyRemovedNativeRule))
+	return tmpl, nil
+}
+
+// handlePostNativeRuleImport creates a ruleset that does the same as the native rule,
+// the native rule is left untouched so it can be disabled once the ruleset is set up
+func (p *Plugin) handlePostNativeRuleImport(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	g, tmpl := web.GetBaseCPContextData(r.Context())
+
+	ruleID, _ := strconv.ParseInt(pat.Param(r, "ruleID"), 10, 64)
+	native, err := common.BotSession.AutoModerationRule(g.ID, ruleID)
+	if err != nil {
+		return nativeRuleError(tmpl, "retrieving", err)
+	}
+
+	if problem := nativeRuleImportProblem(native); problem != "" {
+		return tmpl.AddAlerts(web.ErrorAlert(problem)), nil
+	}
+
+	numRulesets, err := models.AutomodRulesets(qm.Where("guild_id=?", g.ID)).CountG(r.Context())
+	if err != nil {
+		return tmpl, err
+	}
+	if numRulesets >= int64(GuildMaxRulesets(g.ID)) {
+		return tmpl.AddAlerts(web.ErrorAlert("Reached max number of rulesets, ", MaxRulesets)), nil
+	}
+
+	numRules, err := models.AutomodRules(qm.Where("guild_id = ? ", g.ID)).CountG(r.Context())
+	if err != nil {
+		return tmpl, err
+	}
+	if numRules >= int64(GuildMaxTotalRules(g.ID)) {
+		return tmpl.AddAlerts(web.ErrorAlert(fmt.Sprintf("Reached max number of rules, %d for normal servers and %d for premium servers", MaxTotalRules, MaxTotalRulesPremium))), nil
+	}
+
+	var words, skippedKeywords []string
+	if native.TriggerType == discordgo.AutoModerationEventTriggerKeyword {
+		words, skippedKeywords = nativeKeywordList(native)
+		if len(words) == 0 && len(native.TriggerMetadata.RegexPatterns) == 0 {
+			return tmpl.AddAlerts(web.ErrorAlert("None of the keywords of the rule can be imported, word lists only match single words")), nil
+		}
+	}
+
+	name := limitNativeName("Discord: " + native.Name)
+
+	tx, err := common.PQ.BeginTx(r.Context(), nil)
+	if err != nil {
+		return tmpl, err
+	}
+
+	var list *models.AutomodList
+	if len(words) > 0 {
+		numLists, err := models.AutomodLists(qm.Where("guild_id = ? ", g.ID)).Count(r.Context(), tx)
+		if err != nil {
+			tx.Rollback()
+			return tmpl, err
+		}
+		if numLists >= int64(GuildMaxLists(g.ID)) {
+			tx.Rollback()
+			return tmpl.AddAlerts(web.ErrorAlert(fmt.Sprintf("Importing keyword rules needs a free list, reached max number of lists, %d for normal servers and %d for premium servers", MaxLists, MaxListsPremium))), nil
+		}
+
+		list = &models.AutomodList{
+			GuildID: g.ID,
+			Name:    name,
+			Content: words,
+		}
+		if err = list.Insert(r.Context(), tx, boil.Infer()); err != nil {
+			tx.Rollback()
+			return tmpl, err
+		}
+	}
+
+	listID := int64(0)
+	if list != nil {
+		listID = list.ID
+	}
+
+	parts, err := nativeRuleParts(native, listID)
+	if err != nil {
+		tx.Rollback()
+		return tmpl.AddAlerts(web.ErrorAlert("Failed importing the rule: ", err.Error())), nil
+	}
+
+	// rulesets start disabled so the native rule and the ruleset don't both act on messages
+	ruleset := &models.AutomodRuleset{
+		GuildID: g.ID,
+		Name:    name,
+		Enabled: false,
+	}
+	if err = ruleset.Insert(r.Context(), tx, boil.Infer()); err != nil {
+		tx.Rollback()
+		return tmpl, err
+	}
+
+	rule := &models.AutomodRule{
+		GuildID:   g.ID,
+		RulesetID: ruleset.ID,
+		Name:      limitNativeName(native.Name),
+	}
+	if err = rule.Insert(r.Context(), tx, boil.Infer()); err != nil {
+		tx.Rollback()
+		return tmpl, err
+	}
+
+	parts, ok, err := CheckLimits(tx, rule, tmpl, parts)
+	if !ok || err != nil {
+		tx.Rollback()
+		return tmpl, err
+	}
+
+	for _, part := range parts {
+		part.GuildID = g.ID
+		part.RuleID = rule.ID
+		if err = part.Insert(r.Context(), tx, boil.Infer()); err != nil {
+			tx.Rollback()
+			return tmpl, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return tmpl, err
+	}
+
+	pubsub.EvictCacheSet(cachedRulesets, g.ID)
+	pubsub.EvictCacheSet(cachedLists, g.ID)
+	featureflags.MarkGuildDirty(g.ID)
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyImportedNativeRule))
+
+	msg := fmt.Sprintf("Imported the rule as the disabled ruleset %q, enable it and disable the Discord AutoMod rule to switch over.", name)
+	if native.TriggerMetadata.AllowList != nil && len(*native.TriggerMetadata.AllowList) > 0 {
+		msg += " The allow list of the rule was not imported, rulesets have no equivalent."
+	}
+	if len(skippedKeywords) > 0 {
+		msg += fmt.Sprintf(" The keywords %q were not imported, word lists only match single words.", skippedKeywords)
+	}
+	tmpl.AddAlerts(web.SucessAlert(msg))
+
+	return tmpl, nil
+}
+
+// rulesets, rules and lists are limited to 50 characters
+func limitNativeName(name string) string {
+	if len([]rune(name)) > 50 {
+		return string([]rune(name)[:50])
+	}
+
+	return name
+}
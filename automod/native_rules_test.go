@@ -0,0 +1,131 @@
+package automod
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+)
+
+func TestNativeRuleDataRoundtrip(t *testing.T) {
+	data := &NativeRuleData{
+		Name:               "bad words",
+		Enabled:            true,
+		Keywords:           "foo*\n\n bar \n",
+		RegexPatterns:      "b[a4]z",
+		AllowList:          "food",
+		BlockMessage:       true,
+		BlockCustomMessage: "nope",
+		AlertChannel:       10,
+		TimeoutDuration:    5,
+		ExemptRoles:        []int64{20},
+	}
+
+	rule, err := data.toRule(discordgo.AutoModerationEventTriggerKeyword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rule.TriggerMetadata.KeywordFilter) != 2 || rule.TriggerMetadata.KeywordFilter[1] != "bar" {
+		t.Errorf("unexpected keywords: %q", rule.TriggerMetadata.KeywordFilter)
+	}
+
+	if len(rule.Actions) != 3 || rule.Actions[2].Metadata.Duration != 300 {
+		t.Errorf("unexpected actions: %+v", rule.Actions)
+	}
+
+	back := nativeRuleFormData(rule)
+	if back.Keywords != "foo*\nbar" || back.BlockCustomMessage != "nope" || back.AlertChannel != 10 || back.TimeoutDuration != 5 || !back.Enabled {
+		t.Errorf("unexpected form data: %+v", back)
+	}
+
+	_, err = (&NativeRuleData{Name: "spam", TimeoutDuration: 5}).toRule(discordgo.AutoModerationEventTriggerSpam)
+	if err == nil {
+		t.Error("expected a error for a timeout on a spam rule")
+	}
+
+	_, err = (&NativeRuleData{Name: "mentions", BlockMessage: true, MentionLimit: 51}).toRule(discordgo.AutoModerationEventTriggerMentionSpam)
+	if err == nil {
+		t.Error("expected a error for a too high mention limit")
+	}
+}
+
+func TestNativeRuleParts(t *testing.T) {
+	rule := &discordgo.AutoModerationRule{
+		Name:        "bad words",
+		TriggerType: discordgo.AutoModerationEventTriggerKeyword,
+		TriggerMetadata: &discordgo.AutoModerationTriggerMetadata{
+			KeywordFilter: []string{"*foo*", "bar baz"},
+			RegexPatterns: []string{"b[a4]z"},
+		},
+		Actions: []discordgo.AutoModerationAction{
+			{Type: discordgo.AutoModerationRuleActionBlockMessage},
+			{Type: discordgo.AutoModerationRuleActionTimeout, Metadata: &discordgo.AutoModerationActionMetadata{Duration: 60}},
+		},
+		ExemptChannels: discordgo.IDSlice{30},
+	}
+
+	if problem := nativeRuleImportProblem(rule); problem != "" {
+		t.Fatalf("unexpected import problem: %s", problem)
+	}
+
+	words, skipped := nativeKeywordList(rule)
+	if len(words) != 1 || words[0] != "foo" {
+		t.Errorf("unexpected word list: %q", words)
+	}
+	if len(skipped) != 1 || skipped[0] != "bar baz" {
+		t.Errorf("unexpected skipped keywords: %q", skipped)
+	}
+
+	parts, err := nativeRuleParts(rule, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var typeIDs []int
+	for _, p := range parts {
+		typeIDs = append(typeIDs, p.TypeID)
+	}
+
+	expected := []int{5, 15, 202, 300, 314}
+	if len(typeIDs) != len(expected) {
+		t.Fatalf("got parts %v, expected %v", typeIDs, expected)
+	}
+	for i := range expected {
+		if typeIDs[i] != expected[i] {
+			t.Fatalf("got parts %v, expected %v", typeIDs, expected)
+		}
+	}
+
+	if string(parts[3].Settings) != "{}" {
+		t.Errorf("expected empty settings for delete message, got %s", parts[3].Settings)
+	}
+
+	rule.TriggerType = discordgo.AutoModerationEventTriggerSpam
+	if nativeRuleImportProblem(rule) == "" {
+		t.Error("expected spam rules to not be importable")
+	}
+}
+
+func TestNativeRuleEditParams(t *testing.T) {
+	rule, err := (&NativeRuleData{Name: "regex", RegexPatterns: "b[a4]z", BlockMessage: true}).toRule(discordgo.AutoModerationEventTriggerKeyword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := json.Marshal(nativeRuleEditParams(rule))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// emptied lists have to be sent for discord to clear them
+	for _, field := range []string{`"exempt_roles":[]`, `"exempt_channels":[]`, `"keyword_filter":[]`, `"regex_patterns":["b[a4]z"]`} {
+		if !strings.Contains(string(encoded), field) {
+			t.Errorf("expected %s in %s", field, encoded)
+		}
+	}
+	if strings.Contains(string(encoded), "trigger_type") {
+		t.Errorf("trigger type should not be sent: %s", encoded)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/botlabs-gg/yagpdb/v2/automod/models"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
 )
@@ -207,18 +208,19 @@ func (t *TriggeredRuleData) ConstructReason(includePrevious bool) string {
 		}
 	}
 
-	builder.WriteString("Triggered rule: ")
+	lang := i18n.GuildLanguage(t.GS.ID)
 	if t.CurrentRule == nil {
-		builder.WriteString("unknown rule?`")
+		builder.WriteString(i18n.T(lang, "Triggered rule: unknown rule?"))
 	} else {
-		builder.WriteString(t.CurrentRule.Model.Name)
-
+		rule := t.CurrentRule.Model.Name
 		for _, p := range t.ActivatedTriggers {
 			if p.RuleModel.RuleID == t.CurrentRule.Model.ID {
-				builder.WriteString(" (`" + p.Part.Name() + "`)")
+				rule += " (`" + p.Part.Name() + "`)"
 				break
 			}
 		}
+
+		builder.WriteString(i18n.Tf(lang, "Triggered rule: %s", rule))
 	}
 
 	return builder.String()
@@ -1,15 +1,21 @@
 package automod_legacy
 
 import (
+	"embed"
+
 	"emperror.dev/errors"
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/web"
 )
 
 var logger = common.GetPluginLogger(&Plugin{})
 
+//go:embed locales
+var locales embed.FS
+
 type Condition string
 
 // Redis keys
@@ -25,6 +31,7 @@ type Plugin struct{}
 func RegisterPlugin() {
 	p := &Plugin{}
 	common.RegisterPlugin(p)
+	i18n.RegisterCatalogs(locales, "locales")
 }
 
 func (p *Plugin) PluginInfo() *common.PluginInfo {
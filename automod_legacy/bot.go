@@ -9,6 +9,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/bot/eventsystem"
 	"github.com/botlabs-gg/yagpdb/v2/commands"
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	"github.com/botlabs-gg/yagpdb/v2/common/pubsub"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
@@ -161,15 +162,16 @@ func CheckMessage(evt *eventsystem.EventData, m *discordgo.Message) bool {
 			}
 		}()
 
+		reason := i18n.GuildTf(cs.GuildID, "Automoderator: %s", punishMsg)
 		switch highestPunish {
 		case PunishNone:
-			err = moderation.WarnUser(nil, cs.GuildID, cs, m, common.BotUser, &member.User, "Automoderator: "+punishMsg, false)
+			err = moderation.WarnUser(nil, cs.GuildID, cs, m, common.BotUser, &member.User, reason, false)
 		case PunishMute:
-			err = moderation.MuteUnmuteUser(nil, true, cs.GuildID, cs, m, common.BotUser, "Automoderator: "+punishMsg, member, muteDuration, false)
+			err = moderation.MuteUnmuteUser(nil, true, cs.GuildID, cs, m, common.BotUser, reason, member, muteDuration, false)
 		case PunishKick:
-			err = moderation.KickUser(nil, cs.GuildID, cs, m, common.BotUser, "Automoderator: "+punishMsg, &member.User, -1, false)
+			err = moderation.KickUser(nil, cs.GuildID, cs, m, common.BotUser, reason, &member.User, -1, false)
 		case PunishBan:
-			err = moderation.BanUser(nil, cs.GuildID, cs, m, common.BotUser, "Automoderator: "+punishMsg, &member.User, false)
+			err = moderation.BanUser(nil, cs.GuildID, cs, m, common.BotUser, reason, &member.User, false)
 		}
 
 		// Execute the punishment before removing the message to make sure it's included in logs
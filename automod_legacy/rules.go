@@ -1,7 +1,6 @@
 package automod_legacy
 
 import (
-	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -11,6 +10,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/antiphishing"
 	"github.com/botlabs-gg/yagpdb/v2/bot"
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	"github.com/botlabs-gg/yagpdb/v2/lib/confusables"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
@@ -140,7 +140,7 @@ func (s *SpamRule) Check(evt *discordgo.Message, cs *dstate.ChannelState) (del b
 		return
 	}
 
-	msg = "Sending messages too fast."
+	msg = i18n.GuildT(cs.GuildID, "Sending messages too fast.")
 
 	return
 }
@@ -191,7 +191,7 @@ func (i *InviteRule) Check(evt *discordgo.Message, cs *dstate.ChannelState) (del
 		return
 	}
 
-	msg = "Sending server invites to another server."
+	msg = i18n.GuildT(cs.GuildID, "Sending server invites to another server.")
 	return
 }
 
@@ -362,7 +362,7 @@ func (m *MentionRule) Check(evt *discordgo.Message, cs *dstate.ChannelState) (de
 	if err != nil {
 		return
 	}
-	msg = "Sending too many mentions."
+	msg = i18n.GuildT(cs.GuildID, "Sending too many mentions.")
 	return
 }
 
@@ -383,7 +383,7 @@ func (l *LinksRule) Check(evt *discordgo.Message, cs *dstate.ChannelState) (del
 		return
 	}
 
-	msg = "You do not have permission to send links"
+	msg = i18n.GuildT(cs.GuildID, "You do not have permission to send links")
 
 	return
 }
@@ -420,7 +420,7 @@ func (w *WordsRule) Check(evt *discordgo.Message, cs *dstate.ChannelState) (del
 	del = true
 	punishment, err = w.PushViolation(KeyViolations(cs.GuildID, evt.Author.ID, "badword"))
 
-	msg = fmt.Sprintf("The word `%s` is banned, watch your language.", word)
+	msg = i18n.GuildTf(cs.GuildID, "The word `%s` is banned, watch your language.", word)
 	return
 }
 
@@ -482,7 +482,7 @@ func (s *SitesRule) Check(evt *discordgo.Message, cs *dstate.ChannelState) (del
 		extraInfo = "(sb: " + threatList + ")"
 	}
 
-	msg = fmt.Sprintf("The website `%s` is banned %s", item, extraInfo)
+	msg = i18n.GuildTf(cs.GuildID, "The website `%s` is banned %s", item, extraInfo)
 	del = true
 	return
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/lib/dshardorchestrator/node"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate/inmemorytracker"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate/redistracker"
 	dshardmanager "github.com/botlabs-gg/yagpdb/v2/lib/jdshardmanager"
 	"github.com/mediocregopher/radix/v3"
 )
@@ -28,7 +29,7 @@ var (
 	Enabled      bool // wether the bot is set to run at some point in this process
 	Running      bool // wether the bot is currently running
 	State        dstate.StateTracker
-	stateTracker *inmemorytracker.InMemoryTracker
+	stateTracker writableStateTracker
 
 	ShardManager *dshardmanager.Manager
 
@@ -96,8 +97,12 @@ func Run(nodeID string) {
 	} else {
 		ShardManager.Init()
 		if usingFixedSharding {
+			restoreStateSnapshot(fixedShardingID)
 			go ShardManager.Session(fixedShardingID).Open()
 		} else {
+			for i := 0; i < totalShardCount; i++ {
+				restoreStateSnapshot(i)
+			}
 			go ShardManager.Start()
 		}
 		botReady()
@@ -239,6 +244,7 @@ func StopAllPlugins(wg *sync.WaitGroup) {
 func Stop(wg *sync.WaitGroup) {
 	StopAllPlugins(wg)
 	ShardManager.StopAll()
+	saveStateSnapshots()
 	wg.Done()
 }
 
@@ -332,13 +338,46 @@ func (rl *identifyRatelimiter) RatelimitIdentify(shardID int) {
 	}
 }
 
-var confStateRemoveOfflineMembers = config.RegisterOption("yagpdb.state.remove_offline_members", "Remove offline members from state", true)
+var (
+	confStateRemoveOfflineMembers = config.RegisterOption("yagpdb.state.remove_offline_members", "Remove offline members from state", true)
+	confStateBackend              = config.RegisterOption("yagpdb.state.backend", "Where to keep the discord state, 'memory' or 'redis'. With redis it survives restarts and doesn't need to be transferred on shard migrations", "memory")
+)
+
+// writableStateTracker is a state tracker the bot feeds events and migrated state into
+type writableStateTracker interface {
+	dstate.StateTracker
+	HandleEvent(s *discordgo.Session, evt interface{})
+	SetGuild(gs *dstate.GuildSet)
+	SetMember(ms *dstate.MemberState)
+	DelShard(shardID int64)
+}
+
+// StateIsShared returns true if the state is kept outside of this process,
+// in which case it doesn't need to be transferred to other nodes
+func StateIsShared() bool {
+	return confStateBackend.GetString() == "redis"
+}
 
 var StateLimitsF func(guildID int64) (int, time.Duration) = func(guildID int64) (int, time.Duration) {
 	return 1000, time.Hour
 }
 
 func setupState() {
+	if StateIsShared() {
+		tracker := redistracker.NewRedisTracker(common.RedisPool, redistracker.TrackerConfig{
+			ChannelMessageLimitsF: StateLimitsF,
+			BotMemberID:           common.BotUser.ID,
+			OnError: func(err error) {
+				logger.WithError(err).Error("redis state tracker error")
+			},
+		}, int64(totalShardCount))
+
+		eventsystem.DiscordState = tracker
+
+		stateTracker = tracker
+		State = tracker
+		return
+	}
 
 	removeMembersDur := time.Duration(0)
 	if confStateRemoveOfflineMembers.GetBool() {
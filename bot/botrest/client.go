@@ -16,21 +16,47 @@ import (
 var clientLogger = common.GetFixedPrefixLogger("botrest_client")
 
 func GetGuild(guildID int64) (g *dstate.GuildSet, err error) {
+	if st := getSharedState(); st != nil {
+		if g = st.GetGuild(guildID); g != nil {
+			return g, nil
+		}
+	}
+
 	err = internalapi.GetWithGuild(guildID, discordgo.StrID(guildID)+"/guild", &g)
 	return
 }
 
 func GetBotMember(guildID int64) (m *discordgo.Member, err error) {
+	if ms, ok := sharedStateMembers(guildID, common.BotUser.ID); ok {
+		return ms[0].DgoMember(), nil
+	}
+
 	err = internalapi.GetWithGuild(guildID, discordgo.StrID(guildID)+"/botmember", &m)
 	return
 }
 
 func GetOnlineCount(guildID int64) (c int64, err error) {
+	if st := getSharedState(); st != nil && st.GetGuild(guildID) != nil {
+		st.IterateMembers(guildID, func(chunk []*dstate.MemberState) bool {
+			c += int64(len(chunk))
+			return true
+		})
+		return c, nil
+	}
+
 	err = internalapi.GetWithGuild(guildID, discordgo.StrID(guildID)+"/onlinecount", &c)
 	return
 }
 
 func GetMembers(guildID int64, members ...int64) (m []*discordgo.Member, err error) {
+	if states, ok := sharedStateMembers(guildID, members...); ok {
+		m = make([]*discordgo.Member, len(states))
+		for i, v := range states {
+			m[i] = v.DgoMember()
+		}
+		return m, nil
+	}
+
 	stringed := make([]string, 0, len(members))
 	for _, v := range members {
 		stringed = append(stringed, strconv.FormatInt(v, 10))
@@ -44,6 +70,12 @@ func GetMembers(guildID int64, members ...int64) (m []*discordgo.Member, err err
 }
 
 func GetMemberColors(guildID int64, members ...int64) (m map[string]int, err error) {
+	if states, ok := sharedStateMembers(guildID, members...); ok {
+		if guild := getSharedState().GetGuild(guildID); guild != nil {
+			return memberColors(guild, states), nil
+		}
+	}
+
 	m = make(map[string]int)
 
 	stringed := make([]string, 0, len(members))
@@ -73,6 +105,12 @@ func GetMemberMultiGuild(userID int64, guilds ...int64) (members []*discordgo.Me
 }
 
 func GetChannelPermissions(guildID, channelID int64) (perms int64, err error) {
+	if ms, ok := sharedStateMembers(guildID, common.BotUser.ID); ok {
+		if guild := getSharedState().GetGuild(guildID); guild != nil {
+			return guild.GetMemberPermissions(channelID, ms[0].User.ID, ms[0].Member.Roles)
+		}
+	}
+
 	err = internalapi.GetWithGuild(guildID, discordgo.StrID(guildID)+"/channelperms/"+discordgo.StrID(channelID), &perms)
 	return
 }
@@ -3,7 +3,6 @@ package botrest
 import (
 	"net/http"
 	"os"
-	"slices"
 	"strconv"
 	"time"
 
@@ -150,25 +149,7 @@ func HandleGetMemberColors(w http.ResponseWriter, r *http.Request) {
 
 	memberStates, _ := bot.GetMembers(gId, uIDsParsed...)
 
-	colors := make(map[string]int)
-	for _, ms := range memberStates {
-		// Find the highest role this user has with a color
-		for _, role := range guild.Roles {
-			if role.Color == 0 {
-				continue
-			}
-
-			if !slices.Contains(ms.Member.Roles, role.ID) {
-				continue
-			}
-
-			// Bingo
-			colors[strconv.FormatInt(ms.User.ID, 10)] = role.Color
-			break
-		}
-	}
-
-	internalapi.ServeJson(w, r, colors)
+	internalapi.ServeJson(w, r, memberColors(guild, memberStates))
 }
 
 func HandleGetOnlineCount(w http.ResponseWriter, r *http.Request) {
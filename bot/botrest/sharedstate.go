@@ -0,0 +1,81 @@
+package botrest
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate/redistracker"
+)
+
+// When the bot keeps its state in redis the client reads it from there directly instead of asking the bot,
+// and only falls back to the internal api for what isn't in the state.
+
+var (
+	sharedState     dstate.StateTracker
+	sharedStateOnce sync.Once
+)
+
+// getSharedState returns the state kept in redis by the bot, or nil if the state is kept in the bot's memory
+func getSharedState() dstate.StateTracker {
+	if !bot.StateIsShared() {
+		return nil
+	}
+
+	sharedStateOnce.Do(func() {
+		// this tracker is never fed events, so the shard count doesn't matter
+		sharedState = redistracker.NewRedisTracker(common.RedisPool, redistracker.TrackerConfig{
+			OnError: func(err error) {
+				clientLogger.WithError(err).Error("failed reading the shared state")
+			},
+		}, 1)
+	})
+
+	return sharedState
+}
+
+// sharedStateMembers returns the members from the shared state, or false if there is no shared state or any of them are missing
+func sharedStateMembers(guildID int64, members ...int64) ([]*dstate.MemberState, bool) {
+	st := getSharedState()
+	if st == nil {
+		return nil, false
+	}
+
+	result := make([]*dstate.MemberState, 0, len(members))
+	for _, v := range members {
+		ms := st.GetMember(guildID, v)
+		if ms == nil || ms.Member == nil {
+			return nil, false
+		}
+
+		result = append(result, ms)
+	}
+
+	return result, true
+}
+
+// memberColors returns the color of the highest colored role of each member, keyed by their id
+func memberColors(guild *dstate.GuildSet, members []*dstate.MemberState) map[string]int {
+	colors := make(map[string]int)
+	for _, ms := range members {
+		// Find the highest role this user has with a color
+		for _, role := range guild.Roles {
+			if role.Color == 0 {
+				continue
+			}
+
+			if !slices.Contains(ms.Member.Roles, role.ID) {
+				continue
+			}
+
+			// Bingo
+			colors[strconv.FormatInt(ms.User.ID, 10)] = role.Color
+			break
+		}
+	}
+
+	return colors
+}
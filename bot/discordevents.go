@@ -67,6 +67,7 @@ func HandleReady(data *eventsystem.EventData) {
 	evt := data.Ready()
 
 	commonEventsTotal.With(prometheus.Labels{"type": "Ready"}).Inc()
+	restoredStateResult(data.Session.ShardID, false)
 	RefreshStatus(ContextSession(data.Context()))
 
 	// We pass the common.Session to the command system and that needs the user from the state
@@ -424,4 +425,5 @@ func HandleRatelimit(evt *eventsystem.EventData) {
 
 func handleResumed(evt *eventsystem.EventData) {
 	commonEventsTotal.With(prometheus.Labels{"type": "Resumed"}).Inc()
+	restoredStateResult(evt.Session.ShardID, true)
 }
@@ -13,9 +13,11 @@ import (
 
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/common/tracing"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var DiscordState dstate.StateTracker
@@ -183,9 +185,13 @@ func runEvents(h []*Handler, data *EventData) {
 
 			first = false
 
+			// handlers share the event data, so the span is only used for timing and errors
+			_, span := tracing.Start(data.Context(), "handler "+v.Plugin.PluginInfo().SysName)
+
 			if v.F != nil {
 				var err error
 				retry, err = v.F(data)
+				tracing.End(span, err)
 
 				guildID := int64(0)
 				if guildIDProvider, ok := data.EvtInterface.(discordgo.GuildEvent); ok {
@@ -202,6 +208,7 @@ func runEvents(h []*Handler, data *EventData) {
 			} else {
 				retry = false
 				v.FLegacy(data)
+				span.End()
 			}
 
 		}
@@ -407,10 +414,19 @@ func eventWorker(ch chan *EventData) {
 }
 
 func handleEvent(evtData *EventData) {
+	ctx, span := tracing.Start(evtData.Context(), "event "+evtData.Type.String())
+	evtData.ctx = ctx
+	defer span.End()
+
+	if evtData.Session != nil {
+		span.SetAttributes(attribute.Int("shard_id", evtData.Session.ShardID))
+	}
+
 	// fill in guild state if applicable
 	if guildEvt, ok := evtData.EvtInterface.(discordgo.GuildEvent); ok {
 		id := guildEvt.GetGuildID()
 		if id != 0 {
+			span.SetAttributes(tracing.GuildID(id))
 			evtData.GS = DiscordState.GetGuild(id)
 
 			// If guild state is not available for any guild related events, except creates and deletes, do not run the handlers
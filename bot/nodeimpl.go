@@ -98,7 +98,9 @@ func (n *NodeImpl) AddNewShards(shards ...int) {
 	ReadyTracker.shardsAdded(shards...)
 
 	for _, shard := range shards {
-		ShardManager.Sessions[shard].GatewayManager.SetSessionInfo("", 0, "")
+		if !restoreStateSnapshot(shard) {
+			ShardManager.Sessions[shard].GatewayManager.SetSessionInfo("", 0, "")
+		}
 
 		go ShardManager.Sessions[shard].GatewayManager.Open()
 	}
@@ -166,6 +168,12 @@ func (n *NodeImpl) SendGuilds(shard int) int {
 		}
 	}
 
+	if StateIsShared() {
+		// the next node reads the same state, nothing to send
+		logger.Printf("Took %s to transfer %d objects", time.Since(started), pluginSentEvents)
+		return pluginSentEvents
+	}
+
 	// Send the guilds on this shard
 	guildsToSend := State.GetShardGuilds(int64(shard))
 
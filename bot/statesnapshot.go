@@ -0,0 +1,132 @@
+package bot
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common/config"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate/inmemorytracker"
+)
+
+var (
+	confStateSnapshotDir    = config.RegisterOption("yagpdb.state.snapshot_dir", "If set, the in memory state is saved to this directory on shutdown and restored on start, so shards can resume without re-requesting everything", "")
+	confStateSnapshotMaxAge = config.RegisterOption("yagpdb.state.snapshot_max_age", "Snapshots older than this many seconds are thrown away, discord won't let us resume sessions that old anyways", 300)
+)
+
+var (
+	// shards that were started with a restored snapshot and haven't received a ready or resumed yet
+	restoredShards   = make(map[int]bool)
+	restoredShardsMu sync.Mutex
+)
+
+func snapshotTracker() (*inmemorytracker.InMemoryTracker, string) {
+	dir := confStateSnapshotDir.GetString()
+	if dir == "" {
+		return nil, ""
+	}
+
+	tracker, ok := stateTracker.(*inmemorytracker.InMemoryTracker)
+	if !ok {
+		return nil, ""
+	}
+
+	return tracker, dir
+}
+
+// saveStateSnapshots saves the state of all shards with a resumable session, should be called after the sessions are closed
+func saveStateSnapshots() {
+	tracker, dir := snapshotTracker()
+	if tracker == nil {
+		return
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		logger.WithError(err).Error("failed creating state snapshot dir")
+		return
+	}
+
+	started := time.Now()
+	saved := 0
+	for i, session := range ShardManager.Sessions {
+		sessionID, sequence, resumeGatewayURL := session.GatewayManager.GetSessionInfo()
+		if sessionID == "" {
+			// nothing to resume, the state would be thrown away anyways
+			continue
+		}
+
+		snap := tracker.SnapshotShard(int64(i))
+		snap.SessionID = sessionID
+		snap.Sequence = sequence
+		snap.ResumeGatewayURL = resumeGatewayURL
+
+		err := inmemorytracker.SaveSnapshotFile(dir, snap)
+		if err != nil {
+			logger.WithError(err).Errorf("failed saving state snapshot for shard %d", i)
+			continue
+		}
+
+		saved++
+	}
+
+	logger.Infof("Saved state snapshots of %d shards in %s", saved, time.Since(started))
+}
+
+// restoreStateSnapshot restores the saved state of the shard and sets up its session to resume
+// if the resume fails the shard receives a ready which resets the state again
+func restoreStateSnapshot(shard int) bool {
+	tracker, dir := snapshotTracker()
+	if tracker == nil {
+		return false
+	}
+
+	snap, err := inmemorytracker.LoadSnapshotFile(dir, int64(shard))
+	if err != nil {
+		logger.WithError(err).Errorf("failed loading state snapshot for shard %d", shard)
+		return false
+	}
+
+	if snap == nil || snap.SessionID == "" {
+		return false
+	}
+
+	maxAge := time.Duration(confStateSnapshotMaxAge.GetInt()) * time.Second
+	if age := time.Since(snap.CreatedAt); age > maxAge {
+		logger.Infof("Ignoring state snapshot for shard %d, too old (%s)", shard, age)
+		return false
+	}
+
+	err = tracker.RestoreShard(snap)
+	if err != nil {
+		logger.WithError(err).Errorf("failed restoring state snapshot for shard %d", shard)
+		return false
+	}
+
+	ShardManager.Sessions[shard].GatewayManager.SetSessionInfo(snap.SessionID, snap.Sequence, snap.ResumeGatewayURL)
+
+	restoredShardsMu.Lock()
+	restoredShards[shard] = true
+	restoredShardsMu.Unlock()
+
+	logger.Infof("Restored state snapshot for shard %d with %d guilds, attempting to resume", shard, len(snap.Guilds))
+	return true
+}
+
+// restoredStateResult is called on ready and resumed events to log whether the restored state was kept
+func restoredStateResult(shard int, resumed bool) {
+	restoredShardsMu.Lock()
+	wasRestored := restoredShards[shard]
+	delete(restoredShards, shard)
+	restoredShardsMu.Unlock()
+
+	if !wasRestored {
+		return
+	}
+
+	if resumed {
+		logger.Infof("Shard %d resumed, keeping the restored state", shard)
+	} else {
+		logger.Warnf("Shard %d failed resuming, the restored state was thrown away", shard)
+	}
+}
@@ -2,7 +2,6 @@ package bulkrole
 
 import (
 	"fmt"
-	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,6 +12,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/bot/botrest"
 	"github.com/botlabs-gg/yagpdb/v2/bot/eventsystem"
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
 	"github.com/mediocregopher/radix/v3"
@@ -23,6 +23,7 @@ var _ bot.BotStopperHandler = (*Plugin)(nil)
 
 func (p *Plugin) BotInit() {
 	eventsystem.AddHandlerAsyncLastLegacy(p, handleGuildChunk, eventsystem.EventGuildMembersChunk)
+	scheduledevents2.RegisterHandler(scheduledEventName, BulkRoleConfig{}, handleScheduledOperation)
 }
 
 func (p *Plugin) StopBot(wg *sync.WaitGroup) {
@@ -106,7 +107,8 @@ func (config *BulkRoleConfig) processBulkRoleChunk(chunk *discordgo.GuildMembers
 		return
 	}
 
-	lastTimeStatusRefreshed := time.Now()
+	now := time.Now()
+	lastTimeStatusRefreshed := now
 
 	guildID := config.GuildID
 	session := bot.ShardManager.SessionForGuild(guildID)
@@ -117,41 +119,40 @@ func (config *BulkRoleConfig) processBulkRoleChunk(chunk *discordgo.GuildMembers
 
 		common.RedisPool.Do(radix.Cmd(nil, "INCR", RedisKeyBulkRoleProcessed(guildID)))
 
-		if !config.filterMember(member) {
+		if !config.matchMember(member, now) {
 			continue
 		}
 
-		hasRole := slices.Contains(member.Roles, config.TargetRole)
-		needsOperation := false
-
-		switch config.Operation {
-		case "assign":
-			needsOperation = !hasRole
-		case "remove":
-			needsOperation = hasRole
-		}
-
-		if !needsOperation {
+		add, remove := config.roleChanges(member)
+		if len(add) == 0 && len(remove) == 0 {
 			continue
 		}
 
-		var err error
-		switch config.Operation {
-		case "assign":
-			err = session.GuildMemberRoleAdd(guildID, member.User.ID, config.TargetRole)
-		case "remove":
-			err = session.GuildMemberRoleRemove(guildID, member.User.ID, config.TargetRole)
+		entry := &ReportEntry{
+			UserID:       member.User.ID,
+			Username:     member.User.String(),
+			RolesAdded:   add,
+			RolesRemoved: remove,
 		}
 
-		if err != nil {
-			logger.WithError(err).WithField("guild", guildID).WithField("user", member.User.ID).Error("Failed to modify role")
-			continue
+		if !config.DryRun {
+			err := config.applyRoleChanges(session, member.User.ID, add, remove)
+			if err != nil {
+				logger.WithError(err).WithField("guild", guildID).WithField("user", member.User.ID).Error("Failed to modify role")
+				entry.Failed = true
+			}
 		}
 
-		common.RedisPool.Do(radix.Cmd(nil, "INCR", RedisKeyBulkRoleResults(guildID)))
+		var results int
+		if !entry.Failed {
+			common.RedisPool.Do(radix.Cmd(&results, "INCR", RedisKeyBulkRoleResults(guildID)))
+		}
+		config.addReportEntry(entry, results)
 
 		// Rate limiting
-		time.Sleep(time.Millisecond * 100)
+		if !config.DryRun {
+			time.Sleep(time.Millisecond * 100)
+		}
 
 		// Refresh status every 50 seconds to keep Redis keys alive
 		if time.Since(lastTimeStatusRefreshed) > time.Second*50 {
@@ -178,68 +179,29 @@ func (config *BulkRoleConfig) processBulkRoleChunk(chunk *discordgo.GuildMembers
 	}
 }
 
-// Check if a member meets the filter criteria
-func (config *BulkRoleConfig) filterMember(member *discordgo.Member) bool {
-	switch config.FilterType {
-	case "all":
-		return true
-	case "has_roles":
-		if len(config.FilterRoleIDs) == 0 {
-			return false
-		}
-		if config.FilterRequireAll {
-			for _, roleID := range config.FilterRoleIDs {
-				if !slices.Contains(member.Roles, roleID) {
-					return false
-				}
-			}
-			return true
-		} else {
-			for _, roleID := range config.FilterRoleIDs {
-				if slices.Contains(member.Roles, roleID) {
-					return true
-				}
-			}
-			return false
-		}
-	case "missing_roles":
-		if len(config.FilterRoleIDs) == 0 {
-			return false
+// applyRoleChanges adds and removes the roles one at a time so that concurrent role changes to the member aren't overwritten
+func (config *BulkRoleConfig) applyRoleChanges(session *discordgo.Session, userID int64, add, remove []int64) error {
+	for i, role := range add {
+		if i > 0 {
+			time.Sleep(time.Millisecond * 100)
 		}
-		if config.FilterRequireAll {
-			for _, roleID := range config.FilterRoleIDs {
-				if slices.Contains(member.Roles, roleID) {
-					return false
-				}
-			}
-			return true
-		} else {
-			for _, roleID := range config.FilterRoleIDs {
-				if !slices.Contains(member.Roles, roleID) {
-					return true
-				}
-			}
-			return false
+
+		if err := session.GuildMemberRoleAdd(config.GuildID, userID, role); err != nil {
+			return err
 		}
-	case "bots":
-		return member.User.Bot
-	case "humans":
-		return !member.User.Bot
-	case "joined_after":
-		if config.FilterDateParsed.IsZero() {
-			return false
+	}
+
+	for i, role := range remove {
+		if i > 0 {
+			time.Sleep(time.Millisecond * 100)
 		}
-		joinedAt, _ := member.JoinedAt.Parse()
-		return joinedAt.After(config.FilterDateParsed)
-	case "joined_before":
-		if config.FilterDateParsed.IsZero() {
-			return false
+
+		if err := session.GuildMemberRoleRemove(config.GuildID, userID, role); err != nil {
+			return err
 		}
-		joinedAt, _ := member.JoinedAt.Parse()
-		return joinedAt.Before(config.FilterDateParsed)
-	default:
-		return false
 	}
+
+	return nil
 }
 
 func (config *BulkRoleConfig) canBotAssignRole() error {
@@ -253,11 +215,6 @@ func (config *BulkRoleConfig) canBotAssignRole() error {
 		return errors.New("failed to get guild")
 	}
 
-	targetRole := guild.GetRole(config.TargetRole)
-	if targetRole == nil {
-		return errors.New("failed to get role")
-	}
-
 	botMember, err := bot.GetMember(guild.ID, common.BotUser.ID)
 	if err != nil {
 		return errors.WithMessage(err, "failed to get bot member")
@@ -273,8 +230,15 @@ func (config *BulkRoleConfig) canBotAssignRole() error {
 		return errors.New("bot cannot manage the target role (missing permissions)")
 	}
 	botHighestRole := bot.MemberHighestRole(guild, botMember)
-	if common.IsRoleAbove(targetRole, botHighestRole) {
-		return errors.New("bot cannot manage the target role (role hierarchy)")
+	for _, roleID := range config.targetRoles() {
+		targetRole := guild.GetRole(roleID)
+		if targetRole == nil {
+			return errors.New("failed to get role")
+		}
+
+		if common.IsRoleAbove(targetRole, botHighestRole) {
+			return errors.New("bot cannot manage the target role (role hierarchy)")
+		}
 	}
 
 	return nil
@@ -301,10 +265,18 @@ func (config *BulkRoleConfig) startBulkRoleOperation() error {
 		return errors.Errorf("Rate limit active. Please wait %d seconds before starting another operation", remaining)
 	}
 
-	if config.TargetRole == 0 {
+	if len(config.targetRoles()) == 0 {
 		return errors.New("Target role is required")
 	}
 
+	if len(config.targetRoles()) > MaxTargetRoles {
+		return errors.Errorf("Too many target roles, max %d", MaxTargetRoles)
+	}
+
+	if err := config.validateFilters(); err != nil {
+		return err
+	}
+
 	if err := config.canBotAssignRole(); err != nil {
 		return errors.WithMessage(err, "insufficient permissions")
 	}
@@ -317,6 +289,7 @@ func (config *BulkRoleConfig) startBulkRoleOperation() error {
 	common.RedisPool.Do(radix.Cmd(nil, "SET", RedisKeyBulkRoleProcessed(guildID), "0"))
 	common.RedisPool.Do(radix.Cmd(nil, "SET", RedisKeyBulkRoleResults(guildID), "0"))
 	common.RedisPool.Do(radix.Cmd(nil, "SET", RedisKeyBulkRoleChunksProcessed(guildID), "0"))
+	common.RedisPool.Do(radix.Cmd(nil, "DEL", RedisKeyBulkRoleReport(guildID)))
 
 	session := bot.ShardManager.SessionForGuild(guildID)
 	query := ""
@@ -352,6 +325,7 @@ func (config *BulkRoleConfig) markBulkRoleOperationEnd(status, msg string) {
 		RedisKeyBulkRoleProcessed(guildID),
 		RedisKeyBulkRoleResults(guildID)))
 
+	config.finishReport(status, processed, results)
 	config.sendNotificationAlert(status, processed, results, msg)
 	logger.WithField("guild", guildID).Info("Bulk role operation force-completed due to timeout/stuck/cancellation state")
 }
@@ -392,93 +366,6 @@ func (config *BulkRoleConfig) setBulkRoleCooldown() {
 	common.RedisPool.Do(radix.Cmd(nil, "SETEX", RedisKeyBulkRoleCooldown(config.GuildID), "30", "1"))
 }
 
-func (config *BulkRoleConfig) filterRoleString(prefix string) string {
-	const maxFieldLength = 1000 // Leave some buffer below Discord's 1024 char limit
-	if len(config.FilterRoleIDs) == 0 {
-		return prefix
-	}
-
-	// Build the suffix first
-	var suffix string
-	if config.FilterRequireAll {
-		suffix = " (must have ALL)"
-	} else {
-		suffix = " (must have ANY)"
-	}
-
-	// Start building the role list
-	roleText := prefix + ": "
-	availableLength := maxFieldLength - len(roleText) - len(suffix)
-
-	var addedRoles []string
-	totalLength := 0
-
-	for i, roleID := range config.FilterRoleIDs {
-		roleStr := fmt.Sprintf("<@&%d>", roleID)
-		separator := ""
-		if i > 0 {
-			separator = ", "
-		}
-
-		testLength := totalLength + len(separator) + len(roleStr)
-
-		// Check if adding this role would exceed the limit
-		if testLength > availableLength {
-			// We need to truncate
-			remaining := len(config.FilterRoleIDs) - len(addedRoles)
-			truncationMsg := fmt.Sprintf(", ... and %d more", remaining)
-
-			// Make sure we have room for the truncation message
-			if totalLength+len(truncationMsg) <= availableLength {
-				roleText += strings.Join(addedRoles, ", ") + truncationMsg + suffix
-			} else {
-				// Not enough room even for truncation message, show fewer roles
-				for j := len(addedRoles) - 1; j >= 0; j-- {
-					testText := strings.Join(addedRoles[:j+1], ", ") + truncationMsg
-					if len(testText) <= availableLength {
-						roleText += testText + suffix
-						break
-					}
-				}
-				// If still too long, just show count
-				if len(roleText) <= len(prefix)+2 {
-					return fmt.Sprintf("%s: %d roles selected%s", prefix, len(config.FilterRoleIDs), suffix)
-				}
-			}
-			return roleText
-		}
-
-		addedRoles = append(addedRoles, roleStr)
-		totalLength = testLength
-	}
-
-	roleText += strings.Join(addedRoles, ", ") + suffix
-	return roleText
-}
-
-func (config *BulkRoleConfig) filterString() string {
-	var prefix string
-	switch config.FilterType {
-	case "has_roles":
-		return config.filterRoleString("Has roles")
-	case "missing_roles":
-		return config.filterRoleString("Missing roles")
-	case "all":
-		prefix = "All members"
-	case "bots":
-		prefix = "Bots"
-	case "humans":
-		prefix = "Humans"
-	case "joined_after":
-		prefix = "Joined after: " + config.FilterDateParsed.Format("January 2, 2006")
-	case "joined_before":
-		prefix = "Joined before: " + config.FilterDateParsed.Format("January 2, 2006")
-	default:
-		prefix = "Roles"
-	}
-	return prefix
-}
-
 func (config *BulkRoleConfig) sendNotificationAlert(status string, processedCount int, resultsCount int, errorMsg string) {
 	if config.NotificationChannel == 0 {
 		return
@@ -502,22 +389,18 @@ func (config *BulkRoleConfig) sendNotificationAlert(status string, processedCoun
 		embed.Color = 0xffa500
 		embed.Title = "⏹️ Bulk Role Operation Cancelled"
 	}
+
+	changesLabel := "Changes Made"
+	if config.DryRun {
+		embed.Title += " (Dry Run)"
+		changesLabel = "Would Change"
+	}
+
 	filterString := config.filterString()
 
 	// Final safety check: if filter details are still too long, use a simple fallback
 	if len(filterString) > 1024 {
-		switch config.FilterType {
-		case "has_roles":
-			filterString = fmt.Sprintf("Has %d specific roles (too many to display)%s",
-				len(config.FilterRoleIDs),
-				map[bool]string{true: " (must have ALL)", false: " (must have ANY)"}[config.FilterRequireAll])
-		case "missing_roles":
-			filterString = fmt.Sprintf("Missing %d specific roles (too many to display)%s",
-				len(config.FilterRoleIDs),
-				map[bool]string{true: " (must be missing ALL)", false: " (must be missing ANY)"}[config.FilterRequireAll])
-		default:
-			filterString = config.FilterType
-		}
+		filterString = fmt.Sprintf("%d conditions (too many to display)", len(config.Filters))
 	}
 
 	embed.Fields = []*discordgo.MessageEmbedField{
@@ -527,8 +410,8 @@ func (config *BulkRoleConfig) sendNotificationAlert(status string, processedCoun
 			Inline: true,
 		},
 		{
-			Name:   "Target Role",
-			Value:  fmt.Sprintf("<@&%d>", config.TargetRole),
+			Name:   "Target Roles",
+			Value:  roleMentions(config.targetRoles()),
 			Inline: true,
 		},
 		{
@@ -547,7 +430,7 @@ func (config *BulkRoleConfig) sendNotificationAlert(status string, processedCoun
 			Inline: true,
 		},
 		{
-			Name:   "Changes Made",
+			Name:   changesLabel,
 			Value:  strconv.Itoa(resultsCount),
 			Inline: true,
 		},
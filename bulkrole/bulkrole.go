@@ -35,11 +35,17 @@ func RegisterPlugin() {
 	common.RegisterPlugin(p)
 }
 
+// MaxTargetRoles is the max number of roles a single operation can assign or remove
+const MaxTargetRoles = 10
+
 type BulkRoleConfig struct {
-	TargetRole int64 `json:",string" valid:"role,true"`
+	// Legacy single target role, TargetRoles is used if set
+	TargetRole  int64   `json:",string" valid:"role,true"`
+	TargetRoles []int64 `json:",omitempty"`
 
 	Operation string `valid:"in(assign|remove)"`
 
+	// Legacy single filter, converted to Filters when loaded
 	FilterType string `valid:"in(all|has_role|missing_role|bots|humans|joined_after|joined_before)"`
 
 	FilterRoleIDs    []int64   `json:",omitempty"`
@@ -47,6 +53,14 @@ type BulkRoleConfig struct {
 	FilterDate       string    `json:",omitempty"`
 	FilterDateParsed time.Time `json:"-"`
 
+	// Members have to match all of the filters, or any of them if FilterMatchAny is set
+	// no filters matches everyone
+	Filters        []*FilterCondition `json:",omitempty"`
+	FilterMatchAny bool               `json:",omitempty"`
+
+	// Only count and report the members that would be changed
+	DryRun bool `json:",omitempty"`
+
 	NotificationChannel int64 `json:",string" valid:"channel,true"`
 
 	StartedBy         int64  `json:",string"`
@@ -54,6 +68,43 @@ type BulkRoleConfig struct {
 	GuildID           int64  `json:",string"`
 }
 
+// targetRoles returns the roles the operation assigns or removes
+func (config *BulkRoleConfig) targetRoles() []int64 {
+	if len(config.TargetRoles) > 0 {
+		return config.TargetRoles
+	}
+
+	if config.TargetRole != 0 {
+		return []int64{config.TargetRole}
+	}
+
+	return nil
+}
+
+// migrateLegacyFilter converts the single FilterType filter to Filters
+func (config *BulkRoleConfig) migrateLegacyFilter() {
+	if len(config.Filters) > 0 || config.FilterType == "" || config.FilterType == "all" {
+		return
+	}
+
+	cond := &FilterCondition{
+		Type:       config.FilterType,
+		RoleIDs:    config.FilterRoleIDs,
+		RequireAll: config.FilterRequireAll,
+		Date:       config.FilterDate,
+	}
+
+	switch config.FilterType {
+	case "has_role":
+		cond.Type = FilterHasRoles
+	case "missing_role":
+		cond.Type = FilterMissingRoles
+	}
+
+	config.Filters = []*FilterCondition{cond}
+	config.FilterType = ""
+}
+
 const (
 	BulkRoleStarted int = iota + 1
 	BulkRoleIterating
@@ -81,6 +132,7 @@ func GetBulkRoleConfig(guildID int64) (*BulkRoleConfig, error) {
 			conf.FilterDateParsed = parsed
 		}
 	}
+	conf.migrateLegacyFilter()
 	conf.GuildID = guildID
 	return conf, err
 }
@@ -0,0 +1,300 @@
+package bulkrole
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+)
+
+const (
+	FilterHasRoles         = "has_roles"
+	FilterMissingRoles     = "missing_roles"
+	FilterNoRoles          = "no_roles"
+	FilterBots             = "bots"
+	FilterHumans           = "humans"
+	FilterJoinedAfter      = "joined_after"
+	FilterJoinedBefore     = "joined_before"
+	FilterAccountOlderThan = "account_older_than"
+	FilterAccountNewerThan = "account_newer_than"
+)
+
+type FilterType struct {
+	Type string
+	Name string
+}
+
+// FilterTypes is the list of filters shown in the control panel
+var FilterTypes = []*FilterType{
+	{FilterHasRoles, "Has roles"},
+	{FilterMissingRoles, "Missing roles"},
+	{FilterNoRoles, "Has no roles"},
+	{FilterBots, "Bots only"},
+	{FilterHumans, "Humans only"},
+	{FilterJoinedAfter, "Joined after date"},
+	{FilterJoinedBefore, "Joined before date"},
+	{FilterAccountOlderThan, "Account older than"},
+	{FilterAccountNewerThan, "Account newer than"},
+}
+
+// MaxFilters is the max number of filters a single operation can have
+const MaxFilters = 10
+
+const filterDateLayout = "2006-01-02"
+
+type FilterCondition struct {
+	Type string
+
+	// has_roles and missing_roles
+	RoleIDs    []int64 `json:",omitempty"`
+	RequireAll bool    `json:",omitempty"`
+
+	// joined_after and joined_before, YYYY-MM-DD
+	Date string `json:",omitempty"`
+
+	// account_older_than and account_newer_than
+	Days int `json:",omitempty"`
+}
+
+func (f *FilterCondition) validate() error {
+	switch f.Type {
+	case FilterHasRoles, FilterMissingRoles:
+		if len(f.RoleIDs) < 1 {
+			return errors.New("Select atleast one role for the role filters")
+		}
+	case FilterJoinedAfter, FilterJoinedBefore:
+		if _, err := time.Parse(filterDateLayout, f.Date); err != nil {
+			return errors.New("Invalid date format. Use YYYY-MM-DD")
+		}
+	case FilterAccountOlderThan, FilterAccountNewerThan:
+		if f.Days < 1 {
+			return errors.New("Account age has to be atleast 1 day")
+		}
+	case FilterNoRoles, FilterBots, FilterHumans:
+	default:
+		return errors.Errorf("Unknown filter: %s", f.Type)
+	}
+
+	return nil
+}
+
+func (f *FilterCondition) match(member *discordgo.Member, now time.Time) bool {
+	switch f.Type {
+	case FilterHasRoles:
+		if f.RequireAll {
+			return containsAll(member.Roles, f.RoleIDs)
+		}
+		return containsAny(member.Roles, f.RoleIDs)
+	case FilterMissingRoles:
+		if f.RequireAll {
+			return !containsAny(member.Roles, f.RoleIDs)
+		}
+		return !containsAll(member.Roles, f.RoleIDs)
+	case FilterNoRoles:
+		return len(member.Roles) == 0
+	case FilterBots:
+		return member.User.Bot
+	case FilterHumans:
+		return !member.User.Bot
+	case FilterJoinedAfter, FilterJoinedBefore:
+		date, err := time.Parse(filterDateLayout, f.Date)
+		if err != nil {
+			return false
+		}
+		joinedAt, err := member.JoinedAt.Parse()
+		if err != nil {
+			return false
+		}
+		if f.Type == FilterJoinedAfter {
+			return joinedAt.After(date)
+		}
+		return joinedAt.Before(date)
+	case FilterAccountOlderThan, FilterAccountNewerThan:
+		age := now.Sub(bot.SnowflakeToTime(member.User.ID))
+		limit := time.Duration(f.Days) * time.Hour * 24
+		if f.Type == FilterAccountOlderThan {
+			return age > limit
+		}
+		return age < limit
+	}
+
+	return false
+}
+
+func (f *FilterCondition) String() string {
+	switch f.Type {
+	case FilterHasRoles:
+		return filterRoleString("Has roles", f.RoleIDs, f.RequireAll)
+	case FilterMissingRoles:
+		return filterRoleString("Missing roles", f.RoleIDs, f.RequireAll)
+	case FilterNoRoles:
+		return "Has no roles"
+	case FilterBots:
+		return "Bots"
+	case FilterHumans:
+		return "Humans"
+	case FilterJoinedAfter, FilterJoinedBefore:
+		prefix := "Joined after: "
+		if f.Type == FilterJoinedBefore {
+			prefix = "Joined before: "
+		}
+		if date, err := time.Parse(filterDateLayout, f.Date); err == nil {
+			return prefix + date.Format("January 2, 2006")
+		}
+		return prefix + f.Date
+	case FilterAccountOlderThan:
+		return fmt.Sprintf("Account older than %d days", f.Days)
+	case FilterAccountNewerThan:
+		return fmt.Sprintf("Account newer than %d days", f.Days)
+	}
+
+	return f.Type
+}
+
+// matchMember returns true if the member matches the filters of the operation
+func (config *BulkRoleConfig) matchMember(member *discordgo.Member, now time.Time) bool {
+	if len(config.Filters) == 0 {
+		return true
+	}
+
+	for _, f := range config.Filters {
+		matched := f.match(member, now)
+		if config.FilterMatchAny && matched {
+			return true
+		}
+
+		if !config.FilterMatchAny && !matched {
+			return false
+		}
+	}
+
+	return !config.FilterMatchAny
+}
+
+// roleChanges returns the target roles that have to be added or removed from the member
+func (config *BulkRoleConfig) roleChanges(member *discordgo.Member) (add []int64, remove []int64) {
+	for _, role := range config.targetRoles() {
+		hasRole := slices.Contains(member.Roles, role)
+
+		switch config.Operation {
+		case "assign":
+			if !hasRole {
+				add = append(add, role)
+			}
+		case "remove":
+			if hasRole {
+				remove = append(remove, role)
+			}
+		}
+	}
+
+	return
+}
+
+func (config *BulkRoleConfig) validateFilters() error {
+	if len(config.Filters) > MaxFilters {
+		return errors.Errorf("Too many filters, max %d", MaxFilters)
+	}
+
+	for _, f := range config.Filters {
+		if err := f.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (config *BulkRoleConfig) filterString() string {
+	if len(config.Filters) == 0 {
+		return "All members"
+	}
+
+	joiner := " **AND** "
+	if config.FilterMatchAny {
+		joiner = " **OR** "
+	}
+
+	parts := make([]string, 0, len(config.Filters))
+	for _, f := range config.Filters {
+		parts = append(parts, f.String())
+	}
+
+	return strings.Join(parts, joiner)
+}
+
+func filterRoleString(prefix string, roleIDs []int64, requireAll bool) string {
+	const maxFieldLength = 1000 // Leave some buffer below Discord's 1024 char limit
+	if len(roleIDs) == 0 {
+		return prefix
+	}
+
+	// Build the suffix first
+	var suffix string
+	if requireAll {
+		suffix = " (ALL)"
+	} else {
+		suffix = " (ANY)"
+	}
+
+	// Start building the role list
+	roleText := prefix + ": "
+	availableLength := maxFieldLength - len(roleText) - len(suffix)
+
+	var addedRoles []string
+	totalLength := 0
+
+	for i, roleID := range roleIDs {
+		roleStr := fmt.Sprintf("<@&%d>", roleID)
+		separator := ""
+		if i > 0 {
+			separator = ", "
+		}
+
+		testLength := totalLength + len(separator) + len(roleStr)
+
+		// Check if adding this role would exceed the limit
+		if testLength > availableLength {
+			return fmt.Sprintf("%s: %d roles selected%s", prefix, len(roleIDs), suffix)
+		}
+
+		addedRoles = append(addedRoles, roleStr)
+		totalLength = testLength
+	}
+
+	roleText += strings.Join(addedRoles, ", ") + suffix
+	return roleText
+}
+
+func roleMentions(roleIDs []int64) string {
+	mentions := make([]string, len(roleIDs))
+	for i, v := range roleIDs {
+		mentions[i] = fmt.Sprintf("<@&%d>", v)
+	}
+
+	return strings.Join(mentions, ", ")
+}
+
+func containsAll(haystack []int64, needles []int64) bool {
+	for _, v := range needles {
+		if !slices.Contains(haystack, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsAny(haystack []int64, needles []int64) bool {
+	for _, v := range needles {
+		if slices.Contains(haystack, v) {
+			return true
+		}
+	}
+
+	return false
+}
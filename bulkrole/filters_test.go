@@ -0,0 +1,80 @@
+package bulkrole
+
+import (
+	"testing"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+)
+
+func TestMatchMember(t *testing.T) {
+	now := time.Now()
+	member := &discordgo.Member{
+		User:     &discordgo.User{ID: 1},
+		Roles:    []int64{10, 20},
+		JoinedAt: discordgo.Timestamp("2023-06-01T00:00:00Z"),
+	}
+
+	tests := []struct {
+		name     string
+		filters  []*FilterCondition
+		matchAny bool
+		want     bool
+	}{
+		{"no filters", nil, false, true},
+		{"has any", []*FilterCondition{{Type: FilterHasRoles, RoleIDs: []int64{20, 30}}}, false, true},
+		{"has all", []*FilterCondition{{Type: FilterHasRoles, RoleIDs: []int64{20, 30}, RequireAll: true}}, false, false},
+		{"missing any", []*FilterCondition{{Type: FilterMissingRoles, RoleIDs: []int64{20, 30}}}, false, true},
+		{"missing all", []*FilterCondition{{Type: FilterMissingRoles, RoleIDs: []int64{20, 30}, RequireAll: true}}, false, false},
+		{"no roles", []*FilterCondition{{Type: FilterNoRoles}}, false, false},
+		{"joined after", []*FilterCondition{{Type: FilterJoinedAfter, Date: "2023-01-01"}}, false, true},
+		{"joined before", []*FilterCondition{{Type: FilterJoinedBefore, Date: "2023-01-01"}}, false, false},
+		{"and", []*FilterCondition{{Type: FilterHumans}, {Type: FilterBots}}, false, false},
+		{"or", []*FilterCondition{{Type: FilterHumans}, {Type: FilterBots}}, true, true},
+		{"or none matching", []*FilterCondition{{Type: FilterBots}, {Type: FilterNoRoles}}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &BulkRoleConfig{Filters: tt.filters, FilterMatchAny: tt.matchAny}
+			if got := config.matchMember(member, now); got != tt.want {
+				t.Errorf("matchMember() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoleChanges(t *testing.T) {
+	member := &discordgo.Member{User: &discordgo.User{ID: 1}, Roles: []int64{10, 20}}
+
+	config := &BulkRoleConfig{Operation: "assign", TargetRoles: []int64{10, 30}}
+	add, remove := config.roleChanges(member)
+	if len(add) != 1 || add[0] != 30 || len(remove) != 0 {
+		t.Errorf("assign: got add %v remove %v", add, remove)
+	}
+
+	config = &BulkRoleConfig{Operation: "remove", TargetRoles: []int64{10, 30}}
+	add, remove = config.roleChanges(member)
+	if len(add) != 0 || len(remove) != 1 || remove[0] != 10 {
+		t.Errorf("remove: got add %v remove %v", add, remove)
+	}
+}
+
+func TestMigrateLegacyFilter(t *testing.T) {
+	config := &BulkRoleConfig{TargetRole: 5, FilterType: "has_role", FilterRoleIDs: []int64{1}, FilterRequireAll: true}
+	config.migrateLegacyFilter()
+
+	if len(config.Filters) != 1 || config.Filters[0].Type != FilterHasRoles || !config.Filters[0].RequireAll {
+		t.Fatalf("unexpected filters: %+v", config.Filters)
+	}
+
+	if roles := config.targetRoles(); len(roles) != 1 || roles[0] != 5 {
+		t.Errorf("unexpected target roles: %v", roles)
+	}
+
+	config = &BulkRoleConfig{FilterType: "all"}
+	config.migrateLegacyFilter()
+	if len(config.Filters) != 0 {
+		t.Errorf("all should not add filters: %+v", config.Filters)
+	}
+}
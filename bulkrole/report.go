@@ -0,0 +1,161 @@
+package bulkrole
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/mediocregopher/radix/v3"
+)
+
+const (
+	// how long the report of the last operation is kept around
+	reportTTL = time.Hour * 24 * 7
+
+	// members past this are still changed, but not included in the report
+	maxReportEntries = 100000
+)
+
+// Members changed by the running operation
+func RedisKeyBulkRoleReport(guildID int64) string {
+	return "bulkrole:" + discordgo.StrID(guildID) + ":report"
+}
+
+// Members changed by the last finished operation
+func RedisKeyBulkRoleLastReport(guildID int64) string {
+	return "bulkrole:" + discordgo.StrID(guildID) + ":last_report"
+}
+
+func RedisKeyBulkRoleLastReportInfo(guildID int64) string {
+	return "bulkrole:" + discordgo.StrID(guildID) + ":last_report_info"
+}
+
+type ReportEntry struct {
+	UserID       int64   `json:"u,string"`
+	Username     string  `json:"n"`
+	RolesAdded   []int64 `json:"a,omitempty"`
+	RolesRemoved []int64 `json:"r,omitempty"`
+	Failed       bool    `json:"f,omitempty"`
+}
+
+// ReportInfo describes the operation the last report is from
+type ReportInfo struct {
+	Status     string
+	DryRun     bool
+	Operation  string
+	Filters    string
+	Processed  int
+	Results    int
+	FinishedAt time.Time
+}
+
+func (config *BulkRoleConfig) addReportEntry(entry *ReportEntry, resultNumber int) {
+	if resultNumber > maxReportEntries {
+		return
+	}
+
+	serialized, err := json.Marshal(entry)
+	if err != nil {
+		logger.WithError(err).Error("failed serializing bulkrole report entry")
+		return
+	}
+
+	err = common.RedisPool.Do(radix.Cmd(nil, "RPUSH", RedisKeyBulkRoleReport(config.GuildID), string(serialized)))
+	if err != nil {
+		logger.WithError(err).WithField("guild", config.GuildID).Error("failed adding bulkrole report entry")
+	}
+}
+
+// finishReport makes the report of the running operation available for download
+func (config *BulkRoleConfig) finishReport(status string, processed, results int) {
+	guildID := config.GuildID
+
+	info := &ReportInfo{
+		Status:     status,
+		DryRun:     config.DryRun,
+		Operation:  config.Operation,
+		Filters:    config.filterString(),
+		Processed:  processed,
+		Results:    results,
+		FinishedAt: time.Now(),
+	}
+
+	ttl := strconv.Itoa(int(reportTTL.Seconds()))
+
+	// RENAME fails if nothing was changed and the report was never created
+	var exists int
+	common.RedisPool.Do(radix.Cmd(&exists, "EXISTS", RedisKeyBulkRoleReport(guildID)))
+	if exists > 0 {
+		common.RedisPool.Do(radix.Cmd(nil, "RENAME", RedisKeyBulkRoleReport(guildID), RedisKeyBulkRoleLastReport(guildID)))
+		common.RedisPool.Do(radix.Cmd(nil, "EXPIRE", RedisKeyBulkRoleLastReport(guildID), ttl))
+	} else {
+		common.RedisPool.Do(radix.Cmd(nil, "DEL", RedisKeyBulkRoleLastReport(guildID)))
+	}
+
+	err := common.SetRedisJson(RedisKeyBulkRoleLastReportInfo(guildID), info)
+	if err != nil {
+		logger.WithError(err).WithField("guild", guildID).Error("failed saving bulkrole report info")
+		return
+	}
+	common.RedisPool.Do(radix.Cmd(nil, "EXPIRE", RedisKeyBulkRoleLastReportInfo(guildID), ttl))
+}
+
+// GetLastReportInfo returns info about the last finished operation, or nil if there's none
+func GetLastReportInfo(guildID int64) (*ReportInfo, error) {
+	var info *ReportInfo
+	err := common.GetRedisJson(RedisKeyBulkRoleLastReportInfo(guildID), &info)
+	return info, err
+}
+
+// WriteLastReportCSV writes the members changed by the last finished operation as csv
+func WriteLastReportCSV(guildID int64, w io.Writer) error {
+	var raw []string
+	err := common.RedisPool.Do(radix.Cmd(&raw, "LRANGE", RedisKeyBulkRoleLastReport(guildID), "0", "-1"))
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"user_id", "username", "roles_added", "roles_removed", "failed"})
+
+	for _, v := range raw {
+		var entry ReportEntry
+		if err := json.Unmarshal([]byte(v), &entry); err != nil {
+			continue
+		}
+
+		cw.Write([]string{
+			discordgo.StrID(entry.UserID),
+			csvSafe(entry.Username),
+			joinIDs(entry.RolesAdded),
+			joinIDs(entry.RolesRemoved),
+			strconv.FormatBool(entry.Failed),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvSafe prefixes values spreadsheets would treat as a formula with a quote
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
+
+func joinIDs(ids []int64) string {
+	strs := make([]string, len(ids))
+	for i, v := range ids {
+		strs[i] = discordgo.StrID(v)
+	}
+
+	return strings.Join(strs, " ")
+}
@@ -0,0 +1,21 @@
+package bulkrole
+
+import "testing"
+
+func TestCSVSafe(t *testing.T) {
+	testcases := map[string]string{
+		"jonas":        "jonas",
+		"=HYPERLINK()": "'=HYPERLINK()",
+		"+1":           "'+1",
+		"-2":           "'-2",
+		"@user":        "'@user",
+		"a=b":          "a=b",
+		"":             "",
+	}
+
+	for input, expected := range testcases {
+		if result := csvSafe(input); result != expected {
+			t.Errorf("csvSafe(%q) = %q, expected %q", input, result, expected)
+		}
+	}
+}
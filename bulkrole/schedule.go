@@ -0,0 +1,104 @@
+package bulkrole
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/pubsub"
+	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
+	seventsmodels "github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2/models"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+const scheduledEventName = "bulkrole_start"
+
+// MaxScheduleAhead is how far into the future operations can be scheduled
+const MaxScheduleAhead = time.Hour * 24 * 30
+
+// ScheduledOperation is a operation waiting to be started
+type ScheduledOperation struct {
+	Config *BulkRoleConfig
+	RunAt  time.Time
+}
+
+// ScheduleOperation schedules the operation to be started at runAt, replacing any operation already scheduled
+func ScheduleOperation(ctx context.Context, config *BulkRoleConfig, runAt time.Time) error {
+	if len(config.targetRoles()) == 0 {
+		return errors.New("Target role is required")
+	}
+
+	if err := config.validateFilters(); err != nil {
+		return err
+	}
+
+	if runAt.Before(time.Now()) {
+		return errors.New("Scheduled time is in the past")
+	}
+
+	if runAt.After(time.Now().Add(MaxScheduleAhead)) {
+		return errors.New("Operations can be scheduled at most 30 days ahead")
+	}
+
+	err := CancelScheduledOperation(ctx, config.GuildID)
+	if err != nil {
+		return err
+	}
+
+	return scheduledevents2.ScheduleEvent(scheduledEventName, config.GuildID, runAt, config)
+}
+
+// CancelScheduledOperation removes the scheduled operation of the guild, if any
+func CancelScheduledOperation(ctx context.Context, guildID int64) error {
+	_, err := seventsmodels.ScheduledEvents(qm.Where("event_name = ? AND guild_id = ? AND processed = false", scheduledEventName, guildID)).DeleteAll(ctx, common.PQ)
+	return err
+}
+
+// GetScheduledOperation returns the scheduled operation of the guild, or nil if there's none
+func GetScheduledOperation(ctx context.Context, guildID int64) (*ScheduledOperation, error) {
+	evt, err := seventsmodels.ScheduledEvents(qm.Where("event_name = ? AND guild_id = ? AND processed = false", scheduledEventName, guildID)).One(ctx, common.PQ)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var config BulkRoleConfig
+	err = json.Unmarshal(evt.Data, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	config.GuildID = guildID
+	return &ScheduledOperation{
+		Config: &config,
+		RunAt:  evt.TriggersAt,
+	}, nil
+}
+
+func handleScheduledOperation(evt *seventsmodels.ScheduledEvent, data interface{}) (retry bool, err error) {
+	config := data.(*BulkRoleConfig)
+	config.GuildID = evt.GuildID
+
+	err = common.SetRedisJson(KeyGeneral(evt.GuildID), config)
+	if err != nil {
+		return true, err
+	}
+
+	pubsub.EvictCacheSet(configCache, evt.GuildID)
+
+	err = config.startBulkRoleOperation()
+	if err != nil {
+		// most likely another operation was running or the bot lost permissions, not worth retrying
+		config.sendNotificationAlert("Failed", 0, 0, "Scheduled operation could not be started: "+err.Error())
+		logger.WithError(err).WithField("guild", evt.GuildID).Warn("failed starting scheduled bulk role operation")
+		return false, nil
+	}
+
+	return false, nil
+}
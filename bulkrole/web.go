@@ -7,6 +7,7 @@ import (
 	"html/template"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"emperror.dev/errors"
@@ -37,6 +38,9 @@ var _ web.SimpleConfigSaver = (*Form)(nil)
 var (
 	panelLogKeyStartedOperation   = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "bulkrole_started_operation", FormatString: "Started bulk role operation"})
 	panelLogKeyCancelledOperation = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "bulkrole_cancelled_operation", FormatString: "Cancelled bulk role operation"})
+	panelLogKeyStartedDryRun      = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "bulkrole_started_dry_run", FormatString: "Started bulk role dry run"})
+	panelLogKeyScheduled          = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "bulkrole_scheduled_operation", FormatString: "Scheduled bulk role operation for %s"})
+	panelLogKeyCancelledScheduled = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "bulkrole_cancelled_scheduled_operation", FormatString: "Cancelled scheduled bulk role operation"})
 )
 
 // getExcludedRoleIDs returns the IDs of roles that should be excluded from bulk role operations
@@ -105,7 +109,12 @@ func (p *Plugin) InitWeb() {
 	muxer.Handle(pat.Get(""), getHandler)
 	muxer.Handle(pat.Get("/"), getHandler)
 
+	muxer.Handle(pat.Get("/report.csv"), http.HandlerFunc(handleGetReportCSV))
+
 	muxer.Handle(pat.Post("/cancel"), web.ControllerPostHandler(handlePostCancelOperation, getHandler, nil))
+	muxer.Handle(pat.Post("/dryrun"), web.ControllerPostHandler(handlePostDryRun, getHandler, nil))
+	muxer.Handle(pat.Post("/schedule"), web.ControllerPostHandler(handlePostSchedule, getHandler, nil))
+	muxer.Handle(pat.Post("/schedule/cancel"), web.ControllerPostHandler(handlePostCancelScheduled, getHandler, nil))
 
 	muxer.Handle(pat.Post(""), web.ControllerPostHandler(handlePostSaveAndStart, getHandler, nil))
 	muxer.Handle(pat.Post("/"), web.ControllerPostHandler(handlePostSaveAndStart, getHandler, nil))
@@ -119,12 +128,36 @@ func handleGetBulkRoleMainPage(w http.ResponseWriter, r *http.Request) interface
 	if err != nil {
 		general = &BulkRoleConfig{
 			Operation:           "assign",
-			FilterType:          "all",
 			NotificationChannel: 0,
 			StartedBy:           0,
 		}
 	}
 	tmpl["BulkRole"] = general
+	tmpl["TargetRoles"] = general.targetRoles()
+	tmpl["MaxTargetRoles"] = MaxTargetRoles
+	tmpl["FilterTypes"] = FilterTypes
+	tmpl["FilterSlots"] = filterSlots(general.Filters)
+
+	filterTypeNames := make(map[string]string)
+	for _, v := range FilterTypes {
+		filterTypeNames[v.Type] = v.Name
+	}
+	tmpl["FilterTypeNames"] = filterTypeNames
+
+	scheduled, err := GetScheduledOperation(ctx, activeGuild.ID)
+	if err != nil {
+		logger.WithError(err).WithField("guild", activeGuild.ID).Error("Failed to get scheduled bulk role operation")
+	} else if scheduled != nil {
+		tmpl["ScheduledOperation"] = scheduled
+		tmpl["ScheduledTargetRoles"] = scheduled.Config.targetRoles()
+	}
+
+	lastReport, err := GetLastReportInfo(activeGuild.ID)
+	if err != nil {
+		logger.WithError(err).WithField("guild", activeGuild.ID).Error("Failed to get last bulk role report")
+	} else if lastReport != nil {
+		tmpl["LastReport"] = lastReport
+	}
 
 	excludedRoleIDs := getExcludedRoleIDs(activeGuild.ID)
 	tmpl["ExcludedRoleIDs"] = excludedRoleIDs
@@ -182,12 +215,11 @@ func handleGetBulkRoleMainPage(w http.ResponseWriter, r *http.Request) interface
 		tmpl["TotalMembers"] = int(activeGuild.MemberCount)
 
 		tmpl["CurrentOperation"] = map[string]interface{}{
-			"TargetRole":          general.TargetRole,
+			"TargetRoles":         general.targetRoles(),
 			"Operation":           general.Operation,
-			"FilterType":          general.FilterType,
-			"FilterRoleIDs":       general.FilterRoleIDs,
-			"FilterRequireAll":    general.FilterRequireAll,
-			"FilterDate":          general.FilterDate,
+			"Filters":             general.Filters,
+			"FilterMatchAny":      general.FilterMatchAny,
+			"DryRun":              general.DryRun,
 			"NotificationChannel": general.NotificationChannel,
 			"StartedBy":           general.StartedBy,
 			"StartedByUsername":   general.StartedByUsername,
@@ -197,69 +229,107 @@ func handleGetBulkRoleMainPage(w http.ResponseWriter, r *http.Request) interface
 	return tmpl
 }
 
-func handlePostSaveAndStart(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
-	ctx := r.Context()
-	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
-
-	if premium.ContextPremiumTier(ctx) != premium.PremiumTierPremium {
-		return tmpl.AddAlerts(web.ErrorAlert("Bulk Role Manager is premium only")), nil
+// filterSlots pads the filters to MaxFilters so the control panel always has room for new ones
+func filterSlots(filters []*FilterCondition) []*FilterCondition {
+	slots := make([]*FilterCondition, 0, MaxFilters)
+	slots = append(slots, filters...)
+	for len(slots) < MaxFilters {
+		slots = append(slots, &FilterCondition{})
 	}
 
+	return slots
+}
+
+// parseConfigForm reads the operation from the submitted form, the returned error is shown to the user
+func parseConfigForm(r *http.Request) (*BulkRoleConfig, error) {
 	err := r.ParseForm()
 	if err != nil {
-		return tmpl.AddAlerts(web.ErrorAlert("Failed to parse form data")), nil
+		return nil, errors.New("Failed to parse form data")
 	}
 
 	user := web.ContextUser(r.Context())
 
-	// Debug: Log the raw form values for FilterRoleIDs
-	logger.WithField("guild", activeGuild.ID).WithField("filterRoleIDs_raw", r.Form["FilterRoleIDs"]).Info("Processing FilterRoleIDs from form")
-
 	config := &BulkRoleConfig{
-		TargetRole:          parseFormInt64(r.FormValue("TargetRole")),
+		TargetRoles:         parseFormInt64Slice(r.Form["TargetRoles"]),
 		Operation:           r.FormValue("Operation"),
-		FilterType:          r.FormValue("FilterType"),
-		FilterRoleIDs:       parseFormInt64Slice(r.Form["FilterRoleIDs"]),
-		FilterRequireAll:    r.FormValue("FilterRequireAll") == "true",
-		FilterDate:          r.FormValue("FilterDate"),
+		FilterMatchAny:      r.FormValue("FilterMatchAny") == "any",
 		NotificationChannel: parseFormInt64(r.FormValue("NotificationChannel")),
 		StartedBy:           user.ID,
 		StartedByUsername:   user.String(),
 	}
 
-	// Debug: Log the parsed FilterRoleIDs
-	logger.WithField("guild", activeGuild.ID).WithField("filterRoleIDs_parsed", config.FilterRoleIDs).Info("Parsed FilterRoleIDs")
-
-	if config.FilterDate != "" {
-		parsed, err := time.Parse("2006-01-02", config.FilterDate)
-		if err != nil {
-			return tmpl.AddAlerts(web.ErrorAlert("Invalid date format. Use YYYY-MM-DD")), nil
+	for i := 0; i < MaxFilters; i++ {
+		suffix := strconv.Itoa(i)
+		filterType := r.FormValue("FilterType" + suffix)
+		if filterType == "" {
+			continue
 		}
-		config.FilterDateParsed = parsed
+
+		days, _ := strconv.Atoi(r.FormValue("FilterDays" + suffix))
+		config.Filters = append(config.Filters, &FilterCondition{
+			Type:       filterType,
+			RoleIDs:    parseFormInt64Slice(r.Form["FilterRoleIDs"+suffix]),
+			RequireAll: isCheckboxChecked(r.FormValue("FilterRequireAll" + suffix)),
+			Date:       r.FormValue("FilterDate" + suffix),
+			Days:       days,
+		})
+	}
+
+	if len(config.TargetRoles) == 0 {
+		return nil, errors.New("Please select a target role")
 	}
 
-	if config.TargetRole == 0 {
-		return tmpl.AddAlerts(web.ErrorAlert("Please select a target role")), nil
+	if len(config.TargetRoles) > MaxTargetRoles {
+		return nil, errors.Errorf("You can select at most %d target roles", MaxTargetRoles)
 	}
 
-	if config.Operation == "" {
-		return tmpl.AddAlerts(web.ErrorAlert("Please select an operation")), nil
+	if config.Operation != "assign" && config.Operation != "remove" {
+		return nil, errors.New("Please select an operation")
 	}
 
-	if config.FilterType == "" {
-		return tmpl.AddAlerts(web.ErrorAlert("Please select a filter type")), nil
+	if err := config.validateFilters(); err != nil {
+		return nil, err
 	}
 
-	err = common.SetRedisJson(KeyGeneral(activeGuild.ID), config)
+	return config, nil
+}
+
+func isCheckboxChecked(value string) bool {
+	return value == "on" || value == "true"
+}
+
+func saveAndStartOperation(guildID int64, config *BulkRoleConfig) error {
+	err := common.SetRedisJson(KeyGeneral(guildID), config)
+	if err != nil {
+		return errors.New("Failed to save configuration")
+	}
+
+	pubsub.EvictCacheSet(configCache, guildID)
+
+	err = internalapi.PostWithGuild(guildID, strconv.FormatInt(guildID, 10)+"/bulkrole/start", nil, nil)
 	if err != nil {
-		return tmpl.AddAlerts(web.ErrorAlert("Failed to save configuration")), nil
+		return errors.New("Failed to start operation: " + err.Error())
+	}
+
+	return nil
+}
+
+func handlePostSaveAndStart(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	if premium.ContextPremiumTier(ctx) != premium.PremiumTierPremium {
+		return tmpl.AddAlerts(web.ErrorAlert("Bulk Role Manager is premium only")), nil
 	}
 
-	pubsub.EvictCacheSet(configCache, activeGuild.ID)
+	config, err := parseConfigForm(r)
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert(err.Error())), nil
+	}
 
-	err = internalapi.PostWithGuild(activeGuild.ID, strconv.FormatInt(activeGuild.ID, 10)+"/bulkrole/start", nil, nil)
+	err = saveAndStartOperation(activeGuild.ID, config)
 	if err != nil {
-		return tmpl.AddAlerts(web.ErrorAlert("Failed to start operation: " + err.Error())), nil
+		return tmpl.AddAlerts(web.ErrorAlert(err.Error())), nil
 	}
 
 	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyStartedOperation))
@@ -267,6 +337,86 @@ func handlePostSaveAndStart(w http.ResponseWriter, r *http.Request) (web.Templat
 	return nil, nil
 }
 
+func handlePostDryRun(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	if premium.ContextPremiumTier(ctx) != premium.PremiumTierPremium {
+		return tmpl.AddAlerts(web.ErrorAlert("Bulk Role Manager is premium only")), nil
+	}
+
+	config, err := parseConfigForm(r)
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert(err.Error())), nil
+	}
+
+	config.DryRun = true
+	err = saveAndStartOperation(activeGuild.ID, config)
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert(err.Error())), nil
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyStartedDryRun))
+
+	return nil, nil
+}
+
+func handlePostSchedule(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	if premium.ContextPremiumTier(ctx) != premium.PremiumTierPremium {
+		return tmpl.AddAlerts(web.ErrorAlert("Bulk Role Manager is premium only")), nil
+	}
+
+	config, err := parseConfigForm(r)
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert(err.Error())), nil
+	}
+
+	// datetime-local inputs don't include a timezone, the control panel labels it as UTC
+	runAt, err := time.Parse("2006-01-02T15:04", r.FormValue("ScheduleAt"))
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert("Invalid schedule time")), nil
+	}
+
+	config.GuildID = activeGuild.ID
+	err = ScheduleOperation(ctx, config, runAt)
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert(err.Error())), nil
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyScheduled, &cplogs.Param{Type: cplogs.ParamTypeString, Value: runAt.Format(time.RFC822)}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Bulk role operation scheduled")), nil
+}
+
+func handlePostCancelScheduled(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	err := CancelScheduledOperation(ctx, activeGuild.ID)
+	if err != nil {
+		return tmpl, err
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyCancelledScheduled))
+
+	return tmpl.AddAlerts(web.SucessAlert("Scheduled bulk role operation cancelled")), nil
+}
+
+func handleGetReportCSV(w http.ResponseWriter, r *http.Request) {
+	activeGuild, _ := web.GetBaseCPContextData(r.Context())
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="bulkrole_%d.csv"`, activeGuild.ID))
+
+	err := WriteLastReportCSV(activeGuild.ID, w)
+	if err != nil {
+		logger.WithError(err).WithField("guild", activeGuild.ID).Error("Failed writing bulk role report")
+	}
+}
+
 func parseFormInt64(value string) int64 {
 	if value == "" {
 		return 0
@@ -326,10 +476,17 @@ func (p *Plugin) LoadServerHomeWidget(w http.ResponseWriter, r *http.Request) (w
 	enabledDisabled := ""
 	targetRole := "none"
 
-	if role := ag.GetRole(general.TargetRole); role != nil {
+	var roleNames []string
+	for _, roleID := range general.targetRoles() {
+		if role := ag.GetRole(roleID); role != nil {
+			roleNames = append(roleNames, html.EscapeString(role.Name))
+		}
+	}
+
+	if len(roleNames) > 0 {
 		templateData["WidgetEnabled"] = true
 		enabledDisabled = web.EnabledDisabledSpanStatus(true)
-		targetRole = html.EscapeString(role.Name)
+		targetRole = strings.Join(roleNames, ", ")
 	} else {
 		templateData["WidgetDisabled"] = true
 		enabledDisabled = web.EnabledDisabledSpanStatus(false)
@@ -337,7 +494,7 @@ func (p *Plugin) LoadServerHomeWidget(w http.ResponseWriter, r *http.Request) (w
 
 	format := `<ul>
 	<li>Status: %s</li>
-	<li>Target role: <code>%s</code></li>
+	<li>Target roles: <code>%s</code></li>
 	<li>Operation: <code>%s</code></li>
 	<li>Notifications: <code>%s</code></li>
 </ul>`
@@ -17,7 +17,7 @@ func (p *Plugin) AddCommands() {
 		Name:        "Create",
 		CmdCategory: commands.CategoryFun,
 		Aliases:     []string{"c"},
-		Description: "Creates a Cards Against Humanity game in this channel, add packs after commands, or * for all packs. (-v for vote mode without a card czar).",
+		Description: "Creates a Cards Against Humanity game in this channel, add packs (including server packs) after commands, or * for all packs. (-v for vote mode without a card czar).",
 		Arguments: []*dcmd.ArgDef{
 			{Name: "packs", Type: dcmd.String, Default: "main", Help: "Packs separated by space, or * for all of them."},
 		},
@@ -29,9 +29,12 @@ func (p *Plugin) AddCommands() {
 			pStr := data.Args[0].Str()
 			packs := strings.Fields(pStr)
 
-			_, err := p.Manager.CreateGame(data.GuildData.GS.ID, data.GuildData.CS.ID, data.Author.ID, data.Author.Username, voteMode, packs...)
+			game, err := p.Manager.CreateGame(data.GuildData.GS.ID, data.GuildData.CS.ID, data.Author.ID, data.Author.Username, voteMode, packs...)
 			if err == nil {
 				logrus.Info("[cah] Created a new game in ", data.GuildData.CS.ID, ":", data.GuildData.GS.ID)
+				if pStr != "*" && game.MixesFamilyFriendly() {
+					return "Note: this game mixes family friendly packs with packs that aren't family friendly.", nil
+				}
 				return nil, nil
 			}
 
@@ -101,6 +104,18 @@ func (p *Plugin) AddCommands() {
 				resp += "`" + v.Name + "` - " + v.Description + "\n"
 			}
 
+			customPacks, err := GetCustomPacks(data.Context(), data.GuildData.GS.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(customPacks) > 0 {
+				resp += "\nServer packs: \n\n"
+				for _, v := range customPacks {
+					resp += "`" + v.Name + "` - " + v.Description + "\n"
+				}
+			}
+
 			return resp, nil
 		},
 	}
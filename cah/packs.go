@@ -0,0 +1,151 @@
+package cah
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/lib/cardsagainstdiscord"
+	"github.com/lib/pq"
+)
+
+const (
+	MaxCustomPacks        = 2
+	MaxCustomPacksPremium = 10
+
+	MaxCardsPerPack   = 500
+	MaxPromptLength   = 250
+	MaxResponseLength = 200
+)
+
+// CustomPack is a guild defined card pack, selectable by name next to the built-in packs
+type CustomPack struct {
+	ID             int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	GuildID        int64
+	Name           string
+	Description    string
+	FamilyFriendly bool
+	Prompts        []string
+	Responses      []string
+}
+
+func (cp *CustomPack) PromptsText() string {
+	return strings.Join(cp.Prompts, "\n")
+}
+
+func (cp *CustomPack) ResponsesText() string {
+	return strings.Join(cp.Responses, "\n")
+}
+
+// underscores are used as blanks in custom prompts
+var promptBlankRe = regexp.MustCompile(`_+`)
+
+// ToCardPack converts the custom pack into a pack usable in games
+func (cp *CustomPack) ToCardPack() *cardsagainstdiscord.CardPack {
+	pack := &cardsagainstdiscord.CardPack{
+		Name:           cp.Name,
+		Description:    cp.Description,
+		FamilyFriendly: cp.FamilyFriendly,
+		Prompts:        make([]*cardsagainstdiscord.PromptCard, 0, len(cp.Prompts)),
+		Responses:      make([]cardsagainstdiscord.ResponseCard, 0, len(cp.Responses)),
+	}
+
+	for _, v := range cp.Prompts {
+		prompt := strings.ReplaceAll(v, "%", "%%")
+		prompt = promptBlankRe.ReplaceAllString(prompt, "%s")
+		pack.Prompts = append(pack.Prompts, &cardsagainstdiscord.PromptCard{Prompt: prompt})
+	}
+
+	for _, v := range cp.Responses {
+		pack.Responses = append(pack.Responses, cardsagainstdiscord.ResponseCard(v))
+	}
+
+	pack.CountPicks()
+	return pack
+}
+
+const customPackColumns = "id, created_at, updated_at, guild_id, name, description, family_friendly, prompts, responses"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCustomPack(row rowScanner) (*CustomPack, error) {
+	cp := &CustomPack{}
+	err := row.Scan(&cp.ID, &cp.CreatedAt, &cp.UpdatedAt, &cp.GuildID, &cp.Name, &cp.Description, &cp.FamilyFriendly, pq.Array(&cp.Prompts), pq.Array(&cp.Responses))
+	return cp, err
+}
+
+func GetCustomPacks(ctx context.Context, guildID int64) ([]*CustomPack, error) {
+	rows, err := common.PQ.QueryContext(ctx, "SELECT "+customPackColumns+" FROM cah_custom_packs WHERE guild_id = $1 ORDER BY name ASC", guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*CustomPack
+	for rows.Next() {
+		cp, err := scanCustomPack(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cp)
+	}
+
+	return result, rows.Err()
+}
+
+func GetCustomPack(ctx context.Context, guildID, id int64) (*CustomPack, error) {
+	row := common.PQ.QueryRowContext(ctx, "SELECT "+customPackColumns+" FROM cah_custom_packs WHERE guild_id = $1 AND id = $2", guildID, id)
+	return scanCustomPack(row)
+}
+
+func CountCustomPacks(ctx context.Context, guildID int64) (int, error) {
+	var count int
+	err := common.PQ.QueryRowContext(ctx, "SELECT count(*) FROM cah_custom_packs WHERE guild_id = $1", guildID).Scan(&count)
+	return count, err
+}
+
+func InsertCustomPack(ctx context.Context, cp *CustomPack) error {
+	const q = `INSERT INTO cah_custom_packs (created_at, updated_at, guild_id, name, description, family_friendly, prompts, responses)
+VALUES ($1, $1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	cp.CreatedAt = time.Now()
+	cp.UpdatedAt = cp.CreatedAt
+	return common.PQ.QueryRowContext(ctx, q, cp.CreatedAt, cp.GuildID, cp.Name, cp.Description, cp.FamilyFriendly, pq.Array(cp.Prompts), pq.Array(cp.Responses)).Scan(&cp.ID)
+}
+
+func UpdateCustomPack(ctx context.Context, cp *CustomPack) error {
+	const q = `UPDATE cah_custom_packs SET updated_at = $3, name = $4, description = $5, family_friendly = $6, prompts = $7, responses = $8
+WHERE guild_id = $1 AND id = $2`
+
+	cp.UpdatedAt = time.Now()
+	_, err := common.PQ.ExecContext(ctx, q, cp.GuildID, cp.ID, cp.UpdatedAt, cp.Name, cp.Description, cp.FamilyFriendly, pq.Array(cp.Prompts), pq.Array(cp.Responses))
+	return err
+}
+
+func DeleteCustomPack(ctx context.Context, guildID, id int64) error {
+	_, err := common.PQ.ExecContext(ctx, "DELETE FROM cah_custom_packs WHERE guild_id = $1 AND id = $2", guildID, id)
+	return err
+}
+
+var _ cardsagainstdiscord.PackProvider = (*Plugin)(nil)
+
+// GuildPacks implements cardsagainstdiscord.PackProvider
+func (p *Plugin) GuildPacks(guildID int64) ([]*cardsagainstdiscord.CardPack, error) {
+	customPacks, err := GetCustomPacks(context.Background(), guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*cardsagainstdiscord.CardPack, 0, len(customPacks))
+	for _, v := range customPacks {
+		result = append(result, v.ToCardPack())
+	}
+
+	return result, nil
+}
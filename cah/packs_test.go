@@ -0,0 +1,37 @@
+package cah
+
+import (
+	"testing"
+)
+
+func TestCustomPackToCardPack(t *testing.T) {
+	testcases := []struct {
+		input       string
+		placeholder string
+		numPick     int
+	}{
+		{input: "Nobody expected _ at the meetup.", placeholder: "Nobody expected \\_\\_\\_\\_\\_ at the meetup.", numPick: 1},
+		{input: "___ and ___, name a better duo.", placeholder: "\\_\\_\\_\\_\\_ and \\_\\_\\_\\_\\_, name a better duo.", numPick: 2},
+		{input: "What gives me 100% energy?", placeholder: "What gives me 100% energy? \\_\\_\\_\\_\\_", numPick: 1},
+		{input: "Type %s to _.", placeholder: "Type %s to \\_\\_\\_\\_\\_.", numPick: 1},
+	}
+
+	for _, v := range testcases {
+		t.Run("Case "+v.input, func(t *testing.T) {
+			cp := &CustomPack{Name: "test", Prompts: []string{v.input}}
+			pack := cp.ToCardPack()
+			if len(pack.Prompts) != 1 {
+				t.Fatalf("expected 1 prompt, got %d", len(pack.Prompts))
+			}
+
+			prompt := pack.Prompts[0]
+			if prompt.NumPick != v.numPick {
+				t.Errorf("expected %d picks, got %d", v.numPick, prompt.NumPick)
+			}
+
+			if placeholder := prompt.PlaceHolder(); placeholder != v.placeholder {
+				t.Errorf("expected placeholder %q, got %q", v.placeholder, placeholder)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/lib/cardsagainstdiscord"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dshardorchestrator"
+	"github.com/botlabs-gg/yagpdb/v2/web"
 )
 
 const ShardMigrationEvtGame = 110
@@ -23,8 +24,10 @@ func init() {
 func RegisterPlugin() {
 	p := &Plugin{}
 	p.Manager = cardsagainstdiscord.NewGameManager(p)
+	p.Manager.PackProvider = p
 	common.RegisterPlugin(p)
 
+	common.InitSchemas("cah", DBSchemas...)
 }
 
 type Plugin struct {
@@ -39,6 +42,8 @@ func (p *Plugin) PluginInfo() *common.PluginInfo {
 	}
 }
 
+var logger = common.GetPluginLogger(&Plugin{})
+
 func (p *Plugin) SessionForGuild(guildID int64) *discordgo.Session {
 	return common.BotSession
 }
@@ -48,6 +53,7 @@ var (
 	_ bot.ShardMigrationReceiver = (*Plugin)(nil)
 	_ bot.ShardMigrationSender   = (*Plugin)(nil)
 	_ commands.CommandProvider   = (*Plugin)(nil)
+	_ web.Plugin                 = (*Plugin)(nil)
 )
 
 func (p *Plugin) BotInit() {
@@ -0,0 +1,17 @@
+package cah
+
+var DBSchemas = []string{`
+CREATE TABLE IF NOT EXISTS cah_custom_packs (
+	id bigserial NOT NULL PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL,
+	guild_id bigint NOT NULL,
+	name text NOT NULL,
+	description text NOT NULL,
+	family_friendly boolean NOT NULL DEFAULT false,
+	prompts text[] NOT NULL,
+	responses text[] NOT NULL
+);
+`, `
+CREATE UNIQUE INDEX IF NOT EXISTS cah_custom_packs_guild_name_idx ON cah_custom_packs(guild_id, name);
+`}
@@ -0,0 +1,236 @@
+package cah
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/cplogs"
+	"github.com/botlabs-gg/yagpdb/v2/lib/cardsagainstdiscord"
+	"github.com/botlabs-gg/yagpdb/v2/premium"
+	"github.com/botlabs-gg/yagpdb/v2/web"
+	"goji.io"
+	"goji.io/pat"
+)
+
+//go:embed assets/cah.html
+var PageHTML string
+
+type FormCustomPack struct {
+	Name           string `valid:",1,32"`
+	Description    string `valid:",200"`
+	FamilyFriendly bool
+	Prompts        string `valid:",150000"`
+	Responses      string `valid:",150000"`
+}
+
+var packNameRe = regexp.MustCompile(`^[a-z0-9\-_]+$`)
+
+func (f *FormCustomPack) Validate(tmpl web.TemplateData, guildID int64) bool {
+	f.Name = strings.ToLower(strings.TrimSpace(f.Name))
+	if !packNameRe.MatchString(f.Name) {
+		tmpl.AddAlerts(web.ErrorAlert("Pack names can only contain lowercase letters, numbers, dashes and underscores"))
+		return false
+	}
+
+	if _, ok := cardsagainstdiscord.Packs[f.Name]; ok {
+		tmpl.AddAlerts(web.ErrorAlert("There's already a built-in pack named ", f.Name))
+		return false
+	}
+
+	prompts := splitCards(f.Prompts)
+	responses := splitCards(f.Responses)
+	if len(prompts)+len(responses) < 1 {
+		tmpl.AddAlerts(web.ErrorAlert("A pack needs at least one card"))
+		return false
+	}
+
+	if len(prompts) > MaxCardsPerPack || len(responses) > MaxCardsPerPack {
+		tmpl.AddAlerts(web.ErrorAlert(fmt.Sprintf("A pack can have max %d prompts and %d responses", MaxCardsPerPack, MaxCardsPerPack)))
+		return false
+	}
+
+	for _, v := range prompts {
+		if utf8.RuneCountInString(v) > MaxPromptLength {
+			tmpl.AddAlerts(web.ErrorAlert(fmt.Sprintf("Prompts can be max %d characters long: %q", MaxPromptLength, v)))
+			return false
+		}
+	}
+
+	for _, v := range responses {
+		if utf8.RuneCountInString(v) > MaxResponseLength {
+			tmpl.AddAlerts(web.ErrorAlert(fmt.Sprintf("Responses can be max %d characters long: %q", MaxResponseLength, v)))
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitCards returns the non empty lines of the input, one card per line
+func splitCards(s string) []string {
+	lines := strings.Split(s, "\n")
+	result := make([]string, 0, len(lines))
+	for _, v := range lines {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func (f *FormCustomPack) apply(cp *CustomPack) {
+	cp.Name = f.Name
+	cp.Description = f.Description
+	cp.FamilyFriendly = f.FamilyFriendly
+	cp.Prompts = splitCards(f.Prompts)
+	cp.Responses = splitCards(f.Responses)
+}
+
+var (
+	panelLogKeyNewPack     = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "cah_new_pack", FormatString: "Created CAH pack %s"})
+	panelLogKeyUpdatedPack = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "cah_updated_pack", FormatString: "Updated CAH pack %s"})
+	panelLogKeyRemovedPack = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "cah_removed_pack", FormatString: "Removed CAH pack %d"})
+)
+
+func MaxCustomPacksForContext(ctx context.Context) int {
+	if premium.ContextPremium(ctx) {
+		return MaxCustomPacksPremium
+	}
+	return MaxCustomPacks
+}
+
+func (p *Plugin) InitWeb() {
+	web.AddHTMLTemplate("cah/assets/cah.html", PageHTML)
+	web.AddSidebarItem(web.SidebarCategoryFun, &web.SidebarItem{
+		Name: "CAH Packs",
+		URL:  "cah",
+		Icon: "fas fa-clone",
+	})
+
+	muxer := goji.SubMux()
+
+	web.CPMux.Handle(pat.New("/cah"), muxer)
+	web.CPMux.Handle(pat.New("/cah/*"), muxer)
+
+	muxer.Use(web.RequireBotMemberMW)
+	muxer.Use(premium.PremiumGuildMW)
+
+	getHandler := web.RenderHandler(handleGetPacks, "cp_cah")
+
+	muxer.Handle(pat.Get(""), getHandler)
+	muxer.Handle(pat.Get("/"), getHandler)
+
+	muxer.Handle(pat.Post("/new"), web.ControllerPostHandler(handleNewPack, getHandler, FormCustomPack{}))
+	muxer.Handle(pat.Post("/:id/update"), web.ControllerPostHandler(handleUpdatePack, getHandler, FormCustomPack{}))
+	muxer.Handle(pat.Post("/:id/delete"), web.ControllerPostHandler(handleDeletePack, getHandler, nil))
+}
+
+func handleGetPacks(w http.ResponseWriter, r *http.Request) interface{} {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	packs, err := GetCustomPacks(ctx, activeGuild.ID)
+	web.CheckErr(tmpl, err, "Failed retrieving custom packs", logger.Error)
+
+	tmpl["CustomPacks"] = packs
+	tmpl["MaxPacks"] = MaxCustomPacksForContext(ctx)
+	tmpl["FreeLimit"] = MaxCustomPacks
+	tmpl["PremiumLimit"] = MaxCustomPacksPremium
+	tmpl["MaxCardsPerPack"] = MaxCardsPerPack
+
+	return tmpl
+}
+
+func handleNewPack(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	form := ctx.Value(common.ContextKeyParsedForm).(*FormCustomPack)
+
+	count, err := CountCustomPacks(ctx, activeGuild.ID)
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed counting custom packs")
+	}
+
+	if count >= MaxCustomPacksForContext(ctx) {
+		return tmpl.AddAlerts(web.ErrorAlert(fmt.Sprintf("Max %d custom packs allowed", MaxCustomPacksForContext(ctx)))), nil
+	}
+
+	cp := &CustomPack{GuildID: activeGuild.ID}
+	form.apply(cp)
+
+	err = InsertCustomPack(ctx, cp)
+	if err != nil {
+		if common.ErrPQIsUniqueViolation(err) {
+			return tmpl.AddAlerts(web.ErrorAlert("There's already a pack with that name")), nil
+		}
+		return tmpl, errors.WithMessage(err, "failed creating custom pack")
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyNewPack, &cplogs.Param{Type: cplogs.ParamTypeString, Value: cp.Name}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Pack created!")), nil
+}
+
+func handleUpdatePack(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	form := ctx.Value(common.ContextKeyParsedForm).(*FormCustomPack)
+
+	id, err := strconv.ParseInt(pat.Param(r, "id"), 10, 64)
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert("Invalid ID")), nil
+	}
+
+	cp, err := GetCustomPack(ctx, activeGuild.ID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return tmpl.AddAlerts(web.ErrorAlert("Pack not found")), nil
+		}
+		return tmpl, errors.WithMessage(err, "failed retrieving custom pack")
+	}
+
+	form.apply(cp)
+	err = UpdateCustomPack(ctx, cp)
+	if err != nil {
+		if common.ErrPQIsUniqueViolation(err) {
+			return tmpl.AddAlerts(web.ErrorAlert("There's already a pack with that name")), nil
+		}
+		return tmpl, errors.WithMessage(err, "failed updating custom pack")
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyUpdatedPack, &cplogs.Param{Type: cplogs.ParamTypeString, Value: cp.Name}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Pack updated!")), nil
+}
+
+func handleDeletePack(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	id, err := strconv.ParseInt(pat.Param(r, "id"), 10, 64)
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert("Invalid ID")), nil
+	}
+
+	err = DeleteCustomPack(ctx, activeGuild.ID, id)
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed deleting custom pack")
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyRemovedPack, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: id}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Pack deleted!")), nil
+}
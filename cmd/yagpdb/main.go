@@ -8,6 +8,8 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/common/prom"
 	"github.com/botlabs-gg/yagpdb/v2/common/run"
 	"github.com/botlabs-gg/yagpdb/v2/lib/confusables"
+	"github.com/botlabs-gg/yagpdb/v2/outboundwebhooks"
+	"github.com/botlabs-gg/yagpdb/v2/tempvoice"
 	"github.com/botlabs-gg/yagpdb/v2/trivia"
 	"github.com/botlabs-gg/yagpdb/v2/twitch"
 	"github.com/botlabs-gg/yagpdb/v2/voiceroles"
@@ -33,6 +35,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/moderation"
 	"github.com/botlabs-gg/yagpdb/v2/notifications"
 	"github.com/botlabs-gg/yagpdb/v2/personalizer"
+	"github.com/botlabs-gg/yagpdb/v2/polls"
 	"github.com/botlabs-gg/yagpdb/v2/premium"
 	"github.com/botlabs-gg/yagpdb/v2/premium/discordpremiumsource"
 	"github.com/botlabs-gg/yagpdb/v2/premium/patreonpremiumsource"
@@ -106,6 +109,9 @@ func main() {
 	personalizer.RegisterPlugin()
 	twitch.RegisterPlugin()
 	voiceroles.RegisterPlugin()
+	tempvoice.RegisterPlugin()
+	polls.RegisterPlugin()
+	outboundwebhooks.RegisterPlugin()
 
 	// Register confusables replacer
 	confusables.Init()
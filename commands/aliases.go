@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/botlabs-gg/yagpdb/v2/commands/models"
+	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
+	"github.com/botlabs-gg/yagpdb/v2/web"
+)
+
+const (
+	MaxAliasesPerOverride = 10
+	MaxAliasLength        = 32
+)
+
+var (
+	_ dcmd.AliasResolver = (*Plugin)(nil)
+
+	aliasRegex = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+)
+
+// ResolveAlias implements dcmd.AliasResolver, looking up the aliases set in the command overrides of the server
+func (p *Plugin) ResolveAlias(data *dcmd.Data, alias string) (string, bool) {
+	if data.GuildData == nil || data.GuildData.CS == nil {
+		return "", false
+	}
+
+	if !featureflags.GuildHasFlagOrLogError(data.GuildData.GS.ID, featureFlagHasAliases) {
+		return "", false
+	}
+
+	overrides, err := GetOverridesForChannel(data.GuildData.CS, data.GuildData.GS)
+	if err != nil {
+		logger.WithError(err).WithField("guild", data.GuildData.GS.ID).Error("failed retrieving command overrides for aliases")
+		return "", false
+	}
+
+	return findOverrideAlias(overrides, alias)
+}
+
+// findOverrideAlias returns the command the alias points to, the channel override takes priority over the global one
+func findOverrideAlias(overrides []*models.CommandsChannelsOverride, alias string) (string, bool) {
+	var global *models.CommandsChannelsOverride
+	for _, v := range overrides {
+		if v.Global {
+			global = v
+			continue
+		}
+
+		if cmd, ok := findAliasInChannelOverride(v, alias); ok {
+			return cmd, true
+		}
+	}
+
+	if global != nil {
+		return findAliasInChannelOverride(global, alias)
+	}
+
+	return "", false
+}
+
+func findAliasInChannelOverride(override *models.CommandsChannelsOverride, alias string) (string, bool) {
+	if override.R == nil {
+		return "", false
+	}
+
+	for _, cmdOverride := range override.R.CommandsCommandOverrides {
+		// aliases are only allowed on overrides with a single command
+		if len(cmdOverride.Commands) != 1 {
+			continue
+		}
+
+		for _, v := range cmdOverride.Aliases {
+			if strings.EqualFold(v, alias) {
+				return cmdOverride.Commands[0], true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func hasAliases(overrides []*models.CommandsChannelsOverride) bool {
+	for _, v := range overrides {
+		if v.R == nil {
+			continue
+		}
+
+		for _, cmdOverride := range v.R.CommandsCommandOverrides {
+			if len(cmdOverride.Aliases) > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseAliases parses and validates the comma or space separated aliases from the dashboard
+func parseAliases(input string, commands []string) ([]string, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+
+	var aliases []string
+	for _, v := range fields {
+		v = strings.ToLower(v)
+		if len(v) > MaxAliasLength || !aliasRegex.MatchString(v) {
+			return nil, web.NewPublicError("Invalid alias `" + v + "`, aliases can only contain letters, numbers, - and _ and be at most 32 characters long")
+		}
+
+		if cmd, _ := CommandSystem.Root.FindCommand(v); cmd != nil {
+			return nil, web.NewPublicError("The alias `" + v + "` is already the name of a command")
+		}
+
+		if !slices.Contains(aliases, v) {
+			aliases = append(aliases, v)
+		}
+	}
+
+	if len(aliases) > MaxAliasesPerOverride {
+		return nil, web.NewPublicError("Max 10 aliases per command override")
+	}
+
+	if len(aliases) > 0 && len(commands) != 1 {
+		return nil, web.NewPublicError("Aliases can only be set on command overrides with a single command")
+	}
+
+	return aliases, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/common/config"
 	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	prfx "github.com/botlabs-gg/yagpdb/v2/common/prefix"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
@@ -53,6 +54,7 @@ func RegisterPlugin() {
 	common.RegisterPlugin(plugin)
 
 	common.InitSchemas("commands", DBSchemas...)
+	i18n.RegisterCatalogs(locales, "locales")
 }
 
 type CommandProvider interface {
@@ -72,6 +74,8 @@ func InitCommands() {
 
 		ResponseSender: &dcmd.StdResponseSender{LogErrors: true},
 		Prefix:         &Plugin{},
+		Aliases:        &Plugin{},
+		Translator:     &Plugin{},
 	}
 
 	// We have our own middleware before the argument parsing, this is to check for things such as whether or not the command is enabled at all
@@ -91,6 +95,7 @@ var _ featureflags.PluginWithFeatureFlags = (*Plugin)(nil)
 const (
 	featureFlagHasCustomPrefix    = "commands_has_custom_prefix"
 	featureFlagHasCustomOverrides = "commands_has_custom_overrides"
+	featureFlagHasAliases         = "commands_has_aliases"
 )
 
 func (p *Plugin) UpdateFeatureFlags(guildID int64) ([]string, error) {
@@ -114,6 +119,19 @@ func (p *Plugin) UpdateFeatureFlags(guildID int64) ([]string, error) {
 		flags = append(flags, featureFlagHasCustomOverrides)
 	}
 
+	if hasAliases(channelOverrides) {
+		flags = append(flags, featureFlagHasAliases)
+	}
+
+	lang, err := i18n.GetGuildLanguageRedis(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	if lang != i18n.DefaultLanguage {
+		flags = append(flags, i18n.FeatureFlagHasLanguage)
+	}
+
 	return flags, nil
 }
 
@@ -142,5 +160,7 @@ func (p *Plugin) AllFeatureFlags() []string {
 	return []string{
 		featureFlagHasCustomPrefix,    // Set if the server has a custom command prefix
 		featureFlagHasCustomOverrides, // set if the server has custom command and/or channel overrides
+		featureFlagHasAliases,         // set if the server has custom command aliases
+		i18n.FeatureFlagHasLanguage,   // set if the server changed the language of the bot
 	}
 }
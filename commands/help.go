@@ -24,8 +24,10 @@ var cmdHelp = &YAGCommand{
 	RunFunc: cmdFuncHelp,
 }
 
+const cmdNotFoundMsg = "Couldn't find that command."
+
 func CmdNotFound(search string) string {
-	return "Couldn't find that command."
+	return cmdNotFoundMsg
 }
 
 func cmdFuncHelp(data *dcmd.Data) (interface{}, error) {
@@ -40,7 +42,7 @@ func cmdFuncHelp(data *dcmd.Data) (interface{}, error) {
 	if target != "" {
 		if len(resp) != 1 {
 			// Send command not found in same channel
-			return CmdNotFound(target), nil
+			return data.Translate(cmdNotFoundMsg), nil
 		}
 
 		// see if we can find the permissions the command needs and add that info to the help message
@@ -75,13 +77,13 @@ func cmdFuncHelp(data *dcmd.Data) (interface{}, error) {
 
 		embed := resp[0]
 		embed.Footer = &discordgo.MessageEmbedFooter{
-			Text: "Required permissions: " + requiredPerms,
+			Text: data.Translatef("Required permissions: %s", requiredPerms),
 		}
 		return embed, nil
 	}
 
 	// Send full help in DM
-	ir, err := createInteractiveHelp(data.Author.ID, resp)
+	ir, err := createInteractiveHelp(data, resp)
 	if ir != nil || err != nil {
 		return ir, err
 	}
@@ -90,18 +92,18 @@ func cmdFuncHelp(data *dcmd.Data) (interface{}, error) {
 		return nil, nil
 	}
 
-	return "You've got mail!", nil
+	return data.Translate("You've got mail!"), nil
 }
 
-func createInteractiveHelp(userID int64, helpEmbeds []*discordgo.MessageEmbed) (interface{}, error) {
-	channel, err := common.BotSession.UserChannelCreate(userID)
+func createInteractiveHelp(data *dcmd.Data, helpEmbeds []*discordgo.MessageEmbed) (interface{}, error) {
+	channel, err := common.BotSession.UserChannelCreate(data.Author.ID)
 	if err != nil {
-		return "Something went wrong, maybe you have DMs disabled? I don't want to spam this channel so here's a external link to available commands: <https://help.yagpdb.xyz/docs/core/all-commands/>", err
+		return data.Translate("Something went wrong, maybe you have DMs disabled? I don't want to spam this channel so here's a external link to available commands: <https://help.yagpdb.xyz/docs/core/all-commands/>"), err
 	}
 
 	// prepend a introductionairy first page
 	firstPage := &discordgo.MessageEmbed{
-		Title: "YAGPDB Help!",
+		Title: data.Translate("YAGPDB Help!"),
 		Description: fmt.Sprintf(`YAGPDB is an open-source multipurpose discord bot that is configured through the web interface at %s.
 For more in depth help and information you should visit https://help.yagpdb.xyz/ as this command only shows information about commands.)
 		
@@ -111,10 +113,10 @@ For more in depth help and information you should visit https://help.yagpdb.xyz/
 
 	var pageLayout strings.Builder
 	for i, v := range helpEmbeds {
-		pageLayout.WriteString(fmt.Sprintf("**Page %d**: %s\n", i+2, v.Title))
+		pageLayout.WriteString(data.Translatef("**Page %d**: %s", i+2, v.Title) + "\n")
 	}
 	firstPage.Fields = []*discordgo.MessageEmbedField{
-		{Name: "Help pages", Value: pageLayout.String()},
+		{Name: data.Translate("Help pages"), Value: pageLayout.String()},
 	}
 
 	helpEmbeds = append([]*discordgo.MessageEmbed{firstPage}, helpEmbeds...)
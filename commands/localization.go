@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"embed"
+
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
+)
+
+//go:embed locales
+var locales embed.FS
+
+var _ dcmd.Translator = (*Plugin)(nil)
+
+// Translate implements dcmd.Translator, messages are translated to the language of the guild the command was ran in
+func (p *Plugin) Translate(data *dcmd.Data, msg string) string {
+	if data.GuildData == nil {
+		return msg
+	}
+
+	return i18n.GuildT(data.GuildData.GS.ID, msg)
+}
@@ -36,6 +36,10 @@ type CommandsCommandOverride struct {
 	AutodeleteTriggerDelay      int               `boil:"autodelete_trigger_delay" json:"autodelete_trigger_delay" toml:"autodelete_trigger_delay" yaml:"autodelete_trigger_delay"`
 	RequireRoles                types.Int64Array  `boil:"require_roles" json:"require_roles" toml:"require_roles" yaml:"require_roles"`
 	IgnoreRoles                 types.Int64Array  `boil:"ignore_roles" json:"ignore_roles" toml:"ignore_roles" yaml:"ignore_roles"`
+	UserCooldown                int               `boil:"user_cooldown" json:"user_cooldown" toml:"user_cooldown" yaml:"user_cooldown"`
+	GuildCooldown               int               `boil:"guild_cooldown" json:"guild_cooldown" toml:"guild_cooldown" yaml:"guild_cooldown"`
+	ChannelCooldown             int               `boil:"channel_cooldown" json:"channel_cooldown" toml:"channel_cooldown" yaml:"channel_cooldown"`
+	Aliases                     types.StringArray `boil:"aliases" json:"aliases" toml:"aliases" yaml:"aliases"`
 
 	R *commandsCommandOverrideR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L commandsCommandOverrideL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -54,6 +58,10 @@ var CommandsCommandOverrideColumns = struct {
 	AutodeleteTriggerDelay      string
 	RequireRoles                string
 	IgnoreRoles                 string
+	UserCooldown                string
+	GuildCooldown               string
+	ChannelCooldown             string
+	Aliases                     string
 }{
 	ID:                          "id",
 	GuildID:                     "guild_id",
@@ -67,6 +75,10 @@ var CommandsCommandOverrideColumns = struct {
 	AutodeleteTriggerDelay:      "autodelete_trigger_delay",
 	RequireRoles:                "require_roles",
 	IgnoreRoles:                 "ignore_roles",
+	UserCooldown:                "user_cooldown",
+	GuildCooldown:               "guild_cooldown",
+	ChannelCooldown:             "channel_cooldown",
+	Aliases:                     "aliases",
 }
 
 var CommandsCommandOverrideTableColumns = struct {
@@ -82,6 +94,10 @@ var CommandsCommandOverrideTableColumns = struct {
 	AutodeleteTriggerDelay      string
 	RequireRoles                string
 	IgnoreRoles                 string
+	UserCooldown                string
+	GuildCooldown               string
+	ChannelCooldown             string
+	Aliases                     string
 }{
 	ID:                          "commands_command_overrides.id",
 	GuildID:                     "commands_command_overrides.guild_id",
@@ -95,6 +111,10 @@ var CommandsCommandOverrideTableColumns = struct {
 	AutodeleteTriggerDelay:      "commands_command_overrides.autodelete_trigger_delay",
 	RequireRoles:                "commands_command_overrides.require_roles",
 	IgnoreRoles:                 "commands_command_overrides.ignore_roles",
+	UserCooldown:                "commands_command_overrides.user_cooldown",
+	GuildCooldown:               "commands_command_overrides.guild_cooldown",
+	ChannelCooldown:             "commands_command_overrides.channel_cooldown",
+	Aliases:                     "commands_command_overrides.aliases",
 }
 
 // Generated where
@@ -133,6 +153,10 @@ var CommandsCommandOverrideWhere = struct {
 	AutodeleteTriggerDelay      whereHelperint
 	RequireRoles                whereHelpertypes_Int64Array
 	IgnoreRoles                 whereHelpertypes_Int64Array
+	UserCooldown                whereHelperint
+	GuildCooldown               whereHelperint
+	ChannelCooldown             whereHelperint
+	Aliases                     whereHelpertypes_StringArray
 }{
 	ID:                          whereHelperint64{field: "\"commands_command_overrides\".\"id\""},
 	GuildID:                     whereHelperint64{field: "\"commands_command_overrides\".\"guild_id\""},
@@ -146,6 +170,10 @@ var CommandsCommandOverrideWhere = struct {
 	AutodeleteTriggerDelay:      whereHelperint{field: "\"commands_command_overrides\".\"autodelete_trigger_delay\""},
 	RequireRoles:                whereHelpertypes_Int64Array{field: "\"commands_command_overrides\".\"require_roles\""},
 	IgnoreRoles:                 whereHelpertypes_Int64Array{field: "\"commands_command_overrides\".\"ignore_roles\""},
+	UserCooldown:                whereHelperint{field: "\"commands_command_overrides\".\"user_cooldown\""},
+	GuildCooldown:               whereHelperint{field: "\"commands_command_overrides\".\"guild_cooldown\""},
+	ChannelCooldown:             whereHelperint{field: "\"commands_command_overrides\".\"channel_cooldown\""},
+	Aliases:                     whereHelpertypes_StringArray{field: "\"commands_command_overrides\".\"aliases\""},
 }
 
 // CommandsCommandOverrideRels is where relationship names are stored.
@@ -176,9 +204,9 @@ func (r *commandsCommandOverrideR) GetCommandsChannelsOverride() *CommandsChanne
 type commandsCommandOverrideL struct{}
 
 var (
-	commandsCommandOverrideAllColumns            = []string{"id", "guild_id", "commands_channels_overrides_id", "commands", "commands_enabled", "always_ephemeral", "autodelete_response", "autodelete_trigger", "autodelete_response_delay", "autodelete_trigger_delay", "require_roles", "ignore_roles"}
+	commandsCommandOverrideAllColumns            = []string{"id", "guild_id", "commands_channels_overrides_id", "commands", "commands_enabled", "always_ephemeral", "autodelete_response", "autodelete_trigger", "autodelete_response_delay", "autodelete_trigger_delay", "require_roles", "ignore_roles", "user_cooldown", "guild_cooldown", "channel_cooldown", "aliases"}
 	commandsCommandOverrideColumnsWithoutDefault = []string{"guild_id", "commands_channels_overrides_id", "commands", "commands_enabled", "always_ephemeral", "autodelete_response", "autodelete_trigger", "autodelete_response_delay", "autodelete_trigger_delay", "require_roles", "ignore_roles"}
-	commandsCommandOverrideColumnsWithDefault    = []string{"id"}
+	commandsCommandOverrideColumnsWithDefault    = []string{"id", "user_cooldown", "guild_cooldown", "channel_cooldown", "aliases"}
 	commandsCommandOverridePrimaryKeyColumns     = []string{"id"}
 	commandsCommandOverrideGeneratedColumns      = []string{}
 )
@@ -2,7 +2,6 @@ package commands
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -206,10 +205,10 @@ func YAGCommandMiddleware(inner dcmd.RunFunc) dcmd.RunFunc {
 		// Lock the command for execution
 		if !BlockingAddRunningCommand(guildID, data.ChannelID, data.Author.ID, yc, time.Second*60) {
 			if atomic.LoadInt32(shuttingDown) == 1 {
-				return &EphemeralOrGuild{Content: yc.Name + ": Bot is restarting, please try again in a couple seconds..."}, nil
+				return &EphemeralOrGuild{Content: data.Translatef("%s: Bot is restarting, please try again in a couple seconds...", yc.Name)}, nil
 			}
 
-			return &EphemeralOrGuild{Content: yc.Name + ": Gave up trying to run command after 60 seconds waiting for your previous instance of this command to finish"}, nil
+			return &EphemeralOrGuild{Content: data.Translatef("%s: Gave up trying to run command after 60 seconds waiting for your previous instance of this command to finish", yc.Name)}, nil
 		}
 
 		defer removeRunningCommand(guildID, data.ChannelID, data.Author.ID, yc)
@@ -226,18 +225,18 @@ func YAGCommandMiddleware(inner dcmd.RunFunc) dcmd.RunFunc {
 			switch resp.Type {
 			case ReasonBotMissingPerms:
 				return &EphemeralOrGuild{
-					Content: "You're unable to run this command:\n> " + resp.Message,
+					Content: data.Translatef("You're unable to run this command:\n> %s", resp.Message),
 				}, nil
 			default:
 				return &EphemeralOrNone{
-					Content: "You're unable to run this command:\n> " + resp.Message,
+					Content: data.Translatef("You're unable to run this command:\n> %s", resp.Message),
 				}, nil
 			}
 		}
 
 		if !canExecute {
 			return &EphemeralOrNone{
-				Content: "You're unable to run this command.",
+				Content: data.Translate("You're unable to run this command."),
 			}, nil
 		}
 
@@ -332,7 +331,7 @@ func handleMsgCreate(evt *eventsystem.EventData) {
 		}
 	}
 
-	CommandSystem.CheckMessageWtihPrefetchedPrefix(common.BotSession, evt.MessageCreate(), prefix)
+	CommandSystem.CheckMessageWtihPrefetchedPrefix(evt.Context(), common.BotSession, evt.MessageCreate(), prefix)
 	// CommandSystem.HandleMessageCreate(common.BotSession, evt.MessageCreate())
 }
 
@@ -406,7 +405,7 @@ var cmdPrefix = &YAGCommand{
 			return nil, err
 		}
 
-		return fmt.Sprintf("Prefix of `%d`: `%s`", targetGuildID, prefix), nil
+		return data.Translatef("Prefix of `%d`: `%s`", targetGuildID, prefix), nil
 	},
 }
 
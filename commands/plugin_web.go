@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"database/sql"
 	_ "embed"
 	"fmt"
@@ -16,6 +17,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/common/cplogs"
 	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	prfx "github.com/botlabs-gg/yagpdb/v2/common/prefix"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
@@ -56,10 +58,15 @@ type CommandOverrideForm struct {
 	AutodeleteTriggerDelay  int     `valid:"0,2678400"`
 	RequireRoles            []int64 `valid:"role,true"`
 	IgnoreRoles             []int64 `valid:"role,true"`
+	UserCooldown            int     `valid:"0,604800"`
+	GuildCooldown           int     `valid:"0,604800"`
+	ChannelCooldown         int     `valid:"0,604800"`
+	Aliases                 string  `valid:",500"`
 }
 
 var (
-	panelLogKeyUpdatedPrefix = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "commands_updated_prefix", FormatString: "Updated command settings: Set prefix to %s"})
+	panelLogKeyUpdatedPrefix   = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "commands_updated_prefix", FormatString: "Updated command settings: Set prefix to %s"})
+	panelLogKeyUpdatedLanguage = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "commands_updated_language", FormatString: "Updated command settings: Set language to %s"})
 
 	panelLogKeyNewChannelOverride     = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "commands_new_channel_override", FormatString: "Updated command settings: Created a new ChannelOverride"})
 	panelLogKeyUpdatedChannelOverride = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "commands_updated_channel_override", FormatString: "Updated command settings: Updated a ChannelOverride"})
@@ -182,6 +189,10 @@ func HandleCommands(w http.ResponseWriter, r *http.Request) (web.TemplateData, e
 
 	templateData["CommandPrefix"] = prefix
 
+	lang, _ := i18n.GetGuildLanguageRedis(activeGuild.ID)
+	templateData["CommandLanguage"] = lang
+	templateData["Languages"] = i18n.Languages()
+
 	templateData["VisibleURL"] = "/manage/" + discordgo.StrID(activeGuild.ID) + "/commands/settings"
 
 	return templateData, nil
@@ -196,13 +207,35 @@ func HandlePostCommands(w http.ResponseWriter, r *http.Request) (web.TemplateDat
 		return templateData, web.NewPublicError("Prefix is smaller than 1 or larger than 100 characters")
 	}
 
-	err := common.RedisPool.Do(radix.Cmd(nil, "SET", "command_prefix:"+discordgo.StrID(activeGuild.ID), newPrefix))
+	newLanguage := discordgo.Locale(r.FormValue("Language"))
+	if newLanguage == "" {
+		newLanguage = i18n.DefaultLanguage
+	}
+
+	if !i18n.IsSupported(newLanguage) {
+		return templateData, web.NewPublicError("Unsupported language")
+	}
+
+	oldLanguage, err := i18n.GetGuildLanguageRedis(activeGuild.ID)
+	if err != nil {
+		return templateData, err
+	}
+
+	err = common.RedisPool.Do(radix.Cmd(nil, "SET", "command_prefix:"+discordgo.StrID(activeGuild.ID), newPrefix))
+	if err != nil {
+		return templateData, err
+	}
+
+	err = i18n.SetGuildLanguage(activeGuild.ID, newLanguage)
 	if err != nil {
 		return templateData, err
 	}
 
 	featureflags.MarkGuildDirty(activeGuild.ID)
 	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyUpdatedPrefix, &cplogs.Param{Type: cplogs.ParamTypeString, Value: newPrefix}))
+	if oldLanguage != newLanguage {
+		go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyUpdatedLanguage, &cplogs.Param{Type: cplogs.ParamTypeString, Value: newLanguage.String()}))
+	}
 
 	return templateData, nil
 }
@@ -367,6 +400,11 @@ func HandleCreateCommandOverride(w http.ResponseWriter, r *http.Request, channel
 		return templateData, web.NewPublicError("No commands specified")
 	}
 
+	aliases, err := checkOverrideAliases(r.Context(), channelOverride.ID, 0, formData)
+	if err != nil {
+		return templateData, err
+	}
+
 	model := &models.CommandsCommandOverride{
 		GuildID:                     activeGuild.ID,
 		CommandsChannelsOverridesID: channelOverride.ID,
@@ -380,6 +418,10 @@ func HandleCreateCommandOverride(w http.ResponseWriter, r *http.Request, channel
 		AutodeleteTriggerDelay:  formData.AutodeleteTriggerDelay,
 		RequireRoles:            formData.RequireRoles,
 		IgnoreRoles:             formData.IgnoreRoles,
+		UserCooldown:            formData.UserCooldown,
+		GuildCooldown:           formData.GuildCooldown,
+		ChannelCooldown:         formData.ChannelCooldown,
+		Aliases:                 aliases,
 	}
 
 	err = model.InsertG(r.Context(), boil.Infer())
@@ -410,6 +452,11 @@ func HandleUpdateCommandOVerride(w http.ResponseWriter, r *http.Request, channel
 		return templateData, web.NewPublicError("One of the selected commands is already used in another command override for this channel override")
 	}
 
+	aliases, err := checkOverrideAliases(r.Context(), channelOverride.ID, override.ID, formData)
+	if err != nil {
+		return templateData, err
+	}
+
 	override.Commands = formData.Commands
 	override.CommandsEnabled = formData.CommandsEnabled
 	override.AlwaysEphemeral = formData.AlwaysEphemeral
@@ -419,6 +466,10 @@ func HandleUpdateCommandOVerride(w http.ResponseWriter, r *http.Request, channel
 	override.AutodeleteTriggerDelay = formData.AutodeleteTriggerDelay
 	override.RequireRoles = formData.RequireRoles
 	override.IgnoreRoles = formData.IgnoreRoles
+	override.UserCooldown = formData.UserCooldown
+	override.GuildCooldown = formData.GuildCooldown
+	override.ChannelCooldown = formData.ChannelCooldown
+	override.Aliases = aliases
 
 	_, err = override.UpdateG(r.Context(), boil.Infer())
 	if err == nil {
@@ -428,6 +479,25 @@ func HandleUpdateCommandOVerride(w http.ResponseWriter, r *http.Request, channel
 	return templateData, errors.WithMessage(err, "UpdateG")
 }
 
+// checkOverrideAliases parses the aliases from the form and makes sure no other command override in the channel override uses them
+func checkOverrideAliases(ctx context.Context, channelOverrideID int64, commandOverrideID int64, formData *CommandOverrideForm) ([]string, error) {
+	aliases, err := parseAliases(formData.Aliases, formData.Commands)
+	if err != nil || len(aliases) < 1 {
+		return aliases, err
+	}
+
+	count, err := models.CommandsCommandOverrides(qm.Where("commands_channels_overrides_id = ?", channelOverrideID), qm.Where("aliases && ?", types.StringArray(aliases)), qm.Where("id != ?", commandOverrideID)).CountG(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "count aliases")
+	}
+
+	if count > 0 {
+		return nil, web.NewPublicError("One of the aliases is already used in another command override for this channel override")
+	}
+
+	return aliases, nil
+}
+
 func HandleDeleteCommandOverride(w http.ResponseWriter, r *http.Request, channelOverride *models.CommandsChannelsOverride) (web.TemplateData, error) {
 	activeGuild, templateData := web.GetBaseCPContextData(r.Context())
 
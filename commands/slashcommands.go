@@ -14,6 +14,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/bot/eventsystem"
 	"github.com/botlabs-gg/yagpdb/v2/commands/models"
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	"github.com/botlabs-gg/yagpdb/v2/common/pubsub"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
@@ -120,9 +121,10 @@ OUTER:
 func (p *Plugin) containerToSlashCommand(container *slashCommandsContainer) *discordgo.CreateApplicationCommandRequest {
 	t := true
 	req := &discordgo.CreateApplicationCommandRequest{
-		Name:              strings.ToLower(container.container.Names[0]),
-		Description:       common.CutStringShort(container.container.Description, 100),
-		DefaultPermission: &t,
+		Name:                     strings.ToLower(container.container.Names[0]),
+		Description:              common.CutStringShort(container.container.Description, 100),
+		DescriptionLocalizations: descriptionLocalizations(container.container.Description),
+		DefaultPermission:        &t,
 	}
 
 	for _, v := range container.container.Commands {
@@ -138,10 +140,11 @@ func (p *Plugin) containerToSlashCommand(container *slashCommandsContainer) *dis
 		}
 
 		opt := &discordgo.ApplicationCommandOption{
-			Name:        strings.ToLower(cast.Name),
-			Description: common.CutStringShort(cast.Description, 100),
-			Type:        kind,
-			Options:     innerOpts,
+			Name:                     strings.ToLower(cast.Name),
+			Description:              common.CutStringShort(cast.Description, 100),
+			DescriptionLocalizations: descriptionLocalizations(cast.Description),
+			Type:                     kind,
+			Options:                  innerOpts,
 		}
 
 		req.Options = append(req.Options, opt)
@@ -166,14 +169,25 @@ func (p *Plugin) yagCommandToSlashCommand(cmd *dcmd.RegisteredCommand) *discordg
 
 	_, opts := cast.slashCommandOptions()
 	return &discordgo.CreateApplicationCommandRequest{
-		Name:              strings.ToLower(cmd.Trigger.Names[0]),
-		Description:       common.CutStringShort(cast.Description, 100),
-		DefaultPermission: &t,
-		Options:           opts,
-		NSFW:              cast.NSFW,
+		Name:                     strings.ToLower(cmd.Trigger.Names[0]),
+		Description:              common.CutStringShort(cast.Description, 100),
+		DescriptionLocalizations: descriptionLocalizations(cast.Description),
+		DefaultPermission:        &t,
+		Options:                  opts,
+		NSFW:                     cast.NSFW,
 	}
 }
 
+// descriptionLocalizations returns the translations of a description, cut to the length discord allows
+func descriptionLocalizations(description string) map[discordgo.Locale]string {
+	result := i18n.Localizations(description)
+	for k, v := range result {
+		result[k] = common.CutStringShort(v, 100)
+	}
+
+	return result
+}
+
 // IsInbuiltSlashCommandName checks if the provided name is already
 // a built-in global slash command
 func IsInbuiltSlashCommandName(name string) bool {
@@ -221,9 +235,10 @@ func (yc *YAGCommand) slashCommandOptions() (turnedIntoSubCommands bool, result
 				}
 
 				subCommands = append(subCommands, &discordgo.ApplicationCommandOption{
-					Type:        kind,
-					Name:        "by-" + opt.Name,
-					Description: common.CutStringShort(yc.Description, 100),
+					Type:                     kind,
+					Name:                     "by-" + opt.Name,
+					Description:              common.CutStringShort(yc.Description, 100),
+					DescriptionLocalizations: descriptionLocalizations(yc.Description),
 					Options: []*discordgo.ApplicationCommandOption{
 						opt,
 					},
@@ -396,7 +411,7 @@ func handleInteractionCreate(evt *eventsystem.EventData) {
 	// logger.Infof("Got interaction %#v", interaction.Interaction)
 	// fmt.Println(string(serialized))
 
-	err := CommandSystem.CheckInteraction(common.BotSession, &interaction.Interaction)
+	err := CommandSystem.CheckInteraction(evt.Context(), common.BotSession, &interaction.Interaction)
 	if err != nil {
 		logger.WithError(err).Error("failed handling command interaction")
 	}
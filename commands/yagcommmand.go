@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"slices"
-	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -15,6 +14,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/bot"
 	"github.com/botlabs-gg/yagpdb/v2/commands/models"
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/tracing"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
@@ -24,6 +24,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"go.opentelemetry.io/otel/attribute"
 
 	commonmodels "github.com/botlabs-gg/yagpdb/v2/common/models"
 )
@@ -68,9 +69,10 @@ var (
 )
 
 var (
-	RKeyCommandCooldown      = func(uID int64, cmd string) string { return "cmd_cd:" + discordgo.StrID(uID) + ":" + cmd }
-	RKeyCommandCooldownGuild = func(gID int64, cmd string) string { return "cmd_guild_cd:" + discordgo.StrID(gID) + ":" + cmd }
-	RKeyCommandLock          = func(uID int64, cmd string) string { return "cmd_lock:" + discordgo.StrID(uID) + ":" + cmd }
+	RKeyCommandCooldown        = func(uID int64, cmd string) string { return "cmd_cd:" + discordgo.StrID(uID) + ":" + cmd }
+	RKeyCommandCooldownGuild   = func(gID int64, cmd string) string { return "cmd_guild_cd:" + discordgo.StrID(gID) + ":" + cmd }
+	RKeyCommandCooldownChannel = func(cID int64, cmd string) string { return "cmd_channel_cd:" + discordgo.StrID(cID) + ":" + cmd }
+	RKeyCommandLock            = func(uID int64, cmd string) string { return "cmd_lock:" + discordgo.StrID(uID) + ":" + cmd }
 
 	CommandExecTimeout = time.Minute
 
@@ -160,7 +162,8 @@ func (yc *YAGCommand) Category() *dcmd.Category {
 }
 
 func (yc *YAGCommand) Descriptions(data *dcmd.Data) (short, long string) {
-	return yc.Description, yc.Description + "\n" + yc.LongDescription
+	short = data.Translate(yc.Description)
+	return short, short + "\n" + data.Translate(yc.LongDescription)
 }
 
 func (yc *YAGCommand) ArgDefs(data *dcmd.Data) (args []*dcmd.ArgDef, required int, combos [][]int) {
@@ -176,7 +179,7 @@ var metricsExcecutedCommands = promauto.NewCounterVec(prometheus.CounterOpts{
 	Help: "Commands the bot executed",
 }, []string{"name", "trigger_type"})
 
-func (yc *YAGCommand) Run(data *dcmd.Data) (interface{}, error) {
+func (yc *YAGCommand) Run(data *dcmd.Data) (r interface{}, cmdErr error) {
 	if data.TriggerType == dcmd.TriggerTypeSlashCommands && data.SlashCommandTriggerData.Interaction.Type == discordgo.InteractionApplicationCommandAutocomplete {
 		for _, v := range data.SlashCommandTriggerData.Options {
 			if !v.Focused {
@@ -199,7 +202,7 @@ func (yc *YAGCommand) Run(data *dcmd.Data) (interface{}, error) {
 	if yc.NSFW {
 		channel := data.GuildData.GS.GetChannelOrThread(data.ChannelID)
 		if !channel.NSFW {
-			return "This command can be used only in age-restricted channels", nil
+			return data.Translate("This command can be used only in age-restricted channels"), nil
 		}
 	}
 
@@ -255,20 +258,25 @@ func (yc *YAGCommand) Run(data *dcmd.Data) (interface{}, error) {
 
 	logger.Info("Handling command: " + rawCommand)
 
-	runCtx, cancelExec := context.WithTimeout(data.Context(), CommandExecTimeout)
+	spanCtx, span := tracing.Start(data.Context(), "command "+cmdFullName, tracing.GuildID(guildID), attribute.String("trigger_type", triggerType))
+	defer func() {
+		tracing.End(span, cmdErr)
+	}()
+
+	runCtx, cancelExec := context.WithTimeout(spanCtx, CommandExecTimeout)
 	defer cancelExec()
 
 	// Run the command
-	r, cmdErr := yc.RunFunc(data.WithContext(runCtx))
+	r, cmdErr = yc.RunFunc(data.WithContext(runCtx))
 	logEntry.ResponseTime = int64(time.Since(started))
 
 	if cmdErr != nil {
 		if errors.Cause(cmdErr) == context.Canceled || errors.Cause(cmdErr) == context.DeadlineExceeded {
-			r = &EphemeralOrGuild{Content: "Took longer than " + CommandExecTimeout.String() + " to handle command: `" + rawCommand + "`, Cancelled the command."}
+			r = &EphemeralOrGuild{Content: data.Translatef("Took longer than %s to handle command: `%s`, Cancelled the command.", CommandExecTimeout.String(), rawCommand)}
 		}
 
 		if r == nil || r == "" {
-			r = &EphemeralOrGuild{Content: yc.humanizeError(cmdErr)}
+			r = &EphemeralOrGuild{Content: yc.humanizeError(data, cmdErr)}
 		}
 
 		// set cmdErr to nil if this was a user error top stop it from being recorded and logged as an actual error
@@ -277,7 +285,8 @@ func (yc *YAGCommand) Run(data *dcmd.Data) (interface{}, error) {
 		}
 	} else {
 		// set cooldowns
-		err := yc.SetCooldowns(data.ContainerChain, data.Author.ID, guildID)
+		settings, _ := data.Context().Value(CtxKeyCmdSettings).(*CommandSettings)
+		err := yc.SetCooldowns(data.ContainerChain, settings, data.Author.ID, guildID, data.ChannelID)
 		if err != nil {
 			logger.WithError(err).Error("Failed setting cooldown")
 		}
@@ -296,27 +305,27 @@ func (yc *YAGCommand) Run(data *dcmd.Data) (interface{}, error) {
 	return r, cmdErr
 }
 
-func (yc *YAGCommand) humanizeError(err error) string {
+func (yc *YAGCommand) humanizeError(data *dcmd.Data, err error) string {
 	cause := errors.Cause(err)
 
 	switch t := cause.(type) {
 	case PublicError:
-		return "The command returned an error: " + t.Error()
+		return data.Translatef("The command returned an error: %s", t.Error())
 	case UserError:
-		return "Unable to run the command: " + t.Error()
+		return data.Translatef("Unable to run the command: %s", t.Error())
 	case *discordgo.RESTError:
 		if t.Message != nil && t.Message.Message != "" {
 			if t.Message.Message == "Unknown Message" {
-				return "The bot was not able to perform the action, Discord responded with: " + t.Message.Message + ". Please be sure you ran the command in the same channel as the message."
+				return data.Translatef("The bot was not able to perform the action, Discord responded with: %s. Please be sure you ran the command in the same channel as the message.", t.Message.Message)
 			} else if t.Response != nil && t.Response.StatusCode == 403 {
-				return "The bot permissions has been incorrectly set up on this server for it to run this command: " + t.Message.Message
+				return data.Translatef("The bot permissions has been incorrectly set up on this server for it to run this command: %s", t.Message.Message)
 			}
 
-			return "The bot was not able to perform the action, discord responded with: " + t.Message.Message
+			return data.Translatef("The bot was not able to perform the action, discord responded with: %s", t.Message.Message)
 		}
 	}
 
-	return "Something went wrong when running this command, either discord or the bot may be having issues."
+	return data.Translate("Something went wrong when running this command, either discord or the bot may be having issues.")
 }
 
 // PostCommandExecuted sends the response and handles the trigger and response deletions
@@ -354,7 +363,7 @@ func (yc *YAGCommand) PostCommandExecuted(settings *CommandSettings, cmdData *dc
 		switch resp.(type) {
 		case *discordgo.MessageEmbed, []*discordgo.MessageEmbed:
 			if hasPerms, _ := bot.BotHasPermissionGS(cmdData.GuildData.GS, cmdData.ChannelID, discordgo.PermissionEmbedLinks); !hasPerms {
-				resp = "This command returned an embed but the bot does not have embed links permissions in this channel, cannot send the response."
+				resp = cmdData.Translate("This command returned an embed but the bot does not have embed links permissions in this channel, cannot send the response.")
 			}
 		}
 	}
@@ -453,7 +462,7 @@ func (yc *YAGCommand) checkCanExecuteCommand(data *dcmd.Data) (canExecute bool,
 		if !settings.Enabled {
 			resp = &CanExecuteError{
 				Type:    ReasonCommandDisabaledSettings,
-				Message: "Command is disabled in this channel by server admins",
+				Message: data.Translate("Command is disabled in this channel by server admins"),
 			}
 
 			return false, resp, settings, nil
@@ -470,11 +479,11 @@ func (yc *YAGCommand) checkCanExecuteCommand(data *dcmd.Data) (canExecute bool,
 			return false, blacklistErr, settings, nil
 		}
 
-		if userPermsErr := yc.checkRequiredMemberPerms(guild, member, data.ChannelID); userPermsErr != nil {
+		if userPermsErr := yc.checkRequiredMemberPerms(data, guild, member); userPermsErr != nil {
 			return false, userPermsErr, settings, nil
 		}
 
-		if userPermsErr := yc.checkRequiredBotPerms(guild, data.ChannelID); userPermsErr != nil {
+		if userPermsErr := yc.checkRequiredBotPerms(data, guild); userPermsErr != nil {
 			return false, userPermsErr, settings, nil
 		}
 	} else {
@@ -489,7 +498,7 @@ func (yc *YAGCommand) checkCanExecuteCommand(data *dcmd.Data) (canExecute bool,
 	}
 
 	// Check the command cooldown
-	cdLeft, err := yc.LongestCooldownLeft(data.ContainerChain, data.Author.ID, guildID)
+	cdLeft, err := yc.LongestCooldownLeft(data.ContainerChain, settings, data.Author.ID, guildID, data.ChannelID)
 	if err != nil {
 		// Just pretend the cooldown is off...
 		yc.Logger(data).Error("Failed checking command cooldown")
@@ -498,7 +507,7 @@ func (yc *YAGCommand) checkCanExecuteCommand(data *dcmd.Data) (canExecute bool,
 	if cdLeft > 0 {
 		resp = &CanExecuteError{
 			Type:    ReasonCooldown,
-			Message: "Command is on cooldown, try again in " + strconv.Itoa(cdLeft) + " seconds",
+			Message: data.Translatef("Command is on cooldown, try again in %d seconds", cdLeft),
 		}
 		return false, resp, settings, nil
 	}
@@ -547,7 +556,7 @@ func checkWhitelistRoles(guildRoles map[int64]string, whitelistRoles []int64, da
 
 	return &CanExecuteError{
 		Type:    ReasonMissingRole,
-		Message: "You need at least one of the server allowed roles: " + humanizedRoles.String(),
+		Message: data.Translatef("You need at least one of the server allowed roles: %s", humanizedRoles.String()),
 	}
 }
 
@@ -581,17 +590,17 @@ func checkBlacklistRoles(guildRoles map[int64]string, blacklistRoles []int64, da
 
 	return &CanExecuteError{
 		Type:    ReasonIgnoredRole,
-		Message: "You have one of the server denylist roles: " + humanizedRole,
+		Message: data.Translatef("You have one of the server denylist roles: %s", humanizedRole),
 	}
 }
 
-func (yc *YAGCommand) checkRequiredMemberPerms(gs *dstate.GuildSet, ms *dstate.MemberState, channelID int64) *CanExecuteError {
+func (yc *YAGCommand) checkRequiredMemberPerms(data *dcmd.Data, gs *dstate.GuildSet, ms *dstate.MemberState) *CanExecuteError {
 	// This command has permission sets required, if the user has one of them then allow this command to be used
 	if len(yc.RequireDiscordPerms) < 1 {
 		return nil
 	}
 
-	perms, err := gs.GetMemberPermissions(channelID, ms.User.ID, ms.Member.Roles)
+	perms, err := gs.GetMemberPermissions(data.ChannelID, ms.User.ID, ms.Member.Roles)
 	if err != nil {
 		return &CanExecuteError{
 			Type:    ReasonError,
@@ -615,17 +624,17 @@ func (yc *YAGCommand) checkRequiredMemberPerms(gs *dstate.GuildSet, ms *dstate.M
 
 	return &CanExecuteError{
 		Type:    ReasonUserMissingPerms,
-		Message: "You need at least one of the following permissions to run this command: " + strings.Join(humanizedPerms, " or "),
+		Message: data.Translatef("You need at least one of the following permissions to run this command: %s", strings.Join(humanizedPerms, " or ")),
 	}
 }
 
-func (yc *YAGCommand) checkRequiredBotPerms(gs *dstate.GuildSet, channelID int64) *CanExecuteError {
+func (yc *YAGCommand) checkRequiredBotPerms(data *dcmd.Data, gs *dstate.GuildSet) *CanExecuteError {
 	// This command has permission sets required, if the user has one of them then allow this command to be used
 	if len(yc.RequireBotPerms) < 1 {
 		return nil
 	}
 
-	perms, err := bot.BotPermissions(gs, channelID)
+	perms, err := bot.BotPermissions(gs, data.ChannelID)
 	if err != nil {
 		return &CanExecuteError{
 			Type:    ReasonError,
@@ -661,7 +670,7 @@ OUTER:
 
 	return &CanExecuteError{
 		Type:    ReasonBotMissingPerms,
-		Message: "The bot needs at least one of the following permissions to run this command: " + strings.Join(humanizedPerms, " or "),
+		Message: data.Translatef("The bot needs at least one of the following permissions to run this command: %s", strings.Join(humanizedPerms, " or ")),
 	}
 }
 
@@ -714,6 +723,11 @@ type CommandSettings struct {
 
 	RequiredRoles []int64
 	IgnoreRoles   []int64
+
+	// Cooldowns set in the command override, 0 keeps the cooldown of the command
+	UserCooldown    int
+	GuildCooldown   int
+	ChannelCooldown int
 }
 
 func GetOverridesForChannel(cs *dstate.ChannelState, guild *dstate.GuildSet) ([]*models.CommandsChannelsOverride, error) {
@@ -812,6 +826,10 @@ func (cs *YAGCommand) fillSettings(cmdFullName string, override *models.Commands
 	settings.DelResponseDelay = override.AutodeleteResponseDelay
 	settings.DelTriggerDelay = override.AutodeleteTriggerDelay
 
+	settings.UserCooldown = 0
+	settings.GuildCooldown = 0
+	settings.ChannelCooldown = 0
+
 OUTER:
 	for _, cmdOverride := range override.R.CommandsCommandOverrides {
 		for _, cmd := range cmdOverride.Commands {
@@ -827,54 +845,77 @@ OUTER:
 				settings.DelResponseDelay = cmdOverride.AutodeleteResponseDelay
 				settings.DelTriggerDelay = cmdOverride.AutodeleteTriggerDelay
 
+				settings.UserCooldown = cmdOverride.UserCooldown
+				settings.GuildCooldown = cmdOverride.GuildCooldown
+				settings.ChannelCooldown = cmdOverride.ChannelCooldown
+
 				break OUTER
 			}
 		}
 	}
 }
 
-// LongestCooldownLeft returns the longest cooldown for this command, either user scoped or guild scoped
-func (cs *YAGCommand) LongestCooldownLeft(cc []*dcmd.Container, userID int64, guildID int64) (int, error) {
-	cdUser, err := cs.UserScopeCooldownLeft(cc, userID)
-	if err != nil {
-		return 0, err
+// cooldowns returns the user, guild and channel scoped cooldowns of the command, with the ones from the command override applied
+func (cs *YAGCommand) cooldowns(settings *CommandSettings) (user, guild, channel int) {
+	user, guild = cs.Cooldown, cs.GuildScopeCooldown
+	if settings == nil {
+		return
 	}
 
-	cdGuild, err := cs.GuildScopeCooldownLeft(cc, guildID)
-	if err != nil {
-		return 0, err
+	if settings.UserCooldown > 0 {
+		user = settings.UserCooldown
 	}
-
-	if cdUser > cdGuild {
-		return cdUser, nil
+	if settings.GuildCooldown > 0 {
+		guild = settings.GuildCooldown
 	}
 
-	return cdGuild, nil
+	return user, guild, settings.ChannelCooldown
 }
 
-// UserScopeCooldownLeft returns the number of seconds before a command can be used again by this user
-func (cs *YAGCommand) UserScopeCooldownLeft(cc []*dcmd.Container, userID int64) (int, error) {
-	if cs.Cooldown < 1 {
-		return 0, nil
-	}
+// LongestCooldownLeft returns the longest cooldown for this command, either user, guild or channel scoped
+func (cs *YAGCommand) LongestCooldownLeft(cc []*dcmd.Container, settings *CommandSettings, userID int64, guildID int64, channelID int64) (int, error) {
+	cdUser, cdGuild, cdChannel := cs.cooldowns(settings)
+	name := cs.FindNameFromContainerChain(cc)
+
+	longest := 0
+	for _, v := range []struct {
+		cd  int
+		key string
+	}{
+		{cdUser, RKeyCommandCooldown(userID, name)},
+		{cdGuild, RKeyCommandCooldownGuild(guildID, name)},
+		{cdChannel, RKeyCommandCooldownChannel(channelID, name)},
+	} {
+		left, err := cooldownLeft(v.cd, v.key)
+		if err != nil {
+			return 0, err
+		}
 
-	var ttl int
-	err := common.RedisPool.Do(radix.Cmd(&ttl, "TTL", RKeyCommandCooldown(userID, cs.FindNameFromContainerChain(cc))))
-	if err != nil {
-		return 0, errors.WithStackIf(err)
+		if left > longest {
+			longest = left
+		}
 	}
 
-	return ttl, nil
+	return longest, nil
+}
+
+// UserScopeCooldownLeft returns the number of seconds before a command can be used again by this user
+func (cs *YAGCommand) UserScopeCooldownLeft(cc []*dcmd.Container, userID int64) (int, error) {
+	return cooldownLeft(cs.Cooldown, RKeyCommandCooldown(userID, cs.FindNameFromContainerChain(cc)))
 }
 
 // GuildScopeCooldownLeft returns the number of seconds before a command can be used again on this server
 func (cs *YAGCommand) GuildScopeCooldownLeft(cc []*dcmd.Container, guildID int64) (int, error) {
-	if cs.GuildScopeCooldown < 1 {
+	return cooldownLeft(cs.GuildScopeCooldown, RKeyCommandCooldownGuild(guildID, cs.FindNameFromContainerChain(cc)))
+}
+
+func cooldownLeft(cooldown int, key string) (int, error) {
+	if cooldown < 1 {
 		return 0, nil
 	}
 
 	var ttl int
-	err := common.RedisPool.Do(radix.Cmd(&ttl, "TTL", RKeyCommandCooldownGuild(guildID, cs.FindNameFromContainerChain(cc))))
+	err := common.RedisPool.Do(radix.Cmd(&ttl, "TTL", key))
 	if err != nil {
 		return 0, errors.WithStackIf(err)
 	}
@@ -882,14 +923,22 @@ func (cs *YAGCommand) GuildScopeCooldownLeft(cc []*dcmd.Container, guildID int64
 	return ttl, nil
 }
 
-// SetCooldowns is a helper that serts both User and Guild cooldown
-func (cs *YAGCommand) SetCooldowns(cc []*dcmd.Container, userID int64, guildID int64) error {
-	err := cs.SetCooldownUser(cc, userID)
+// SetCooldowns is a helper that sets the User, Guild and Channel cooldowns, with the ones from the command override applied
+func (cs *YAGCommand) SetCooldowns(cc []*dcmd.Container, settings *CommandSettings, userID int64, guildID int64, channelID int64) error {
+	cdUser, cdGuild, cdChannel := cs.cooldowns(settings)
+	name := cs.FindNameFromContainerChain(cc)
+
+	err := setCooldown(cdUser, RKeyCommandCooldown(userID, name))
 	if err != nil {
 		return errors.WithStackIf(err)
 	}
 
-	err = cs.SetCooldownGuild(cc, guildID)
+	err = setCooldown(cdGuild, RKeyCommandCooldownGuild(guildID, name))
+	if err != nil {
+		return errors.WithStackIf(err)
+	}
+
+	err = setCooldown(cdChannel, RKeyCommandCooldownChannel(channelID, name))
 	if err != nil {
 		return errors.WithStackIf(err)
 	}
@@ -899,23 +948,21 @@ func (cs *YAGCommand) SetCooldowns(cc []*dcmd.Container, userID int64, guildID i
 
 // SetCooldownUser sets the user scoped cooldown of the command as it's defined in the struct
 func (cs *YAGCommand) SetCooldownUser(cc []*dcmd.Container, userID int64) error {
-	if cs.Cooldown < 1 {
-		return nil
-	}
-	now := time.Now().Unix()
-
-	err := common.RedisPool.Do(radix.FlatCmd(nil, "SET", RKeyCommandCooldown(userID, cs.FindNameFromContainerChain(cc)), now, "EX", cs.Cooldown))
-	return errors.WithStackIf(err)
+	return setCooldown(cs.Cooldown, RKeyCommandCooldown(userID, cs.FindNameFromContainerChain(cc)))
 }
 
 // SetCooldownGuild sets the guild scoped cooldown of the command as it's defined in the struct
 func (cs *YAGCommand) SetCooldownGuild(cc []*dcmd.Container, guildID int64) error {
-	if cs.GuildScopeCooldown < 1 {
+	return setCooldown(cs.GuildScopeCooldown, RKeyCommandCooldownGuild(guildID, cs.FindNameFromContainerChain(cc)))
+}
+
+func setCooldown(cooldown int, key string) error {
+	if cooldown < 1 {
 		return nil
 	}
 
 	now := time.Now().Unix()
-	err := common.RedisPool.Do(radix.FlatCmd(nil, "SET", RKeyCommandCooldownGuild(guildID, cs.FindNameFromContainerChain(cc)), now, "EX", cs.GuildScopeCooldown))
+	err := common.RedisPool.Do(radix.FlatCmd(nil, "SET", key, now, "EX", cooldown))
 	return errors.WithStackIf(err)
 }
 
@@ -0,0 +1,59 @@
+package i18n
+
+import (
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/mediocregopher/radix/v3"
+)
+
+// FeatureFlagHasLanguage is set by the commands plugin on guilds that changed their language
+const FeatureFlagHasLanguage = "commands_has_language"
+
+func guildLanguageKey(guildID int64) string {
+	return "guild_language:" + discordgo.StrID(guildID)
+}
+
+// GetGuildLanguageRedis returns the language set for the guild, or the default language if none is set
+func GetGuildLanguageRedis(guildID int64) (discordgo.Locale, error) {
+	var lang string
+	err := common.RedisPool.Do(radix.Cmd(&lang, "GET", guildLanguageKey(guildID)))
+	if err != nil || lang == "" {
+		return DefaultLanguage, err
+	}
+
+	return discordgo.Locale(lang), nil
+}
+
+// SetGuildLanguage sets the language of the guild, remember to mark the feature flags dirty afterwards
+func SetGuildLanguage(guildID int64, lang discordgo.Locale) error {
+	if lang == DefaultLanguage || lang == "" {
+		return common.RedisPool.Do(radix.Cmd(nil, "DEL", guildLanguageKey(guildID)))
+	}
+
+	return common.RedisPool.Do(radix.Cmd(nil, "SET", guildLanguageKey(guildID), string(lang)))
+}
+
+// GuildLanguage returns the language of the guild, it only hits redis for guilds that changed it
+func GuildLanguage(guildID int64) discordgo.Locale {
+	if guildID == 0 || !featureflags.GuildHasFlagOrLogError(guildID, FeatureFlagHasLanguage) {
+		return DefaultLanguage
+	}
+
+	lang, err := GetGuildLanguageRedis(guildID)
+	if err != nil {
+		logger.WithError(err).WithField("guild", guildID).Error("Failed retrieving guild language")
+	}
+
+	return lang
+}
+
+// GuildT translates msg to the language of the guild
+func GuildT(guildID int64, msg string) string {
+	return T(GuildLanguage(guildID), msg)
+}
+
+// GuildTf translates the format string to the language of the guild and then formats it like fmt.Sprintf
+func GuildTf(guildID int64, format string, args ...interface{}) string {
+	return Tf(GuildLanguage(guildID), format, args...)
+}
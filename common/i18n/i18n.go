@@ -0,0 +1,158 @@
+// Package i18n provides gettext style message catalogs.
+//
+// The english text of a message is used as its id, so anything that has no translation
+// (or is looked up in the default language) is returned as is.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+)
+
+// DefaultLanguage is the language the message ids are written in
+const DefaultLanguage = discordgo.EnglishUS
+
+var (
+	logger = common.GetFixedPrefixLogger("i18n")
+
+	catalogs   = make(map[discordgo.Locale]map[string]string)
+	catalogsMU sync.RWMutex
+)
+
+// RegisterCatalogs loads all the catalogs in dir of fsys, meant to be called with an embedded locales folder when registering a plugin.
+//
+// Every file is named after the discord locale it's for (e.g de.json or es-ES.json)
+// and contains a json object mapping the english text to the translated text.
+// It panics on invalid catalogs since they're part of the binary.
+func RegisterCatalogs(fsys fs.FS, dir string) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		panic("i18n: failed listing catalogs: " + err.Error())
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		file := path.Join(dir, entry.Name())
+		lang := discordgo.Locale(strings.TrimSuffix(entry.Name(), ".json"))
+		if _, ok := discordgo.Locales[lang]; !ok {
+			panic("i18n: catalog " + file + " is not named after a discord locale")
+		}
+
+		raw, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			panic("i18n: failed reading catalog " + file + ": " + err.Error())
+		}
+
+		var messages map[string]string
+		err = json.Unmarshal(raw, &messages)
+		if err != nil {
+			panic("i18n: failed decoding catalog " + file + ": " + err.Error())
+		}
+
+		AddMessages(lang, messages)
+	}
+}
+
+// AddMessages adds translations to the catalog of lang, existing translations are overwritten
+func AddMessages(lang discordgo.Locale, messages map[string]string) {
+	catalogsMU.Lock()
+	defer catalogsMU.Unlock()
+
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = make(map[string]string)
+		catalogs[lang] = catalog
+	}
+
+	for k, v := range messages {
+		if v == "" {
+			continue
+		}
+
+		catalog[k] = v
+	}
+}
+
+// T returns the translation of msg in lang, or msg itself if there is none
+func T(lang discordgo.Locale, msg string) string {
+	if lang == DefaultLanguage || lang == "" || msg == "" {
+		return msg
+	}
+
+	catalogsMU.RLock()
+	translated, ok := catalogs[lang][msg]
+	catalogsMU.RUnlock()
+
+	if !ok {
+		return msg
+	}
+
+	return translated
+}
+
+// Tf translates the format string and then formats it like fmt.Sprintf
+func Tf(lang discordgo.Locale, format string, args ...interface{}) string {
+	return fmt.Sprintf(T(lang, format), args...)
+}
+
+// Localizations returns all the translations of msg, keyed by language, in the form discord wants for application commands
+func Localizations(msg string) map[discordgo.Locale]string {
+	catalogsMU.RLock()
+	defer catalogsMU.RUnlock()
+
+	var result map[discordgo.Locale]string
+	for lang, catalog := range catalogs {
+		translated, ok := catalog[msg]
+		if !ok {
+			continue
+		}
+
+		if result == nil {
+			result = make(map[discordgo.Locale]string)
+		}
+		result[lang] = translated
+	}
+
+	return result
+}
+
+// Languages returns the languages that can be selected, the default language first and the rest sorted by name
+func Languages() []discordgo.Locale {
+	catalogsMU.RLock()
+	result := make([]discordgo.Locale, 0, len(catalogs)+1)
+	for lang := range catalogs {
+		if lang != DefaultLanguage {
+			result = append(result, lang)
+		}
+	}
+	catalogsMU.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].String() < result[j].String()
+	})
+
+	return append([]discordgo.Locale{DefaultLanguage}, result...)
+}
+
+// IsSupported returns true if lang is the default language or there's a catalog for it
+func IsSupported(lang discordgo.Locale) bool {
+	if lang == DefaultLanguage {
+		return true
+	}
+
+	catalogsMU.RLock()
+	_, ok := catalogs[lang]
+	catalogsMU.RUnlock()
+	return ok
+}
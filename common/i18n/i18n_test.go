@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+)
+
+func TestCatalogs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/de.json":   {Data: []byte(`{"Hello": "Hallo", "You have %d reminders": "Du hast %d Erinnerungen", "Empty": ""}`)},
+		"locales/README.md": {Data: []byte("not a catalog")},
+	}
+
+	RegisterCatalogs(fsys, "locales")
+
+	cases := []struct {
+		lang     discordgo.Locale
+		msg      string
+		expected string
+	}{
+		{discordgo.German, "Hello", "Hallo"},
+		{discordgo.German, "Missing", "Missing"},
+		{discordgo.German, "Empty", "Empty"},
+		{discordgo.French, "Hello", "Hello"},
+		{DefaultLanguage, "Hello", "Hello"},
+		{"", "Hello", "Hello"},
+	}
+
+	for _, c := range cases {
+		if got := T(c.lang, c.msg); got != c.expected {
+			t.Errorf("T(%q, %q) = %q, expected %q", c.lang, c.msg, got, c.expected)
+		}
+	}
+
+	if got := Tf(discordgo.German, "You have %d reminders", 5); got != "Du hast 5 Erinnerungen" {
+		t.Errorf("unexpected Tf result: %q", got)
+	}
+
+	localizations := Localizations("Hello")
+	if len(localizations) != 1 || localizations[discordgo.German] != "Hallo" {
+		t.Errorf("unexpected localizations: %v", localizations)
+	}
+
+	if Localizations("Missing") != nil {
+		t.Error("expected no localizations for a message without translations")
+	}
+
+	if !IsSupported(discordgo.German) || !IsSupported(DefaultLanguage) || IsSupported(discordgo.French) {
+		t.Error("unexpected supported languages")
+	}
+
+	langs := Languages()
+	if len(langs) != 2 || langs[0] != DefaultLanguage || langs[1] != discordgo.German {
+		t.Errorf("unexpected languages: %v", langs)
+	}
+}
+
+func TestRegisterCatalogsInvalidName(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a catalog not named after a locale")
+		}
+	}()
+
+	RegisterCatalogs(fstest.MapFS{"locales/german.json": {Data: []byte(`{}`)}}, "locales")
+}
@@ -34,17 +34,25 @@ var _ bot.LateBotInitHandler = (*Plugin)(nil)
 
 // LateBotInit implements bot.LateBotInitHandler
 func (p *Plugin) LateBotInit() {
-	redisBackend := &RedisBackend{
-		pool: common.RedisPool,
-	}
+	backend := defaultStorage()
+	server := NewServer(backend, &DiscordProcessor{})
+	go server.Run()
 
-	server := NewServer(redisBackend, &DiscordProcessor{})
-	redisPubsub := RedisPushServer{
-		pushwork:    server.PushWork,
-		fullRefresh: server.refreshWork,
+	if pgBackend, ok := backend.(*PostgresBackend); ok {
+		pgPoller := &PostgresPushServer{
+			backend:     pgBackend,
+			redis:       NewRedisBackend(common.RedisPool),
+			pushwork:    server.PushWork,
+			fullRefresh: server.refreshWork,
+		}
+		go pgPoller.run()
+	} else {
+		redisPubsub := RedisPushServer{
+			pushwork:    server.PushWork,
+			fullRefresh: server.refreshWork,
+		}
+		go redisPubsub.run()
 	}
-	go server.Run()
-	go redisPubsub.run()
 	p.server = server
 
 	logger.Info("Started mqueue server")
@@ -69,6 +77,11 @@ var (
 		Name: "yagpdb_mqueue_processed_total",
 		Help: "Total mqueue elements processed",
 	}, []string{"source"})
+
+	metricsDeadLetters = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "yagpdb_mqueue_dead_letters_total",
+		Help: "Total mqueue elements moved to the dead letter queue",
+	}, []string{"source"})
 )
 
 func handleWebhookSessionRatelimit(s *discordgo.Session, r *discordgo.RateLimit) {
@@ -0,0 +1,110 @@
+package mqueue
+
+import (
+	"errors"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common/config"
+)
+
+var (
+	confMaxAttempts    = config.RegisterOption("yagpdb.mqueue.max_attempts", "Default max number of attempts to send a mqueue item before it's moved to the dead letter queue", 10)
+	confMaxDeadLetters = config.RegisterOption("yagpdb.mqueue.max_dead_letters", "Max number of items kept in the dead letter queue, the oldest ones are dropped first", 5000)
+
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+)
+
+// DeadLetter is a queued element that failed permanently or ran out of attempts
+type DeadLetter struct {
+	Elem     *QueuedElement
+	Error    string
+	Attempts int
+	FailedAt time.Time
+}
+
+// RetryPolicy controls how many times an element is attempted before it's dead lettered
+type RetryPolicy struct {
+	MaxAttempts int
+	RetryDelay  time.Duration
+}
+
+// PluginWithRetryPolicy can be implemented by sources for a custom retry policy
+type PluginWithRetryPolicy interface {
+	MqueueRetryPolicy() RetryPolicy
+}
+
+func defaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: confMaxAttempts.GetInt(),
+		RetryDelay:  time.Second,
+	}
+}
+
+// sourceRetryPolicy returns the retry policy of the source, with the defaults filled in
+func sourceRetryPolicy(source string) RetryPolicy {
+	def := defaultRetryPolicy()
+
+	s, ok := sources[source]
+	if !ok {
+		return def
+	}
+
+	withPolicy, ok := s.(PluginWithRetryPolicy)
+	if !ok {
+		return def
+	}
+
+	policy := withPolicy.MqueueRetryPolicy()
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = def.MaxAttempts
+	}
+	if policy.RetryDelay <= 0 {
+		policy.RetryDelay = def.RetryDelay
+	}
+
+	return policy
+}
+
+// GetDeadLetters returns the dead letters, newest first, and the total number of them
+func GetDeadLetters(offset, limit int) ([]*DeadLetter, int, error) {
+	storage := defaultStorage()
+
+	total, err := storage.CountDeadLetters()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	letters, err := storage.GetDeadLetters(offset, limit)
+	return letters, total, err
+}
+
+// ReplayDeadLetter removes the dead letter and queues the element again
+func ReplayDeadLetter(id int64) (*DeadLetter, error) {
+	return replayDeadLetter(defaultStorage(), id)
+}
+
+func replayDeadLetter(storage Storage, id int64) (*DeadLetter, error) {
+	dl, err := storage.GetDeadLetter(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = storage.DelDeadLetter(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = storage.AppendItem(dl.Elem)
+	if err != nil {
+		// put it back so it's not lost
+		storage.AddDeadLetter(dl)
+		return nil, err
+	}
+
+	return dl, nil
+}
+
+// DeleteDeadLetter drops the dead letter without sending it
+func DeleteDeadLetter(id int64) error {
+	return defaultStorage().DelDeadLetter(id)
+}
@@ -1,15 +1,18 @@
 package mqueue
 
 import (
+	"context"
 	"slices"
 	"time"
 
 	"emperror.dev/errors"
 	"github.com/botlabs-gg/yagpdb/v2/bot"
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/tracing"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type DiscordProcessor struct {
@@ -18,11 +21,25 @@ type DiscordProcessor struct {
 func (d *DiscordProcessor) ProcessItem(resp chan *workResult, wi *workItem) {
 	metricsProcessed.With(prometheus.Labels{"source": wi.Elem.Source}).Inc()
 
+	ctx, span := tracing.Start(tracing.Extract(wi.Elem.TraceContext), "mqueue "+wi.Elem.Source,
+		tracing.GuildID(wi.Elem.GuildID),
+		attribute.Int64("mqueue.id", wi.Elem.ID),
+		attribute.String("mqueue.source_item_id", wi.Elem.SourceItemID))
+	if !wi.Elem.CreatedAt.IsZero() {
+		// how long it waited in the queue, to tell slow sending apart from slow feeds
+		span.SetAttributes(attribute.Int64("mqueue.queued_ms", time.Since(wi.Elem.CreatedAt).Milliseconds()))
+	}
+
 	retry := false
+	var resultErr error
 	defer func() {
+		span.SetAttributes(attribute.Bool("mqueue.retry", retry))
+		tracing.End(span, resultErr)
+
 		resp <- &workResult{
 			item:  wi,
 			retry: retry,
+			err:   resultErr,
 		}
 	}()
 
@@ -30,9 +47,9 @@ func (d *DiscordProcessor) ProcessItem(resp chan *workResult, wi *workItem) {
 
 	var err error
 	if wi.Elem.UseWebhook {
-		err = trySendWebhook(queueLogger, wi.Elem)
+		err = trySendWebhook(ctx, queueLogger, wi.Elem)
 	} else {
-		err = trySendNormal(queueLogger, wi.Elem)
+		err = trySendNormal(ctx, queueLogger, wi.Elem)
 	}
 
 	if err == nil {
@@ -41,8 +58,9 @@ func (d *DiscordProcessor) ProcessItem(resp chan *workResult, wi *workItem) {
 
 	if e, ok := errors.Cause(err).(*discordgo.RESTError); ok {
 		if (e.Response != nil && e.Response.StatusCode >= 400 && e.Response.StatusCode < 500) || (e.Message != nil && e.Message.Code != 0) {
-			if source, ok := sources[wi.Elem.Source]; ok {
-				maybeDisableFeed(source, wi.Elem, e)
+			// the feed getting disabled takes care of it, otherwise keep it around for inspection
+			if source, ok := sources[wi.Elem.Source]; !ok || !maybeDisableFeed(source, wi.Elem, e) {
+				resultErr = err
 			}
 
 			return
@@ -60,13 +78,14 @@ func (d *DiscordProcessor) ProcessItem(resp chan *workResult, wi *workItem) {
 		}
 	}
 
+	resultErr = err
 	if c, _ := common.DiscordError(err); c != 0 {
 		return
 	}
 
 	retry = true
 	queueLogger.Warn("Non-discord related error when sending message, retrying. ", err)
-	time.Sleep(time.Second)
+	time.Sleep(sourceRetryPolicy(wi.Elem.Source).RetryDelay)
 
 }
 
@@ -80,26 +99,29 @@ var disableOnError = []int{
 	220001, // webhook points to a forum channel
 }
 
-func maybeDisableFeed(source PluginWithSourceDisabler, elem *QueuedElement, err *discordgo.RESTError) {
+// maybeDisableFeed disables the feed if the error is one that won't go away by itself, returns true if it was disabled
+func maybeDisableFeed(source PluginWithSourceDisabler, elem *QueuedElement, err *discordgo.RESTError) bool {
 	l := logger.WithError(err).WithField("source", elem.Source).WithField("sourceid", elem.SourceItemID).WithField("guild_id", elem.GuildID)
 	if err.Message == nil || !slices.Contains(disableOnError, err.Message.Code) {
 		l.Error("error sending mqueue message")
-		return
+		return false
 	}
 	l.Warn("disabling feed item")
 	source.DisableFeed(elem, err)
+	return true
 }
 
-func trySendNormal(l *logrus.Entry, elem *QueuedElement) (err error) {
+func trySendNormal(ctx context.Context, l *logrus.Entry, elem *QueuedElement) (err error) {
+	session := common.BotSession.WithContext(ctx)
 	send := func(msg *discordgo.MessageSend) error {
-		m, err := common.BotSession.ChannelMessageSendComplex(elem.ChannelID, msg)
+		m, err := session.ChannelMessageSendComplex(elem.ChannelID, msg)
 		if err != nil {
 			logrus.WithError(err).Errorf("Failed sending mqueue message %#v", msg)
 			return err
 		}
 
 		if elem.PublishAnnouncement {
-			_, err = common.BotSession.ChannelMessageCrosspost(elem.ChannelID, m.ID)
+			_, err = session.ChannelMessageCrosspost(elem.ChannelID, m.ID)
 		}
 		return err
 	}
@@ -127,7 +149,9 @@ func trySendNormal(l *logrus.Entry, elem *QueuedElement) (err error) {
 
 var errGuildNotFound = errors.New("Guild not found")
 
-func trySendWebhook(l *logrus.Entry, elem *QueuedElement) (err error) {
+func trySendWebhook(ctx context.Context, l *logrus.Entry, elem *QueuedElement) (err error) {
+	whSession := webhookSession.WithContext(ctx)
+
 	// Helper to fetch or create the webhook
 	getWebhook := func() (*webhook, string, error) {
 		avatar := ""
@@ -167,7 +191,7 @@ func trySendWebhook(l *logrus.Entry, elem *QueuedElement) (err error) {
 			Flags:           elem.MessageSend.Flags,
 			AllowedMentions: &elem.MessageSend.AllowedMentions,
 		}
-		_, err = webhookSession.WebhookExecuteComplex(wh.ID, wh.Token, true, params)
+		_, err = whSession.WebhookExecuteComplex(wh.ID, wh.Token, true, params)
 		if code, _ := common.DiscordError(err); code == discordgo.ErrCodeUnknownWebhook {
 			webhookCache.Delete(elem.ChannelID)
 			if delErr := deleteWebhookRow(elem.ChannelID, elem.Source); delErr != nil {
@@ -201,7 +225,7 @@ func trySendWebhook(l *logrus.Entry, elem *QueuedElement) (err error) {
 		webhookParams.Embeds = []*discordgo.MessageEmbed{elem.MessageEmbed}
 	}
 
-	err = webhookSession.WebhookExecute(wh.ID, wh.Token, true, webhookParams)
+	err = whSession.WebhookExecute(wh.ID, wh.Token, true, webhookParams)
 	if code, _ := common.DiscordError(err); code == discordgo.ErrCodeUnknownWebhook {
 		// webhook got deleted, drop our stale records and try again with a fresh one
 		webhookCache.Delete(elem.ChannelID)
@@ -212,7 +236,7 @@ func trySendWebhook(l *logrus.Entry, elem *QueuedElement) (err error) {
 		if err != nil {
 			return err
 		}
-		err = webhookSession.WebhookExecute(wh.ID, wh.Token, true, webhookParams)
+		err = whSession.WebhookExecute(wh.ID, wh.Token, true, webhookParams)
 	}
 	return err
 }
@@ -42,6 +42,9 @@ type QueuedElement struct {
 	Priority int
 
 	CreatedAt time.Time
+
+	// The trace this was queued as part of, see QueueMessageContext
+	TraceContext map[string]string `json:",omitempty"`
 }
 
 type webhook struct {
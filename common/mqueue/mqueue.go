@@ -90,4 +90,11 @@ type Storage interface {
 	AppendItem(elem *QueuedElement) error
 	DelItem(elem *workItem) error
 	NextID() (int64, error)
+
+	// Dead letters are keyed by the ID of the queued element
+	AddDeadLetter(dl *DeadLetter) error
+	GetDeadLetters(offset, limit int) ([]*DeadLetter, error)
+	CountDeadLetters() (int, error)
+	GetDeadLetter(id int64) (*DeadLetter, error)
+	DelDeadLetter(id int64) error
 }
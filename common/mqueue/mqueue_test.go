@@ -1,25 +1,60 @@
 package mqueue
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/config"
+)
+
+var (
+	redisAvailable    bool
+	postgresAvailable bool
 )
 
 func TestMain(m *testing.M) {
+	config.Load()
+
 	if err := common.InitTestRedis(); err != nil {
-		fmt.Printf("Failed redis init, not running tests... %v \n", err)
-		return
+		fmt.Printf("Failed redis init, only running tests not using redis... %v \n", err)
+	} else {
+		redisAvailable = true
+	}
+
+	// set YAGPDB_TEST_DB to run the postgres tests
+	common.InitTest()
+	if common.PQ != nil {
+		if _, err := common.PQ.Exec(DBSchema); err != nil {
+			fmt.Printf("Failed postgres init, not running postgres tests... %v \n", err)
+		} else {
+			postgresAvailable = true
+		}
 	}
 
 	os.Exit(m.Run())
 }
 
+func requireRedis(t *testing.T) {
+	if !redisAvailable {
+		t.Skip("redis not available")
+	}
+}
+
+func requirePostgres(t *testing.T) {
+	if !postgresAvailable {
+		t.Skip("postgres not available")
+	}
+}
+
 func TestMqueuePubsub(t *testing.T) {
+	requireRedis(t)
+
 	var wg sync.WaitGroup
 	fakeProcessor := &FakeProcessor{
 		retry: false,
@@ -71,6 +106,8 @@ func TestMqueuePubsub(t *testing.T) {
 }
 
 func TestMqueueRefresh(t *testing.T) {
+	requireRedis(t)
+
 	var wg sync.WaitGroup
 	fakeProcessor := &FakeProcessor{
 		retry: false,
@@ -131,9 +168,81 @@ func TestMqueueRefresh(t *testing.T) {
 	stopWG.Wait()
 }
 
+func TestMqueueDeadLetter(t *testing.T) {
+	RegisterSource("test_dlq", &fakeSource{policy: RetryPolicy{MaxAttempts: 3}})
+	defer delete(sources, "test_dlq")
+
+	var wg sync.WaitGroup
+	fakeProcessor := &FakeProcessor{
+		retry: true,
+		err:   errors.New("send failed"),
+		onHit: func(wi *workItem) {
+			wg.Done()
+		},
+	}
+
+	backend := NewFakeStorage()
+	server := NewServer(backend, fakeProcessor)
+	server.forceAllShards = true
+	go server.Run()
+
+	elem := &QueuedElement{
+		ID:         1,
+		ChannelID:  100,
+		GuildID:    10,
+		Source:     "test_dlq",
+		MessageStr: "test message",
+	}
+	if err := backend.AppendItem(elem); err != nil {
+		t.Fatal(err)
+	}
+
+	wg.Add(3)
+	server.PushWork <- &workItem{Elem: elem}
+	wg.Wait()
+
+	// stopping the server makes sure the last result was handled
+	var stopWG sync.WaitGroup
+	stopWG.Add(1)
+	server.Stop <- &stopWG
+	stopWG.Wait()
+
+	if n, _ := backend.CountDeadLetters(); n != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", n)
+	}
+
+	dl, err := backend.GetDeadLetter(elem.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dl.Attempts != 3 || dl.Error != "send failed" {
+		t.Errorf("unexpected dead letter: attempts %d, error %q", dl.Attempts, dl.Error)
+	}
+
+	if queue, _ := backend.GetFullQueue(); len(queue) != 0 {
+		t.Errorf("expected the queue to be empty, got %d items", len(queue))
+	}
+
+	// replaying puts it back in the queue
+	_, err = replayDeadLetter(backend, elem.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if queue, _ := backend.GetFullQueue(); len(queue) != 1 {
+		t.Errorf("expected the replayed item in the queue, got %d items", len(queue))
+	}
+
+	if _, err = backend.GetDeadLetter(elem.ID); err != ErrDeadLetterNotFound {
+		t.Errorf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+}
+
 type FakeProcessor struct {
 	onHit func(wi *workItem)
 	retry bool
+	err   error
 }
 
 func (f *FakeProcessor) ProcessItem(resp chan *workResult, wi *workItem) {
@@ -141,5 +250,122 @@ func (f *FakeProcessor) ProcessItem(resp chan *workResult, wi *workItem) {
 	resp <- &workResult{
 		item:  wi,
 		retry: f.retry,
+		err:   f.err,
+	}
+}
+
+type fakeSource struct {
+	policy RetryPolicy
+}
+
+func (f *fakeSource) DisableFeed(elem *QueuedElement, err error) {}
+
+func (f *fakeSource) MqueueRetryPolicy() RetryPolicy {
+	return f.policy
+}
+
+var _ Storage = (*FakeStorage)(nil)
+
+// FakeStorage is a in memory storage for tests
+type FakeStorage struct {
+	mu          sync.Mutex
+	queue       []*workItem
+	deadLetters map[int64]*DeadLetter
+	lastID      int64
+}
+
+func NewFakeStorage() *FakeStorage {
+	return &FakeStorage{
+		deadLetters: make(map[int64]*DeadLetter),
 	}
 }
+
+func (f *FakeStorage) GetFullQueue() ([]*workItem, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*workItem(nil), f.queue...), nil
+}
+
+func (f *FakeStorage) AppendItem(elem *QueuedElement) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queue = append(f.queue, &workItem{Elem: elem})
+	return nil
+}
+
+func (f *FakeStorage) DelItem(item *workItem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queue = removeFromWorkSlice(f.queue, item)
+	return nil
+}
+
+func (f *FakeStorage) NextID() (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastID++
+	return f.lastID, nil
+}
+
+func (f *FakeStorage) AddDeadLetter(dl *DeadLetter) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.deadLetters[dl.Elem.ID] = dl
+	return nil
+}
+
+func (f *FakeStorage) GetDeadLetters(offset, limit int) ([]*DeadLetter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]*DeadLetter, 0, len(f.deadLetters))
+	for _, v := range f.deadLetters {
+		result = append(result, v)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FailedAt.After(result[j].FailedAt)
+	})
+
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+func (f *FakeStorage) CountDeadLetters() (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.deadLetters), nil
+}
+
+func (f *FakeStorage) GetDeadLetter(id int64) (*DeadLetter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	dl, ok := f.deadLetters[id]
+	if !ok {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	return dl, nil
+}
+
+func (f *FakeStorage) DelDeadLetter(id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.deadLetters, id)
+	return nil
+}
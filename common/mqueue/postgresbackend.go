@@ -0,0 +1,355 @@
+package mqueue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	_ Storage         = (*PostgresBackend)(nil)
+	_ ClaimingStorage = (*PostgresBackend)(nil)
+)
+
+// claimLease is how long a node can hold on to an item before other nodes are allowed to claim it,
+// this is only relevant if a node dies while sending something
+const claimLease = time.Minute * 5
+
+// ClaimingStorage is implemented by storages shared between nodes that can process the same items,
+// an item is only processed if it was successfully claimed
+type ClaimingStorage interface {
+	ClaimItem(item *workItem) (bool, error)
+}
+
+// PostgresBackend is a durable storage, work is claimed per item so several nodes can share the queue
+type PostgresBackend struct {
+	db      *sql.DB
+	claimID string
+}
+
+func NewPostgresBackend(db *sql.DB) *PostgresBackend {
+	hostname, _ := os.Hostname()
+	return &PostgresBackend{
+		db:      db,
+		claimID: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+func (pb *PostgresBackend) GetFullQueue() ([]*workItem, error) {
+	const q = `SELECT data FROM mqueue_items WHERE claimed_at IS NULL OR claimed_by = $1 OR claimed_at < $2 ORDER BY id`
+	rows, err := pb.db.Query(q, pb.claimID, time.Now().Add(-claimLease))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanWorkItems(rows)
+}
+
+// getItemsAfter returns the items added after the provided id
+func (pb *PostgresBackend) getItemsAfter(id int64) ([]*workItem, error) {
+	rows, err := pb.db.Query(`SELECT data FROM mqueue_items WHERE id > $1 ORDER BY id LIMIT 1000`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanWorkItems(rows)
+}
+
+func scanWorkItems(rows *sql.Rows) ([]*workItem, error) {
+	defer rows.Close()
+
+	var result []*workItem
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+
+		var dec QueuedElement
+		err := json.Unmarshal(raw, &dec)
+		if err != nil {
+			logger.WithError(err).Error("Failed decoding queued mqueue element from postgres")
+			continue
+		}
+
+		result = append(result, &workItem{
+			Elem: &dec,
+			Raw:  raw,
+		})
+	}
+
+	return result, rows.Err()
+}
+
+func (pb *PostgresBackend) AppendItem(elem *QueuedElement) error {
+	serialized, err := json.Marshal(elem)
+	if err != nil {
+		return err
+	}
+
+	// ids come from mqueue_id_seq, so a conflict here is a bug and should fail loudly
+	const q = `INSERT INTO mqueue_items (id, guild_id, data, created_at) VALUES ($1, $2, $3, $4)`
+	_, err = pb.db.Exec(q, elem.ID, elem.GuildID, string(serialized), time.Now())
+	return err
+}
+
+// appendRedisItem inserts an item moved over from redis under a new id, the redis id is only kept to skip items
+// that were already moved, for example if a node died before removing them from redis
+func (pb *PostgresBackend) appendRedisItem(elem *QueuedElement) error {
+	var exists bool
+	err := pb.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM mqueue_items WHERE redis_id = $1 AND created_at = $2)`, elem.ID, elem.CreatedAt).Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+
+	moved := *elem
+	moved.ID, err = pb.NextID()
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(&moved)
+	if err != nil {
+		return err
+	}
+
+	const q = `INSERT INTO mqueue_items (id, redis_id, guild_id, data, created_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err = pb.db.Exec(q, moved.ID, elem.ID, elem.GuildID, string(serialized), elem.CreatedAt)
+	return err
+}
+
+func (pb *PostgresBackend) DelItem(item *workItem) error {
+	_, err := pb.db.Exec(`DELETE FROM mqueue_items WHERE id = $1`, item.Elem.ID)
+	return err
+}
+
+func (pb *PostgresBackend) NextID() (next int64, err error) {
+	err = pb.db.QueryRow(`SELECT nextval('mqueue_id_seq')`).Scan(&next)
+	return
+}
+
+// ClaimItem implements ClaimingStorage, rows locked by other nodes are skipped instead of waited on
+func (pb *PostgresBackend) ClaimItem(item *workItem) (bool, error) {
+	const q = `UPDATE mqueue_items SET claimed_by = $1, claimed_at = $2
+WHERE id = (
+	SELECT id FROM mqueue_items
+	WHERE id = $3 AND (claimed_at IS NULL OR claimed_by = $1 OR claimed_at < $4)
+	FOR UPDATE SKIP LOCKED
+)`
+
+	now := time.Now()
+	res, err := pb.db.Exec(q, pb.claimID, now, item.Elem.ID, now.Add(-claimLease))
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (pb *PostgresBackend) AddDeadLetter(dl *DeadLetter) error {
+	serialized, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+
+	const q = `INSERT INTO mqueue_dead_letters (id, data, failed_at) VALUES ($1, $2, $3)
+ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data, failed_at = EXCLUDED.failed_at`
+	_, err = pb.db.Exec(q, dl.Elem.ID, string(serialized), dl.FailedAt)
+	if err != nil {
+		return err
+	}
+
+	max := confMaxDeadLetters.GetInt()
+	if max < 1 {
+		return nil
+	}
+
+	_, err = pb.db.Exec(`DELETE FROM mqueue_dead_letters WHERE id IN (SELECT id FROM mqueue_dead_letters ORDER BY failed_at DESC OFFSET $1)`, max)
+	return err
+}
+
+func (pb *PostgresBackend) GetDeadLetters(offset, limit int) ([]*DeadLetter, error) {
+	rows, err := pb.db.Query(`SELECT data FROM mqueue_dead_letters ORDER BY failed_at DESC, id DESC OFFSET $1 LIMIT $2`, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*DeadLetter
+	for rows.Next() {
+		var raw []byte
+		if err = rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+
+		var dec DeadLetter
+		err = json.Unmarshal(raw, &dec)
+		if err != nil {
+			logger.WithError(err).Error("Failed decoding mqueue dead letter")
+			continue
+		}
+
+		result = append(result, &dec)
+	}
+
+	return result, rows.Err()
+}
+
+func (pb *PostgresBackend) CountDeadLetters() (count int, err error) {
+	err = pb.db.QueryRow(`SELECT count(*) FROM mqueue_dead_letters`).Scan(&count)
+	return
+}
+
+func (pb *PostgresBackend) GetDeadLetter(id int64) (*DeadLetter, error) {
+	var raw []byte
+	err := pb.db.QueryRow(`SELECT data FROM mqueue_dead_letters WHERE id = $1`, id).Scan(&raw)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+
+	var dec DeadLetter
+	err = json.Unmarshal(raw, &dec)
+	return &dec, err
+}
+
+func (pb *PostgresBackend) DelDeadLetter(id int64) error {
+	_, err := pb.db.Exec(`DELETE FROM mqueue_dead_letters WHERE id = $1`, id)
+	return err
+}
+
+// PostgresPushServer polls postgres for new items, postgres has no cheap pubsub we can use with the shared pool
+type PostgresPushServer struct {
+	backend     *PostgresBackend
+	redis       *RedisBackend
+	pushwork    chan *workItem
+	fullRefresh chan bool
+}
+
+func (pp *PostgresPushServer) run() {
+	pp.drainRedis()
+
+	var lastID int64
+	err := pp.backend.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM mqueue_items`).Scan(&lastID)
+	if err != nil {
+		logger.WithError(err).Error("Failed retrieving last mqueue id")
+	}
+
+	pp.fullRefresh <- true
+
+	pollTicker := time.NewTicker(time.Second)
+	defer pollTicker.Stop()
+
+	// ids are handed out before the items are inserted so an item can show up after one with a higher id,
+	// the periodic full refresh picks those up, along with items from nodes that died while sending them
+	refreshTicker := time.NewTicker(time.Minute)
+	defer refreshTicker.Stop()
+
+	for {
+		select {
+		case <-refreshTicker.C:
+			pp.drainRedis()
+			pp.fullRefresh <- true
+		case <-pollTicker.C:
+			items, err := pp.backend.getItemsAfter(lastID)
+			if err != nil {
+				logger.WithError(err).Error("Failed polling postgres mqueue")
+				continue
+			}
+
+			for _, v := range items {
+				lastID = v.Elem.ID
+				pp.pushwork <- v
+			}
+		}
+	}
+}
+
+// drainRedis moves the items left in redis by nodes still using it into postgres
+func (pp *PostgresPushServer) drainRedis() {
+	if pp.redis == nil {
+		return
+	}
+
+	n, err := DrainRedisQueue(pp.redis, pp.backend)
+	if err != nil {
+		logger.WithError(err).Error("Failed draining redis mqueue into postgres")
+	} else if n > 0 {
+		logger.Infof("Moved %d mqueue items from redis to postgres", n)
+	}
+}
+
+// DrainRedisQueue moves all the queued items and dead letters from redis into postgres, they're given new ids
+// as the redis and postgres ids can overlap. Items are only removed from redis after they were inserted,
+// and only one node drains at a time, so an item is never lost or moved twice.
+func DrainRedisQueue(from *RedisBackend, to *PostgresBackend) (int, error) {
+	ctx := context.Background()
+	conn, err := to.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext('mqueue_redis_drain'))`).Scan(&locked)
+	if err != nil || !locked {
+		// another node is already draining
+		return 0, err
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock(hashtext('mqueue_redis_drain'))`)
+
+	items, err := from.GetFullQueue()
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, v := range items {
+		err = to.appendRedisItem(v.Elem)
+		if err != nil {
+			return moved, err
+		}
+
+		err = from.DelItem(v)
+		if err != nil {
+			return moved, err
+		}
+
+		moved++
+	}
+
+	for {
+		letters, err := from.GetDeadLetters(0, 100)
+		if err != nil || len(letters) < 1 {
+			return moved, err
+		}
+
+		for _, v := range letters {
+			redisID := v.Elem.ID
+
+			dl := *v
+			elem := *v.Elem
+			dl.Elem = &elem
+			dl.Elem.ID, err = to.NextID()
+			if err != nil {
+				return moved, err
+			}
+
+			err = to.AddDeadLetter(&dl)
+			if err != nil {
+				return moved, err
+			}
+
+			err = from.DelDeadLetter(redisID)
+			if err != nil {
+				return moved, err
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package mqueue
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+)
+
+func clearPostgresQueue(t *testing.T) {
+	_, err := common.PQ.Exec("DELETE FROM mqueue_items; DELETE FROM mqueue_dead_letters;")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresBackend(t *testing.T) {
+	requirePostgres(t)
+	clearPostgresQueue(t)
+
+	var wg sync.WaitGroup
+	fakeProcessor := &FakeProcessor{
+		retry: false,
+		onHit: func(wi *workItem) {
+			t.Log("hit process")
+			wg.Done()
+		},
+	}
+
+	backend := NewPostgresBackend(common.PQ)
+	producer := &Producer{backend: backend}
+
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		if err := producer.QueueMessage(&QueuedElement{
+			ChannelID:  100,
+			GuildID:    10,
+			Source:     "test",
+			MessageStr: "test message",
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := NewServer(backend, fakeProcessor)
+	server.forceAllShards = true
+	go server.Run()
+
+	poller := &PostgresPushServer{
+		backend:     backend,
+		pushwork:    server.PushWork,
+		fullRefresh: server.refreshWork,
+	}
+	go poller.run()
+
+	t.Log("waiting for process")
+	wg.Wait()
+
+	var stopWG sync.WaitGroup
+	stopWG.Add(1)
+	server.Stop <- &stopWG
+	stopWG.Wait()
+
+	queue, err := backend.GetFullQueue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(queue) != 0 {
+		t.Errorf("expected the queue to be empty, got %d items", len(queue))
+	}
+}
+
+func TestPostgresClaimItem(t *testing.T) {
+	requirePostgres(t)
+	clearPostgresQueue(t)
+
+	nodeA := NewPostgresBackend(common.PQ)
+	nodeB := NewPostgresBackend(common.PQ)
+	nodeB.claimID += "-b"
+
+	if err := (&Producer{backend: nodeA}).QueueMessage(&QueuedElement{
+		ChannelID:  100,
+		GuildID:    10,
+		Source:     "test",
+		MessageStr: "test message",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	queue, err := nodeA.GetFullQueue()
+	if err != nil || len(queue) != 1 {
+		t.Fatalf("expected 1 item in the queue, got %d (%v)", len(queue), err)
+	}
+
+	if claimed, err := nodeA.ClaimItem(queue[0]); err != nil || !claimed {
+		t.Fatalf("node a failed claiming: %v", err)
+	}
+
+	if claimed, err := nodeB.ClaimItem(queue[0]); err != nil || claimed {
+		t.Fatalf("node b claimed an item claimed by node a: %v", err)
+	}
+
+	// retries keep the claim
+	if claimed, err := nodeA.ClaimItem(queue[0]); err != nil || !claimed {
+		t.Fatalf("node a failed claiming again: %v", err)
+	}
+
+	if queue, _ = nodeB.GetFullQueue(); len(queue) != 0 {
+		t.Errorf("expected claimed items to be hidden from node b, got %d", len(queue))
+	}
+}
+
+func TestDrainRedisQueue(t *testing.T) {
+	requireRedis(t)
+	requirePostgres(t)
+	clearPostgresQueue(t)
+
+	redisBackend := NewRedisBackend(common.RedisPool)
+	pgBackend := NewPostgresBackend(common.PQ)
+
+	if err := (&Producer{backend: redisBackend}).QueueMessage(&QueuedElement{
+		ChannelID:  100,
+		GuildID:    10,
+		Source:     "test",
+		MessageStr: "test message",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	redisQueue, err := redisBackend.GetFullQueue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := DrainRedisQueue(redisBackend, pgBackend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != len(redisQueue) {
+		t.Errorf("expected %d items moved, got %d", len(redisQueue), n)
+	}
+
+	if left, _ := redisBackend.GetFullQueue(); len(left) != 0 {
+		t.Errorf("expected redis to be drained, %d items left", len(left))
+	}
+
+	pgQueue, err := pgBackend.GetFullQueue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pgQueue) != len(redisQueue) {
+		t.Errorf("expected %d items in postgres, got %d", len(redisQueue), len(pgQueue))
+	}
+
+	// items that were already moved are skipped if they're still in redis
+	for _, v := range redisQueue {
+		if err = pgBackend.appendRedisItem(v.Elem); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if pgQueue, _ = pgBackend.GetFullQueue(); len(pgQueue) != len(redisQueue) {
+		t.Errorf("expected %d items in postgres after moving them again, got %d", len(redisQueue), len(pgQueue))
+	}
+}
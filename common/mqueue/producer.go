@@ -1,10 +1,14 @@
 package mqueue
 
 import (
+	"context"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/config"
+	"github.com/botlabs-gg/yagpdb/v2/common/tracing"
 )
 
 type Producer struct {
@@ -25,19 +29,42 @@ func (p *Producer) QueueMessage(elem *QueuedElement) error {
 }
 
 var (
+	confStorage = config.RegisterOption("yagpdb.mqueue.storage", "Where the mqueue is stored, redis or postgres, items left in redis are moved over when switching to postgres", "redis")
+
 	producerOnce     sync.Once
 	standardProducer *Producer
+	standardStorage  Storage
 )
 
-// QueueMessage queues a message in the message queue
-func QueueMessage(elem *QueuedElement) error {
+func usePostgres() bool {
+	return strings.EqualFold(strings.TrimSpace(confStorage.GetString()), "postgres")
+}
+
+// defaultStorage returns the configured storage, it's decided once on first use
+func defaultStorage() Storage {
 	producerOnce.Do(func() {
+		if usePostgres() {
+			standardStorage = NewPostgresBackend(common.PQ)
+		} else {
+			standardStorage = NewRedisBackend(common.RedisPool)
+		}
+
 		standardProducer = &Producer{
-			backend: &RedisBackend{
-				pool: common.RedisPool,
-			},
+			backend: standardStorage,
 		}
 	})
 
+	return standardStorage
+}
+
+// QueueMessage queues a message in the message queue
+func QueueMessage(elem *QueuedElement) error {
+	defaultStorage()
 	return standardProducer.QueueMessage(elem)
 }
+
+// QueueMessageContext queues a message in the message queue, sending it is traced as part of the trace in ctx
+func QueueMessageContext(ctx context.Context, elem *QueuedElement) error {
+	elem.TraceContext = tracing.Inject(ctx)
+	return QueueMessage(elem)
+}
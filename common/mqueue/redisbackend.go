@@ -2,6 +2,7 @@ package mqueue
 
 import (
 	"encoding/json"
+	"strconv"
 
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/mediocregopher/radix/v3"
@@ -117,3 +118,112 @@ func (rp *RedisPushServer) run() {
 		}
 	}
 }
+
+const (
+	deadLettersKey      = "mqueue_dead_letters"
+	deadLettersIndexKey = "mqueue_dead_letters_idx"
+)
+
+func (rb *RedisBackend) AddDeadLetter(dl *DeadLetter) error {
+	serialized, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+
+	id := strconv.FormatInt(dl.Elem.ID, 10)
+	err = rb.pool.Do(radix.Cmd(nil, "HSET", deadLettersKey, id, string(serialized)))
+	if err != nil {
+		return err
+	}
+
+	err = rb.pool.Do(radix.FlatCmd(nil, "ZADD", deadLettersIndexKey, dl.FailedAt.Unix(), id))
+	if err != nil {
+		return err
+	}
+
+	return rb.trimDeadLetters()
+}
+
+// trimDeadLetters drops the oldest dead letters above the configured max
+func (rb *RedisBackend) trimDeadLetters() error {
+	max := confMaxDeadLetters.GetInt()
+	if max < 1 {
+		return nil
+	}
+
+	var overflow []string
+	err := rb.pool.Do(radix.FlatCmd(&overflow, "ZRANGE", deadLettersIndexKey, 0, -(max + 1)))
+	if err != nil || len(overflow) < 1 {
+		return err
+	}
+
+	err = rb.pool.Do(radix.Cmd(nil, "ZREM", append([]string{deadLettersIndexKey}, overflow...)...))
+	if err != nil {
+		return err
+	}
+
+	return rb.pool.Do(radix.Cmd(nil, "HDEL", append([]string{deadLettersKey}, overflow...)...))
+}
+
+func (rb *RedisBackend) GetDeadLetters(offset, limit int) ([]*DeadLetter, error) {
+	var ids []string
+	err := rb.pool.Do(radix.FlatCmd(&ids, "ZREVRANGE", deadLettersIndexKey, offset, offset+limit-1))
+	if err != nil || len(ids) < 1 {
+		return nil, err
+	}
+
+	var raw [][]byte
+	err = rb.pool.Do(radix.Cmd(&raw, "HMGET", append([]string{deadLettersKey}, ids...)...))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*DeadLetter, 0, len(raw))
+	for _, v := range raw {
+		if len(v) < 1 {
+			continue
+		}
+
+		var dec DeadLetter
+		err = json.Unmarshal(v, &dec)
+		if err != nil {
+			logger.WithError(err).Error("Failed decoding mqueue dead letter")
+			continue
+		}
+
+		result = append(result, &dec)
+	}
+
+	return result, nil
+}
+
+func (rb *RedisBackend) CountDeadLetters() (count int, err error) {
+	err = rb.pool.Do(radix.Cmd(&count, "ZCARD", deadLettersIndexKey))
+	return
+}
+
+func (rb *RedisBackend) GetDeadLetter(id int64) (*DeadLetter, error) {
+	var raw []byte
+	mn := radix.MaybeNil{Rcv: &raw}
+	err := rb.pool.Do(radix.FlatCmd(&mn, "HGET", deadLettersKey, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if mn.Nil {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	var dec DeadLetter
+	err = json.Unmarshal(raw, &dec)
+	return &dec, err
+}
+
+func (rb *RedisBackend) DelDeadLetter(id int64) error {
+	err := rb.pool.Do(radix.FlatCmd(nil, "HDEL", deadLettersKey, id))
+	if err != nil {
+		return err
+	}
+
+	return rb.pool.Do(radix.FlatCmd(nil, "ZREM", deadLettersIndexKey, id))
+}
@@ -12,4 +12,33 @@ CREATE TABLE IF NOT EXISTS mqueue_webhooks (
 );
 
 CREATE INDEX IF NOT EXISTS mqueue_webhooks_channel_id_idx ON mqueue_webhooks(channel_id);
+
+CREATE SEQUENCE IF NOT EXISTS mqueue_id_seq;
+
+CREATE TABLE IF NOT EXISTS mqueue_items (
+	id BIGINT PRIMARY KEY DEFAULT nextval('mqueue_id_seq'),
+
+	-- the id the item had in redis, if it was moved over from there
+	redis_id BIGINT,
+
+	guild_id BIGINT NOT NULL,
+	data JSONB NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+	-- set by the node currently sending the item
+	claimed_by TEXT,
+	claimed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- the redis id counter starts over if redis is flushed, so the creation time is part of the key
+CREATE UNIQUE INDEX IF NOT EXISTS mqueue_items_redis_id_idx ON mqueue_items(redis_id, created_at) WHERE redis_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS mqueue_dead_letters (
+	id BIGINT PRIMARY KEY,
+
+	data JSONB NOT NULL,
+	failed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS mqueue_dead_letters_failed_at_idx ON mqueue_dead_letters(failed_at);
 `
@@ -6,6 +6,7 @@ import (
 
 	"github.com/botlabs-gg/yagpdb/v2/bot"
 	"github.com/botlabs-gg/yagpdb/v2/common/config"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 var confMaxConcurrentSends = config.RegisterOption("yagpdb.mqueue.max_concurrent_sends", "Max number of concurrent sends that mqueue will do", 3)
@@ -18,6 +19,9 @@ type workItem struct {
 type workResult struct {
 	item  *workItem
 	retry bool
+
+	// err is set when the item failed, if it's not retried it's moved to the dead letter queue
+	err error
 }
 
 // MqueueServer is a worker that processes mqueue items for the current shards on the process
@@ -53,6 +57,9 @@ type MqueueServer struct {
 	forceAllShards bool
 
 	recentSentTimes map[int64]time.Time
+
+	// number of failed attempts per element id, only tracked in memory so it starts over after a restart
+	attempts map[int64]int
 }
 
 func NewServer(backend Storage, processor ItemProcessor) *MqueueServer {
@@ -65,6 +72,7 @@ func NewServer(backend Storage, processor ItemProcessor) *MqueueServer {
 		backend:         backend,
 		processor:       processor,
 		recentSentTimes: make(map[int64]time.Time),
+		attempts:        make(map[int64]int),
 	}
 }
 
@@ -180,17 +188,54 @@ func (m *MqueueServer) addWork(wi *workItem) {
 }
 
 func (m *MqueueServer) checkRunNextWork() {
-	next := m.findWork()
-	if next == nil {
+	for {
+		next := m.findWork()
+		if next == nil {
+			return
+		}
+
+		if !m.claimWork(next) {
+			// another node has it, or it's gone, the next full refresh brings it back if needed
+			m.localWork = removeFromWorkSlice(m.localWork, next)
+			continue
+		}
+
+		m.activeWork = append(m.activeWork, next)
+		go m.processor.ProcessItem(m.doneWork, next)
 		return
 	}
+}
+
+// claimWork claims the item if the backend is shared between nodes
+func (m *MqueueServer) claimWork(wi *workItem) bool {
+	claimer, ok := m.backend.(ClaimingStorage)
+	if !ok {
+		return true
+	}
+
+	claimed, err := claimer.ClaimItem(wi)
+	if err != nil {
+		logger.WithError(err).WithField("mq_id", wi.Elem.ID).Error("Failed claiming mqueue item")
+		return false
+	}
 
-	m.activeWork = append(m.activeWork, next)
-	go m.processor.ProcessItem(m.doneWork, next)
+	return claimed
 }
 
 func (m *MqueueServer) finishWork(wr *workResult) {
+	id := wr.item.Elem.ID
+	if wr.retry {
+		m.attempts[id]++
+		if policy := sourceRetryPolicy(wr.item.Elem.Source); m.attempts[id] >= policy.MaxAttempts {
+			wr.retry = false
+			m.deadLetter(wr.item, wr.err, m.attempts[id])
+		}
+	} else if wr.err != nil {
+		m.deadLetter(wr.item, wr.err, m.attempts[id]+1)
+	}
+
 	if !wr.retry {
+		delete(m.attempts, id)
 		m.backend.DelItem(wr.item)
 		m.localWork = removeFromWorkSlice(m.localWork, wr.item)
 		if m.totalWorkPresent {
@@ -202,6 +247,26 @@ func (m *MqueueServer) finishWork(wr *workResult) {
 	m.checkRunNextWork()
 }
 
+func (m *MqueueServer) deadLetter(wi *workItem, err error, attempts int) {
+	errStr := "unknown error"
+	if err != nil {
+		errStr = err.Error()
+	}
+
+	logger.WithField("mq_id", wi.Elem.ID).WithField("source", wi.Elem.Source).Warnf("Moving mqueue item to the dead letter queue after %d attempt(s): %s", attempts, errStr)
+	metricsDeadLetters.With(prometheus.Labels{"source": wi.Elem.Source}).Inc()
+
+	dlErr := m.backend.AddDeadLetter(&DeadLetter{
+		Elem:     wi.Elem,
+		Error:    errStr,
+		Attempts: attempts,
+		FailedAt: time.Now(),
+	})
+	if dlErr != nil {
+		logger.WithError(dlErr).Error("Failed adding mqueue dead letter")
+	}
+}
+
 func removeFromWorkSlice(s []*workItem, wi *workItem) []*workItem {
 	for i, v := range s {
 		if v.Elem.ID == wi.Elem.ID {
@@ -20,6 +20,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/common/mqueue"
 	"github.com/botlabs-gg/yagpdb/v2/common/pubsub"
 	"github.com/botlabs-gg/yagpdb/v2/common/sentryhook"
+	"github.com/botlabs-gg/yagpdb/v2/common/tracing"
 	"github.com/botlabs-gg/yagpdb/v2/feeds"
 	"github.com/botlabs-gg/yagpdb/v2/web"
 	"github.com/getsentry/sentry-go"
@@ -109,6 +110,11 @@ func Init() {
 		addSentryHook()
 	}
 
+	err = tracing.Init()
+	if err != nil {
+		log.WithError(err).Error("Failed setting up tracing")
+	}
+
 	err = common.Init()
 	if err != nil {
 		log.WithError(err).Fatal("Failed intializing")
@@ -229,6 +235,8 @@ func shutdown() {
 	log.Info("Sleeping for a second to allow work to finish")
 	time.Sleep(time.Second)
 
+	tracing.Shutdown()
+
 	log.Info("Bye..")
 	os.Exit(0)
 }
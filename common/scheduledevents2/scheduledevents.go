@@ -18,11 +18,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/volatiletech/null/v8"
+	"go.opentelemetry.io/otel/attribute"
 
 	"emperror.dev/errors"
 	"github.com/botlabs-gg/yagpdb/v2/bot"
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2/models"
+	"github.com/botlabs-gg/yagpdb/v2/common/tracing"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 )
@@ -60,6 +62,18 @@ func RegisterPlugin() {
 
 type HandlerFunc func(evt *models.ScheduledEvent, data interface{}) (retry bool, err error)
 
+// contexts of the events currently being handled, keyed by the event
+var handlerContexts sync.Map
+
+// Context returns the context of a event while it's being handled, REST requests and work queued using it are part of the event's trace
+func Context(evt *models.ScheduledEvent) context.Context {
+	if ctx, ok := handlerContexts.Load(evt); ok {
+		return ctx.(context.Context)
+	}
+
+	return context.Background()
+}
+
 type RegisteredHandler struct {
 	EvtName    string
 	DataFormat interface{}
@@ -326,6 +340,18 @@ func (se *ScheduledEvents) processItem(id int64, guildID int64) {
 		}
 	}()
 
+	// handlers don't take a context, they get it using Context(evt)
+	ctx, span := tracing.Start(context.Background(), "scheduled event "+item.EventName,
+		tracing.GuildID(item.GuildID),
+		attribute.Int64("scheduled_event.id", item.ID),
+		attribute.Int64("scheduled_event.delay_ms", time.Since(item.TriggersAt).Milliseconds()))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	handlerContexts.Store(item, ctx)
+	defer handlerContexts.Delete(item)
+
 	retryDelay := time.Second
 	for nRetry := 0; nRetry < 10; nRetry++ {
 		var retry bool
@@ -335,6 +361,7 @@ func (se *ScheduledEvents) processItem(id int64, guildID int64) {
 		}
 
 		if retry {
+			span.AddEvent("retry")
 			l.WithError(err).Warn("retrying handling event")
 			time.Sleep(retryDelay)
 			retryDelay *= 2
@@ -2,6 +2,7 @@ package templates
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -21,13 +22,16 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/common/prefix"
 	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
+	"github.com/botlabs-gg/yagpdb/v2/common/tracing"
 	"github.com/botlabs-gg/yagpdb/v2/lib/confusables"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
 	"github.com/botlabs-gg/yagpdb/v2/lib/template"
+	"github.com/botlabs-gg/yagpdb/v2/lib/template/parse"
 	"github.com/botlabs-gg/yagpdb/v2/web/discorddata"
 	"github.com/sirupsen/logrus"
 	"github.com/vmihailenco/msgpack"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -251,12 +255,19 @@ type Context struct {
 
 	IsPremium bool
 
+	// OpsUsed is the highest number of operations used by a single execution with this context,
+	// which is what the MaxOps limits apply to
+	OpsUsed int
+
 	RegexCache map[string]*regexp.Regexp
 
 	CurrentFrame *ContextFrame
 
 	ExecutedFrom ExecutedFromType
 
+	// Ctx is what the template was executed from, optional, used as the parent of the execution span
+	Ctx context.Context
+
 	contextFuncsAdded bool
 }
 
@@ -407,6 +418,48 @@ func (c *Context) Parse(source string) (*template.Template, error) {
 	return parsed, nil
 }
 
+// ParseDefinitions parses source and returns the templates defined in it by name,
+// anything outside of the definitions is discarded.
+// The returned trees are not modified during execution and can be shared between contexts.
+func (c *Context) ParseDefinitions(source string) (map[string]*parse.Tree, error) {
+	parsed, err := c.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	trees := make(map[string]*parse.Tree)
+	for _, t := range parsed.Templates() {
+		if t == parsed || t.Tree == nil {
+			continue
+		}
+
+		trees[t.Name()] = t.Tree
+	}
+
+	return trees, nil
+}
+
+// AddDefinitions makes the templates in trees available to the template action
+// in the template currently being executed, replacing existing templates with the same name.
+func (c *Context) AddDefinitions(trees map[string]*parse.Tree) error {
+	if c.CurrentFrame.parsedTemplate == nil {
+		return errors.New("no template is being executed")
+	}
+
+	for name, tree := range trees {
+		if name == c.CurrentFrame.parsedTemplate.Name() {
+			continue
+		}
+
+		_, err := c.CurrentFrame.parsedTemplate.AddParseTree(name, tree)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 const (
 	MaxOpsNormal      = 1000000
 	MaxOpsPremium     = 2500000
@@ -475,6 +528,11 @@ func (c *Context) executeParsed() (string, error) {
 
 	started := time.Now()
 
+	_, span := tracing.Start(c.Context(), "template "+c.Name, attribute.Int("executed_from", int(c.ExecutedFrom)))
+	if c.GS != nil {
+		span.SetAttributes(tracing.GuildID(c.GS.ID))
+	}
+
 	//log only if execution takes longer than 5 seconds
 	timer := time.AfterFunc(5*time.Second, func() {
 		logger.WithFields(logrus.Fields{
@@ -484,9 +542,11 @@ func (c *Context) executeParsed() (string, error) {
 		}).Warn("Template execution is taking longer than 5 seconds")
 	})
 
-	err := parsed.Execute(w, c.Data)
+	ops, err := parsed.ExecuteWithOps(w, c.Data)
+	c.OpsUsed = max(c.OpsUsed, ops)
 
 	defer func() {
+		tracing.End(span, err)
 		timer.Stop()
 		dur := time.Since(started)
 		if dur > 5*time.Second {
@@ -516,6 +576,15 @@ func (c *Context) executeParsed() (string, error) {
 	return result, nil
 }
 
+// Context returns Ctx, or a background context if not set
+func (c *Context) Context() context.Context {
+	if c.Ctx == nil {
+		return context.Background()
+	}
+
+	return c.Ctx
+}
+
 // creates a new context frame and returns the old one
 func (c *Context) newContextFrame(cs *dstate.ChannelState) *ContextFrame {
 	old := c.CurrentFrame
@@ -739,11 +808,74 @@ func (c *Context) IncreaseCheckCallCounterPremium(key string, normalLimit, premi
 	return current > normalLimit
 }
 
-func (c *Context) IncreaseCheckGenericAPICall() bool {
-	if c.ExecutedFrom == ExecutedFromEvalCC {
-		return c.IncreaseCheckCallCounter("api_call", 20)
+// CallLimit is the per execution limit of a call counter
+type CallLimit struct {
+	Normal  int
+	Premium int
+	// if set, the limit when executed from evalcc regardless of premium
+	EvalCC int
+}
+
+// Limit returns the limit that applies to a execution
+func (l CallLimit) Limit(isPremium bool, executedFrom ExecutedFromType) int {
+	if executedFrom == ExecutedFromEvalCC && l.EvalCC > 0 {
+		return l.EvalCC
+	}
+
+	if isPremium {
+		return l.Premium
+	}
+
+	return l.Normal
+}
+
+// CallLimits holds the limits of the call counters, keyed by counter name.
+// Plugins add their own counters with RegisterCallLimit.
+// Counters kept per target, with the id of the channel or member in the name, are not in here.
+var CallLimits = map[string]CallLimit{
+	"api_call":              {Normal: 100, Premium: 100, EvalCC: 20},
+	"exec_child":            {Normal: 3, Premium: 3},
+	"send_dm":               {Normal: 1, Premium: 1},
+	"edit_nick":             {Normal: 2, Premium: 2},
+	"edit_channel":          {Normal: 10, Premium: 10},
+	"images":                {Normal: 2, Premium: 5},
+	"image_avatars":         {Normal: 3, Premium: 10},
+	"message_pins":          {Normal: 2, Premium: 2},
+	"message_publish":       {Normal: 1, Premium: 1},
+	"del_reaction_message":  {Normal: 10, Premium: 10},
+	"create_thread":         {Normal: 1, Premium: 1},
+	"delete_thread":         {Normal: 1, Premium: 1},
+	"edit_thread":           {Normal: 10, Premium: 10},
+	"channel_pins":          {Normal: 1, Premium: 2},
+	"add_reaction_trigger":  {Normal: 20, Premium: 20},
+	"add_reaction_response": {Normal: 20, Premium: 20},
+	"add_reaction_message":  {Normal: 20, Premium: 20},
+	"online_users":          {Normal: 1, Premium: 1},
+	"sort":                  {Normal: 1, Premium: 3},
+	"decode_base64":         {Normal: 2, Premium: 2},
+	"encode_base64":         {Normal: 2, Premium: 2},
+	"sha256":                {Normal: 2, Premium: 2},
+	"modal":                 {Normal: 1, Premium: 1},
+	"interaction_response":  {Normal: 1, Premium: 1},
+}
+
+// RegisterCallLimit adds a call counter limit, should only be called during init
+func RegisterCallLimit(key string, limit CallLimit) {
+	CallLimits[key] = limit
+}
+
+// IncreaseCheckCallLimit increases the counter of key and returns true if it's above its limit in CallLimits
+func (c *Context) IncreaseCheckCallLimit(key string) bool {
+	limit, ok := CallLimits[key]
+	if !ok {
+		panic("templates: no call limit registered for " + key)
 	}
-	return c.IncreaseCheckCallCounter("api_call", 100)
+
+	return c.IncreaseCheckCallCounter(key, limit.Limit(c.IsPremium, c.ExecutedFrom))
+}
+
+func (c *Context) IncreaseCheckGenericAPICall() bool {
+	return c.IncreaseCheckCallLimit("api_call")
 }
 
 func (c *Context) LogEntry() *logrus.Entry {
@@ -899,6 +1031,9 @@ func baseContextFuncs(c *Context) {
 	c.addContextFunc("reReplace", c.reReplace)
 	c.addContextFunc("reSplit", c.reSplit)
 
+	// Image functions
+	c.addContextFunc("newImage", c.tmplNewImage)
+
 	// Miscellaneous functions
 	c.addContextFunc("onlineCount", c.tmplOnlineCount)
 	c.addContextFunc("onlineCountBots", c.tmplOnlineCountBots)
@@ -53,6 +53,12 @@ func (c *Context) parseMessageInput(msg interface{}) (*discordgo.MessageSend, er
 		if err != nil {
 			return nil, err
 		}
+	case *TemplateImage:
+		file, err := typedMsg.File("image")
+		if err != nil {
+			return nil, err
+		}
+		msgSend.Files = []*discordgo.File{file}
 	default:
 		msgSend.Content = ToString(msg)
 	}
@@ -72,7 +78,7 @@ func (c *Context) parseMessageInput(msg interface{}) (*discordgo.MessageSend, er
 }
 
 func (c *Context) tmplSendDM(s ...interface{}) string {
-	if len(s) < 1 || c.IncreaseCheckCallCounter("send_dm", 1) || c.IncreaseCheckGenericAPICall() || c.MS == nil || c.ExecutedFrom == ExecutedFromLeave {
+	if len(s) < 1 || c.IncreaseCheckCallLimit("send_dm") || c.IncreaseCheckGenericAPICall() || c.MS == nil || c.ExecutedFrom == ExecutedFromLeave {
 		return ""
 	}
 
@@ -195,7 +201,7 @@ func (c *Context) tmplSendTemplate(channel interface{}, name string, data ...int
 }
 
 func (c *Context) sendNestedTemplate(channel interface{}, dm bool, name string, data ...interface{}) (interface{}, error) {
-	if c.IncreaseCheckCallCounter("exec_child", 3) {
+	if c.IncreaseCheckCallLimit("exec_child") {
 		return "", ErrTooManyCalls
 	}
 	if name == "" {
@@ -574,7 +580,7 @@ func (c *Context) tmplEditComponentsMessage(filterSpecialMentions bool) func(cha
 
 func (c *Context) tmplPinMessage(unpin bool) func(channel, msgID interface{}) (string, error) {
 	return func(channel, msgID interface{}) (string, error) {
-		if c.IncreaseCheckCallCounter("message_pins", 2) {
+		if c.IncreaseCheckCallLimit("message_pins") {
 			return "", ErrTooManyCalls
 		}
 
@@ -603,7 +609,7 @@ func (c *Context) tmplPublishMessage(channel, msgID interface{}) (string, error)
 		return "", ErrTooManyAPICalls
 	}
 
-	if c.IncreaseCheckCallCounter("message_publish", 1) {
+	if c.IncreaseCheckCallLimit("message_publish") {
 		return "", ErrTooManyCalls
 	}
 
@@ -951,7 +957,7 @@ func (c *Context) tmplDelMessageReaction(values ...reflect.Value) (reflect.Value
 
 		for _, reaction := range args[3:] {
 
-			if c.IncreaseCheckCallCounter("del_reaction_message", 10) {
+			if c.IncreaseCheckCallLimit("del_reaction_message") {
 				return reflect.Value{}, ErrTooManyCalls
 			}
 
@@ -988,7 +994,7 @@ func (c *Context) tmplDelAllMessageReactions(values ...reflect.Value) (reflect.V
 
 		if len(args) > 2 {
 			for _, emoji := range args[2:] {
-				if c.IncreaseCheckCallCounter("del_reaction_message", 10) {
+				if c.IncreaseCheckCallLimit("del_reaction_message") {
 					return reflect.Value{}, ErrTooManyCalls
 				}
 
@@ -1189,7 +1195,7 @@ func (c *Context) tmplThreadMemberAdd(threadID, memberID interface{}) string {
 
 func (c *Context) tmplCloseThread(channel interface{}, flags ...bool) (string, error) {
 
-	if c.IncreaseCheckCallCounter("edit_thread", 10) {
+	if c.IncreaseCheckCallLimit("edit_thread") {
 		return "", ErrTooManyCalls
 	}
 
@@ -1237,7 +1243,7 @@ func (c *Context) tmplCloseThread(channel interface{}, flags ...bool) (string, e
 }
 
 func (c *Context) tmplCreateThread(channel, msgID, name interface{}, optionals ...interface{}) (*CtxChannel, error) {
-	if c.IncreaseCheckCallCounterPremium("create_thread", 1, 1) {
+	if c.IncreaseCheckCallLimit("create_thread") {
 		return nil, ErrTooManyCalls
 	}
 
@@ -1343,7 +1349,7 @@ func (c *Context) overwriteThreadInGuildSet(t *dstate.ChannelState) {
 
 // This function can delete both basic threads and forum threads
 func (c *Context) tmplDeleteThread(thread interface{}) (string, error) {
-	if c.IncreaseCheckCallCounterPremium("delete_thread", 1, 1) {
+	if c.IncreaseCheckCallLimit("delete_thread") {
 		return "", ErrTooManyCalls
 	}
 
@@ -1363,7 +1369,7 @@ func (c *Context) tmplDeleteThread(thread interface{}) (string, error) {
 
 func (c *Context) tmplEditThread(channel interface{}, args ...interface{}) (string, error) {
 
-	if c.IncreaseCheckCallCounter("edit_thread", 10) {
+	if c.IncreaseCheckCallLimit("edit_thread") {
 		return "", ErrTooManyCalls
 	}
 
@@ -1422,7 +1428,7 @@ func (c *Context) tmplEditThread(channel interface{}, args ...interface{}) (stri
 
 func (c *Context) tmplOpenThread(cID int64) (string, error) {
 
-	if c.IncreaseCheckCallCounter("edit_thread", 10) {
+	if c.IncreaseCheckCallLimit("edit_thread") {
 		return "", ErrTooManyCalls
 	}
 
@@ -1479,7 +1485,7 @@ func (c *Context) tmplThreadMemberRemove(threadID, memberID interface{}) string
 func (c *Context) tmplCreateForumPost(channel, name, content interface{}, optional ...interface{}) (*CtxChannel, error) {
 
 	// shares same counter as create thread
-	if c.IncreaseCheckCallCounterPremium("create_thread", 1, 1) {
+	if c.IncreaseCheckCallLimit("create_thread") {
 		return nil, ErrTooManyCalls
 	}
 
@@ -1668,7 +1674,7 @@ func processThreadArgs(newThread bool, parent *dstate.ChannelState, values ...in
 func (c *Context) tmplPinForumPost(unpin bool) func(channel interface{}) (string, error) {
 	return func(channel interface{}) (string, error) {
 
-		if c.IncreaseCheckCallCounter("edit_thread", 10) {
+		if c.IncreaseCheckCallLimit("edit_thread") {
 			return "", ErrTooManyCalls
 		}
 
@@ -1735,7 +1741,7 @@ func (c *Context) tmplGetChannelOrThread(channel interface{}) (*CtxChannel, erro
 
 func (c *Context) tmplGetChannelPins(pinCount bool) func(channel interface{}) (interface{}, error) {
 	return func(channel interface{}) (interface{}, error) {
-		if c.IncreaseCheckCallCounterPremium("channel_pins", 1, 2) {
+		if c.IncreaseCheckCallLimit("channel_pins") {
 			return 0, ErrTooManyCalls
 		}
 
@@ -1776,7 +1782,7 @@ func (c *Context) tmplAddReactions(values ...reflect.Value) (reflect.Value, erro
 		}
 
 		for _, reaction := range args {
-			if c.IncreaseCheckCallCounter("add_reaction_trigger", 20) {
+			if c.IncreaseCheckCallLimit("add_reaction_trigger") {
 				return reflect.Value{}, ErrTooManyCalls
 			}
 
@@ -1793,7 +1799,7 @@ func (c *Context) tmplAddReactions(values ...reflect.Value) (reflect.Value, erro
 func (c *Context) tmplAddResponseReactions(values ...reflect.Value) (reflect.Value, error) {
 	f := func(args []reflect.Value) (reflect.Value, error) {
 		for _, reaction := range args {
-			if c.IncreaseCheckCallCounter("add_reaction_response", 20) {
+			if c.IncreaseCheckCallLimit("add_reaction_response") {
 				return reflect.Value{}, ErrTooManyCalls
 			}
 
@@ -1832,7 +1838,7 @@ func (c *Context) tmplAddMessageReactions(values ...reflect.Value) (reflect.Valu
 				continue
 			}
 
-			if c.IncreaseCheckCallCounter("add_reaction_message", 20) {
+			if c.IncreaseCheckCallLimit("add_reaction_message") {
 				return reflect.Value{}, ErrTooManyCalls
 			}
 
@@ -1983,7 +1989,7 @@ func (c *Context) reSplit(r, s string, i ...int) ([]string, error) {
 }
 
 func (c *Context) tmplEditChannelName(channel interface{}, newName string) (string, error) {
-	if c.IncreaseCheckCallCounter("edit_channel", 10) {
+	if c.IncreaseCheckCallLimit("edit_channel") {
 		return "", ErrTooManyCalls
 	}
 
@@ -2007,7 +2013,7 @@ func (c *Context) tmplEditChannelName(channel interface{}, newName string) (stri
 }
 
 func (c *Context) tmplEditChannelTopic(channel interface{}, newTopic string) (string, error) {
-	if c.IncreaseCheckCallCounter("edit_channel", 10) {
+	if c.IncreaseCheckCallLimit("edit_channel") {
 		return "", ErrTooManyCalls
 	}
 
@@ -2035,7 +2041,7 @@ func (c *Context) tmplEditChannelTopic(channel interface{}, newTopic string) (st
 }
 
 func (c *Context) tmplOnlineCount() (int, error) {
-	if c.IncreaseCheckCallCounter("online_users", 1) {
+	if c.IncreaseCheckCallLimit("online_users") {
 		return 0, ErrTooManyCalls
 	}
 
@@ -2049,7 +2055,7 @@ func (c *Context) tmplOnlineCount() (int, error) {
 
 // DEPRECATED: this function will likely not return
 func (c *Context) tmplOnlineCountBots() (int, error) {
-	// if c.IncreaseCheckCallCounter("online_bots", 1) {
+	// if c.IncreaseCheckCallLimit("online_bots") {
 	// 	return 0, ErrTooManyCalls
 	// }
 
@@ -2065,7 +2071,7 @@ func (c *Context) tmplOnlineCountBots() (int, error) {
 }
 
 func (c *Context) tmplEditNickname(Nickname string) (string, error) {
-	if c.IncreaseCheckCallCounter("edit_nick", 2) {
+	if c.IncreaseCheckCallLimit("edit_nick") {
 		return "", ErrTooManyCalls
 	}
 
@@ -2086,7 +2092,7 @@ func (c *Context) tmplEditNickname(Nickname string) (string, error) {
 }
 
 func (c *Context) tmplSort(input interface{}, args ...interface{}) (interface{}, error) {
-	if c.IncreaseCheckCallCounterPremium("sort", 1, 3) {
+	if c.IncreaseCheckCallLimit("sort") {
 		return "", ErrTooManyCalls
 	}
 
@@ -2686,7 +2692,7 @@ func (c *Context) validateDurationDelay(in interface{}) time.Duration {
 }
 
 func (c *Context) tmplDecodeBase64(str string) (string, error) {
-	if c.IncreaseCheckCallCounter("decode_base64", 2) {
+	if c.IncreaseCheckCallLimit("decode_base64") {
 		return "", ErrTooManyCalls
 	}
 	raw, err := base64.StdEncoding.DecodeString(str)
@@ -2700,7 +2706,7 @@ func (c *Context) tmplDecodeBase64(str string) (string, error) {
 }
 
 func (c *Context) tmplEncodeBase64(str string) (string, error) {
-	if c.IncreaseCheckCallCounter("encode_base64", 2) {
+	if c.IncreaseCheckCallLimit("encode_base64") {
 		return "", ErrTooManyCalls
 	}
 	encoded := base64.StdEncoding.EncodeToString([]byte(str))
@@ -2712,7 +2718,7 @@ func (c *Context) tmplEncodeBase64(str string) (string, error) {
 }
 
 func (c *Context) tmplSha256(str string) (string, error) {
-	if c.IncreaseCheckCallCounter("sha256", 2) {
+	if c.IncreaseCheckCallLimit("sha256") {
 		return "", ErrTooManyCalls
 	}
 	hash := sha256.New()
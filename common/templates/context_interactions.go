@@ -264,11 +264,11 @@ func (c *Context) tmplSendModal(modal interface{}) (interface{}, error) {
 		return "", ErrTooManyAPICalls
 	}
 
-	if c.IncreaseCheckCallCounter("modal", 1) {
+	if c.IncreaseCheckCallLimit("modal") {
 		return "", errors.New("cannot send multiple modals to the same interaction")
 	}
 
-	if c.IncreaseCheckCallCounter("interaction_response", 1) {
+	if c.IncreaseCheckCallLimit("interaction_response") {
 		return "", ErrTooManyInteractionResponses
 	}
 
@@ -332,7 +332,7 @@ func (c *Context) tmplSendInteractionResponse(filterSpecialMentions bool, return
 		}
 		switch sendType {
 		case sendMessageInteractionResponse:
-			if c.IncreaseCheckCallCounter("interaction_response", 1) {
+			if c.IncreaseCheckCallLimit("interaction_response") {
 				return "", ErrTooManyInteractionResponses
 			}
 			err = common.BotSession.CreateInteractionResponse(c.CurrentFrame.Interaction.ID, token, &discordgo.InteractionResponse{
@@ -381,7 +381,7 @@ func (c *Context) tmplUpdateMessage(filterSpecialMentions bool) func(msg interfa
 			return "", ErrTooManyAPICalls
 		}
 
-		if c.IncreaseCheckCallCounter("interaction_response", 1) {
+		if c.IncreaseCheckCallLimit("interaction_response") {
 			return "", ErrTooManyInteractionResponses
 		}
 
@@ -282,6 +282,7 @@ func CreateComplexMessage(values ...interface{}) (*discordgo.MessageSend, error)
 
 	// Default filename
 	filename := "attachment_" + time.Now().Format("2006-01-02_15-04-05")
+	var image *TemplateImage
 	for i, key := range compBuilder.Components {
 		val := compBuilder.Values[i]
 
@@ -323,6 +324,15 @@ func CreateComplexMessage(values ...interface{}) (*discordgo.MessageSend, error)
 				ContentType: "text/plain",
 				Reader:      &buf,
 			}
+		case "image":
+			if val == nil {
+				continue
+			}
+			img, ok := val.(*TemplateImage)
+			if !ok {
+				return nil, errors.New("invalid image passed to send message builder, create one with newImage")
+			}
+			image = img
 		case "allowed_mentions":
 			if val == nil {
 				msg.AllowedMentions = discordgo.AllowedMentions{}
@@ -499,6 +509,14 @@ func CreateComplexMessage(values ...interface{}) (*discordgo.MessageSend, error)
 		msg.File.Name = filename + ".txt"
 	}
 
+	if image != nil {
+		file, err := image.File(filename)
+		if err != nil {
+			return nil, err
+		}
+		msg.Files = append(msg.Files, file)
+	}
+
 	if len(msg.Components) > 0 {
 		err := validateTopLevelComponentsCustomIDs(msg.Components, nil)
 		if err != nil {
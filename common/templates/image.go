@@ -0,0 +1,543 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"golang.org/x/image/colornames"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// Max width and height of a image
+	MaxImageSize = 1024
+	// Max number of draw operations on a single image
+	MaxImageOps = 100
+
+	maxImageTextLength = 256
+	minImageFontSize   = 6
+	maxImageFontSize   = 128
+	maxAvatarDownload  = 8 << 20
+	maxAvatarDimension = 4096
+	avatarFetchTimeout = 5 * time.Second
+	defaultImageFont   = "regular"
+)
+
+var (
+	ErrImageTooManyOps = errors.New("too many draw operations on this image")
+
+	imageFontSources = map[string][]byte{
+		"regular": goregular.TTF,
+		"bold":    gobold.TTF,
+		"italic":  goitalic.TTF,
+		"mono":    gomono.TTF,
+	}
+
+	imageFonts     = make(map[string]*opentype.Font)
+	imageFontsOnce sync.Once
+	imageFontsErr  error
+)
+
+func loadImageFont(name string) (*opentype.Font, error) {
+	imageFontsOnce.Do(func() {
+		for k, v := range imageFontSources {
+			f, err := opentype.Parse(v)
+			if err != nil {
+				imageFontsErr = err
+				return
+			}
+			imageFonts[k] = f
+		}
+	})
+
+	if imageFontsErr != nil {
+		return nil, imageFontsErr
+	}
+
+	f, ok := imageFonts[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown font %q, available fonts are regular, bold, italic and mono", name)
+	}
+
+	return f, nil
+}
+
+// TemplateImage is a image built by a template, it's attached to messages as a png
+type TemplateImage struct {
+	img *image.RGBA
+	ops int
+
+	// used for fetching avatars
+	ctx *Context
+}
+
+func (c *Context) tmplNewImage(width, height interface{}, background ...interface{}) (*TemplateImage, error) {
+	if c.IncreaseCheckCallLimit("images") {
+		return nil, ErrTooManyCalls
+	}
+
+	w := tmplToInt(width)
+	h := tmplToInt(height)
+	if w < 1 || h < 1 || w > MaxImageSize || h > MaxImageSize {
+		return nil, fmt.Errorf("image size has to be between 1x1 and %dx%d", MaxImageSize, MaxImageSize)
+	}
+
+	img := &TemplateImage{
+		img: image.NewRGBA(image.Rect(0, 0, w, h)),
+		ctx: c,
+	}
+
+	if len(background) > 0 {
+		if _, err := img.Fill(background[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	return img, nil
+}
+
+func (t *TemplateImage) String() string {
+	b := t.img.Bounds()
+	return fmt.Sprintf("Image %dx%d", b.Dx(), b.Dy())
+}
+
+func (t *TemplateImage) Width() int {
+	return t.img.Bounds().Dx()
+}
+
+func (t *TemplateImage) Height() int {
+	return t.img.Bounds().Dy()
+}
+
+func (t *TemplateImage) incrOps() error {
+	t.ops++
+	if t.ops > MaxImageOps {
+		return ErrImageTooManyOps
+	}
+
+	return nil
+}
+
+// Fill fills the whole image with a color
+func (t *TemplateImage) Fill(col interface{}) (string, error) {
+	return t.Rect(0, 0, t.Width(), t.Height(), col)
+}
+
+// Rect draws a filled rectangle
+func (t *TemplateImage) Rect(x, y, w, h interface{}, col interface{}) (string, error) {
+	if err := t.incrOps(); err != nil {
+		return "", err
+	}
+
+	c, err := parseImageColor(col)
+	if err != nil {
+		return "", err
+	}
+
+	r := imageRect(x, y, w, h)
+	draw.Draw(t.img, r, image.NewUniform(c), image.Point{}, draw.Over)
+	return "", nil
+}
+
+// Circle draws a filled circle centered on x, y
+func (t *TemplateImage) Circle(x, y, radius interface{}, col interface{}) (string, error) {
+	if err := t.incrOps(); err != nil {
+		return "", err
+	}
+
+	c, err := parseImageColor(col)
+	if err != nil {
+		return "", err
+	}
+
+	r := ToFloat64(radius)
+	if r <= 0 {
+		return "", nil
+	}
+
+	mask := &circleMask{cx: ToFloat64(x), cy: ToFloat64(y), r: r}
+	draw.DrawMask(t.img, mask.Bounds(), image.NewUniform(c), image.Point{}, mask, mask.Bounds().Min, draw.Over)
+	return "", nil
+}
+
+// ProgressBar draws a horizontal bar filled according to progress, which is between 0 and 1
+func (t *TemplateImage) ProgressBar(x, y, w, h, progress interface{}, fg, bg interface{}) (string, error) {
+	if err := t.incrOps(); err != nil {
+		return "", err
+	}
+
+	fgCol, err := parseImageColor(fg)
+	if err != nil {
+		return "", err
+	}
+
+	bgCol, err := parseImageColor(bg)
+	if err != nil {
+		return "", err
+	}
+
+	p := math.Max(0, math.Min(1, ToFloat64(progress)))
+	r := imageRect(x, y, w, h)
+	draw.Draw(t.img, r, image.NewUniform(bgCol), image.Point{}, draw.Over)
+
+	filled := r
+	filled.Max.X = r.Min.X + int(math.Round(float64(r.Dx())*p))
+	draw.Draw(t.img, filled, image.NewUniform(fgCol), image.Point{}, draw.Over)
+	return "", nil
+}
+
+type imageTextOptions struct {
+	Size     float64
+	Color    color.Color
+	Font     string
+	Align    string
+	MaxWidth int
+}
+
+func parseImageTextOptions(values ...interface{}) (*imageTextOptions, error) {
+	opts := &imageTextOptions{
+		Size:  16,
+		Color: color.White,
+		Font:  defaultImageFont,
+		Align: "left",
+	}
+
+	if len(values) == 0 {
+		return opts, nil
+	}
+
+	var m map[string]interface{}
+	switch t := values[0].(type) {
+	case SDict:
+		m = t
+	case *SDict:
+		m = *t
+	case map[string]interface{}:
+		m = t
+	default:
+		dict, err := StringKeyDictionary(values...)
+		if err != nil {
+			return nil, err
+		}
+		m = dict
+	}
+
+	for k, v := range m {
+		switch strings.ToLower(k) {
+		case "size":
+			opts.Size = ToFloat64(v)
+			if opts.Size < minImageFontSize || opts.Size > maxImageFontSize {
+				return nil, fmt.Errorf("font size has to be between %d and %d", minImageFontSize, maxImageFontSize)
+			}
+		case "color":
+			c, err := parseImageColor(v)
+			if err != nil {
+				return nil, err
+			}
+			opts.Color = c
+		case "font":
+			opts.Font = ToString(v)
+		case "align":
+			opts.Align = strings.ToLower(ToString(v))
+			if opts.Align != "left" && opts.Align != "center" && opts.Align != "right" {
+				return nil, errors.New("align has to be left, center or right")
+			}
+		case "maxwidth":
+			opts.MaxWidth = tmplToInt(v)
+		default:
+			return nil, errors.New(`invalid key "` + k + `" passed to image text options`)
+		}
+	}
+
+	return opts, nil
+}
+
+// Text draws text with its top at y, x is the left edge, center or right edge depending on the align option
+func (t *TemplateImage) Text(x, y interface{}, text interface{}, options ...interface{}) (string, error) {
+	if err := t.incrOps(); err != nil {
+		return "", err
+	}
+
+	opts, err := parseImageTextOptions(options...)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := loadImageFont(opts.Font)
+	if err != nil {
+		return "", err
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: opts.Size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return "", err
+	}
+	defer face.Close()
+
+	str := strings.ReplaceAll(ToString(text), "\n", " ")
+	if utf8.RuneCountInString(str) > maxImageTextLength {
+		str = string([]rune(str)[:maxImageTextLength])
+	}
+
+	d := &font.Drawer{
+		Dst:  t.img,
+		Src:  image.NewUniform(opts.Color),
+		Face: face,
+	}
+
+	str = fitImageText(d, str, opts.MaxWidth)
+	width := d.MeasureString(str)
+
+	dotX := fixed.I(tmplToInt(x))
+	switch opts.Align {
+	case "center":
+		dotX -= width / 2
+	case "right":
+		dotX -= width
+	}
+
+	d.Dot = fixed.Point26_6{X: dotX, Y: fixed.I(tmplToInt(y)) + face.Metrics().Ascent}
+	d.DrawString(str)
+	return "", nil
+}
+
+// fitImageText cuts off the text with a ellipsis until it fits within maxWidth pixels
+func fitImageText(d *font.Drawer, str string, maxWidth int) string {
+	if maxWidth <= 0 || d.MeasureString(str) <= fixed.I(maxWidth) {
+		return str
+	}
+
+	runes := []rune(str)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		cut := string(runes) + "…"
+		if d.MeasureString(cut) <= fixed.I(maxWidth) {
+			return cut
+		}
+	}
+
+	return ""
+}
+
+// Avatar draws the avatar of a user or member scaled to size, optionally cut to a circle
+func (t *TemplateImage) Avatar(x, y, size interface{}, target interface{}, circle ...bool) (string, error) {
+	if err := t.incrOps(); err != nil {
+		return "", err
+	}
+
+	if t.ctx.IncreaseCheckCallLimit("image_avatars") {
+		return "", ErrTooManyCalls
+	}
+
+	s := tmplToInt(size)
+	if s < 1 || s > MaxImageSize {
+		return "", fmt.Errorf("avatar size has to be between 1 and %d", MaxImageSize)
+	}
+
+	avatarURL, err := t.ctx.imageAvatarURL(target, s)
+	if err != nil {
+		return "", err
+	}
+
+	avatar, err := fetchImage(avatarURL)
+	if err != nil {
+		return "", err
+	}
+
+	scaled := image.NewRGBA(image.Rect(0, 0, s, s))
+	xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), avatar, avatar.Bounds(), xdraw.Src, nil)
+
+	dst := image.Rect(0, 0, s, s).Add(image.Pt(tmplToInt(x), tmplToInt(y)))
+	if len(circle) > 0 && circle[0] {
+		r := float64(s) / 2
+		mask := &circleMask{cx: r, cy: r, r: r}
+		draw.DrawMask(t.img, dst, scaled, image.Point{}, mask, image.Point{}, draw.Over)
+	} else {
+		draw.Draw(t.img, dst, scaled, image.Point{}, draw.Over)
+	}
+
+	return "", nil
+}
+
+func (c *Context) imageAvatarURL(target interface{}, size int) (string, error) {
+	// discord serves sizes that are powers of two
+	sizeStr := "16"
+	for n := 16; n < size && n < 4096; n *= 2 {
+		sizeStr = strconv.Itoa(n * 2)
+	}
+
+	var avatarURL string
+	switch t := target.(type) {
+	case *discordgo.Member:
+		avatarURL = t.AvatarURL(sizeStr)
+	case *discordgo.User:
+		avatarURL = t.AvatarURL(sizeStr)
+	default:
+		if c.IncreaseCheckGenericAPICall() {
+			return "", ErrTooManyAPICalls
+		}
+
+		ms, err := bot.GetMember(c.GS.ID, TargetUserID(target))
+		if err != nil || ms == nil {
+			return "", errors.New("member not found")
+		}
+		avatarURL = ms.DgoMember().AvatarURL(sizeStr)
+	}
+
+	// only ever fetch from discord
+	if !strings.HasPrefix(avatarURL, discordgo.EndpointCDN) {
+		return "", errors.New("invalid avatar url")
+	}
+
+	return avatarURL, nil
+}
+
+func fetchImage(url string) (image.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), avatarFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := common.BotSession.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed fetching avatar: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAvatarDownload))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAvatar(data)
+}
+
+// decodeAvatar checks the dimensions in the header before decoding, as decoding allocates the whole image
+func decodeAvatar(data []byte) (image.Image, error) {
+	conf, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Width > maxAvatarDimension || conf.Height > maxAvatarDimension {
+		return nil, errors.New("avatar too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// File encodes the image as a png attachment
+func (t *TemplateImage) File(name string) (*discordgo.File, error) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, t.img)
+	if err != nil {
+		return nil, err
+	}
+
+	return &discordgo.File{
+		Name:        name + ".png",
+		ContentType: "image/png",
+		Reader:      &buf,
+	}, nil
+}
+
+func imageRect(x, y, w, h interface{}) image.Rectangle {
+	return image.Rect(0, 0, tmplToInt(w), tmplToInt(h)).Add(image.Pt(tmplToInt(x), tmplToInt(y)))
+}
+
+// parseImageColor parses a hex color string like #ff0000 or #ff000080, a color name or a integer like embed colors use
+func parseImageColor(v interface{}) (color.Color, error) {
+	if s, ok := v.(string); ok {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if !strings.HasPrefix(s, "#") {
+			if c, ok := colornames.Map[s]; ok {
+				return c, nil
+			}
+			return nil, fmt.Errorf("unknown color %q", s)
+		}
+
+		hex := s[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) == 6 {
+			hex += "ff"
+		}
+
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if len(hex) != 8 || err != nil {
+			return nil, fmt.Errorf("invalid color %q", s)
+		}
+
+		return color.NRGBA{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}, nil
+	}
+
+	if !common.IsNumber(v) {
+		return nil, fmt.Errorf("invalid color %v", v)
+	}
+
+	n := ToInt64(v)
+	return color.NRGBA{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n), A: 0xff}, nil
+}
+
+// circleMask is a anti aliased circle used as a alpha mask
+type circleMask struct {
+	cx, cy, r float64
+}
+
+func (c *circleMask) ColorModel() color.Model {
+	return color.AlphaModel
+}
+
+func (c *circleMask) Bounds() image.Rectangle {
+	return image.Rect(int(math.Floor(c.cx-c.r)), int(math.Floor(c.cy-c.r)), int(math.Ceil(c.cx+c.r)), int(math.Ceil(c.cy+c.r)))
+}
+
+func (c *circleMask) At(x, y int) color.Color {
+	dx := float64(x) + 0.5 - c.cx
+	dy := float64(y) + 0.5 - c.cy
+	coverage := c.r - math.Sqrt(dx*dx+dy*dy) + 0.5
+	if coverage <= 0 {
+		return color.Alpha{}
+	}
+	if coverage >= 1 {
+		return color.Alpha{A: 0xff}
+	}
+
+	return color.Alpha{A: uint8(coverage * 0xff)}
+}
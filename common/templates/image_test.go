@@ -0,0 +1,118 @@
+package templates
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestParseImageColor(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want color.NRGBA
+	}{
+		{"#ff0000", color.NRGBA{R: 0xff, A: 0xff}},
+		{"#0f0", color.NRGBA{G: 0xff, A: 0xff}},
+		{"#0000ff80", color.NRGBA{B: 0xff, A: 0x80}},
+		{0x00ff00, color.NRGBA{G: 0xff, A: 0xff}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseImageColor(tt.in)
+		if err != nil {
+			t.Errorf("parseImageColor(%v) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseImageColor(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	if c, err := parseImageColor("white"); err != nil || c != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("parseImageColor(white) = %v, %v", c, err)
+	}
+
+	for _, in := range []interface{}{"#12", "#gggggg", "notacolor", nil} {
+		if _, err := parseImageColor(in); err == nil {
+			t.Errorf("expected an error for %v", in)
+		}
+	}
+}
+
+func TestTemplateImage(t *testing.T) {
+	ctx := &Context{Counters: make(map[string]int)}
+
+	if _, err := ctx.tmplNewImage(MaxImageSize+1, 10); err == nil {
+		t.Error("expected an error for a image above the size limit")
+	}
+
+	img, err := ctx.tmplNewImage(200, 50, "#000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := img.ProgressBar(0, 0, 100, 10, 0.5, "#ff0000", "#0000ff"); err != nil {
+		t.Fatal(err)
+	}
+	if got := img.img.RGBAAt(10, 5); got != (color.RGBA{R: 0xff, A: 0xff}) {
+		t.Errorf("filled part of progress bar is %v", got)
+	}
+	if got := img.img.RGBAAt(90, 5); got != (color.RGBA{B: 0xff, A: 0xff}) {
+		t.Errorf("empty part of progress bar is %v", got)
+	}
+
+	if _, err := img.Text(0, 20, "hello", "size", 20, "font", "bold"); err != nil {
+		t.Fatal(err)
+	}
+	drawn := false
+	for x := 0; x < 100 && !drawn; x++ {
+		for y := 20; y < 45; y++ {
+			if img.img.RGBAAt(x, y).R > 0x80 && img.img.RGBAAt(x, y).G > 0x80 {
+				drawn = true
+				break
+			}
+		}
+	}
+	if !drawn {
+		t.Error("expected text to be drawn")
+	}
+
+	if _, err := img.Text(0, 0, "x", "font", "comic sans"); err == nil {
+		t.Error("expected an error for a unknown font")
+	}
+
+	for i := 0; i < MaxImageOps; i++ {
+		img.Circle(10, 10, 5, "red")
+	}
+	if _, err := img.Rect(0, 0, 1, 1, "red"); err != ErrImageTooManyOps {
+		t.Errorf("expected ErrImageTooManyOps, got %v", err)
+	}
+
+	file, err := img.File("rank")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "rank.png" {
+		t.Errorf("got file name %q", file.Name)
+	}
+}
+
+func TestDecodeAvatar(t *testing.T) {
+	encode := func(width, height int) []byte {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	if _, err := decodeAvatar(encode(16, 16)); err != nil {
+		t.Errorf("failed decoding a small avatar: %v", err)
+	}
+
+	if _, err := decodeAvatar(encode(maxAvatarDimension+1, 1)); err == nil {
+		t.Error("expected an error for a avatar wider than the max dimension")
+	}
+}
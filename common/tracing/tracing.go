@@ -0,0 +1,121 @@
+// Package tracing sets up opentelemetry tracing with a otlp exporter
+//
+// Spans are started from Tracer, when no endpoint is configured the global no-op provider is used and starting spans is close to free.
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/config"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	confOTLPEndpoint  = config.RegisterOption("yagpdb.tracing.otlp_endpoint", "OTLP/HTTP endpoint (host:port) to export traces to, tracing is disabled if empty", "")
+	confOTLPInsecure  = config.RegisterOption("yagpdb.tracing.otlp_insecure", "Use plain http instead of https for the OTLP endpoint", false)
+	confServiceName   = config.RegisterOption("yagpdb.tracing.service_name", "Service name reported with the traces", "yagpdb")
+	confSamplePercent = config.RegisterOption("yagpdb.tracing.sample_percent", "Percentage of traces that are sampled, child spans follow the decision of their parent", 10)
+)
+
+// Tracer is used to start all the spans in yagpdb
+var Tracer = otel.Tracer("github.com/botlabs-gg/yagpdb/v2")
+
+var provider *sdktrace.TracerProvider
+
+// Init sets up the exporter and the global tracer provider if a endpoint is configured
+func Init() error {
+	endpoint := confOTLPEndpoint.GetString()
+	if endpoint == "" {
+		return nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if confOTLPInsecure.GetBool() {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return err
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", confServiceName.GetString()),
+		attribute.String("service.version", common.VERSION),
+		attribute.String("service.instance.id", common.NodeID),
+	)
+
+	ratio := float64(confSamplePercent.GetInt()) / 100
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	logrus.Infof("Exporting traces to %s, sampling %d%%", endpoint, confSamplePercent.GetInt())
+	return nil
+}
+
+// Shutdown flushes the remaining spans
+func Shutdown() {
+	if provider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	err := provider.Shutdown(ctx)
+	if err != nil {
+		logrus.WithError(err).Error("failed shutting down tracer provider")
+	}
+}
+
+// Start starts a span as a child of the span in ctx, if any
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span if not nil, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Inject returns the trace context of ctx for storing it along with queued work, nil if ctx isn't part of a trace
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) < 1 {
+		return nil
+	}
+
+	return carrier
+}
+
+// Extract returns a context continuing the trace stored using Inject
+func Extract(carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(carrier))
+}
+
+// GuildID returns the guild_id attribute used across all spans
+func GuildID(guildID int64) attribute.KeyValue {
+	return attribute.Int64("guild_id", guildID)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"maps"
 	"math/rand"
 	"regexp"
 	"runtime/debug"
@@ -32,13 +33,16 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/common/pubsub"
 	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
 	"github.com/botlabs-gg/yagpdb/v2/common/templates"
+	"github.com/botlabs-gg/yagpdb/v2/common/tracing"
 	"github.com/botlabs-gg/yagpdb/v2/customcommands/models"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/botlabs-gg/yagpdb/v2/outboundwebhooks"
 	"github.com/botlabs-gg/yagpdb/v2/stdcommands/util"
 	"github.com/sirupsen/logrus"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -71,6 +75,14 @@ func (p *Plugin) BotInit() {
 	eventsystem.AddHandlerAsyncLastLegacy(p, bot.ConcurrentEventHandler(handleInteractionCreate), eventsystem.EventInteractionCreate)
 	eventsystem.AddHandlerAsyncLastLegacy(p, bot.ConcurrentEventHandler(handleGuildAuditLogEntryCreate), eventsystem.EventGuildAuditLogEntryCreate)
 
+	// guild event triggers, the ones that need the state from before the event run first and execute the commands in the background
+	eventsystem.AddHandlerAsyncLastLegacy(p, bot.ConcurrentEventHandler(handleGuildEventMemberAdd), eventsystem.EventGuildMemberAdd)
+	eventsystem.AddHandlerAsyncLastLegacy(p, bot.ConcurrentEventHandler(handleGuildEventMessageDelete), eventsystem.EventMessageDelete)
+	eventsystem.AddHandlerFirst(p, handleGuildEventMemberRemove, eventsystem.EventGuildMemberRemove)
+	eventsystem.AddHandlerFirst(p, handleGuildEventMessageUpdate, eventsystem.EventMessageUpdate)
+	eventsystem.AddHandlerFirst(p, handleGuildEventVoiceStateUpdate, eventsystem.EventVoiceStateUpdate)
+	eventsystem.AddHandlerFirst(p, handleGuildEventMemberUpdate, eventsystem.EventGuildMemberUpdate)
+
 	pubsub.AddHandler("custom_commands_run_now", handleCustomCommandsRunNow, models.CustomCommand{})
 	pubsub.AddHandler(SlashCommandResyncEvent, handleResyncGuildSlashCommands, nil)
 	scheduledevents2.RegisterHandler("cc_next_run", NextRunScheduledEvent{}, handleNextRunScheduledEVent)
@@ -286,7 +298,7 @@ var cmdListCommands = &commands.YAGCommand{
 	CmdCategory:    commands.CategoryTool,
 	Name:           "CustomCommands",
 	Aliases:        []string{"cc"},
-	Description:    "Shows a custom command specified by id, trigger, or name, or lists them all",
+	Description:    "Shows a custom command specified by id, trigger, or name, or lists them all. `cc stats <id>` shows its execution stats",
 	ArgumentCombos: [][]int{{0}, {1}, {}},
 	Arguments: []*dcmd.ArgDef{
 		{Name: "ID", Type: dcmd.Int},
@@ -298,6 +310,8 @@ var cmdListCommands = &commands.YAGCommand{
 		{Name: "file", Help: "Send responses in file"},
 		{Name: "color", Help: "Use syntax highlighting (Go)"},
 		{Name: "raw", Help: "Force raw output"},
+		{Name: "lint", Help: "Check the command for problems instead of showing it"},
+		{Name: "stats", Help: "Show execution stats of the command instead of showing it, same as cc stats <id>"},
 	},
 	RunFunc: func(data *dcmd.Data) (interface{}, error) {
 		ccs, err := models.CustomCommands(qm.Where("guild_id = ?", data.GuildData.GS.ID), qm.OrderBy("local_id")).AllG(data.Context())
@@ -317,6 +331,13 @@ var cmdListCommands = &commands.YAGCommand{
 		}
 
 		foundCCS, provided := FindCommands(ccs, data)
+
+		showStats := data.Switches["stats"].Value != nil
+		if ref, ok := ccStatsSubcommand(data, foundCCS); ok {
+			foundCCS = findCommandsByRef(ccs, ref)
+			showStats = true
+		}
+
 		if len(foundCCS) < 1 {
 			list := StringCommands(ccs, groupMap)
 			if len(list) == 0 {
@@ -335,6 +356,20 @@ var cmdListCommands = &commands.YAGCommand{
 
 		cc := foundCCS[0]
 
+		if data.Switches["lint"].Value != nil {
+			isPremium, _ := premium.IsGuildPremium(data.GuildData.GS.ID)
+			return lintCommandOutput(cc, isPremium), nil
+		}
+
+		if showStats {
+			isPremium, _ := premium.IsGuildPremium(data.GuildData.GS.ID)
+			out, err := ccStatsOutput(cc, isPremium)
+			if err != nil {
+				return "Failed retrieving execution stats", err
+			}
+			return out, nil
+		}
+
 		highlight := "txt"
 		if data.Switches["color"].Value != nil {
 			highlight = "go"
@@ -436,6 +471,30 @@ var cmdListCommands = &commands.YAGCommand{
 	},
 }
 
+// max number of warnings shown by the lint switch, to stay within the message length limit
+const maxLintWarningsShown = 15
+
+func lintCommandOutput(cc *models.CustomCommand, isPremium bool) string {
+	warnings := LintCustomCommand(cc, isPremium)
+	if len(warnings) == 0 {
+		return fmt.Sprintf("No problems found in custom command #%d.", cc.LocalID)
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "Found %d possible problem(s) in custom command #%d:\n```\n", len(warnings), cc.LocalID)
+	for i, w := range warnings {
+		if i >= maxLintWarningsShown {
+			fmt.Fprintf(&out, "... and %d more\n", len(warnings)-i)
+			break
+		}
+
+		out.WriteString(common.CutStringShort(w.String(), 200) + "\n")
+	}
+	out.WriteString("```")
+
+	return out.String()
+}
+
 func cmdControlPanelLink(cmd *models.CustomCommand) string {
 	return fmt.Sprintf("%s/customcommands/commands/%d/", web.ManageServerURL(cmd.GuildID), cmd.LocalID)
 }
@@ -453,13 +512,7 @@ func FindCommands(ccs []*models.CustomCommand, data *dcmd.Data) (foundCCS []*mod
 			}
 		}
 	} else if data.Args[1].Value != nil {
-		// Find by trigger/name
-		nameOrTrigger := data.Args[1].Str()
-		for _, v := range ccs {
-			if strings.EqualFold(nameOrTrigger, v.TextTrigger) || (v.Name.Valid && strings.EqualFold(nameOrTrigger, v.Name.String)) {
-				foundCCS = append(foundCCS, v)
-			}
-		}
+		foundCCS = findCommandsByNameOrTrigger(ccs, data.Args[1].Str())
 	} else {
 		provided = false
 	}
@@ -467,6 +520,49 @@ func FindCommands(ccs []*models.CustomCommand, data *dcmd.Data) (foundCCS []*mod
 	return
 }
 
+func findCommandsByNameOrTrigger(ccs []*models.CustomCommand, nameOrTrigger string) []*models.CustomCommand {
+	var found []*models.CustomCommand
+	for _, v := range ccs {
+		if strings.EqualFold(nameOrTrigger, v.TextTrigger) || (v.Name.Valid && strings.EqualFold(nameOrTrigger, v.Name.String)) {
+			found = append(found, v)
+		}
+	}
+
+	return found
+}
+
+// findCommandsByRef finds commands by an id, or by trigger or name if ref isn't a number
+func findCommandsByRef(ccs []*models.CustomCommand, ref string) []*models.CustomCommand {
+	id, err := strconv.ParseInt(ref, 10, 64)
+	if err != nil {
+		return findCommandsByNameOrTrigger(ccs, ref)
+	}
+
+	var found []*models.CustomCommand
+	for _, v := range ccs {
+		if v.LocalID == id {
+			found = append(found, v)
+		}
+	}
+
+	return found
+}
+
+// ccStatsSubcommand returns the command reference in `cc stats <id>`, which is parsed as the trigger "stats <id>".
+// Commands that actually have such a trigger or name take precedence.
+func ccStatsSubcommand(data *dcmd.Data, found []*models.CustomCommand) (string, bool) {
+	if len(found) > 0 || data.Args[0].Value != nil || data.Args[1].Value == nil {
+		return "", false
+	}
+
+	fields := strings.Fields(data.Args[1].Str())
+	if len(fields) < 2 || !strings.EqualFold(fields[0], "stats") {
+		return "", false
+	}
+
+	return strings.Join(fields[1:], " "), true
+}
+
 func StringCommands(ccs []*models.CustomCommand, gMap map[int64]string) string {
 	out := ""
 
@@ -645,6 +741,12 @@ func ExecuteCustomCommand(cmd *models.CustomCommand, tmplCtx *templates.Context)
 	}()
 
 	tmplCtx.Name = "CC #" + strconv.Itoa(int(cmd.LocalID))
+
+	// covers waiting for the lock and sending the response, the execution itself gets its own span
+	spanCtx, span := tracing.Start(tmplCtx.Context(), "custom command", tracing.GuildID(cmd.GuildID), attribute.Int64("cc_id", cmd.LocalID))
+	defer span.End()
+	tmplCtx.Ctx = spanCtx
+
 	tmplCtx.Data["CCID"] = cmd.LocalID
 	tmplCtx.Data["CCRunCount"] = cmd.RunCount + 1
 	tmplCtx.Data["CCTrigger"] = cmd.TextTrigger
@@ -683,8 +785,12 @@ func ExecuteCustomCommand(cmd *models.CustomCommand, tmplCtx *templates.Context)
 	f.Debug("Custom command triggered")
 
 	chanMsg := cmd.Responses[rand.Intn(len(cmd.Responses))]
+	started := time.Now()
 	out, err := tmplCtx.Execute(chanMsg)
 
+	// the counters keep being used by anything the execution scheduled, so record a copy
+	go recordCCExecutionStats(cmd.GuildID, cmd.LocalID, time.Since(started), tmplCtx.OpsUsed, maps.Clone(tmplCtx.Counters), err)
+
 	// trim whitespace for accurate character count
 	out = strings.TrimSpace(out)
 
@@ -877,6 +983,10 @@ func updatePostCommandRan(cmd *models.CustomCommand, runErr error) {
 		_, err = common.PQ.Exec(qNoErr, cmd.GuildID, cmd.LocalID)
 	} else {
 		_, err = common.PQ.Exec(qErr, cmd.GuildID, cmd.LocalID, runErr.Error())
+		outboundwebhooks.Emit(cmd.GuildID, outboundwebhooks.EventCustomCommandError, &outboundwebhooks.CustomCommandErrorData{
+			CCID:  cmd.LocalID,
+			Error: runErr.Error(),
+		})
 	}
 
 	if err != nil {
@@ -6,7 +6,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math"
 	"regexp"
 	"slices"
 	"sort"
@@ -85,6 +84,12 @@ const (
 	CommandTriggerSlash              CommandTriggerType = 12
 	CommandTriggerUserContextMenu    CommandTriggerType = 13
 	CommandTriggerMessageContextMenu CommandTriggerType = 14
+	CommandTriggerMemberJoin         CommandTriggerType = 15
+	CommandTriggerMemberLeave        CommandTriggerType = 16
+	CommandTriggerMessageDelete      CommandTriggerType = 17
+	CommandTriggerMessageEdit        CommandTriggerType = 18
+	CommandTriggerVoiceJoin          CommandTriggerType = 19
+	CommandTriggerServerBoost        CommandTriggerType = 20
 )
 
 var (
@@ -104,6 +109,12 @@ var (
 		CommandTriggerSlash,
 		CommandTriggerUserContextMenu,
 		CommandTriggerMessageContextMenu,
+		CommandTriggerMemberJoin,
+		CommandTriggerMemberLeave,
+		CommandTriggerMessageDelete,
+		CommandTriggerMessageEdit,
+		CommandTriggerVoiceJoin,
+		CommandTriggerServerBoost,
 	}
 
 	triggerStrings = map[CommandTriggerType]string{
@@ -122,6 +133,12 @@ var (
 		CommandTriggerSlash:              "Slash Command",
 		CommandTriggerUserContextMenu:    "User Context Menu",
 		CommandTriggerMessageContextMenu: "Message Context Menu",
+		CommandTriggerMemberJoin:         "Member Join",
+		CommandTriggerMemberLeave:        "Member Leave",
+		CommandTriggerMessageDelete:      "Message Delete",
+		CommandTriggerMessageEdit:        "Message Edit",
+		CommandTriggerVoiceJoin:          "Voice Join",
+		CommandTriggerServerBoost:        "Server Boost",
 	}
 )
 
@@ -206,6 +223,7 @@ type CustomCommand struct {
 	ContextChannel        int64 `schema:"context_channel" valid:"channel,true"`
 	TimeContextChannel    int64 `schema:"time_context_channel" valid:"channel,true"`
 	RoleContextChannel    int64 `schema:"role_context_channel" valid:"channel,true"`
+	EventContextChannel   int64 `schema:"event_context_channel" valid:"channel,true"`
 	RedirectErrorsChannel int64 `schema:"redirect_errors_channel" valid:"channel,true"`
 
 	TimeTriggerInterval       int     `schema:"time_trigger_interval"`
@@ -1028,25 +1046,20 @@ func (p *Plugin) AllFeatureFlags() []string {
 	}
 }
 
-func getDatabaseEntries(ctx context.Context, guildID int64, page int, queryType, query string, limit int) (models.TemplatesUserDatabaseSlice, int64, error) {
+func getDatabaseEntries(ctx context.Context, guildID int64, page int, filter *DBEntryFilter, limit int) (models.TemplatesUserDatabaseSlice, int64, error) {
 	qms := []qm.QueryMod{
 		models.TemplatesUserDatabaseWhere.GuildID.EQ(guildID),
 	}
 
-	if len(query) > 0 {
-		switch queryType {
-		case "id":
-			qms = append(qms, qm.Where("id = ?", query))
-		case "user_id":
-			qms = append(qms, qm.Where("user_id = ?", query))
-		case "key":
-			qms = append(qms, qm.Where("key ILIKE ?", query))
-		}
-	}
+	qms = append(qms, filter.queryMods()...)
 	qms = append(qms, qm.Where("(expires_at IS NULL or expires_at > now())"))
 	count, err := models.TemplatesUserDatabases(qms...).CountG(ctx)
-	if int64(page) > (count / 100) {
-		page = int(math.Ceil(float64(count) / 100))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if totalPages := dbTotalPages(count, limit); page > totalPages {
+		page = totalPages
 	}
 	if page > 1 {
 		qms = append(qms, qm.Offset((limit * (page - 1))))
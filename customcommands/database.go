@@ -0,0 +1,359 @@
+package customcommands
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/pubsub"
+	"github.com/botlabs-gg/yagpdb/v2/common/templates"
+	"github.com/botlabs-gg/yagpdb/v2/customcommands/models"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+const (
+	// max number of entries in a single import
+	MaxDBImportEntries = 10000
+
+	// max size of a serialized value, the same as when setting it from a template
+	MaxDBValueSize = 100000
+
+	dbExportBatchSize = 1000
+)
+
+var ErrDBImportAboveLimit = errors.New("Importing would put the database above its limit")
+
+// DBEntryFilter narrows down the database entries shown in the dashboard, empty fields match everything
+type DBEntryFilter struct {
+	ID     string
+	UserID string
+	// postgres ILIKE pattern
+	Key string
+}
+
+func (f *DBEntryFilter) queryMods() []qm.QueryMod {
+	var qms []qm.QueryMod
+	if f.ID != "" {
+		qms = append(qms, qm.Where("id = ?", f.ID))
+	}
+	if f.UserID != "" {
+		qms = append(qms, qm.Where("user_id = ?", f.UserID))
+	}
+	if f.Key != "" {
+		qms = append(qms, qm.Where("key ILIKE ?", f.Key))
+	}
+
+	return qms
+}
+
+func (f *DBEntryFilter) IsEmpty() bool {
+	return f.ID == "" && f.UserID == "" && f.Key == ""
+}
+
+// GuildDBLimit returns the max number of database entries a guild can have
+func GuildDBLimit(memberCount int64, isPremium bool) int64 {
+	limitMuliplier := int64(1)
+	if isPremium {
+		limitMuliplier = 10
+	}
+
+	return memberCount * 50 * limitMuliplier
+}
+
+// EvictDBLimitCache makes the bot recount the entries of the guild the next time it checks the limit
+func EvictDBLimitCache(guildID int64) {
+	pubsub.EvictCacheSet(cachedDBLimits, guildID)
+}
+
+// decodeDBValue decodes the msgpack encoded value of a entry, the same way it's decoded for templates
+func decodeDBValue(raw []byte) (v interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New(fmt.Sprint("panic decoding db entry: ", r))
+		}
+	}()
+
+	err = newDecoder(bytes.NewBuffer(raw)).Decode(&v)
+	return v, err
+}
+
+// dbValueJSON returns the value of the entry as indented json, or an error if the value can't be represented as json
+func dbValueJSON(m *models.TemplatesUserDatabase) (string, error) {
+	entry, err := ToLightDBEntry(m)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.MarshalIndent(entry.Value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// parseDBValueJSON parses a json value into what the template functions would store,
+// whole numbers are stored as integers like they would be when set from a template
+func parseDBValueJSON(data string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	err := dec.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+
+	if dec.More() {
+		return nil, errors.New("unexpected data after value")
+	}
+
+	return normalizeJSONNumbers(v), nil
+}
+
+func normalizeJSONNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = normalizeJSONNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = normalizeJSONNumbers(e)
+		}
+	}
+
+	return v
+}
+
+// setDBEntryValue sets the value of the entry like dbSet would
+func setDBEntryValue(m *models.TemplatesUserDatabase, value interface{}) error {
+	serialized, err := serializeValue(value)
+	if err != nil {
+		return err
+	}
+
+	m.ValueRaw = serialized
+	m.ValueNum = templates.ToFloat64(value)
+	return nil
+}
+
+// DBExportEntry is a database entry in the json export, value_raw holds the exact stored value
+// while value is only there to make the export readable
+type DBExportEntry struct {
+	UserID    int64           `json:"user_id,string"`
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value,omitempty"`
+	ValueNum  float64         `json:"value_num"`
+	ValueRaw  []byte          `json:"value_raw,omitempty"`
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func toDBExportEntry(m *models.TemplatesUserDatabase) *DBExportEntry {
+	entry := &DBExportEntry{
+		UserID:    m.UserID,
+		Key:       m.Key,
+		ValueNum:  m.ValueNum,
+		ValueRaw:  m.ValueRaw,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+
+	if m.ExpiresAt.Valid {
+		t := m.ExpiresAt.Time
+		entry.ExpiresAt = &t
+	}
+
+	if v, err := decodeDBValue(m.ValueRaw); err == nil {
+		if common.IsNumber(v) {
+			v = m.ValueNum
+		}
+
+		// values with non string map keys can't be represented as json, value_raw still has them
+		if b, err := json.Marshal(v); err == nil {
+			entry.Value = b
+		}
+	}
+
+	return entry
+}
+
+// WriteDBExport writes all the non expired database entries of the guild as a json array
+func WriteDBExport(ctx context.Context, guildID int64, w io.Writer) error {
+	_, err := io.WriteString(w, "[")
+	if err != nil {
+		return err
+	}
+
+	lastID := int64(0)
+	first := true
+	for {
+		entries, err := models.TemplatesUserDatabases(
+			qm.Where("guild_id = ? AND id > ? AND (expires_at IS NULL OR expires_at > now())", guildID, lastID),
+			qm.OrderBy("id asc"), qm.Limit(dbExportBatchSize)).AllG(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range entries {
+			b, err := json.Marshal(toDBExportEntry(m))
+			if err != nil {
+				return err
+			}
+
+			if !first {
+				b = append([]byte(",\n"), b...)
+			}
+			first = false
+
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+
+			lastID = m.ID
+		}
+
+		if len(entries) < dbExportBatchSize {
+			break
+		}
+	}
+
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
+
+// parseDBImport parses and validates a json export, returning the entries to upsert
+func parseDBImport(r io.Reader) ([]*models.TemplatesUserDatabase, error) {
+	var entries []*DBExportEntry
+	err := json.NewDecoder(r).Decode(&entries)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed parsing import file")
+	}
+
+	if len(entries) > MaxDBImportEntries {
+		return nil, fmt.Errorf("Max %d entries can be imported at a time", MaxDBImportEntries)
+	}
+
+	now := time.Now()
+	result := make([]*models.TemplatesUserDatabase, 0, len(entries))
+	for i, e := range entries {
+		if e.Key == "" {
+			return nil, fmt.Errorf("Entry %d has no key", i+1)
+		}
+
+		if e.ExpiresAt != nil && e.ExpiresAt.Before(now) {
+			continue
+		}
+
+		m := &models.TemplatesUserDatabase{
+			UserID:    e.UserID,
+			Key:       limitString(e.Key, 256),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		if e.ExpiresAt != nil {
+			m.ExpiresAt = null.TimeFrom(*e.ExpiresAt)
+		}
+
+		switch {
+		case len(e.ValueRaw) > 0:
+			if len(e.ValueRaw) > MaxDBValueSize {
+				return nil, fmt.Errorf("Entry %d has a value_raw larger than %d bytes", i+1, MaxDBValueSize)
+			}
+			if _, err := decodeDBValue(e.ValueRaw); err != nil {
+				return nil, fmt.Errorf("Entry %d has a invalid value_raw: %v", i+1, err)
+			}
+			m.ValueRaw = e.ValueRaw
+			m.ValueNum = e.ValueNum
+		case len(e.Value) > 0:
+			v, err := parseDBValueJSON(string(e.Value))
+			if err != nil {
+				return nil, fmt.Errorf("Entry %d has a invalid value: %v", i+1, err)
+			}
+
+			if err := setDBEntryValue(m, v); err != nil {
+				return nil, fmt.Errorf("Entry %d: %v", i+1, err)
+			}
+		default:
+			if err := setDBEntryValue(m, e.ValueNum); err != nil {
+				return nil, fmt.Errorf("Entry %d: %v", i+1, err)
+			}
+		}
+
+		result = append(result, m)
+	}
+
+	return result, nil
+}
+
+// importDBEntries upserts the entries, overwriting existing entries with the same user and key.
+// Nothing is imported if the guild would end up above limit.
+func importDBEntries(ctx context.Context, guildID int64, entries []*models.TemplatesUserDatabase, limit int64) error {
+	tx, err := common.PQ.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range entries {
+		_, err = tx.ExecContext(ctx, `INSERT INTO templates_user_database (created_at, updated_at, expires_at, guild_id, user_id, key, value_num, value_raw)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (guild_id, user_id, key) DO UPDATE SET updated_at = $2, expires_at = $3, value_num = $7, value_raw = $8`,
+			m.CreatedAt, m.UpdatedAt, m.ExpiresAt, guildID, m.UserID, m.Key, m.ValueNum, m.ValueRaw)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	var count int64
+	err = tx.QueryRowContext(ctx, "SELECT count(*) FROM templates_user_database WHERE guild_id = $1 AND (expires_at IS NULL OR expires_at > now())", guildID).Scan(&count)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count > limit {
+		tx.Rollback()
+		return fmt.Errorf("%w: %d entries while the limit is %d", ErrDBImportAboveLimit, count, limit)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	EvictDBLimitCache(guildID)
+	return nil
+}
+
+// getDBEntry returns the entry with the id, or nil if it does not exist
+func getDBEntry(ctx context.Context, guildID, id int64) (*models.TemplatesUserDatabase, error) {
+	m, err := models.TemplatesUserDatabases(qm.Where("guild_id = ? AND id = ?", guildID, id)).OneG(ctx)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return m, err
+}
+
+func dbTotalPages(total int64, perPage int) int {
+	return int(math.Ceil(float64(total) / float64(perPage)))
+}
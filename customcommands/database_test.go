@@ -0,0 +1,155 @@
+package customcommands
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/botlabs-gg/yagpdb/v2/common/templates"
+)
+
+func TestParseDBValueJSON(t *testing.T) {
+	got, err := parseDBValueJSON(`{"a": 1, "b": 1.5, "c": [2, "x"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"a": int64(1),
+		"b": 1.5,
+		"c": []interface{}{int64(2), "x"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDBValueJSON() = %#v, want %#v", got, want)
+	}
+
+	if _, err := parseDBValueJSON(`1 2`); err == nil {
+		t.Error("expected an error for trailing data")
+	}
+}
+
+func TestParseDBImport(t *testing.T) {
+	input := `[
+		{"user_id": "1", "key": "a", "value": 5},
+		{"user_id": "2", "key": "b", "value": "hi", "expires_at": "2001-01-01T00:00:00Z"},
+		{"user_id": "3", "key": "c", "value": {"x": [1]}}
+	]`
+
+	entries, err := parseDBImport(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2 since expired entries are skipped", len(entries))
+	}
+
+	if entries[0].UserID != 1 || entries[0].Key != "a" || entries[0].ValueNum != 5 {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+
+	// the exported value_raw should import as the exact same value
+	exported := toDBExportEntry(entries[1])
+	v, err := decodeDBValue(exported.ValueRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.Value == nil {
+		t.Errorf("expected a readable value in the export, decoded value was %#v", v)
+	}
+
+	if _, err := parseDBImport(strings.NewReader(`[{"user_id": "1", "key": ""}]`)); err == nil {
+		t.Error("expected an error for a missing key")
+	}
+
+	oversized := base64.StdEncoding.EncodeToString(make([]byte, MaxDBValueSize+1))
+	if _, err := parseDBImport(strings.NewReader(`[{"user_id": "1", "key": "a", "value_raw": "` + oversized + `"}]`)); err == nil {
+		t.Error("expected an error for a value_raw above the max size")
+	}
+}
+
+func TestDBValuesEqual(t *testing.T) {
+	stored, err := decodeDBValue(mustSerialize(t, templates.SDict{"items": templates.Slice{"sword", 2}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		stored interface{}
+		value  interface{}
+		want   bool
+	}{
+		{"both nil", nil, nil, true},
+		{"nil and value", nil, "a", false},
+		{"numbers of different types", float64(5), int64(5), true},
+		{"different numbers", float64(5), 6, false},
+		{"number and string", float64(5), "5", false},
+		{"equal sdict", stored, templates.SDict{"items": templates.Slice{"sword", 2}}, true},
+		{"different sdict", stored, templates.SDict{"items": templates.Slice{"sword", 3}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dbValuesEqual(tt.stored, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("dbValuesEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustSerialize(t *testing.T, v interface{}) []byte {
+	b, err := serializeValue(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestDBTransferAmounts(t *testing.T) {
+	entry := func(v interface{}, live bool) *dbTransferEntry {
+		raw, err := serializeValue(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &dbTransferEntry{raw: raw, live: live}
+	}
+
+	inventory := entry(templates.SDict{"apples": 3}, true)
+
+	tests := []struct {
+		name     string
+		from, to *dbTransferEntry
+		fromNum  float64
+		toNum    float64
+		ok       bool
+		err      bool
+	}{
+		{name: "numbers", from: entry(int64(10), true), to: entry(2.5, true), fromNum: 6, toNum: 6.5, ok: true},
+		{name: "new destination", from: entry(int64(10), true), fromNum: 6, toNum: 4, ok: true},
+		{name: "expired destination", from: entry(int64(10), true), to: entry("old", false), fromNum: 6, toNum: 4, ok: true},
+		{name: "not enough", from: entry(int64(3), true), to: entry(int64(1), true)},
+		{name: "missing source", to: entry(int64(1), true)},
+		{name: "expired source", from: entry(int64(10), false)},
+		{name: "into a sdict", from: entry(int64(10), true), to: inventory, err: true},
+		{name: "into a string", from: entry(int64(10), true), to: entry("10", true), err: true},
+		{name: "from a string", from: entry("10", true), to: entry(int64(1), true), err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fromNum, toNum, ok, err := dbTransferAmounts(test.from, test.to, 4)
+			if (err != nil) != test.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != test.ok || fromNum != test.fromNum || toNum != test.toNum {
+				t.Errorf("got (%v, %v, %v), expected (%v, %v, %v)", fromNum, toNum, ok, test.fromNum, test.toNum, test.ok)
+			}
+		})
+	}
+}
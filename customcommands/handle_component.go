@@ -101,7 +101,7 @@ func handleInteractionCreate(evt *eventsystem.EventData) {
 
 		deferResponseToCCs(&interaction, triggeredCmds)
 		for _, matched := range triggeredCmds {
-			err = ExecuteCustomCommandFromComponent(matched.CC, evt.GS, cState, matched.Args, matched.Stripped, &interaction)
+			err = ExecuteCustomCommandFromComponent(evt.Context(), matched.CC, evt.GS, cState, matched.Args, matched.Stripped, &interaction)
 			if err != nil {
 				logger.WithField("guild", cState.GuildID).WithField("cc_id", matched.CC.LocalID).WithError(err).Error("Error executing custom command")
 			}
@@ -130,7 +130,7 @@ func handleInteractionCreate(evt *eventsystem.EventData) {
 
 		deferResponseToCCs(&interaction, triggeredCmds)
 		for _, matched := range triggeredCmds {
-			err = ExecuteCustomCommandFromModal(matched.CC, evt.GS, cState, matched.Args, matched.Stripped, &interaction)
+			err = ExecuteCustomCommandFromModal(evt.Context(), matched.CC, evt.GS, cState, matched.Args, matched.Stripped, &interaction)
 			if err != nil {
 				logger.WithField("guild", cState.GuildID).WithField("cc_id", matched.CC.LocalID).WithError(err).Error("Error executing custom command")
 			}
@@ -269,9 +269,10 @@ func findComponentOrModalTriggerCustomCommands(ctx context.Context, cs *dstate.C
 	return filtered, false, nil
 }
 
-func ExecuteCustomCommandFromComponent(cc *models.CustomCommand, gs *dstate.GuildSet, cs *dstate.ChannelState, cmdArgs []string, stripped string, interaction *templates.CustomCommandInteraction) error {
+func ExecuteCustomCommandFromComponent(ctx context.Context, cc *models.CustomCommand, gs *dstate.GuildSet, cs *dstate.ChannelState, cmdArgs []string, stripped string, interaction *templates.CustomCommandInteraction) error {
 	ms := dstate.MemberStateFromMember(interaction.Member)
 	tmplCtx := templates.NewContext(gs, cs, ms)
+	tmplCtx.Ctx = ctx
 	tmplCtx.CurrentFrame.Interaction = interaction
 
 	tmplCtx.Data["Interaction"] = interaction
@@ -333,9 +334,10 @@ func CheckMatchComponent(cmd *models.CustomCommand, cID string) (match bool, str
 	return
 }
 
-func ExecuteCustomCommandFromModal(cc *models.CustomCommand, gs *dstate.GuildSet, cs *dstate.ChannelState, cmdArgs []string, stripped string, interaction *templates.CustomCommandInteraction) error {
+func ExecuteCustomCommandFromModal(ctx context.Context, cc *models.CustomCommand, gs *dstate.GuildSet, cs *dstate.ChannelState, cmdArgs []string, stripped string, interaction *templates.CustomCommandInteraction) error {
 	ms := dstate.MemberStateFromMember(interaction.Member)
 	tmplCtx := templates.NewContext(gs, cs, ms)
+	tmplCtx.Ctx = ctx
 	tmplCtx.CurrentFrame.Interaction = interaction
 
 	tmplCtx.Data["Interaction"] = interaction
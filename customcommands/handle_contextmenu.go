@@ -100,7 +100,7 @@ func handleContextMenuInteraction(evt *eventsystem.EventData, cs *dstate.Channel
 	}
 
 	deferResponseToCCs(interaction, []*TriggeredCC{{CC: matched}})
-	if err := ExecuteCustomCommandFromContextMenu(matched, evt.GS, cs, interaction); err != nil {
+	if err := ExecuteCustomCommandFromContextMenu(evt.Context(), matched, evt.GS, cs, interaction); err != nil {
 		logger.WithField("guild", cs.GuildID).WithField("cc_id", matched.LocalID).WithError(err).Error("Error executing context menu custom command")
 	}
 }
@@ -118,9 +118,10 @@ func handleContextMenuInteraction(evt *eventsystem.EventData, cs *dstate.Channel
 // A nil member is passed to NewContext so .User/.Member are not exposed and sendDM (which
 // targets the context member) is disabled — a context menu command must not be usable to
 // DM an arbitrary target. This mirrors role trigger commands.
-func ExecuteCustomCommandFromContextMenu(cc *models.CustomCommand, gs *dstate.GuildSet, cs *dstate.ChannelState, interaction *templates.CustomCommandInteraction) error {
+func ExecuteCustomCommandFromContextMenu(ctx context.Context, cc *models.CustomCommand, gs *dstate.GuildSet, cs *dstate.ChannelState, interaction *templates.CustomCommandInteraction) error {
 	ms := dstate.MemberStateFromMember(interaction.Member)
 	tmplCtx := templates.NewContext(gs, cs, nil)
+	tmplCtx.Ctx = ctx
 	tmplCtx.CurrentFrame.Interaction = interaction
 
 	data := interaction.DataCommand
@@ -0,0 +1,373 @@
+package customcommands
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/bot/eventsystem"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/multiratelimit"
+	"github.com/botlabs-gg/yagpdb/v2/common/templates"
+	"github.com/botlabs-gg/yagpdb/v2/customcommands/models"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+var (
+	guildEventTriggerTypes = []CommandTriggerType{
+		CommandTriggerMemberJoin,
+		CommandTriggerMemberLeave,
+		CommandTriggerMessageDelete,
+		CommandTriggerMessageEdit,
+		CommandTriggerVoiceJoin,
+		CommandTriggerServerBoost,
+	}
+
+	// Raids, purges and mass voice moves can fire a lot of these at once, so they're limited per guild and trigger type
+	GuildEventCCRunLimit = multiratelimit.NewMultiRatelimiter(0.5, 10)
+)
+
+type GuildEventRunLimitKey struct {
+	GuildID     int64
+	TriggerType CommandTriggerType
+}
+
+// IsGuildEventTrigger reports whether the trigger type runs on guild events, as opposed to user input or timers
+func IsGuildEventTrigger(t CommandTriggerType) bool {
+	return slices.Contains(guildEventTriggerTypes, t)
+}
+
+// guildEventUsesContextChannel reports whether the command runs in its configured channel, message triggers run in the channel of the message
+func guildEventUsesContextChannel(t CommandTriggerType) bool {
+	return t != CommandTriggerMessageDelete && t != CommandTriggerMessageEdit
+}
+
+var cachedCommandsGuildEventTrigger = common.CacheSet.RegisterSlot("custom_commands_guild_event_trigger", nil, int64(0))
+
+func BotCachedGetCommandsWithGuildEventTrigger(guildID int64, ctx context.Context) ([]*models.CustomCommand, error) {
+	v, err := cachedCommandsGuildEventTrigger.GetCustomFetch(guildID, func(key interface{}) (interface{}, error) {
+		var cmds []*models.CustomCommand
+		var err error
+
+		types := make([]interface{}, len(guildEventTriggerTypes))
+		for i, v := range guildEventTriggerTypes {
+			types[i] = int(v)
+		}
+
+		common.LogLongCallTime(time.Second, true, "Took longer than a second to fetch custom commands from db", logrus.Fields{"guild": guildID}, func() {
+			cmds, err = models.CustomCommands(qm.Where("guild_id = ?", guildID), qm.AndIn("trigger_type IN ?", types...), qm.OrderBy("local_id desc"), qm.Load("Group")).AllG(ctx)
+		})
+
+		return cmds, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]*models.CustomCommand), nil
+}
+
+// guildEvent is a guild event custom commands can be triggered by
+type guildEvent struct {
+	triggerType CommandTriggerType
+	gs          *dstate.GuildSet
+
+	// The channel the event happened in, checked against the channel restrictions and used as the context channel of message triggers
+	// 0 if the event isn't tied to a channel
+	channelID int64
+
+	// The member the event is about, checked against the role restrictions
+	ms *dstate.MemberState
+
+	msg  *discordgo.Message
+	data map[string]interface{}
+}
+
+func findGuildEventCommands(ctx context.Context, evt *guildEvent) ([]*models.CustomCommand, error) {
+	allCmds, err := BotCachedGetCommandsWithGuildEventTrigger(evt.gs.ID, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterGuildEventCommands(allCmds, evt), nil
+}
+
+// filterGuildEventCommands returns the commands that should run for the event
+func filterGuildEventCommands(cmds []*models.CustomCommand, evt *guildEvent) []*models.CustomCommand {
+	var matches []*models.CustomCommand
+	for _, cmd := range cmds {
+		if CommandTriggerType(cmd.TriggerType) != evt.triggerType {
+			continue
+		}
+
+		if cmd.Disabled || cmd.R != nil && cmd.R.Group != nil && cmd.R.Group.Disabled {
+			continue
+		}
+
+		if guildEventUsesContextChannel(evt.triggerType) && cmd.ContextChannel == 0 {
+			continue
+		}
+
+		if evt.channelID != 0 && !CmdRunsInChannel(cmd, evt.channelID) {
+			continue
+		}
+
+		if evt.ms != nil && evt.ms.Member != nil && !CmdRunsForUser(cmd, evt.ms) {
+			continue
+		}
+
+		matches = append(matches, cmd)
+	}
+
+	return matches
+}
+
+// allowGuildEventRun reports whether commands can run for the event, or if the guild has had too many of this type of event recently
+func allowGuildEventRun(evt *guildEvent) bool {
+	return GuildEventCCRunLimit.AllowN(GuildEventRunLimitKey{GuildID: evt.gs.ID, TriggerType: evt.triggerType}, time.Now(), 1)
+}
+
+// guildEventTemplateContext returns the template context to run cmd with for the event, or nil if the channel it runs in is gone
+func guildEventTemplateContext(evt *guildEvent, cmd *models.CustomCommand) *templates.Context {
+	channelID := evt.channelID
+	if guildEventUsesContextChannel(evt.triggerType) {
+		channelID = cmd.ContextChannel
+	}
+
+	cs := evt.gs.GetChannelOrThread(channelID)
+	if cs == nil {
+		return nil
+	}
+
+	tmplCtx := templates.NewContext(evt.gs, cs, evt.ms)
+	if evt.msg != nil {
+		tmplCtx.Msg = evt.msg
+		tmplCtx.Data["Message"] = evt.msg
+	}
+
+	for k, v := range evt.data {
+		tmplCtx.Data[k] = v
+	}
+
+	return tmplCtx
+}
+
+func runGuildEventCommands(ctx context.Context, evt *guildEvent) {
+	cmds, err := findGuildEventCommands(ctx, evt)
+	if err != nil {
+		logger.WithField("guild", evt.gs.ID).WithError(err).Warn("failed fetching guild event trigger commands")
+		return
+	}
+
+	if len(cmds) == 0 {
+		return
+	}
+
+	if !allowGuildEventRun(evt) {
+		logger.WithField("guild", evt.gs.ID).WithField("trigger_type", evt.triggerType.String()).Warn("Skipping guild event custom commands, too many events")
+		return
+	}
+
+	metricsExecutedCommands.With(prometheus.Labels{"trigger": "guild_event"}).Inc()
+
+	for _, cmd := range cmds {
+		tmplCtx := guildEventTemplateContext(evt, cmd)
+		if tmplCtx == nil {
+			continue
+		}
+
+		tmplCtx.Ctx = ctx
+		err = ExecuteCustomCommand(cmd, tmplCtx)
+		if err != nil {
+			logger.WithField("guild", evt.gs.ID).WithField("cc_id", cmd.LocalID).WithError(err).Error("Error executing guild event custom command")
+		}
+	}
+}
+
+func handleGuildEventMemberAdd(evt *eventsystem.EventData) {
+	m := evt.GuildMemberAdd()
+	if evt.GS == nil || !evt.HasFeatureFlag(featureFlagHasCommands) {
+		return
+	}
+
+	ms := dstate.MemberStateFromMember(m.Member)
+	runGuildEventCommands(evt.Context(), &guildEvent{
+		triggerType: CommandTriggerMemberJoin,
+		gs:          evt.GS,
+		ms:          ms,
+	})
+}
+
+// handleGuildEventMemberRemove runs before the state is updated so the roles of the member are still available
+func handleGuildEventMemberRemove(evt *eventsystem.EventData) (retry bool, err error) {
+	m := evt.GuildMemberRemove()
+	if evt.GS == nil || !evt.HasFeatureFlag(featureFlagHasCommands) {
+		return false, nil
+	}
+
+	ms := bot.State.GetMember(m.GuildID, m.User.ID)
+	if ms == nil {
+		ms = dstate.MemberStateFromMember(m.Member)
+	}
+
+	go runGuildEventCommands(evt.Context(), &guildEvent{
+		triggerType: CommandTriggerMemberLeave,
+		gs:          evt.GS,
+		ms:          ms,
+	})
+
+	return false, nil
+}
+
+// handleGuildEventMessageDelete only runs for messages that are still in the state, as nothing is known about the others
+func handleGuildEventMessageDelete(evt *eventsystem.EventData) {
+	m := evt.MessageDelete()
+	if evt.GS == nil || !evt.HasFeatureFlag(featureFlagHasCommands) {
+		return
+	}
+
+	msgs := bot.State.GetMessages(m.GuildID, m.ChannelID, &dstate.MessagesQuery{
+		Before:         m.ID + 1,
+		After:          m.ID - 1,
+		Limit:          1,
+		IncludeDeleted: true,
+	})
+
+	if len(msgs) < 1 || msgs[0].ID != m.ID || msgs[0].Author.Bot {
+		return
+	}
+
+	deleted := msgs[0]
+	ms, err := bot.GetMember(m.GuildID, deleted.Author.ID)
+	if err != nil || ms == nil {
+		ms = &dstate.MemberState{User: deleted.Author, GuildID: m.GuildID, Member: &dstate.MemberFields{}}
+	}
+
+	runGuildEventCommands(evt.Context(), &guildEvent{
+		triggerType: CommandTriggerMessageDelete,
+		gs:          evt.GS,
+		channelID:   m.ChannelID,
+		ms:          ms,
+		data: map[string]interface{}{
+			"DeletedMessage": deleted,
+		},
+	})
+}
+
+// handleGuildEventMessageUpdate runs before the state is updated to get the message as it was before the edit
+func handleGuildEventMessageUpdate(evt *eventsystem.EventData) (retry bool, err error) {
+	mu := evt.MessageUpdate()
+	if evt.GS == nil || !evt.HasFeatureFlag(featureFlagHasCommands) {
+		return false, nil
+	}
+
+	// embeds being resolved also sends message updates, those are partial and have no edited timestamp
+	if mu.Author == nil || mu.Author.Bot || mu.EditedTimestamp == "" || !bot.IsUserMessage(mu.Message) {
+		return false, nil
+	}
+
+	var old *dstate.MessageState
+	msgs := bot.State.GetMessages(mu.GuildID, mu.ChannelID, &dstate.MessagesQuery{
+		Before: mu.ID + 1,
+		After:  mu.ID - 1,
+		Limit:  1,
+	})
+	if len(msgs) > 0 && msgs[0].ID == mu.ID {
+		old = msgs[0]
+	}
+
+	ms := &dstate.MemberState{User: *mu.Author, GuildID: mu.GuildID, Member: &dstate.MemberFields{}}
+	if mu.Member != nil {
+		// the member object in messages doesn't include the user
+		ms = dstate.MemberStateFromMember(mu.Member)
+		ms.User = *mu.Author
+		ms.GuildID = mu.GuildID
+	}
+
+	go runGuildEventCommands(evt.Context(), &guildEvent{
+		triggerType: CommandTriggerMessageEdit,
+		gs:          evt.GS,
+		channelID:   mu.ChannelID,
+		ms:          ms,
+		msg:         mu.Message,
+		data: map[string]interface{}{
+			"OldMessage": old,
+		},
+	})
+
+	return false, nil
+}
+
+// handleGuildEventVoiceStateUpdate runs before the state is updated to get the previous voice state
+func handleGuildEventVoiceStateUpdate(evt *eventsystem.EventData) (retry bool, err error) {
+	vs := evt.VoiceStateUpdate()
+	if evt.GS == nil || vs.UserID == 0 || vs.ChannelID == 0 || !evt.HasFeatureFlag(featureFlagHasCommands) {
+		return false, nil
+	}
+
+	previous := evt.GS.GetVoiceState(vs.UserID)
+	if previous != nil && previous.ChannelID == vs.ChannelID {
+		// mute, deafen and similar
+		return false, nil
+	}
+
+	var previousCopy *discordgo.VoiceState
+	if previous != nil {
+		cop := *previous
+		previousCopy = &cop
+	}
+
+	current := *vs.VoiceState
+	gs := evt.GS
+	go func() {
+		ms, err := bot.GetMember(gs.ID, current.UserID)
+		if err != nil || ms == nil {
+			return
+		}
+
+		runGuildEventCommands(evt.Context(), &guildEvent{
+			triggerType: CommandTriggerVoiceJoin,
+			gs:          gs,
+			channelID:   current.ChannelID,
+			ms:          ms,
+			data: map[string]interface{}{
+				"VoiceState":         &current,
+				"PreviousVoiceState": previousCopy,
+				"VoiceChannel":       gs.GetChannel(current.ChannelID),
+			},
+		})
+	}()
+
+	return false, nil
+}
+
+// handleGuildEventMemberUpdate runs before the state is updated to detect members that started boosting
+//
+// Discord only sets premium_since on the first boost, so additional boosts from a member that's already boosting are not detected
+func handleGuildEventMemberUpdate(evt *eventsystem.EventData) (retry bool, err error) {
+	m := evt.GuildMemberUpdate()
+	if evt.GS == nil || m.PremiumSince == nil || !evt.HasFeatureFlag(featureFlagHasCommands) {
+		return false, nil
+	}
+
+	old := bot.State.GetMember(m.GuildID, m.User.ID)
+	if old == nil || old.Member == nil || old.Member.PremiumSince != nil {
+		// if the member isn't cached we can't tell if they just started boosting
+		return false, nil
+	}
+
+	ms := dstate.MemberStateFromMember(m.Member)
+	go runGuildEventCommands(evt.Context(), &guildEvent{
+		triggerType: CommandTriggerServerBoost,
+		gs:          evt.GS,
+		ms:          ms,
+	})
+
+	return false, nil
+}
@@ -0,0 +1,223 @@
+package customcommands
+
+import (
+	"testing"
+
+	"github.com/botlabs-gg/yagpdb/v2/customcommands/models"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/volatiletech/null/v8"
+)
+
+func guildEventTestCmd(triggerType CommandTriggerType, contextChannel int64) *models.CustomCommand {
+	cmd := &models.CustomCommand{
+		LocalID:        1,
+		TriggerType:    int(triggerType),
+		ContextChannel: contextChannel,
+	}
+	cmd.R = cmd.R.NewStruct()
+	return cmd
+}
+
+func TestFilterGuildEventCommands(t *testing.T) {
+	member := &dstate.MemberState{User: discordgo.User{ID: 5}, Member: &dstate.MemberFields{Roles: []int64{50}}}
+
+	tests := []struct {
+		name  string
+		cmd   func() *models.CustomCommand
+		evt   *guildEvent
+		match bool
+	}{
+		{
+			name:  "join",
+			cmd:   func() *models.CustomCommand { return guildEventTestCmd(CommandTriggerMemberJoin, 10) },
+			evt:   &guildEvent{triggerType: CommandTriggerMemberJoin, ms: member},
+			match: true,
+		},
+		{
+			name:  "other trigger type",
+			cmd:   func() *models.CustomCommand { return guildEventTestCmd(CommandTriggerMemberLeave, 10) },
+			evt:   &guildEvent{triggerType: CommandTriggerMemberJoin, ms: member},
+			match: false,
+		},
+		{
+			name:  "join without context channel",
+			cmd:   func() *models.CustomCommand { return guildEventTestCmd(CommandTriggerMemberJoin, 0) },
+			evt:   &guildEvent{triggerType: CommandTriggerMemberJoin, ms: member},
+			match: false,
+		},
+		{
+			name:  "message delete runs in the message channel",
+			cmd:   func() *models.CustomCommand { return guildEventTestCmd(CommandTriggerMessageDelete, 0) },
+			evt:   &guildEvent{triggerType: CommandTriggerMessageDelete, channelID: 20, ms: member},
+			match: true,
+		},
+		{
+			name: "disabled",
+			cmd: func() *models.CustomCommand {
+				cmd := guildEventTestCmd(CommandTriggerServerBoost, 10)
+				cmd.Disabled = true
+				return cmd
+			},
+			evt:   &guildEvent{triggerType: CommandTriggerServerBoost, ms: member},
+			match: false,
+		},
+		{
+			name: "disabled group",
+			cmd: func() *models.CustomCommand {
+				cmd := guildEventTestCmd(CommandTriggerServerBoost, 10)
+				cmd.GroupID = null.Int64From(1)
+				cmd.R.Group = &models.CustomCommandGroup{ID: 1, Disabled: true}
+				return cmd
+			},
+			evt:   &guildEvent{triggerType: CommandTriggerServerBoost, ms: member},
+			match: false,
+		},
+		{
+			name: "ignored channel",
+			cmd: func() *models.CustomCommand {
+				cmd := guildEventTestCmd(CommandTriggerMessageEdit, 0)
+				cmd.Channels = []int64{20}
+				return cmd
+			},
+			evt:   &guildEvent{triggerType: CommandTriggerMessageEdit, channelID: 20, ms: member},
+			match: false,
+		},
+		{
+			name: "voice join in a whitelisted channel",
+			cmd: func() *models.CustomCommand {
+				cmd := guildEventTestCmd(CommandTriggerVoiceJoin, 10)
+				cmd.Channels = []int64{30}
+				cmd.ChannelsWhitelistMode = true
+				return cmd
+			},
+			evt:   &guildEvent{triggerType: CommandTriggerVoiceJoin, channelID: 30, ms: member},
+			match: true,
+		},
+		{
+			name: "member without a whitelisted role",
+			cmd: func() *models.CustomCommand {
+				cmd := guildEventTestCmd(CommandTriggerMemberLeave, 10)
+				cmd.Roles = []int64{60}
+				cmd.RolesWhitelistMode = true
+				return cmd
+			},
+			evt:   &guildEvent{triggerType: CommandTriggerMemberLeave, ms: member},
+			match: false,
+		},
+		{
+			name: "member with a whitelisted role",
+			cmd: func() *models.CustomCommand {
+				cmd := guildEventTestCmd(CommandTriggerMemberLeave, 10)
+				cmd.Roles = []int64{50}
+				cmd.RolesWhitelistMode = true
+				return cmd
+			},
+			evt:   &guildEvent{triggerType: CommandTriggerMemberLeave, ms: member},
+			match: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches := filterGuildEventCommands([]*models.CustomCommand{test.cmd()}, test.evt)
+			if (len(matches) == 1) != test.match {
+				t.Errorf("expected match %v, got %d matches", test.match, len(matches))
+			}
+		})
+	}
+}
+
+func TestAllowGuildEventRun(t *testing.T) {
+	evt := &guildEvent{triggerType: CommandTriggerMessageDelete, gs: &dstate.GuildSet{GuildState: dstate.GuildState{ID: 1001}}}
+
+	allowed := 0
+	for i := 0; i < 20; i++ {
+		if allowGuildEventRun(evt) {
+			allowed++
+		}
+	}
+
+	// the burst of the limiter, the rate is too low to let any more through during the test
+	if allowed != 10 {
+		t.Errorf("expected 10 runs to be allowed, got %d", allowed)
+	}
+
+	other := &guildEvent{triggerType: CommandTriggerMemberJoin, gs: evt.gs}
+	if !allowGuildEventRun(other) {
+		t.Error("expected other trigger types in the same guild to not be limited")
+	}
+}
+
+func TestGuildEventTemplateContext(t *testing.T) {
+	gs := &dstate.GuildSet{
+		GuildState: dstate.GuildState{ID: 1},
+		Channels:   []dstate.ChannelState{{ID: 10, GuildID: 1}, {ID: 20, GuildID: 1}},
+	}
+	member := &dstate.MemberState{User: discordgo.User{ID: 5}, Member: &dstate.MemberFields{}}
+	deleted := &dstate.MessageState{ID: 100, ChannelID: 20}
+
+	evt := &guildEvent{
+		triggerType: CommandTriggerMessageDelete,
+		gs:          gs,
+		channelID:   20,
+		ms:          member,
+		data:        map[string]interface{}{"DeletedMessage": deleted},
+	}
+
+	tmplCtx := guildEventTemplateContext(evt, guildEventTestCmd(CommandTriggerMessageDelete, 10))
+	if tmplCtx == nil {
+		t.Fatal("expected a context")
+	}
+	if tmplCtx.CurrentFrame.CS.ID != 20 {
+		t.Errorf("expected message triggers to run in the message channel, got %d", tmplCtx.CurrentFrame.CS.ID)
+	}
+	if tmplCtx.Data["DeletedMessage"] != deleted {
+		t.Errorf("expected the deleted message in the template data, got %v", tmplCtx.Data["DeletedMessage"])
+	}
+
+	edited := &discordgo.Message{ID: 101, ChannelID: 20}
+	evt = &guildEvent{
+		triggerType: CommandTriggerMessageEdit,
+		gs:          gs,
+		channelID:   20,
+		ms:          member,
+		msg:         edited,
+		data:        map[string]interface{}{"OldMessage": (*dstate.MessageState)(nil)},
+	}
+
+	tmplCtx = guildEventTemplateContext(evt, guildEventTestCmd(CommandTriggerMessageEdit, 0))
+	if tmplCtx == nil {
+		t.Fatal("expected a context")
+	}
+	if tmplCtx.Msg != edited || tmplCtx.Data["Message"] != edited {
+		t.Errorf("expected the edited message to be the context message")
+	}
+	if _, ok := tmplCtx.Data["OldMessage"]; !ok {
+		t.Errorf("expected OldMessage to be set even if the old message is unknown")
+	}
+
+	voiceState := &discordgo.VoiceState{UserID: 5, ChannelID: 30}
+	evt = &guildEvent{
+		triggerType: CommandTriggerVoiceJoin,
+		gs:          gs,
+		channelID:   30,
+		ms:          member,
+		data:        map[string]interface{}{"VoiceState": voiceState},
+	}
+
+	tmplCtx = guildEventTemplateContext(evt, guildEventTestCmd(CommandTriggerVoiceJoin, 10))
+	if tmplCtx == nil {
+		t.Fatal("expected a context")
+	}
+	if tmplCtx.CurrentFrame.CS.ID != 10 {
+		t.Errorf("expected voice triggers to run in the context channel, got %d", tmplCtx.CurrentFrame.CS.ID)
+	}
+	if tmplCtx.Data["VoiceState"] != voiceState {
+		t.Errorf("expected the voice state in the template data")
+	}
+
+	if guildEventTemplateContext(evt, guildEventTestCmd(CommandTriggerVoiceJoin, 40)) != nil {
+		t.Error("expected no context when the context channel is gone")
+	}
+}
@@ -82,7 +82,7 @@ func handleMessageReactions(evt *eventsystem.EventData) {
 	rMessage.GuildID = cState.GuildID
 
 	for _, matched := range triggeredCmds {
-		err = ExecuteCustomCommandFromReaction(matched.CC, evt.GS, ms, cState, reaction, added, rMessage)
+		err = ExecuteCustomCommandFromReaction(evt.Context(), matched.CC, evt.GS, ms, cState, reaction, added, rMessage)
 		if err != nil {
 			logger.WithField("guild", cState.GuildID).WithField("cc_id", matched.CC.LocalID).WithError(err).Error("Error executing custom command")
 		}
@@ -165,8 +165,9 @@ func findReactionTriggerCustomCommands(ctx context.Context, cs *dstate.ChannelSt
 	return ms, filtered, nil
 }
 
-func ExecuteCustomCommandFromReaction(cc *models.CustomCommand, gs *dstate.GuildSet, ms *dstate.MemberState, cs *dstate.ChannelState, reaction *discordgo.MessageReaction, added bool, message *discordgo.Message) error {
+func ExecuteCustomCommandFromReaction(ctx context.Context, cc *models.CustomCommand, gs *dstate.GuildSet, ms *dstate.MemberState, cs *dstate.ChannelState, reaction *discordgo.MessageReaction, added bool, message *discordgo.Message) error {
 	tmplCtx := templates.NewContext(gs, cs, ms)
+	tmplCtx.Ctx = ctx
 
 	// to make sure the message is in the proper context of the user reacting we set the mssage context to a fake message
 	fakeMsg := *message
@@ -97,7 +97,7 @@ func handleSlashCommandInteraction(evt *eventsystem.EventData, cs *dstate.Channe
 	}
 
 	deferResponseToCCs(interaction, []*TriggeredCC{{CC: matched}})
-	if err := ExecuteCustomCommandFromSlash(matched, evt.GS, cs, interaction); err != nil {
+	if err := ExecuteCustomCommandFromSlash(evt.Context(), matched, evt.GS, cs, interaction); err != nil {
 		logger.WithField("guild", cs.GuildID).WithField("cc_id", matched.LocalID).WithError(err).Error("Error executing slash command custom command")
 	}
 }
@@ -106,9 +106,10 @@ func handleSlashCommandInteraction(evt *eventsystem.EventData, cs *dstate.Channe
 // custom command and executes it. Option values are exposed in .Options (keyed by
 // option name); .Args is the ordered slice (command name at index 0) and .CmdArgs
 // is the ordered option values.
-func ExecuteCustomCommandFromSlash(cc *models.CustomCommand, gs *dstate.GuildSet, cs *dstate.ChannelState, interaction *templates.CustomCommandInteraction) error {
+func ExecuteCustomCommandFromSlash(ctx context.Context, cc *models.CustomCommand, gs *dstate.GuildSet, cs *dstate.ChannelState, interaction *templates.CustomCommandInteraction) error {
 	ms := dstate.MemberStateFromMember(interaction.Member)
 	tmplCtx := templates.NewContext(gs, cs, ms)
+	tmplCtx.Ctx = ctx
 	tmplCtx.CurrentFrame.Interaction = interaction
 
 	data := interaction.DataCommand
@@ -118,7 +118,7 @@ func HandleMessageUpdate(evt *eventsystem.EventData) {
 		if !matched.CC.TriggerOnEdit {
 			continue
 		}
-		err = ExecuteCustomCommandFromMessage(evt.GS, matched.CC, member, cs, matched.Args, matched.Stripped, mu.Message, true)
+		err = ExecuteCustomCommandFromMessage(evt.Context(), evt.GS, matched.CC, member, cs, matched.Args, matched.Stripped, mu.Message, true)
 		if err != nil {
 			logger.WithField("guild", mu.GuildID).WithField("cc_id", matched.CC.LocalID).WithError(err).Error("Error executing custom command")
 		}
@@ -157,7 +157,7 @@ func HandleMessageCreate(evt *eventsystem.EventData) {
 	metricsExecutedCommands.With(prometheus.Labels{"trigger": "message"}).Inc()
 
 	for _, matched := range matchedCustomCommands {
-		err = ExecuteCustomCommandFromMessage(evt.GS, matched.CC, member, cs, matched.Args, matched.Stripped, mc.Message, false)
+		err = ExecuteCustomCommandFromMessage(evt.Context(), evt.GS, matched.CC, member, cs, matched.Args, matched.Stripped, mc.Message, false)
 		if err != nil {
 			logger.WithField("guild", mc.GuildID).WithField("cc_id", matched.CC.LocalID).WithError(err).Error("Error executing custom command")
 		}
@@ -195,8 +195,9 @@ func CheckMatch(globalPrefix string, cmd *models.CustomCommand, msg string) (mat
 	return matchRegexSplitArgs(cmdMatch, msg)
 }
 
-func ExecuteCustomCommandFromMessage(gs *dstate.GuildSet, cmd *models.CustomCommand, member *dstate.MemberState, cs *dstate.ChannelState, cmdArgs []string, stripped string, m *discordgo.Message, isEdit bool) error {
+func ExecuteCustomCommandFromMessage(ctx context.Context, gs *dstate.GuildSet, cmd *models.CustomCommand, member *dstate.MemberState, cs *dstate.ChannelState, cmdArgs []string, stripped string, m *discordgo.Message, isEdit bool) error {
 	tmplCtx := templates.NewContext(gs, cs, member)
+	tmplCtx.Ctx = ctx
 	tmplCtx.Msg = m
 
 	// prepare message specific data
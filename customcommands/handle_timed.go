@@ -8,6 +8,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/bot"
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/common/pubsub"
+	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
 	schEventsModels "github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2/models"
 	"github.com/botlabs-gg/yagpdb/v2/common/templates"
 	"github.com/botlabs-gg/yagpdb/v2/customcommands/models"
@@ -100,6 +101,7 @@ func handleDelayedRunCC(evt *schEventsModels.ScheduledEvent, data interface{}) (
 	}
 
 	tmplCtx := templates.NewContext(gs, cs, dataCast.Member)
+	tmplCtx.Ctx = scheduledevents2.Context(evt)
 	if dataCast.Message != nil {
 		tmplCtx.Msg = dataCast.Message
 		tmplCtx.Data["Message"] = dataCast.Message
@@ -175,6 +177,7 @@ func handleNextRunScheduledEVent(evt *schEventsModels.ScheduledEvent, data inter
 	metricsExecutedCommands.With(prometheus.Labels{"trigger": "timed"}).Inc()
 
 	tmplCtx := templates.NewContext(gs, cs, nil)
+	tmplCtx.Ctx = scheduledevents2.Context(evt)
 	ExecuteCustomCommand(cmd, tmplCtx)
 
 	// schedule next runs
@@ -0,0 +1,454 @@
+package customcommands
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/pubsub"
+	"github.com/botlabs-gg/yagpdb/v2/common/templates"
+	"github.com/lib/pq"
+	"github.com/mediocregopher/radix/v3"
+)
+
+// Custom commands can make http requests to domains the guild admins have allowed,
+// this is disabled by default.
+
+const (
+	MaxHTTPAllowedDomains = 20
+
+	// per execution
+	MaxHTTPRequestsPerRun        = 2
+	MaxHTTPRequestsPerRunPremium = 5
+
+	// per guild, counted in fixed windows that start on the hour
+	MaxHTTPRequestsPerHour        = 100
+	MaxHTTPRequestsPerHourPremium = 500
+
+	MaxHTTPResponseSize = 100000
+	MaxHTTPRequestSize  = 10000
+
+	httpRequestTimeout = 5 * time.Second
+	httpMaxRedirects   = 3
+)
+
+type HTTPSettings struct {
+	GuildID        int64
+	Enabled        bool
+	AllowedDomains []string
+}
+
+// GetHTTPSettings returns the http settings of the guild, guilds without settings get the disabled defaults
+func GetHTTPSettings(ctx context.Context, guildID int64) (*HTTPSettings, error) {
+	s := &HTTPSettings{GuildID: guildID}
+	err := common.PQ.QueryRowContext(ctx, "SELECT enabled, allowed_domains FROM custom_command_http_settings WHERE guild_id = $1", guildID).
+		Scan(&s.Enabled, pq.Array(&s.AllowedDomains))
+	if err == sql.ErrNoRows {
+		return s, nil
+	}
+
+	return s, err
+}
+
+func saveHTTPSettings(ctx context.Context, s *HTTPSettings) error {
+	if s.AllowedDomains == nil {
+		s.AllowedDomains = []string{}
+	}
+
+	_, err := common.PQ.ExecContext(ctx, `INSERT INTO custom_command_http_settings (guild_id, enabled, allowed_domains, updated_at) VALUES ($1, $2, $3, now())
+ON CONFLICT (guild_id) DO UPDATE SET enabled = $2, allowed_domains = $3, updated_at = now()`, s.GuildID, s.Enabled, pq.Array(s.AllowedDomains))
+	return err
+}
+
+var cachedHTTPSettings = common.CacheSet.RegisterSlot("custom_commands_http_settings", nil, int64(0))
+
+func BotCachedGetHTTPSettings(guildID int64) (*HTTPSettings, error) {
+	v, err := cachedHTTPSettings.GetCustomFetch(guildID, func(key interface{}) (interface{}, error) {
+		return GetHTTPSettings(context.Background(), guildID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*HTTPSettings), nil
+}
+
+func EvictHTTPSettingsCache(guildID int64) {
+	pubsub.EvictCacheSet(cachedHTTPSettings, guildID)
+}
+
+// parseAllowedDomains parses the allowlist entered on the dashboard, one domain per line.
+// "example.com" only allows that exact host while "*.example.com" allows all its subdomains.
+func parseAllowedDomains(input string) ([]string, error) {
+	var result []string
+	for _, line := range strings.Split(input, "\n") {
+		d := strings.ToLower(strings.TrimSpace(line))
+		if d == "" {
+			continue
+		}
+
+		host := strings.TrimPrefix(d, "*.")
+		if strings.ContainsAny(host, "*/:@ ") || !strings.Contains(host, ".") || net.ParseIP(host) != nil {
+			return nil, fmt.Errorf("Invalid domain %q, enter domains like api.example.com or *.example.com", d)
+		}
+
+		if !slices.Contains(result, d) {
+			result = append(result, d)
+		}
+	}
+
+	if len(result) > MaxHTTPAllowedDomains {
+		return nil, fmt.Errorf("Max %d allowed domains", MaxHTTPAllowedDomains)
+	}
+
+	return result, nil
+}
+
+func domainAllowed(allowed []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, d := range allowed {
+		if wildcard, ok := strings.CutPrefix(d, "*."); ok {
+			if strings.HasSuffix(host, "."+wildcard) {
+				return true
+			}
+			continue
+		}
+
+		if host == d {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkHTTPURL validates a url requested by a template against the allowlist
+func checkHTTPURL(allowed []string, u *url.URL) error {
+	if u.Scheme != "https" {
+		return errors.New("only https urls are allowed")
+	}
+
+	if u.User != nil {
+		return errors.New("urls with credentials are not allowed")
+	}
+
+	if u.Port() != "" && u.Port() != "443" {
+		return errors.New("urls with custom ports are not allowed")
+	}
+
+	if !domainAllowed(allowed, u.Hostname()) {
+		return fmt.Errorf("domain %q is not in this server's allowlist", u.Hostname())
+	}
+
+	return nil
+}
+
+// special purpose ranges not covered by the net.IP methods, some of them are routed internally on some networks
+var specialIPRanges = parseCIDRs(
+	"0.0.0.0/8",       // "this" network
+	"100.64.0.0/10",   // carrier grade NAT
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // documentation
+	"198.18.0.0/15",   // benchmarking
+	"198.51.100.0/24", // documentation
+	"203.0.113.0/24",  // documentation
+	"240.0.0.0/4",     // reserved, includes broadcast
+	"64:ff9b::/96",    // NAT64, can map to internal ipv4 addresses
+	"64:ff9b:1::/48",  // local use NAT64
+	"100::/64",        // discard only
+	"2001:db8::/32",   // documentation
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	result := make([]*net.IPNet, len(cidrs))
+	for i, v := range cidrs {
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			panic(err)
+		}
+		result[i] = n
+	}
+
+	return result
+}
+
+func isInternalIP(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return true
+	}
+
+	for _, v := range specialIPRanges {
+		if v.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var errInternalAddress = errors.New("requests to internal addresses are not allowed")
+
+// denyPrivateAddresses stops requests to allowed domains that resolve to internal addresses
+func denyPrivateAddresses(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if isInternalIP(net.ParseIP(host)) {
+		return errInternalAddress
+	}
+
+	return nil
+}
+
+// denyPrivateHost resolves host and returns a error if any of its addresses are internal, used with a proxy as the dialer only sees the proxy address.
+// The proxy resolves the host again, so it should also refuse internal addresses to not be open to dns rebinding.
+func denyPrivateHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range addrs {
+		if isInternalIP(v.IP) {
+			return errInternalAddress
+		}
+	}
+
+	return nil
+}
+
+func newCCHTTPClient(allowed []string) *http.Client {
+	transport := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: httpRequestTimeout,
+			Control: denyPrivateAddresses,
+		}).DialContext,
+		TLSHandshakeTimeout:   httpRequestTimeout,
+		ResponseHeaderTimeout: httpRequestTimeout,
+		DisableKeepAlives:     true,
+	}
+
+	//use an http proxy if configured
+	proxy := common.ConfHttpProxy.GetString()
+	if len(proxy) > 0 {
+		proxyURL, err := url.Parse(proxy)
+		if err == nil {
+			transport.Proxy = func(req *http.Request) (*url.URL, error) {
+				if err := denyPrivateHost(req.Context(), req.URL.Hostname()); err != nil {
+					return nil, err
+				}
+
+				return proxyURL, nil
+			}
+			// the proxy itself is usually on a internal address
+			transport.DialContext = (&net.Dialer{Timeout: httpRequestTimeout}).DialContext
+		} else {
+			logger.WithError(err).WithField("proxy", proxy).Warn("Invalid HTTP proxy configured for custom command http requests, falling back to direct requests")
+		}
+	}
+
+	return &http.Client{
+		Timeout:   httpRequestTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= httpMaxRedirects {
+				return errors.New("too many redirects")
+			}
+
+			return checkHTTPURL(allowed, req.URL)
+		},
+	}
+}
+
+// checkHTTPHourlyLimit returns true if the guild is above the hourly http request limit
+func checkHTTPHourlyLimit(guildID int64, isPremium bool) (bool, error) {
+	limit := MaxHTTPRequestsPerHour
+	if isPremium {
+		limit = MaxHTTPRequestsPerHourPremium
+	}
+
+	key := "cc_http_requests:" + strconv.FormatInt(guildID, 10) + ":" + strconv.FormatInt(time.Now().Unix()/3600, 10)
+
+	var count int
+	err := common.RedisPool.Do(radix.Cmd(&count, "INCR", key))
+	if err != nil {
+		return false, err
+	}
+
+	if count == 1 {
+		common.RedisPool.Do(radix.FlatCmd(nil, "EXPIRE", key, 3600))
+	}
+
+	return count > limit, nil
+}
+
+// httpRequestBody encodes the body of a httpPost call, anything that isn't a string is sent as json
+func httpRequestBody(body interface{}) (io.Reader, string, error) {
+	var b []byte
+	contentType := "application/json"
+	switch t := body.(type) {
+	case nil:
+		return nil, "", nil
+	case string:
+		b = []byte(t)
+		contentType = "text/plain; charset=utf-8"
+	default:
+		encoded, err := json.Marshal(t)
+		if err != nil {
+			return nil, "", err
+		}
+		b = encoded
+	}
+
+	if len(b) > MaxHTTPRequestSize {
+		return nil, "", fmt.Errorf("request body can be at most %d bytes", MaxHTTPRequestSize)
+	}
+
+	return bytes.NewReader(b), contentType, nil
+}
+
+// httpResponseData converts a response into what the template functions return,
+// JSON is returned under JSON with objects converted to sdicts and arrays to slices
+func httpResponseData(resp *http.Response) (templates.SDict, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxHTTPResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) > MaxHTTPResponseSize {
+		return nil, fmt.Errorf("response larger than %d bytes", MaxHTTPResponseSize)
+	}
+
+	result := templates.SDict{
+		"StatusCode": resp.StatusCode,
+		"Body":       string(body),
+		"JSON":       nil,
+	}
+
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		var parsed interface{}
+		if err := json.Unmarshal(body, &parsed); err == nil {
+			result["JSON"] = jsonToTemplateValue(parsed)
+		}
+	}
+
+	return result, nil
+}
+
+// jsonToTemplateValue converts decoded json into the types templates work with
+func jsonToTemplateValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = jsonToTemplateValue(e)
+		}
+		return templates.SDict(t)
+	case []interface{}:
+		for i, e := range t {
+			t[i] = jsonToTemplateValue(e)
+		}
+		return templates.Slice(t)
+	}
+
+	return v
+}
+
+func tmplHTTPRequest(ctx *templates.Context, method string) interface{} {
+	return func(rawURL string, args ...interface{}) (templates.SDict, error) {
+		if ctx.GS == nil {
+			return nil, errors.New("http requests can only be made in a server")
+		}
+
+		if ctx.IncreaseCheckCallLimit("http_requests") {
+			return nil, templates.ErrTooManyCalls
+		}
+
+		settings, err := BotCachedGetHTTPSettings(ctx.GS.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !settings.Enabled {
+			return nil, errors.New("http requests are disabled on this server, enable them in the custom command settings on the control panel")
+		}
+
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkHTTPURL(settings.AllowedDomains, u)
+		if err != nil {
+			return nil, err
+		}
+
+		var body interface{}
+		var headers interface{}
+		if method == http.MethodPost && len(args) > 0 {
+			body = args[0]
+			args = args[1:]
+		}
+		if len(args) > 0 {
+			headers = args[0]
+		}
+
+		reqBody, contentType, err := httpRequestBody(body)
+		if err != nil {
+			return nil, err
+		}
+
+		if above, err := checkHTTPHourlyLimit(ctx.GS.ID, ctx.IsPremium); err != nil || above {
+			if err != nil {
+				return nil, err
+			}
+			return nil, errors.New("this server is above its hourly http request limit")
+		}
+
+		reqCtx, cancel := context.WithTimeout(context.Background(), httpRequestTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(reqCtx, method, u.String(), reqBody)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("User-Agent", "YAGPDB.xyz custom command (https://yagpdb.xyz)")
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		if headers != nil {
+			dict, err := templates.StringKeyDictionary(headers)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range dict {
+				req.Header.Set(k, templates.ToString(v))
+			}
+		}
+
+		resp, err := newCCHTTPClient(settings.AllowedDomains).Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		return httpResponseData(resp)
+	}
+}
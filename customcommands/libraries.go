@@ -0,0 +1,278 @@
+package customcommands
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/pubsub"
+	"github.com/botlabs-gg/yagpdb/v2/common/templates"
+	"github.com/botlabs-gg/yagpdb/v2/customcommands/models"
+	"github.com/botlabs-gg/yagpdb/v2/lib/template/parse"
+	"github.com/botlabs-gg/yagpdb/v2/premium"
+)
+
+// Libraries hold {{define}} blocks shared between the custom commands of a guild,
+// commands use them with {{import "name"}} followed by {{template "defined-name" .}}.
+// Their size is counted separately from MaxCCResponsesLength.
+
+const (
+	MaxLibraries            = 5
+	MaxLibrariesPremium     = 25
+	MaxLibraryLength        = 10000
+	MaxLibraryLengthPremium = 20000
+
+	// max number of different libraries a single execution can import
+	MaxImportsPerExecution = 10
+)
+
+var (
+	libraryNameRegex = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+	// matches {{import "name"}} calls, used to find the commands depending on a library
+	libraryImportRegex = regexp.MustCompile(`\bimport\s+"([a-z0-9_-]{1,32})"`)
+)
+
+type Library struct {
+	ID        int64
+	GuildID   int64
+	Name      string
+	Source    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+const libraryColumns = "id, guild_id, name, source, created_at, updated_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLibrary(row rowScanner) (*Library, error) {
+	l := &Library{}
+	err := row.Scan(&l.ID, &l.GuildID, &l.Name, &l.Source, &l.CreatedAt, &l.UpdatedAt)
+	return l, err
+}
+
+// GetLibraries returns the libraries of the guild sorted by name
+func GetLibraries(ctx context.Context, guildID int64) ([]*Library, error) {
+	rows, err := common.PQ.QueryContext(ctx, "SELECT "+libraryColumns+" FROM custom_command_libraries WHERE guild_id = $1 ORDER BY name", guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Library
+	for rows.Next() {
+		l, err := scanLibrary(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, l)
+	}
+
+	return result, rows.Err()
+}
+
+// GetLibrary returns the library with the id, or nil if it does not exist
+func GetLibrary(ctx context.Context, guildID, id int64) (*Library, error) {
+	row := common.PQ.QueryRowContext(ctx, "SELECT "+libraryColumns+" FROM custom_command_libraries WHERE guild_id = $1 AND id = $2", guildID, id)
+	l, err := scanLibrary(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return l, err
+}
+
+func insertLibrary(ctx context.Context, l *Library) error {
+	now := time.Now()
+	l.CreatedAt = now
+	l.UpdatedAt = now
+
+	return common.PQ.QueryRowContext(ctx, "INSERT INTO custom_command_libraries (guild_id, name, source, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		l.GuildID, l.Name, l.Source, l.CreatedAt, l.UpdatedAt).Scan(&l.ID)
+}
+
+func updateLibrary(ctx context.Context, l *Library) error {
+	l.UpdatedAt = time.Now()
+
+	_, err := common.PQ.ExecContext(ctx, "UPDATE custom_command_libraries SET name = $3, source = $4, updated_at = $5 WHERE guild_id = $1 AND id = $2",
+		l.GuildID, l.ID, l.Name, l.Source, l.UpdatedAt)
+	return err
+}
+
+func deleteLibrary(ctx context.Context, guildID, id int64) (bool, error) {
+	res, err := common.PQ.ExecContext(ctx, "DELETE FROM custom_command_libraries WHERE guild_id = $1 AND id = $2", guildID, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func countLibraries(ctx context.Context, guildID int64) (int, error) {
+	var count int
+	err := common.PQ.QueryRowContext(ctx, "SELECT count(*) FROM custom_command_libraries WHERE guild_id = $1", guildID).Scan(&count)
+	return count, err
+}
+
+func MaxLibrariesForContext(ctx context.Context) int {
+	if premium.ContextPremium(ctx) {
+		return MaxLibrariesPremium
+	}
+
+	return MaxLibraries
+}
+
+func MaxLibraryLengthForContext(ctx context.Context) int {
+	if premium.ContextPremium(ctx) {
+		return MaxLibraryLengthPremium
+	}
+
+	return MaxLibraryLength
+}
+
+// validateLibrary checks the name and source of a library, it does not check the size limit
+func validateLibrary(l *Library) error {
+	if !libraryNameRegex.MatchString(l.Name) {
+		return errors.New("Library names can only contain lowercase letters, numbers, dashes and underscores, and be at most 32 characters long")
+	}
+
+	trees, err := templates.NewContext(nil, nil, nil).ParseDefinitions(l.Source)
+	if err != nil {
+		return errors.WithMessage(err, "Failed parsing library")
+	}
+
+	if len(trees) == 0 {
+		return errors.New("Library does not define any templates, use {{define \"name\"}} ... {{end}} to define them")
+	}
+
+	return nil
+}
+
+// cachedLibrary is a library cached on the bot, parsed the first time it's imported
+type cachedLibrary struct {
+	*Library
+
+	parseOnce sync.Once
+	trees     map[string]*parse.Tree
+	parseErr  error
+}
+
+func (l *cachedLibrary) definitions(tmplCtx *templates.Context) (map[string]*parse.Tree, error) {
+	l.parseOnce.Do(func() {
+		l.trees, l.parseErr = tmplCtx.ParseDefinitions(l.Source)
+	})
+
+	return l.trees, l.parseErr
+}
+
+var cachedLibraries = common.CacheSet.RegisterSlot("custom_commands_libraries", nil, int64(0))
+
+// BotCachedGetLibraries returns the libraries of the guild by name
+func BotCachedGetLibraries(guildID int64, ctx context.Context) (map[string]*cachedLibrary, error) {
+	v, err := cachedLibraries.GetCustomFetch(guildID, func(key interface{}) (interface{}, error) {
+		libs, err := GetLibraries(ctx, guildID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make(map[string]*cachedLibrary, len(libs))
+		for _, l := range libs {
+			result[l.Name] = &cachedLibrary{Library: l}
+		}
+
+		return result, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(map[string]*cachedLibrary), nil
+}
+
+func EvictLibraryCache(guildID int64) {
+	pubsub.EvictCacheSet(cachedLibraries, guildID)
+}
+
+func tmplImportLibrary(ctx *templates.Context) interface{} {
+	imported := make(map[string]bool)
+
+	return func(name string) (string, error) {
+		if ctx.GS == nil {
+			return "", errors.New("libraries can only be imported in a server")
+		}
+
+		if imported[name] {
+			return "", nil
+		}
+
+		if len(imported) >= MaxImportsPerExecution {
+			return "", fmt.Errorf("max %d libraries can be imported", MaxImportsPerExecution)
+		}
+
+		libs, err := BotCachedGetLibraries(ctx.GS.ID, ctx.Context())
+		if err != nil {
+			return "", err
+		}
+
+		lib, ok := libs[name]
+		if !ok {
+			return "", fmt.Errorf("unknown library %q", name)
+		}
+
+		trees, err := lib.definitions(ctx)
+		if err != nil {
+			return "", errors.WithMessagef(err, "failed parsing library %q", name)
+		}
+
+		err = ctx.AddDefinitions(trees)
+		if err != nil {
+			return "", err
+		}
+
+		imported[name] = true
+		return "", nil
+	}
+}
+
+// findLibraryImports returns the names of the libraries imported in the responses
+func findLibraryImports(responses []string) []string {
+	var result []string
+	for _, r := range responses {
+		for _, m := range libraryImportRegex.FindAllStringSubmatch(r, -1) {
+			if !slices.Contains(result, m[1]) {
+				result = append(result, m[1])
+			}
+		}
+	}
+
+	return result
+}
+
+// libraryDependents returns the local ids of the commands importing each library, by library name
+func libraryDependents(cmds []*models.CustomCommand) map[string][]int64 {
+	result := make(map[string][]int64)
+	for _, cmd := range cmds {
+		for _, name := range findLibraryImports(cmd.Responses) {
+			result[name] = append(result[name], cmd.LocalID)
+		}
+	}
+
+	for _, v := range result {
+		sort.Slice(v, func(i, j int) bool { return v[i] < v[j] })
+	}
+
+	return result
+}
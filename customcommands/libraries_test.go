@@ -0,0 +1,26 @@
+package customcommands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/botlabs-gg/yagpdb/v2/customcommands/models"
+)
+
+func TestLibraryDependents(t *testing.T) {
+	cmds := []*models.CustomCommand{
+		{LocalID: 3, Responses: []string{`{{import "economy"}}{{template "balance" .}}`}},
+		{LocalID: 1, Responses: []string{`{{- import "economy" -}}`, `{{import "embeds"}}{{import "economy"}}`}},
+		{LocalID: 2, Responses: []string{`{{/* import economy */}}`}},
+	}
+
+	got := libraryDependents(cmds)
+	want := map[string][]int64{
+		"economy": {1, 3},
+		"embeds":  {1},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("libraryDependents() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,409 @@
+package customcommands
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/botlabs-gg/yagpdb/v2/common/templates"
+	"github.com/botlabs-gg/yagpdb/v2/customcommands/models"
+	"github.com/botlabs-gg/yagpdb/v2/lib/template/parse"
+)
+
+// LintWarning is a possible problem found in a custom command response without running it
+type LintWarning struct {
+	// index of the response the warning is in
+	Response int
+	Line     int
+	Col      int
+	Message  string
+}
+
+func (w *LintWarning) String() string {
+	return fmt.Sprintf("Response %d, line %d:%d: %s", w.Response+1, w.Line, w.Col, w.Message)
+}
+
+var interactionTriggerTypes = []CommandTriggerType{
+	CommandTriggerComponent,
+	CommandTriggerModal,
+	CommandTriggerSlash,
+	CommandTriggerUserContextMenu,
+	CommandTriggerMessageContextMenu,
+}
+
+var textTriggerTypes = []CommandTriggerType{
+	CommandTriggerCommand,
+	CommandTriggerStartsWith,
+	CommandTriggerContains,
+	CommandTriggerRegex,
+	CommandTriggerExact,
+}
+
+// lintTriggerFuncs are the functions that only work in commands triggered in certain ways
+var lintTriggerFuncs = map[string][]CommandTriggerType{
+	"sendModal":             {CommandTriggerComponent, CommandTriggerSlash, CommandTriggerUserContextMenu, CommandTriggerMessageContextMenu},
+	"updateMessage":         {CommandTriggerComponent, CommandTriggerModal},
+	"updateMessageNoEscape": {CommandTriggerComponent, CommandTriggerModal},
+	"editResponse":          interactionTriggerTypes,
+	"editResponseNoEscape":  interactionTriggerTypes,
+}
+
+// lintTriggerData is the template data only available in commands triggered in certain ways
+var lintTriggerData = map[string][]CommandTriggerType{
+	"Reaction":             {CommandTriggerReaction},
+	"ReactionAdded":        {CommandTriggerReaction},
+	"ReactionMessage":      {CommandTriggerReaction},
+	"Role":                 {CommandTriggerRole},
+	"RoleAdded":            {CommandTriggerRole},
+	"TargetMember":         {CommandTriggerRole, CommandTriggerUserContextMenu, CommandTriggerMessageContextMenu},
+	"TargetUser":           {CommandTriggerRole, CommandTriggerUserContextMenu, CommandTriggerMessageContextMenu},
+	"CustomID":             {CommandTriggerComponent, CommandTriggerModal},
+	"StrippedID":           {CommandTriggerComponent, CommandTriggerModal},
+	"IsButton":             {CommandTriggerComponent, CommandTriggerModal},
+	"IsMenu":               {CommandTriggerComponent, CommandTriggerModal},
+	"IsModal":              {CommandTriggerComponent, CommandTriggerModal},
+	"MenuType":             {CommandTriggerComponent, CommandTriggerModal},
+	"Values":               {CommandTriggerComponent, CommandTriggerModal},
+	"ModalValues":          {CommandTriggerComponent, CommandTriggerModal},
+	"Options":              {CommandTriggerSlash},
+	"SubCommand":           {CommandTriggerSlash},
+	"IsSlashCommand":       {CommandTriggerSlash},
+	"CommandType":          {CommandTriggerUserContextMenu, CommandTriggerMessageContextMenu},
+	"IsContextMenuCommand": {CommandTriggerUserContextMenu, CommandTriggerMessageContextMenu},
+	"IsMessageEdit":        textTriggerTypes,
+	"DeletedMessage":       {CommandTriggerMessageDelete},
+	"OldMessage":           {CommandTriggerMessageEdit},
+	"VoiceState":           {CommandTriggerVoiceJoin},
+	"PreviousVoiceState":   {CommandTriggerVoiceJoin},
+	"VoiceChannel":         {CommandTriggerVoiceJoin},
+}
+
+// lintCallLimit maps template functions to the call counter they increase during execution,
+// the limit itself is looked up in templates.CallLimits
+type lintCallLimit struct {
+	Desc    string
+	Funcs   []string
+	Counter string
+}
+
+var lintCallLimits = []*lintCallLimit{
+	{Desc: "database interactions", Counter: "db_interactions", Funcs: []string{"dbSet", "dbSetExpire", "dbIncr", "dbCompareAndSet", "dbSetField", "dbIncrField", "dbDelField", "dbTransfer", "dbGet", "dbGetPattern", "dbGetPatternReverse",
+		"dbDel", "dbDelById", "dbDelByID", "dbDelMultiple", "dbTopEntries", "dbBottomEntries", "dbCount", "dbRank"}},
+	{Desc: "execCC calls", Counter: "runcc", Funcs: []string{"execCC"}},
+	{Desc: "sendTemplate calls", Counter: "exec_child", Funcs: []string{"sendTemplate", "sendTemplateDM"}},
+	{Desc: "sendDM calls", Counter: "send_dm", Funcs: []string{"sendDM"}},
+	{Desc: "images", Counter: "images", Funcs: []string{"newImage"}},
+	{Desc: "http requests", Counter: "http_requests", Funcs: []string{"httpGet", "httpPost"}},
+	{Desc: "editNickname calls", Counter: "edit_nick", Funcs: []string{"editNickname"}},
+	{Desc: "channel edits", Counter: "edit_channel", Funcs: []string{"editChannelName", "editChannelTopic"}},
+}
+
+var lintParseErrorRegex = regexp.MustCompile(`^template: [^:]*:(\d+):(?:(\d+):)? (.*)$`)
+
+// LintCustomCommand checks the responses of the command for problems that would otherwise only show up when it runs
+func LintCustomCommand(cc *models.CustomCommand, premium bool) []*LintWarning {
+	var result []*LintWarning
+	for i, r := range cc.Responses {
+		for _, w := range lintResponse(CommandTriggerType(cc.TriggerType), r, premium) {
+			w.Response = i
+			result = append(result, w)
+		}
+	}
+
+	return result
+}
+
+type linter struct {
+	triggerType CommandTriggerType
+	source      string
+	premium     bool
+
+	warnings []*LintWarning
+
+	callCounts   map[*lintCallLimit]int
+	templateRefs map[string]bool
+	// set if a template is referenced by a name not known before execution
+	dynamicTemplateRefs bool
+}
+
+// lintScope is the state of the walk at a node
+type lintScope struct {
+	// whether . and $ are the template data
+	dotIsData    bool
+	dollarIsData bool
+
+	// how many times the node runs, 0 if unknown
+	iterations int
+}
+
+func lintResponse(triggerType CommandTriggerType, source string, premium bool) []*LintWarning {
+	l := &linter{
+		triggerType:  triggerType,
+		source:       source,
+		premium:      premium,
+		callCounts:   make(map[*lintCallLimit]int),
+		templateRefs: make(map[string]bool),
+	}
+
+	parsed, err := templates.NewContext(nil, nil, nil).Parse(source)
+	if err != nil {
+		return []*LintWarning{lintParseError(err)}
+	}
+
+	l.walk(parsed.Tree.Root, lintScope{dotIsData: true, dollarIsData: true, iterations: 1})
+
+	var defines []*parse.Tree
+	for _, t := range parsed.Templates() {
+		if t == parsed || t.Tree == nil {
+			continue
+		}
+
+		// the data passed to defined templates is unknown
+		l.walk(t.Tree.Root, lintScope{iterations: 1})
+		defines = append(defines, t.Tree)
+	}
+
+	if !l.dynamicTemplateRefs {
+		for _, t := range defines {
+			if !l.templateRefs[t.Name] {
+				l.warn(t.Root, fmt.Sprintf("template %q is defined but never used", t.Name))
+			}
+		}
+	}
+
+	sort.SliceStable(l.warnings, func(i, j int) bool {
+		if l.warnings[i].Line != l.warnings[j].Line {
+			return l.warnings[i].Line < l.warnings[j].Line
+		}
+		return l.warnings[i].Col < l.warnings[j].Col
+	})
+
+	return l.warnings
+}
+
+func lintParseError(err error) *LintWarning {
+	w := &LintWarning{Line: 1, Col: 1, Message: err.Error()}
+
+	m := lintParseErrorRegex.FindStringSubmatch(err.Error())
+	if m == nil {
+		return w
+	}
+
+	w.Line, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		w.Col, _ = strconv.Atoi(m[2])
+	}
+	w.Message = m[3]
+	return w
+}
+
+func (l *linter) warn(n parse.Node, msg string) {
+	l.warnAt(int(n.Position()), msg)
+}
+
+func (l *linter) warnAt(pos int, msg string) {
+	if pos > len(l.source) {
+		pos = len(l.source)
+	}
+
+	before := l.source[:pos]
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+
+	l.warnings = append(l.warnings, &LintWarning{
+		Line:    strings.Count(before, "\n") + 1,
+		Col:     utf8.RuneCountInString(before[lineStart:]) + 1,
+		Message: msg,
+	})
+}
+
+func (l *linter) walk(node parse.Node, s lintScope) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, v := range n.Nodes {
+			l.walk(v, s)
+		}
+	case *parse.ActionNode:
+		l.pipe(n.Pipe, s)
+	case *parse.IfNode:
+		l.pipe(n.Pipe, s)
+		l.walk(n.List, s)
+		l.walk(n.ElseList, s)
+	case *parse.WithNode:
+		l.pipe(n.Pipe, s)
+		inner := s
+		inner.dotIsData = false
+		l.walk(n.List, inner)
+		l.walk(n.ElseList, s)
+	case *parse.RangeNode:
+		l.pipe(n.Pipe, s)
+		inner := s
+		inner.dotIsData = false
+		inner.iterations = s.iterations * rangeIterations(n.Pipe)
+		l.walk(n.List, inner)
+		l.walk(n.ElseList, s)
+	case *parse.WhileNode:
+		l.pipe(n.Pipe, s)
+		inner := s
+		inner.iterations = 0
+		l.walk(n.List, inner)
+		l.walk(n.ElseList, s)
+	case *parse.TryNode:
+		l.walk(n.List, s)
+		l.walk(n.CatchList, s)
+	case *parse.TemplateNode:
+		l.templateRefs[n.Name] = true
+		l.pipe(n.Pipe, s)
+	case *parse.ReturnNode:
+		l.pipe(n.Pipe, s)
+	}
+}
+
+func (l *linter) pipe(p *parse.PipeNode, s lintScope) {
+	if p == nil {
+		return
+	}
+
+	for _, cmd := range p.Cmds {
+		l.command(cmd, s)
+	}
+}
+
+func (l *linter) command(cmd *parse.CommandNode, s lintScope) {
+	for _, arg := range cmd.Args {
+		switch a := arg.(type) {
+		case *parse.IdentifierNode:
+			l.call(a, cmd, s)
+		case *parse.FieldNode:
+			if s.dotIsData {
+				// chained fields are positioned at the second field
+				pos := int(a.Pos)
+				if len(a.Ident) > 1 {
+					pos -= len(a.Ident[0]) + 1
+				}
+				l.data(pos, a.Ident[0])
+			}
+		case *parse.VariableNode:
+			if s.dollarIsData && len(a.Ident) > 1 && a.Ident[0] == "$" {
+				l.data(int(a.Pos), a.Ident[1])
+			}
+		case *parse.ChainNode:
+			if p, ok := a.Node.(*parse.PipeNode); ok {
+				l.pipe(p, s)
+			}
+		case *parse.PipeNode:
+			l.pipe(a, s)
+		}
+	}
+}
+
+func (l *linter) call(ident *parse.IdentifierNode, cmd *parse.CommandNode, s lintScope) {
+	name := ident.Ident
+
+	if cmd.Args[0] == ident {
+		switch name {
+		case "execTemplate":
+			l.templateRef(cmd, 1)
+		case "sendTemplate":
+			l.templateRef(cmd, 2)
+		case "sendTemplateDM":
+			l.templateRef(cmd, 1)
+		}
+	}
+
+	if types, ok := lintTriggerFuncs[name]; ok && l.checkTriggerType() && !slices.Contains(types, l.triggerType) {
+		l.warn(ident, fmt.Sprintf("%s does not work in commands with the %s trigger, it only works with %s", name, l.triggerType, triggerTypesString(types)))
+	}
+
+	if s.iterations == 0 {
+		// we don't know how many times loops with dynamic conditions run, so don't guess
+		return
+	}
+
+	for _, limit := range lintCallLimits {
+		if !slices.Contains(limit.Funcs, name) {
+			continue
+		}
+
+		max := templates.CallLimits[limit.Counter].Limit(l.premium, templates.ExecutedFromStandard)
+
+		before := l.callCounts[limit]
+		l.callCounts[limit] += s.iterations
+		if before <= max && l.callCounts[limit] > max {
+			l.warn(ident, fmt.Sprintf("too many %s, at least %d calls while the limit is %d per execution", limit.Desc, l.callCounts[limit], max))
+		}
+	}
+}
+
+// templateRef records the template name passed as the argument at index i of cmd
+func (l *linter) templateRef(cmd *parse.CommandNode, i int) {
+	if i >= len(cmd.Args) {
+		return
+	}
+
+	str, ok := cmd.Args[i].(*parse.StringNode)
+	if !ok {
+		l.dynamicTemplateRefs = true
+		return
+	}
+
+	l.templateRefs[str.Text] = true
+}
+
+func (l *linter) data(pos int, field string) {
+	types, ok := lintTriggerData[field]
+	if !ok || !l.checkTriggerType() || slices.Contains(types, l.triggerType) {
+		return
+	}
+
+	l.warnAt(pos, fmt.Sprintf(".%s is not available in commands with the %s trigger, it's only set with %s", field, l.triggerType, triggerTypesString(types)))
+}
+
+// checkTriggerType returns false for commands that are only ran by other commands,
+// they get data and interactions from whatever ran them
+func (l *linter) checkTriggerType() bool {
+	return l.triggerType != CommandTriggerNone
+}
+
+// rangeIterations returns how many times a range over the pipe runs, or 0 if it's not known before execution
+func rangeIterations(p *parse.PipeNode) int {
+	if p == nil || len(p.Cmds) != 1 {
+		return 0
+	}
+
+	args := p.Cmds[0].Args
+	switch {
+	case len(args) == 1:
+		if n, ok := args[0].(*parse.NumberNode); ok && n.IsInt && n.Int64 > 0 {
+			return int(n.Int64)
+		}
+	case len(args) == 3:
+		ident, ok := args[0].(*parse.IdentifierNode)
+		if !ok || ident.Ident != "seq" {
+			return 0
+		}
+
+		start, ok1 := args[1].(*parse.NumberNode)
+		stop, ok2 := args[2].(*parse.NumberNode)
+		if ok1 && ok2 && start.IsInt && stop.IsInt && stop.Int64 > start.Int64 {
+			return int(stop.Int64 - start.Int64)
+		}
+	}
+
+	return 0
+}
+
+func triggerTypesString(types []CommandTriggerType) string {
+	strs := make([]string, len(types))
+	for i, v := range types {
+		strs[i] = v.String()
+	}
+
+	return strings.Join(strs, ", ")
+}
@@ -0,0 +1,65 @@
+package customcommands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		triggerType CommandTriggerType
+		source      string
+		// substrings of the expected warnings, in order
+		want []string
+		line int
+		col  int
+	}{
+		{"clean", CommandTriggerCommand, `{{$x := 1}}{{range seq 0 5}}{{dbGet $.User.ID "a"}}{{end}}`, nil, 0, 0},
+		{"parse error", CommandTriggerCommand, "hello\n{{$x}}", []string{`undefined variable "$x"`}, 2, 0},
+		{"unknown function", CommandTriggerCommand, "{{notAFunc}}", []string{`function "notAFunc" not defined`}, 1, 0},
+		{"interaction func", CommandTriggerCommand, "a\n  {{sendModal (cmodal)}}", []string{"sendModal does not work"}, 2, 5},
+		{"interaction func allowed", CommandTriggerComponent, "{{updateMessage \"hi\"}}", nil, 0, 0},
+		{"trigger data", CommandTriggerCommand, "{{.Reaction.Emoji}}", []string{".Reaction is not available"}, 1, 3},
+		{"trigger data via $", CommandTriggerCommand, "{{with 1}}{{$.Role}}{{end}}", []string{".Role is not available"}, 1, 14},
+		{"dot inside range", CommandTriggerCommand, "{{range .Args}}{{.Reaction}}{{end}}", nil, 0, 0},
+		{"none trigger", CommandTriggerNone, "{{.Reaction}}{{sendModal 1}}", nil, 0, 0},
+		{"unused define", CommandTriggerCommand, `{{define "a"}}x{{end}}{{define "b"}}y{{end}}{{template "b"}}`, []string{`template "a" is defined but never used`}, 1, 15},
+		{"execTemplate ref", CommandTriggerCommand, `{{define "a"}}x{{end}}{{execTemplate "a"}}`, nil, 0, 0},
+		{"dynamic ref", CommandTriggerCommand, `{{define "a"}}x{{end}}{{execTemplate (print "a")}}`, nil, 0, 0},
+		{"db in range", CommandTriggerCommand, `{{range seq 0 20}}{{dbSet 0 "k" 1}}{{end}}`, []string{"too many database interactions, at least 20 calls while the limit is 10"}, 1, 21},
+		{"db in unknown range", CommandTriggerCommand, `{{range .Args}}{{dbSet 0 "k" 1}}{{end}}`, nil, 0, 0},
+		{"db nested range", CommandTriggerCommand, `{{range 3}}{{range 4}}{{dbGet 0 "k"}}{{end}}{{end}}`, []string{"at least 12 calls"}, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lintResponse(tt.triggerType, tt.source, false)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d warnings %v, want %d", len(got), got, len(tt.want))
+			}
+
+			for i, w := range got {
+				if !strings.Contains(w.Message, tt.want[i]) {
+					t.Errorf("warning %d: got %q, want it to contain %q", i, w.Message, tt.want[i])
+				}
+			}
+
+			if len(got) > 0 {
+				if tt.line != 0 && got[0].Line != tt.line {
+					t.Errorf("got line %d, want %d", got[0].Line, tt.line)
+				}
+				if tt.col != 0 && got[0].Col != tt.col {
+					t.Errorf("got col %d, want %d", got[0].Col, tt.col)
+				}
+			}
+		})
+	}
+}
+
+func TestLintPremiumLimits(t *testing.T) {
+	source := `{{range seq 0 20}}{{dbSet 0 "k" 1}}{{end}}`
+	if got := lintResponse(CommandTriggerCommand, source, true); len(got) != 0 {
+		t.Errorf("expected no warnings with premium limits, got %v", got)
+	}
+}
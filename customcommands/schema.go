@@ -104,4 +104,26 @@ CREATE TABLE IF NOT EXISTS templates_user_database (
 CREATE INDEX IF NOT EXISTS templates_user_database_combined_idx ON templates_user_database (guild_id, user_id, key, value_num);
 `, `
 CREATE INDEX IF NOT EXISTS templates_user_database_expires_idx ON templates_user_database (expires_at);
+`, `
+CREATE TABLE IF NOT EXISTS custom_command_libraries (
+	id BIGSERIAL PRIMARY KEY,
+	guild_id BIGINT NOT NULL,
+
+	name TEXT NOT NULL,
+	source TEXT NOT NULL,
+
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+	UNIQUE(guild_id, name)
+);
+`, `
+CREATE TABLE IF NOT EXISTS custom_command_http_settings (
+	guild_id BIGINT PRIMARY KEY,
+
+	enabled BOOLEAN NOT NULL,
+	allowed_domains TEXT[] NOT NULL,
+
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
 `}
@@ -0,0 +1,270 @@
+package customcommands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/templates"
+	"github.com/botlabs-gg/yagpdb/v2/customcommands/models"
+	"github.com/mediocregopher/radix/v3"
+)
+
+// Execution stats are kept per custom command in hourly buckets in redis,
+// each bucket is a hash with the sums and maximums of the executions in that hour.
+
+const (
+	ccStatsBucketSize = time.Hour
+	ccStatsRetention  = 7 * 24 * time.Hour
+
+	// prefix of the hash fields holding call counter sums, and maximums
+	ccStatsCounterPrefix    = "c:"
+	ccStatsCounterMaxPrefix = "cm:"
+)
+
+func keyCCStats(guildID, ccID int64, bucket time.Time) string {
+	return "cc_stats:" + strconv.FormatInt(guildID, 10) + ":" + strconv.FormatInt(ccID, 10) + ":" + strconv.FormatInt(bucket.Unix(), 10)
+}
+
+var recordCCStatsScript = radix.NewEvalScript(1, `
+local key = KEYS[1]
+redis.call('HINCRBY', key, 'runs', 1)
+redis.call('HINCRBY', key, 'errors', ARGV[1])
+redis.call('HINCRBY', key, 'duration_ms', ARGV[2])
+redis.call('HINCRBY', key, 'ops', ARGV[3])
+
+local function setMax(field, value)
+	if tonumber(redis.call('HGET', key, field) or '0') < tonumber(value) then
+		redis.call('HSET', key, field, value)
+	end
+end
+
+setMax('max_duration_ms', ARGV[2])
+setMax('max_ops', ARGV[3])
+
+for i = 5, #ARGV, 2 do
+	redis.call('HINCRBY', key, 'c:' .. ARGV[i], ARGV[i + 1])
+	setMax('cm:' .. ARGV[i], ARGV[i + 1])
+end
+
+redis.call('EXPIRE', key, ARGV[4])
+return 1
+`)
+
+// recordCCExecutionStats adds a execution to the current stats bucket of the custom command
+func recordCCExecutionStats(guildID, ccID int64, duration time.Duration, ops int, counters map[string]int, execErr error) {
+	errored := 0
+	if execErr != nil {
+		errored = 1
+	}
+
+	bucket := time.Now().Truncate(ccStatsBucketSize)
+	args := []string{
+		keyCCStats(guildID, ccID, bucket),
+		strconv.Itoa(errored),
+		strconv.FormatInt(duration.Milliseconds(), 10),
+		strconv.Itoa(ops),
+		strconv.Itoa(int((ccStatsRetention + ccStatsBucketSize).Seconds())),
+	}
+	for k, v := range counters {
+		args = append(args, k, strconv.Itoa(v))
+	}
+
+	err := common.RedisPool.Do(recordCCStatsScript.Cmd(nil, args...))
+	if err != nil {
+		logger.WithError(err).WithField("guild", guildID).Error("failed recording custom command execution stats")
+	}
+}
+
+type CCCounterStats struct {
+	Name  string
+	Total int64
+	Max   int64
+	// the limit of the counter if it's one the linter knows about, 0 otherwise
+	Limit int
+}
+
+func (c *CCCounterStats) Avg(runs int64) float64 {
+	if runs == 0 {
+		return 0
+	}
+
+	return float64(c.Total) / float64(runs)
+}
+
+type CCStats struct {
+	Since time.Time
+
+	Runs          int64
+	Errors        int64
+	DurationMS    int64
+	MaxDurationMS int64
+	Ops           int64
+	MaxOps        int64
+	OpsLimit      int
+
+	Counters []*CCCounterStats
+}
+
+func (s *CCStats) ErrorRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+
+	return float64(s.Errors) / float64(s.Runs) * 100
+}
+
+func (s *CCStats) AvgDurationMS() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+
+	return float64(s.DurationMS) / float64(s.Runs)
+}
+
+func (s *CCStats) AvgOps() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+
+	return float64(s.Ops) / float64(s.Runs)
+}
+
+// MaxOpsPercent is how close the heaviest execution got to the ops limit
+func (s *CCStats) MaxOpsPercent() float64 {
+	if s.OpsLimit == 0 {
+		return 0
+	}
+
+	return float64(s.MaxOps) / float64(s.OpsLimit) * 100
+}
+
+// GetCCStats returns the combined stats of the custom command over the last period
+func GetCCStats(guildID, ccID int64, period time.Duration, isPremium bool) (*CCStats, error) {
+	now := time.Now().Truncate(ccStatsBucketSize)
+	since := now.Add(-period + ccStatsBucketSize)
+
+	var buckets []map[string]string
+	var actions []radix.CmdAction
+	for t := since; !t.After(now); t = t.Add(ccStatsBucketSize) {
+		bucket := make(map[string]string)
+		buckets = append(buckets, bucket)
+		actions = append(actions, radix.Cmd(&bucket, "HGETALL", keyCCStats(guildID, ccID, t)))
+	}
+
+	err := common.RedisPool.Do(radix.Pipeline(actions...))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := combineCCStatsBuckets(buckets, isPremium)
+	stats.Since = since
+	return stats, nil
+}
+
+func combineCCStatsBuckets(buckets []map[string]string, isPremium bool) *CCStats {
+	stats := &CCStats{OpsLimit: templates.MaxOpsNormal}
+	if isPremium {
+		stats.OpsLimit = templates.MaxOpsPremium
+	}
+
+	counters := make(map[string]*CCCounterStats)
+	getCounter := func(name string) *CCCounterStats {
+		c, ok := counters[name]
+		if !ok {
+			c = &CCCounterStats{Name: name, Limit: ccCounterLimit(name, isPremium)}
+			counters[name] = c
+		}
+		return c
+	}
+
+	for _, b := range buckets {
+		for k, v := range b {
+			n, _ := strconv.ParseInt(v, 10, 64)
+			switch {
+			case k == "runs":
+				stats.Runs += n
+			case k == "errors":
+				stats.Errors += n
+			case k == "duration_ms":
+				stats.DurationMS += n
+			case k == "max_duration_ms":
+				stats.MaxDurationMS = max(stats.MaxDurationMS, n)
+			case k == "ops":
+				stats.Ops += n
+			case k == "max_ops":
+				stats.MaxOps = max(stats.MaxOps, n)
+			case strings.HasPrefix(k, ccStatsCounterPrefix):
+				getCounter(strings.TrimPrefix(k, ccStatsCounterPrefix)).Total += n
+			case strings.HasPrefix(k, ccStatsCounterMaxPrefix):
+				c := getCounter(strings.TrimPrefix(k, ccStatsCounterMaxPrefix))
+				c.Max = max(c.Max, n)
+			}
+		}
+	}
+
+	for _, c := range counters {
+		stats.Counters = append(stats.Counters, c)
+	}
+	sort.Slice(stats.Counters, func(i, j int) bool {
+		return stats.Counters[i].Name < stats.Counters[j].Name
+	})
+
+	return stats
+}
+
+// ccCounterLimit returns the per execution limit of a call counter, or 0 if it's not a known one.
+// Only custom command executions are recorded, so evalcc limits don't apply here.
+func ccCounterLimit(name string, isPremium bool) int {
+	limit, ok := templates.CallLimits[name]
+	if !ok {
+		return 0
+	}
+
+	return limit.Limit(isPremium, templates.ExecutedFromStandard)
+}
+
+// ccStatsOutput formats the stats of the last 24 hours and 7 days for `-cc stats <id>` and the -stats switch
+func ccStatsOutput(cc *models.CustomCommand, isPremium bool) (string, error) {
+	day, err := GetCCStats(cc.GuildID, cc.LocalID, 24*time.Hour, isPremium)
+	if err != nil {
+		return "", err
+	}
+
+	week, err := GetCCStats(cc.GuildID, cc.LocalID, ccStatsRetention, isPremium)
+	if err != nil {
+		return "", err
+	}
+
+	if week.Runs == 0 {
+		return fmt.Sprintf("Custom command #%d has not run in the last 7 days.", cc.LocalID), nil
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "Execution stats for custom command #%d:\n```\n", cc.LocalID)
+	fmt.Fprintf(&out, "%-16s %12s %12s\n", "", "24 hours", "7 days")
+	fmt.Fprintf(&out, "%-16s %12d %12d\n", "Runs", day.Runs, week.Runs)
+	fmt.Fprintf(&out, "%-16s %11.1f%% %11.1f%%\n", "Error rate", day.ErrorRate(), week.ErrorRate())
+	fmt.Fprintf(&out, "%-16s %10.0fms %10.0fms\n", "Avg duration", day.AvgDurationMS(), week.AvgDurationMS())
+	fmt.Fprintf(&out, "%-16s %10dms %10dms\n", "Max duration", day.MaxDurationMS, week.MaxDurationMS)
+	fmt.Fprintf(&out, "%-16s %12.0f %12.0f\n", "Avg ops", day.AvgOps(), week.AvgOps())
+	fmt.Fprintf(&out, "%-16s %12d %12d\n", "Max ops", day.MaxOps, week.MaxOps)
+	fmt.Fprintf(&out, "\nHeaviest run used %.1f%% of the %d ops limit.\n", week.MaxOpsPercent(), week.OpsLimit)
+
+	if len(week.Counters) > 0 {
+		out.WriteString("\nCall counters (7 days, avg / max per run):\n")
+		for _, c := range week.Counters {
+			limit := ""
+			if c.Limit > 0 {
+				limit = fmt.Sprintf(" (limit %d)", c.Limit)
+			}
+			fmt.Fprintf(&out, "%-20s %.1f / %d%s\n", c.Name, c.Avg(week.Runs), c.Max, limit)
+		}
+	}
+	out.WriteString("```")
+
+	return out.String(), nil
+}
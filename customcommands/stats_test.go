@@ -0,0 +1,113 @@
+package customcommands
+
+import (
+	"testing"
+
+	"github.com/botlabs-gg/yagpdb/v2/common/templates"
+	"github.com/botlabs-gg/yagpdb/v2/customcommands/models"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
+	"github.com/volatiletech/null/v8"
+)
+
+func TestCombineCCStatsBuckets(t *testing.T) {
+	buckets := []map[string]string{
+		{"runs": "3", "errors": "1", "duration_ms": "30", "max_duration_ms": "20", "ops": "300", "max_ops": "150", "c:db_interactions": "6", "cm:db_interactions": "4"},
+		{},
+		{"runs": "1", "duration_ms": "50", "max_duration_ms": "50", "ops": "100", "max_ops": "100", "c:db_interactions": "2", "cm:db_interactions": "2", "c:api_call": "1", "cm:api_call": "1"},
+	}
+
+	stats := combineCCStatsBuckets(buckets, false)
+	if stats.Runs != 4 || stats.Errors != 1 {
+		t.Fatalf("unexpected runs/errors: %d/%d", stats.Runs, stats.Errors)
+	}
+
+	if stats.ErrorRate() != 25 || stats.AvgDurationMS() != 20 || stats.MaxDurationMS != 50 {
+		t.Errorf("unexpected durations: rate %v avg %v max %d", stats.ErrorRate(), stats.AvgDurationMS(), stats.MaxDurationMS)
+	}
+
+	if stats.AvgOps() != 100 || stats.MaxOps != 150 || stats.OpsLimit != templates.MaxOpsNormal {
+		t.Errorf("unexpected ops: avg %v max %d limit %d", stats.AvgOps(), stats.MaxOps, stats.OpsLimit)
+	}
+
+	if len(stats.Counters) != 2 {
+		t.Fatalf("expected 2 counters, got %d", len(stats.Counters))
+	}
+
+	api, db := stats.Counters[0], stats.Counters[1]
+	if api.Name != "api_call" || api.Total != 1 || api.Max != 1 || api.Limit != 100 {
+		t.Errorf("unexpected api_call counter: %+v", api)
+	}
+
+	if db.Name != "db_interactions" || db.Total != 8 || db.Max != 4 || db.Limit != 10 || db.Avg(stats.Runs) != 2 {
+		t.Errorf("unexpected db_interactions counter: %+v", db)
+	}
+
+	empty := combineCCStatsBuckets(nil, true)
+	if empty.Runs != 0 || empty.ErrorRate() != 0 || empty.AvgOps() != 0 || empty.OpsLimit != templates.MaxOpsPremium {
+		t.Errorf("unexpected empty stats: %+v", empty)
+	}
+}
+
+func TestLintCallLimitsRegistered(t *testing.T) {
+	for _, limit := range lintCallLimits {
+		if _, ok := templates.CallLimits[limit.Counter]; !ok {
+			t.Errorf("lint counter %q has no call limit", limit.Counter)
+		}
+	}
+
+	if ccCounterLimit("cancelcc", false) != 10 || ccCounterLimit("sort", true) != 3 {
+		t.Error("expected the limits of all fixed counters to be known")
+	}
+
+	apiCall := templates.CallLimits["api_call"]
+	if apiCall.Limit(false, templates.ExecutedFromEvalCC) != 20 || apiCall.Limit(true, templates.ExecutedFromStandard) != 100 {
+		t.Errorf("unexpected api_call limits: %+v", apiCall)
+	}
+}
+
+func TestCCStatsSubcommand(t *testing.T) {
+	ccs := []*models.CustomCommand{
+		{LocalID: 5, TextTrigger: "hello"},
+		{LocalID: 6, TextTrigger: "stats 7"},
+		{LocalID: 8, TextTrigger: "other", Name: null.StringFrom("My Command")},
+	}
+
+	tests := []struct {
+		id      interface{}
+		input   interface{}
+		wantIDs []int64
+		wantOK  bool
+	}{
+		{nil, "stats 5", []int64{5}, true},
+		{nil, "STATS hello", []int64{5}, true},
+		{nil, "stats my command", []int64{8}, true},
+		{nil, "stats 7", nil, false},
+		{nil, "stats", nil, false},
+		{nil, "hello", nil, false},
+		{int64(5), nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		data := &dcmd.Data{Args: []*dcmd.ParsedArg{{Value: tt.id}, {Value: tt.input}}}
+		found, _ := FindCommands(ccs, data)
+		ref, ok := ccStatsSubcommand(data, found)
+		if ok != tt.wantOK {
+			t.Errorf("%v/%v: got ok %v, want %v", tt.id, tt.input, ok, tt.wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		got := findCommandsByRef(ccs, ref)
+		if len(got) != len(tt.wantIDs) {
+			t.Errorf("%v: got %d commands, want %d", tt.input, len(got), len(tt.wantIDs))
+			continue
+		}
+		for i, cc := range got {
+			if cc.LocalID != tt.wantIDs[i] {
+				t.Errorf("%v: got id %d, want %d", tt.input, cc.LocalID, tt.wantIDs[i])
+			}
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net/http"
+	"reflect"
 	"slices"
 	"time"
 
@@ -27,16 +29,30 @@ import (
 )
 
 func init() {
+	templates.RegisterCallLimit("db_interactions", templates.CallLimit{Normal: 10, Premium: 50})
+	templates.RegisterCallLimit("db_multiple", templates.CallLimit{Normal: 2, Premium: 10})
+	templates.RegisterCallLimit("runcc", templates.CallLimit{Normal: 1, Premium: 10})
+	templates.RegisterCallLimit("cancelcc", templates.CallLimit{Normal: 10, Premium: 10})
+	templates.RegisterCallLimit("http_requests", templates.CallLimit{Normal: MaxHTTPRequestsPerRun, Premium: MaxHTTPRequestsPerRunPremium})
+
 	templates.RegisterSetupFunc(func(ctx *templates.Context) {
 		ctx.ContextFuncs["parseArgs"] = tmplExpectArgs(ctx)
 		ctx.ContextFuncs["carg"] = tmplCArg
 		ctx.ContextFuncs["execCC"] = tmplRunCC(ctx)
 		ctx.ContextFuncs["scheduleUniqueCC"] = tmplScheduleUniqueCC(ctx)
 		ctx.ContextFuncs["cancelScheduledUniqueCC"] = tmplCancelUniqueCC(ctx)
+		ctx.ContextFuncs["import"] = tmplImportLibrary(ctx)
+		ctx.ContextFuncs["httpGet"] = tmplHTTPRequest(ctx, http.MethodGet)
+		ctx.ContextFuncs["httpPost"] = tmplHTTPRequest(ctx, http.MethodPost)
 
 		ctx.ContextFuncs["dbSet"] = tmplDBSet(ctx)
 		ctx.ContextFuncs["dbSetExpire"] = tmplDBSetExpire(ctx)
 		ctx.ContextFuncs["dbIncr"] = tmplDBIncr(ctx)
+		ctx.ContextFuncs["dbCompareAndSet"] = tmplDBCompareAndSet(ctx)
+		ctx.ContextFuncs["dbSetField"] = tmplDBSetField(ctx)
+		ctx.ContextFuncs["dbIncrField"] = tmplDBIncrField(ctx)
+		ctx.ContextFuncs["dbDelField"] = tmplDBDelField(ctx)
+		ctx.ContextFuncs["dbTransfer"] = tmplDBTransfer(ctx)
 		ctx.ContextFuncs["dbGet"] = tmplDBGet(ctx)
 		ctx.ContextFuncs["dbGetPattern"] = tmplDBGetPattern(ctx, false)
 		ctx.ContextFuncs["dbGetPatternReverse"] = tmplDBGetPattern(ctx, true)
@@ -182,7 +198,7 @@ func tmplRunCC(ctx *templates.Context) interface{} {
 			return "", nil
 		}
 
-		if ctx.IncreaseCheckCallCounterPremium("runcc", 1, 10) {
+		if ctx.IncreaseCheckCallLimit("runcc") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -289,7 +305,7 @@ func tmplRunCC(ctx *templates.Context) interface{} {
 // then when you use the custom mute command again it will overwrite the mute duration and overwrite the scheduled unmute cc for that user
 func tmplScheduleUniqueCC(ctx *templates.Context) interface{} {
 	return func(ccID int, channel interface{}, delaySeconds interface{}, key interface{}, data interface{}) (string, error) {
-		if ctx.IncreaseCheckCallCounterPremium("runcc", 1, 10) {
+		if ctx.IncreaseCheckCallLimit("runcc") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -376,7 +392,7 @@ func tmplScheduleUniqueCC(ctx *templates.Context) interface{} {
 // tmplCancelUniqueCC cancels a scheduled cc execution in the future with the provided cc id and key
 func tmplCancelUniqueCC(ctx *templates.Context) interface{} {
 	return func(ccID int, key interface{}) (string, error) {
-		if ctx.IncreaseCheckCallCounter("cancelcc", 10) {
+		if ctx.IncreaseCheckCallLimit("cancelcc") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -408,7 +424,7 @@ func tmplDBSet(ctx *templates.Context) interface{} {
 
 func tmplDBSetExpire(ctx *templates.Context) func(userID int64, key interface{}, value interface{}, ttl int) (string, error) {
 	return func(userID int64, key interface{}, value interface{}, ttl int) (string, error) {
-		if ctx.IncreaseCheckCallCounterPremium("db_interactions", 10, 50) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -452,7 +468,7 @@ func tmplDBSetExpire(ctx *templates.Context) func(userID int64, key interface{},
 
 func tmplDBIncr(ctx *templates.Context) interface{} {
 	return func(userID int64, key interface{}, incrBy interface{}) (interface{}, error) {
-		if ctx.IncreaseCheckCallCounterPremium("db_interactions", 10, 50) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -503,9 +519,422 @@ RETURNING value_num`
 	}
 }
 
+// dbLockedUpdate locks the entry for the duration of fn and writes the value it returns, unless write is false.
+// Expired entries are passed as not existing, and updating them clears their expiry.
+// Live entries keep their expiry.
+func dbLockedUpdate(guildID, userID int64, key string, fn func(current interface{}, exists bool) (newValue interface{}, write bool, err error)) (bool, error) {
+	// a second attempt is needed if another execution created the entry while we were creating it
+	for range 2 {
+		written, retry, err := dbLockedUpdateAttempt(guildID, userID, key, fn)
+		if !retry {
+			return written, err
+		}
+	}
+
+	return false, errors.New("Entry was modified concurrently, try again")
+}
+
+func dbLockedUpdateAttempt(guildID, userID int64, key string, fn func(current interface{}, exists bool) (interface{}, bool, error)) (written bool, retry bool, err error) {
+	tx, err := common.PQ.Begin()
+	if err != nil {
+		return false, false, err
+	}
+	defer func() {
+		if !written || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var id int64
+	var valueRaw []byte
+	var valueNum float64
+	var expired bool
+	err = tx.QueryRow(`SELECT id, value_raw, value_num, (expires_at IS NOT NULL AND expires_at <= now()) FROM templates_user_database
+WHERE guild_id = $1 AND user_id = $2 AND key = $3 FOR UPDATE`, guildID, userID, key).Scan(&id, &valueRaw, &valueNum, &expired)
+	found := err == nil
+	if err != nil && err != sql.ErrNoRows {
+		return false, false, err
+	}
+
+	var current interface{}
+	exists := found && !expired
+	if exists {
+		current, err = decodeDBValue(valueRaw)
+		if err != nil {
+			return false, false, err
+		}
+
+		if common.IsNumber(current) {
+			current = valueNum
+		}
+	}
+
+	newValue, write, err := fn(current, exists)
+	if err != nil || !write {
+		return false, false, err
+	}
+
+	serialized, err := serializeValue(newValue)
+	if err != nil {
+		return false, false, err
+	}
+	newNum := templates.ToFloat64(newValue)
+
+	if found {
+		_, err = tx.Exec(`UPDATE templates_user_database SET value_raw = $2, value_num = $3, updated_at = now(),
+	created_at = CASE WHEN $4 THEN now() ELSE created_at END,
+	expires_at = CASE WHEN $4 THEN NULL ELSE expires_at END
+WHERE id = $1`, id, serialized, newNum, expired)
+		if err != nil {
+			return false, false, err
+		}
+	} else {
+		res, err := tx.Exec(`INSERT INTO templates_user_database (created_at, updated_at, guild_id, user_id, key, value_raw, value_num)
+VALUES (now(), now(), $1, $2, $3, $4, $5) ON CONFLICT (guild_id, user_id, key) DO NOTHING`, guildID, userID, key, serialized, newNum)
+		if err != nil {
+			return false, false, err
+		}
+
+		if n, _ := res.RowsAffected(); n == 0 {
+			return false, true, nil
+		}
+	}
+
+	written = true
+	err = tx.Commit()
+	return written, false, err
+}
+
+// dbValuesEqual compares a decoded database value with a value from a template,
+// the template value is passed through the same encoding as stored values so types line up
+func dbValuesEqual(stored, value interface{}) (bool, error) {
+	if stored == nil || value == nil {
+		return stored == nil && value == nil, nil
+	}
+
+	if common.IsNumber(stored) || common.IsNumber(value) {
+		return common.IsNumber(stored) && common.IsNumber(value) && templates.ToFloat64(stored) == templates.ToFloat64(value), nil
+	}
+
+	serialized, err := serializeValue(value)
+	if err != nil {
+		return false, err
+	}
+
+	decoded, err := decodeDBValue(serialized)
+	if err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(stored, decoded), nil
+}
+
+// dbSDictValue returns the stored value as a map to update fields in, a missing entry is treated as a empty sdict
+func dbSDictValue(current interface{}, exists bool) (map[string]interface{}, error) {
+	if !exists || current == nil {
+		return make(map[string]interface{}), nil
+	}
+
+	m, ok := current.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("Value is not a sdict")
+	}
+
+	return m, nil
+}
+
+func tmplDBCompareAndSet(ctx *templates.Context) interface{} {
+	return func(userID int64, key interface{}, expected interface{}, value interface{}) (bool, error) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
+			return false, templates.ErrTooManyCalls
+		}
+
+		if aboveLimit, err := CheckGuildDBLimit(ctx.GS); err != nil || aboveLimit {
+			if err != nil {
+				return false, err
+			}
+
+			return false, errors.New("Above DB Limit")
+		}
+
+		keyStr := limitString(templates.ToString(key), 256)
+		return dbLockedUpdate(ctx.GS.ID, userID, keyStr, func(current interface{}, exists bool) (interface{}, bool, error) {
+			equal, err := dbValuesEqual(current, expected)
+			return value, equal, err
+		})
+	}
+}
+
+func tmplDBSetField(ctx *templates.Context) interface{} {
+	return func(userID int64, key interface{}, field interface{}, value interface{}) (string, error) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
+			return "", templates.ErrTooManyCalls
+		}
+
+		if aboveLimit, err := CheckGuildDBLimit(ctx.GS); err != nil || aboveLimit {
+			if err != nil {
+				return "", err
+			}
+
+			return "", errors.New("Above DB Limit")
+		}
+
+		keyStr := limitString(templates.ToString(key), 256)
+		_, err := dbLockedUpdate(ctx.GS.ID, userID, keyStr, func(current interface{}, exists bool) (interface{}, bool, error) {
+			m, err := dbSDictValue(current, exists)
+			if err != nil {
+				return nil, false, err
+			}
+
+			m[templates.ToString(field)] = value
+			return m, true, nil
+		})
+		return "", err
+	}
+}
+
+func tmplDBIncrField(ctx *templates.Context) interface{} {
+	return func(userID int64, key interface{}, field interface{}, incrBy interface{}) (interface{}, error) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
+			return "", templates.ErrTooManyCalls
+		}
+
+		if aboveLimit, err := CheckGuildDBLimit(ctx.GS); err != nil || aboveLimit {
+			if err != nil {
+				return "", err
+			}
+
+			return "", errors.New("Above DB Limit")
+		}
+
+		keyStr := limitString(templates.ToString(key), 256)
+		fieldStr := templates.ToString(field)
+
+		var newVal float64
+		_, err := dbLockedUpdate(ctx.GS.ID, userID, keyStr, func(current interface{}, exists bool) (interface{}, bool, error) {
+			m, err := dbSDictValue(current, exists)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if cur, ok := m[fieldStr]; ok && cur != nil && !common.IsNumber(cur) {
+				return nil, false, errors.New("Field is not a number")
+			}
+
+			newVal = templates.ToFloat64(m[fieldStr]) + templates.ToFloat64(incrBy)
+			m[fieldStr] = newVal
+			return m, true, nil
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return newVal, nil
+	}
+}
+
+func tmplDBDelField(ctx *templates.Context) interface{} {
+	return func(userID int64, key interface{}, field interface{}) (string, error) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
+			return "", templates.ErrTooManyCalls
+		}
+
+		keyStr := limitString(templates.ToString(key), 256)
+		_, err := dbLockedUpdate(ctx.GS.ID, userID, keyStr, func(current interface{}, exists bool) (interface{}, bool, error) {
+			if !exists {
+				return nil, false, nil
+			}
+
+			m, err := dbSDictValue(current, exists)
+			if err != nil {
+				return nil, false, err
+			}
+
+			fieldStr := templates.ToString(field)
+			if _, ok := m[fieldStr]; !ok {
+				return nil, false, nil
+			}
+
+			delete(m, fieldStr)
+			return m, true, nil
+		})
+		return "", err
+	}
+}
+
+// tmplDBTransfer moves amount from one numeric entry to another in a single transaction,
+// returning false without changing anything if the source entry holds less than amount
+func tmplDBTransfer(ctx *templates.Context) interface{} {
+	return func(fromUserID int64, fromKey interface{}, toUserID int64, toKey interface{}, amount interface{}) (bool, error) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
+			return false, templates.ErrTooManyCalls
+		}
+
+		if aboveLimit, err := CheckGuildDBLimit(ctx.GS); err != nil || aboveLimit {
+			if err != nil {
+				return false, err
+			}
+
+			return false, errors.New("Above DB Limit")
+		}
+
+		fromKeyStr := limitString(templates.ToString(fromKey), 256)
+		toKeyStr := limitString(templates.ToString(toKey), 256)
+		if fromUserID == toUserID && fromKeyStr == toKeyStr {
+			return false, errors.New("Can't transfer to the same entry")
+		}
+
+		vNum := templates.ToFloat64(amount)
+		if vNum <= 0 {
+			return false, errors.New("Amount has to be above 0")
+		}
+
+		return dbTransfer(ctx.GS.ID, fromUserID, fromKeyStr, toUserID, toKeyStr, vNum)
+	}
+}
+
+// dbTransferEntry is a entry involved in a transfer, as read under the lock
+type dbTransferEntry struct {
+	raw []byte
+	// false if the entry has expired, it's then treated as missing
+	live bool
+}
+
+// dbEntryNumber returns the number stored in a entry, missing and expired entries count as 0
+func dbEntryNumber(e *dbTransferEntry) (float64, error) {
+	if e == nil || !e.live {
+		return 0, nil
+	}
+
+	v, err := decodeDBValue(e.raw)
+	if err != nil {
+		return 0, err
+	}
+
+	switch t := v.(type) {
+	case int8, int16, int32, int64, int, uint8, uint16, uint32, uint64, uint:
+		return templates.ToFloat64(t), nil
+	case float32:
+		return float64(t), nil
+	case float64:
+		return t, nil
+	}
+
+	return 0, errors.New("Can only transfer between entries holding numbers")
+}
+
+// dbTransferAmounts returns the values of both entries after the transfer, or false if the source doesn't have enough
+func dbTransferAmounts(from, to *dbTransferEntry, amount float64) (fromNum, toNum float64, ok bool, err error) {
+	if from == nil || !from.live {
+		return 0, 0, false, nil
+	}
+
+	fromNum, err = dbEntryNumber(from)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	toNum, err = dbEntryNumber(to)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	if fromNum < amount {
+		return 0, 0, false, nil
+	}
+
+	return fromNum - amount, toNum + amount, true, nil
+}
+
+func dbTransfer(guildID, fromUserID int64, fromKey string, toUserID int64, toKey string, amount float64) (transferred bool, err error) {
+	tx, err := common.PQ.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if !transferred || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// lock both entries in a consistent order so opposite transfers can't deadlock
+	rows, err := tx.Query(`SELECT user_id, key, value_raw, (expires_at IS NULL OR expires_at > now()) FROM templates_user_database
+WHERE guild_id = $1 AND ((user_id = $2 AND key = $3) OR (user_id = $4 AND key = $5))
+ORDER BY user_id, key FOR UPDATE`, guildID, fromUserID, fromKey, toUserID, toKey)
+	if err != nil {
+		return false, err
+	}
+
+	var from, to *dbTransferEntry
+	for rows.Next() {
+		var userID int64
+		var key string
+		e := &dbTransferEntry{}
+		if err = rows.Scan(&userID, &key, &e.raw, &e.live); err != nil {
+			rows.Close()
+			return false, err
+		}
+
+		if userID == fromUserID && key == fromKey {
+			from = e
+		} else {
+			to = e
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+
+	fromNum, toNum, ok, err := dbTransferAmounts(from, to, amount)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	fromSerialized, err := serializeValue(fromNum)
+	if err != nil {
+		return false, err
+	}
+
+	toSerialized, err := serializeValue(toNum)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`UPDATE templates_user_database SET value_raw = $4, value_num = $5, updated_at = now()
+WHERE guild_id = $1 AND user_id = $2 AND key = $3`, guildID, fromUserID, fromKey, fromSerialized, fromNum)
+	if err != nil {
+		return false, err
+	}
+
+	// a expired destination starts over as a new entry
+	_, err = tx.Exec(`INSERT INTO templates_user_database (created_at, updated_at, guild_id, user_id, key, value_raw, value_num)
+VALUES (now(), now(), $1, $2, $3, $4, $5)
+ON CONFLICT (guild_id, user_id, key)
+DO UPDATE SET
+	value_raw = $4,
+	value_num = $5,
+	updated_at = now(),
+	created_at =
+		CASE WHEN (templates_user_database.expires_at IS NULL OR templates_user_database.expires_at > now()) THEN templates_user_database.created_at
+		ELSE now()
+		END,
+	expires_at =
+		CASE WHEN (templates_user_database.expires_at IS NULL OR templates_user_database.expires_at > now()) THEN templates_user_database.expires_at
+		ELSE NULL
+		END`, guildID, toUserID, toKey, toSerialized, toNum)
+	if err != nil {
+		return false, err
+	}
+
+	transferred = true
+	err = tx.Commit()
+	return transferred, err
+}
+
 func tmplDBGet(ctx *templates.Context) interface{} {
 	return func(userID int64, key interface{}) (interface{}, error) {
-		if ctx.IncreaseCheckCallCounterPremium("db_interactions", 10, 50) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -530,11 +959,11 @@ func tmplDBGetPattern(ctx *templates.Context, inverse bool) interface{} {
 	}
 
 	return func(userID int64, pattern interface{}, iAmount interface{}, iSkip interface{}) (interface{}, error) {
-		if ctx.IncreaseCheckCallCounterPremium("db_interactions", 10, 50) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
 			return "", templates.ErrTooManyCalls
 		}
 
-		if ctx.IncreaseCheckCallCounterPremium("db_multiple", 2, 10) {
+		if ctx.IncreaseCheckCallLimit("db_multiple") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -560,7 +989,7 @@ func tmplDBGetPattern(ctx *templates.Context, inverse bool) interface{} {
 
 func tmplDBDel(ctx *templates.Context) interface{} {
 	return func(userID int64, key interface{}) (interface{}, error) {
-		if ctx.IncreaseCheckCallCounterPremium("db_interactions", 10, 50) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -575,7 +1004,7 @@ func tmplDBDel(ctx *templates.Context) interface{} {
 
 func tmplDBDelById(ctx *templates.Context) interface{} {
 	return func(userID int64, id int64) (interface{}, error) {
-		if ctx.IncreaseCheckCallCounterPremium("db_interactions", 10, 50) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -588,11 +1017,11 @@ func tmplDBDelById(ctx *templates.Context) interface{} {
 }
 func tmplDBDelMultiple(ctx *templates.Context) interface{} {
 	return func(query interface{}, iAmount interface{}, iSkip interface{}) (interface{}, error) {
-		if ctx.IncreaseCheckCallCounterPremium("db_interactions", 10, 50) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
 			return "", templates.ErrTooManyCalls
 		}
 
-		if ctx.IncreaseCheckCallCounterPremium("db_multiple", 2, 10) {
+		if ctx.IncreaseCheckCallLimit("db_multiple") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -637,11 +1066,11 @@ func tmplDBDelMultiple(ctx *templates.Context) interface{} {
 
 func tmplDBRank(ctx *templates.Context) interface{} {
 	return func(query interface{}, userID int64, key string) (interface{}, error) {
-		if ctx.IncreaseCheckCallCounterPremium("db_interactions", 10, 50) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
 			return "", templates.ErrTooManyCalls
 		}
 
-		if ctx.IncreaseCheckCallCounterPremium("db_multiple", 2, 10) {
+		if ctx.IncreaseCheckCallLimit("db_multiple") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -686,11 +1115,11 @@ WHERE user_id = $5 AND key = $6`
 
 func tmplDBCount(ctx *templates.Context) interface{} {
 	return func(variadicArg ...interface{}) (interface{}, error) {
-		if ctx.IncreaseCheckCallCounterPremium("db_interactions", 10, 50) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
 			return "", templates.ErrTooManyCalls
 		}
 
-		if ctx.IncreaseCheckCallCounterPremium("db_multiple", 2, 10) {
+		if ctx.IncreaseCheckCallLimit("db_multiple") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -775,11 +1204,11 @@ func tmplDBTopEntries(ctx *templates.Context, bottom bool) interface{} {
 	}
 
 	return func(pattern interface{}, iAmount interface{}, iSkip interface{}) (interface{}, error) {
-		if ctx.IncreaseCheckCallCounterPremium("db_interactions", 10, 50) {
+		if ctx.IncreaseCheckCallLimit("db_interactions") {
 			return "", templates.ErrTooManyCalls
 		}
 
-		if ctx.IncreaseCheckCallCounterPremium("db_multiple", 2, 10) {
+		if ctx.IncreaseCheckCallLimit("db_multiple") {
 			return "", templates.ErrTooManyCalls
 		}
 
@@ -805,26 +1234,22 @@ func tmplDBTopEntries(ctx *templates.Context, bottom bool) interface{} {
 
 func serializeValue(v interface{}) ([]byte, error) {
 	var b bytes.Buffer
-	enc := msgpack.NewEncoder(templates.LimitWriter(&b, 100000))
+	enc := msgpack.NewEncoder(templates.LimitWriter(&b, MaxDBValueSize))
 	err := enc.Encode(v)
 	return b.Bytes(), err
 }
 
 // returns true if were above db limit for the specified guild
 func CheckGuildDBLimit(gs *dstate.GuildSet) (bool, error) {
-	limitMuliplier := 1
-	if isPremium, _ := premium.IsGuildPremium(gs.ID); isPremium {
-		limitMuliplier = 10
-	}
-
-	limit := gs.MemberCount * 50 * int64(limitMuliplier)
+	isPremium, _ := premium.IsGuildPremium(gs.ID)
+	limit := GuildDBLimit(gs.MemberCount, isPremium)
 
 	curValues, err := cacheCheckDBLimit(gs)
 	if err != nil {
 		return false, err
 	}
 
-	return curValues >= int64(limit), nil
+	return curValues >= limit, nil
 }
 
 func getGuildCCDBNumValues(guildID int64) (int64, error) {
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"html/template"
 	"io"
-	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -48,6 +47,12 @@ var PageHTMLDatabase string
 //go:embed assets/customcommands-public.html
 var PageHTMLPublicCmd string
 
+//go:embed assets/customcommands-libraries.html
+var PageHTMLLibraries string
+
+//go:embed assets/customcommands-http.html
+var PageHTMLHTTP string
+
 // GroupForm is the form bindings used when creating or updating groups
 type GroupForm struct {
 	ID                int64
@@ -77,6 +82,16 @@ var (
 	panelLogKeyNewGroup     = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "customcommands_new_group", FormatString: "Created a new custom command group: %s"})
 	panelLogKeyUpdatedGroup = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "customcommands_updated_group", FormatString: "Updated custom command group: %s"})
 	panelLogKeyRemovedGroup = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "customcommands_removed_group", FormatString: "Removed custom command group: %d"})
+
+	panelLogKeyNewLibrary     = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "customcommands_new_library", FormatString: "Created a new custom command library: %s"})
+	panelLogKeyUpdatedLibrary = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "customcommands_updated_library", FormatString: "Updated custom command library: %s"})
+	panelLogKeyRemovedLibrary = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "customcommands_removed_library", FormatString: "Removed custom command library: %d"})
+
+	panelLogKeyUpdatedDBEntry = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "customcommands_updated_db_entry", FormatString: "Updated custom command database entry: %d"})
+	panelLogKeyRemovedDBEntry = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "customcommands_removed_db_entry", FormatString: "Removed custom command database entry: %d"})
+	panelLogKeyImportedDB     = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "customcommands_imported_db", FormatString: "Imported %d custom command database entries"})
+
+	panelLogKeyUpdatedHTTPSettings = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "customcommands_updated_http_settings", FormatString: "Updated custom command http request settings"})
 )
 
 // InitWeb implements web.Plugin
@@ -97,11 +112,27 @@ func (p *Plugin) InitWeb() {
 		Icon: "fas fa-database",
 	})
 
+	web.AddHTMLTemplate("customcommands/assets/customcommands-libraries.html", PageHTMLLibraries)
+	web.AddSidebarItem(web.SidebarCategoryCustomCommands, &web.SidebarItem{
+		Name: "Libraries",
+		URL:  "customcommands/libraries",
+		Icon: "fas fa-book",
+	})
+
+	web.AddHTMLTemplate("customcommands/assets/customcommands-http.html", PageHTMLHTTP)
+	web.AddSidebarItem(web.SidebarCategoryCustomCommands, &web.SidebarItem{
+		Name: "HTTP Requests",
+		URL:  "customcommands/http",
+		Icon: "fas fa-globe",
+	})
+
 	getHandler := web.ControllerHandler(handleCommands, "cp_custom_commands")
 	getCmdHandler := web.ControllerHandler(handleGetCommand, "cp_custom_commands_edit_cmd")
 	getPublicCmdHandler := web.ControllerHandler(handleGetPublicCommand, "cp_custom_commands_public")
 	getGroupHandler := web.ControllerHandler(handleGetCommandsGroup, "cp_custom_commands")
 	getDBHandler := web.ControllerHandler(handleGetDatabase, "cp_custom_commands_database")
+	getLibrariesHandler := web.ControllerHandler(handleGetLibraries, "cp_custom_commands_libraries")
+	getHTTPHandler := web.ControllerHandler(handleGetHTTPSettings, "cp_custom_commands_http")
 
 	subMux := goji.SubMux()
 	web.CPMux.Handle(pat.New("/customcommands"), subMux)
@@ -129,6 +160,19 @@ func (p *Plugin) InitWeb() {
 	subMux.Handle(pat.Get("/database"), getDBHandler)
 	subMux.Handle(pat.Get("/database/"), getDBHandler)
 	subMux.Handle(pat.Post("/database/delete/:id"), web.ControllerPostHandler(handleDeleteDatabaseEntry, getDBHandler, nil))
+	subMux.Handle(pat.Post("/database/update/:id"), web.ControllerPostHandler(handleUpdateDatabaseEntry, getDBHandler, DBEntryForm{}))
+	subMux.Handle(pat.Post("/database/import"), web.ControllerPostHandler(handleImportDatabase, getDBHandler, nil))
+	subMux.Handle(pat.Get("/database/export"), http.HandlerFunc(handleExportDatabase))
+
+	subMux.Handle(pat.Get("/libraries"), getLibrariesHandler)
+	subMux.Handle(pat.Get("/libraries/"), getLibrariesHandler)
+	subMux.Handle(pat.Post("/libraries/new"), web.ControllerPostHandler(handleNewLibrary, getLibrariesHandler, LibraryForm{}))
+	subMux.Handle(pat.Post("/libraries/:library/update"), web.ControllerPostHandler(handleUpdateLibrary, getLibrariesHandler, LibraryForm{}))
+	subMux.Handle(pat.Post("/libraries/:library/delete"), web.ControllerPostHandler(handleDeleteLibrary, getLibrariesHandler, nil))
+
+	subMux.Handle(pat.Get("/http"), getHTTPHandler)
+	subMux.Handle(pat.Get("/http/"), getHTTPHandler)
+	subMux.Handle(pat.Post("/http"), web.ControllerPostHandler(handleUpdateHTTPSettings, getHTTPHandler, HTTPSettingsForm{}))
 
 	subMux.Handle(pat.Get("/commands/:cmd/"), getCmdHandler)
 	subMux.Handle(pat.Get("/commands/:cmd"), getCmdHandler)
@@ -190,18 +234,14 @@ func handleGetDatabase(w http.ResponseWriter, r *http.Request) (web.TemplateData
 		page = 1
 	}
 
-	queryType := r.URL.Query().Get("type")
-	query := r.URL.Query().Get("query")
-	if len(query) > 0 {
-		templateData["Query"] = query
-		templateData["QueryType"] = queryType
-	}
+	filter := dbFilterFromQuery(r)
+	templateData["Filter"] = filter
 
-	result, total, err := getDatabaseEntries(ctx, activeGuild.ID, page, queryType, query, 100)
+	result, total, err := getDatabaseEntries(ctx, activeGuild.ID, page, filter, 100)
 	if err != nil {
 		return templateData, err
 	}
-	totalPages := int(math.Ceil((float64(total) / 100)))
+	totalPages := dbTotalPages(total, 100)
 	if page > totalPages {
 		page = totalPages
 	}
@@ -211,20 +251,32 @@ func handleGetDatabase(w http.ResponseWriter, r *http.Request) (web.TemplateData
 	templateData["TotalPages"] = totalPages
 	templateData["Page"] = page
 
-	premium := premium.ContextPremium(r.Context())
-	limitMuliplier := 1
-	if premium {
-		limitMuliplier = 10
+	if editStr := r.URL.Query().Get("edit"); editStr != "" {
+		err = setEditDBEntryData(ctx, templateData, activeGuild.ID, editStr)
+		if err != nil {
+			return templateData, err
+		}
+	}
+
+	used := total
+	if !filter.IsEmpty() {
+		used, err = getGuildCCDBNumValues(activeGuild.ID)
+		if err != nil {
+			return templateData, err
+		}
 	}
-	limit := activeGuild.MemberCount * 50 * int64(limitMuliplier)
-	usagePercent := int(float64(total) * 100 / float64(limit))
+
+	premium := premium.ContextPremium(r.Context())
+	limit := GuildDBLimit(activeGuild.MemberCount, premium)
+	usagePercent := int(float64(used) * 100 / float64(limit))
 
 	templateData["IsGuildPremium"] = premium
-	templateData["TotalDatabaseUsage"] = total
+	templateData["TotalDatabaseUsage"] = used
 	templateData["TotalDatabaseCapacity"] = limit
 	templateData["CapacityWarningCap"] = float64(limit) * 0.75
 	templateData["CapacityDangerCap"] = float64(limit) * 0.9
 	templateData["DatabaseUsagePercent"] = usagePercent
+	templateData["MaxDBImportEntries"] = MaxDBImportEntries
 
 	if usagePercent > 95 {
 		templateData.AddAlerts(web.WarningAlert("Database is almost full. Creating new entires will fail if you hit your limit."))
@@ -233,6 +285,139 @@ func handleGetDatabase(w http.ResponseWriter, r *http.Request) (web.TemplateData
 	return templateData, nil
 }
 
+// dbFilterFromQuery reads the search filters, the old type and query params are still supported for existing links
+func dbFilterFromQuery(r *http.Request) *DBEntryFilter {
+	q := r.URL.Query()
+	filter := &DBEntryFilter{
+		ID:     strings.TrimSpace(q.Get("id")),
+		UserID: strings.TrimSpace(q.Get("user_id")),
+		Key:    q.Get("key"),
+	}
+
+	if query := q.Get("query"); query != "" {
+		switch q.Get("type") {
+		case "id":
+			filter.ID = query
+		case "user_id":
+			filter.UserID = query
+		case "key":
+			filter.Key = query
+		}
+	}
+
+	// avoid a query error on non numeric input, nothing can match it anyways
+	if _, err := strconv.ParseInt(filter.ID, 10, 64); filter.ID != "" && err != nil {
+		filter.ID = "-1"
+	}
+	if _, err := strconv.ParseInt(filter.UserID, 10, 64); filter.UserID != "" && err != nil {
+		filter.UserID = "-1"
+	}
+
+	return filter
+}
+
+func setEditDBEntryData(ctx context.Context, templateData web.TemplateData, guildID int64, idStr string) error {
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		templateData.AddAlerts(web.ErrorAlert("Invalid entry ID"))
+		return nil
+	}
+
+	m, err := getDBEntry(ctx, guildID, id)
+	if err != nil {
+		return err
+	}
+
+	if m == nil {
+		templateData.AddAlerts(web.ErrorAlert("Entry not found, it may have expired or been deleted"))
+		return nil
+	}
+
+	editData := map[string]interface{}{
+		"ID":     m.ID,
+		"UserID": m.UserID,
+		"Key":    m.Key,
+	}
+
+	if m.ExpiresAt.Valid {
+		editData["ExpiresAt"] = m.ExpiresAt.Time.UTC().Format(dbExpiresAtFormat)
+	}
+
+	value, err := dbValueJSON(m)
+	if err != nil {
+		editData["ValueError"] = err.Error()
+	} else {
+		editData["Value"] = value
+	}
+
+	templateData["EditEntry"] = editData
+	return nil
+}
+
+// format used by datetime-local inputs
+const dbExpiresAtFormat = "2006-01-02T15:04"
+
+type DBEntryForm struct {
+	Value     string `valid:",1,100000"`
+	ExpiresAt string
+}
+
+func handleUpdateDatabaseEntry(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, templateData := web.GetBaseCPContextData(ctx)
+
+	form := ctx.Value(common.ContextKeyParsedForm).(*DBEntryForm)
+
+	id, err := strconv.ParseInt(pat.Param(r, "id"), 10, 64)
+	if err != nil {
+		return templateData, err
+	}
+
+	m, err := getDBEntry(ctx, activeGuild.ID, id)
+	if err != nil {
+		return templateData, err
+	}
+
+	if m == nil {
+		return templateData, web.NewPublicError("Entry not found, it may have expired or been deleted")
+	}
+
+	value, err := parseDBValueJSON(form.Value)
+	if err != nil {
+		return templateData, web.NewPublicError("Invalid JSON value: " + err.Error())
+	}
+
+	err = setDBEntryValue(m, value)
+	if err != nil {
+		return templateData, web.NewPublicError("Failed encoding value: " + err.Error())
+	}
+
+	m.ExpiresAt = null.Time{}
+	if form.ExpiresAt != "" {
+		expiresAt, err := time.Parse(dbExpiresAtFormat, form.ExpiresAt)
+		if err != nil {
+			return templateData, web.NewPublicError("Invalid expiry time")
+		}
+
+		if expiresAt.Before(time.Now()) {
+			return templateData, web.NewPublicError("Expiry time has to be in the future")
+		}
+
+		m.ExpiresAt = null.TimeFrom(expiresAt)
+	}
+
+	m.UpdatedAt = time.Now()
+	_, err = m.UpdateG(ctx, boil.Whitelist("value_raw", "value_num", "expires_at", "updated_at"))
+	if err != nil {
+		return templateData, err
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyUpdatedDBEntry, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: m.ID}))
+
+	EvictDBLimitCache(activeGuild.ID)
+	return templateData, nil
+}
+
 func handleDeleteDatabaseEntry(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
 	ctx := r.Context()
 	activeGuild, templateData := web.GetBaseCPContextData(ctx)
@@ -242,14 +427,63 @@ func handleDeleteDatabaseEntry(w http.ResponseWriter, r *http.Request) (web.Temp
 		return templateData, err
 	}
 
-	_, err = models.TemplatesUserDatabases(qm.Where("guild_id = ? AND id = ?", activeGuild.ID, id)).DeleteAll(ctx, common.PQ)
+	n, err := models.TemplatesUserDatabases(qm.Where("guild_id = ? AND id = ?", activeGuild.ID, id)).DeleteAll(ctx, common.PQ)
 	if err != nil {
 		return templateData, err
 	}
 
+	if n > 0 {
+		go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyRemovedDBEntry, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: id}))
+		EvictDBLimitCache(activeGuild.ID)
+	}
+
 	return templateData.AddAlerts(), nil
 }
 
+func handleExportDatabase(w http.ResponseWriter, r *http.Request) {
+	activeGuild, _ := web.GetBaseCPContextData(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="cc_database_%d.json"`, activeGuild.ID))
+
+	err := WriteDBExport(r.Context(), activeGuild.ID, w)
+	if err != nil {
+		logger.WithError(err).WithField("guild", activeGuild.ID).Error("Failed writing custom command database export")
+	}
+}
+
+// max size of a uploaded database import
+const maxDBImportSize = 10 << 20
+
+func handleImportDatabase(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, templateData := web.GetBaseCPContextData(ctx)
+
+	f, _, err := r.FormFile("File")
+	if err != nil {
+		return templateData, web.NewPublicError("No file uploaded")
+	}
+	defer f.Close()
+
+	entries, err := parseDBImport(io.LimitReader(f, maxDBImportSize))
+	if err != nil {
+		return templateData, web.NewPublicError(err.Error())
+	}
+
+	limit := GuildDBLimit(activeGuild.MemberCount, premium.ContextPremium(ctx))
+	err = importDBEntries(ctx, activeGuild.ID, entries, limit)
+	if err != nil {
+		if errors.Is(err, ErrDBImportAboveLimit) {
+			return templateData, web.NewPublicError(err.Error())
+		}
+		return templateData, err
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyImportedDB, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: int64(len(entries))}))
+
+	return templateData.AddAlerts(web.SucessAlert(fmt.Sprintf("Imported %d entries", len(entries)))), nil
+}
+
 func getLangBuiltInFuncs() string {
 	var langBuiltins strings.Builder
 	for k := range yagtemplate.StandardFuncMap {
@@ -306,6 +540,25 @@ func handleGetCommand(w http.ResponseWriter, r *http.Request) (web.TemplateData,
 	templateData["MaxCCLength"] = allowedCCLength
 	templateData["PublicLink"] = getPublicLink(cc)
 
+	lintWarnings := make(map[int][]*LintWarning)
+	for _, w := range LintCustomCommand(cc, premium.ContextPremium(r.Context())) {
+		lintWarnings[w.Response] = append(lintWarnings[w.Response], w)
+	}
+	templateData["LintWarnings"] = lintWarnings
+
+	// stats are only informational, don't fail the page over them
+	dayStats, err := GetCCStats(cc.GuildID, cc.LocalID, 24*time.Hour, premium.ContextPremium(r.Context()))
+	if err == nil {
+		var weekStats *CCStats
+		weekStats, err = GetCCStats(cc.GuildID, cc.LocalID, ccStatsRetention, premium.ContextPremium(r.Context()))
+		templateData["StatsDay"] = dayStats
+		templateData["StatsWeek"] = weekStats
+	}
+	if err != nil {
+		web.CtxLogger(r.Context()).WithError(err).WithField("guild", cc.GuildID).Error("failed retrieving custom command stats")
+		delete(templateData, "StatsDay")
+	}
+
 	return serveGroupSelected(r, templateData, cc.GroupID.Int64, cc.GuildID)
 }
 
@@ -607,6 +860,10 @@ func handleUpdateCommand(w http.ResponseWriter, r *http.Request) (web.TemplateDa
 
 	}
 
+	if IsGuildEventTrigger(CommandTriggerType(dbModel.TriggerType)) && guildEventUsesContextChannel(CommandTriggerType(dbModel.TriggerType)) {
+		dbModel.ContextChannel = cmdEdit.EventContextChannel
+	}
+
 	// check low interval limits
 	if dbModel.TriggerType == int(CommandTriggerInterval) || dbModel.TriggerType == int(CommandTriggerCron) {
 		dbModel.ContextChannel = cmdEdit.TimeContextChannel
@@ -700,6 +957,10 @@ func handleUpdateCommand(w http.ResponseWriter, r *http.Request) (web.TemplateDa
 		go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyDisabledSharingCommand, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: dbModel.LocalID}))
 	}
 
+	if warnings := LintCustomCommand(dbModel, premium.ContextPremium(ctx)); len(warnings) > 0 {
+		templateData.AddAlerts(web.WarningAlert(fmt.Sprintf("Saved, but found %d possible problem(s) in the response, see the warnings below it.", len(warnings))))
+	}
+
 	EvictCustomCommandCache(activeGuild.ID)
 	return templateData, err
 }
@@ -927,6 +1188,148 @@ func handleDeleteGroup(w http.ResponseWriter, r *http.Request) (web.TemplateData
 	return templateData, err
 }
 
+type LibraryForm struct {
+	Name   string `valid:",1,32"`
+	Source string `valid:",0,20000"`
+}
+
+func handleGetLibraries(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, templateData := web.GetBaseCPContextData(ctx)
+
+	libs, err := GetLibraries(ctx, activeGuild.ID)
+	if err != nil {
+		return templateData, err
+	}
+
+	cmds, err := models.CustomCommands(qm.Where("guild_id = ?", activeGuild.ID), qm.Select("local_id", "responses")).AllG(ctx)
+	if err != nil {
+		return templateData, err
+	}
+
+	templateData["Libraries"] = libs
+	templateData["LibraryDependents"] = libraryDependents(cmds)
+	templateData["MaxLibraries"] = MaxLibrariesForContext(ctx)
+	templateData["MaxLibraryLength"] = MaxLibraryLengthForContext(ctx)
+	templateData["IsGuildPremium"] = premium.ContextPremium(ctx)
+
+	return templateData, nil
+}
+
+func handleNewLibrary(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, templateData := web.GetBaseCPContextData(ctx)
+
+	form := ctx.Value(common.ContextKeyParsedForm).(*LibraryForm)
+
+	count, err := countLibraries(ctx, activeGuild.ID)
+	if err != nil {
+		return templateData, err
+	}
+
+	if count >= MaxLibrariesForContext(ctx) {
+		return templateData, web.NewPublicError(fmt.Sprintf("Max %d libraries allowed (or %d for premium servers)", MaxLibraries, MaxLibrariesPremium))
+	}
+
+	lib := &Library{
+		GuildID: activeGuild.ID,
+		Name:    strings.ToLower(strings.TrimSpace(form.Name)),
+		Source:  form.Source,
+	}
+
+	err = checkLibrary(ctx, lib)
+	if err != nil {
+		return templateData, err
+	}
+
+	err = insertLibrary(ctx, lib)
+	if err != nil {
+		if common.ErrPQIsUniqueViolation(err) {
+			return templateData, web.NewPublicError("A library with that name already exists")
+		}
+		return templateData, err
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyNewLibrary, &cplogs.Param{Type: cplogs.ParamTypeString, Value: lib.Name}))
+
+	EvictLibraryCache(activeGuild.ID)
+	return templateData, nil
+}
+
+func handleUpdateLibrary(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, templateData := web.GetBaseCPContextData(ctx)
+
+	form := ctx.Value(common.ContextKeyParsedForm).(*LibraryForm)
+
+	id, _ := strconv.ParseInt(pat.Param(r, "library"), 10, 64)
+	lib, err := GetLibrary(ctx, activeGuild.ID, id)
+	if err != nil {
+		return templateData, err
+	}
+
+	if lib == nil {
+		return templateData, web.NewPublicError("Unknown library")
+	}
+
+	lib.Name = strings.ToLower(strings.TrimSpace(form.Name))
+	lib.Source = form.Source
+
+	err = checkLibrary(ctx, lib)
+	if err != nil {
+		return templateData, err
+	}
+
+	err = updateLibrary(ctx, lib)
+	if err != nil {
+		if common.ErrPQIsUniqueViolation(err) {
+			return templateData, web.NewPublicError("A library with that name already exists")
+		}
+		return templateData, err
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyUpdatedLibrary, &cplogs.Param{Type: cplogs.ParamTypeString, Value: lib.Name}))
+
+	EvictLibraryCache(activeGuild.ID)
+	return templateData, nil
+}
+
+func handleDeleteLibrary(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, templateData := web.GetBaseCPContextData(ctx)
+
+	id, err := strconv.ParseInt(pat.Param(r, "library"), 10, 64)
+	if err != nil {
+		return templateData, err
+	}
+
+	deleted, err := deleteLibrary(ctx, activeGuild.ID, id)
+	if err != nil {
+		return templateData, err
+	}
+
+	if deleted {
+		go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyRemovedLibrary, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: id}))
+	}
+
+	EvictLibraryCache(activeGuild.ID)
+	return templateData, nil
+}
+
+// checkLibrary validates the library and checks it against the size limit of the guild
+func checkLibrary(ctx context.Context, lib *Library) error {
+	if utf8.RuneCountInString(lib.Source) > MaxLibraryLengthForContext(ctx) {
+		return web.NewPublicError(fmt.Sprintf("Library is too long, max %d characters (or %d for premium servers)", MaxLibraryLength, MaxLibraryLengthPremium))
+	}
+
+	err := validateLibrary(lib)
+	if err != nil {
+		return web.NewPublicError(err.Error())
+	}
+
+	return nil
+}
+
 func triggerTypeFromForm(str string) CommandTriggerType {
 	switch str {
 	case "none":
@@ -959,6 +1362,18 @@ func triggerTypeFromForm(str string) CommandTriggerType {
 		return CommandTriggerUserContextMenu
 	case "message_context_menu":
 		return CommandTriggerMessageContextMenu
+	case "member_join":
+		return CommandTriggerMemberJoin
+	case "member_leave":
+		return CommandTriggerMemberLeave
+	case "message_delete":
+		return CommandTriggerMessageDelete
+	case "message_edit":
+		return CommandTriggerMessageEdit
+	case "voice_join":
+		return CommandTriggerVoiceJoin
+	case "server_boost":
+		return CommandTriggerServerBoost
 	default:
 		return CommandTriggerCommand
 
@@ -1049,6 +1464,18 @@ func tmplGetCCSlashSubcommands(cc *models.CustomCommand) []SlashSubcommandView {
 	return views
 }
 
+var _ web.PluginWithPublicAPI = (*Plugin)(nil)
+
+func (p *Plugin) PublicAPIResources() []*web.APIResource {
+	return []*web.APIResource{
+		{
+			Path:     "/customcommands",
+			Scope:    "customcommands",
+			DataKeys: []string{"CustomCommands", "CommandGroups", "CurrentCommandGroup", "CC", "PublicLink", "DBEntries", "TotalPages", "Page", "TotalDatabaseUsage", "TotalDatabaseCapacity"},
+		},
+	}
+}
+
 var _ web.PluginWithServerHomeWidget = (*Plugin)(nil)
 
 func (p *Plugin) LoadServerHomeWidget(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
@@ -1129,6 +1556,7 @@ func EvictCustomCommandCache(guildID int64) {
 	pubsub.EvictCacheSet(cachedCommandsComponentTrigger, guildID)
 	pubsub.EvictCacheSet(cachedCommandsReactionTrigger, guildID)
 	pubsub.EvictCacheSet(cachedCommandsRoleTrigger, guildID)
+	pubsub.EvictCacheSet(cachedCommandsGuildEventTrigger, guildID)
 	pubsub.EvictCacheSet(cachedCommandsSlashTrigger, guildID)
 	pubsub.EvictCacheSet(cachedCommandsContextMenuTrigger, guildID)
 
@@ -1137,3 +1565,60 @@ func EvictCustomCommandCache(guildID int64) {
 	// it's safe to fire on every custom command mutation.
 	pubsub.PublishLogErr(SlashCommandResyncEvent, guildID, nil)
 }
+
+type HTTPSettingsForm struct {
+	Enabled        bool
+	AllowedDomains string `valid:",0,2000"`
+}
+
+func handleGetHTTPSettings(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, templateData := web.GetBaseCPContextData(ctx)
+
+	settings, err := GetHTTPSettings(ctx, activeGuild.ID)
+	if err != nil {
+		return templateData, err
+	}
+
+	templateData["HTTPSettings"] = settings
+	templateData["AllowedDomains"] = strings.Join(settings.AllowedDomains, "\n")
+	templateData["MaxHTTPAllowedDomains"] = MaxHTTPAllowedDomains
+	templateData["MaxHTTPRequestsPerRun"] = MaxHTTPRequestsPerRun
+	templateData["MaxHTTPRequestsPerRunPremium"] = MaxHTTPRequestsPerRunPremium
+	templateData["MaxHTTPRequestsPerHour"] = MaxHTTPRequestsPerHour
+	templateData["MaxHTTPRequestsPerHourPremium"] = MaxHTTPRequestsPerHourPremium
+	templateData["MaxHTTPResponseSize"] = MaxHTTPResponseSize
+	templateData["IsGuildPremium"] = premium.ContextPremium(ctx)
+
+	return templateData, nil
+}
+
+func handleUpdateHTTPSettings(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, templateData := web.GetBaseCPContextData(ctx)
+
+	form := ctx.Value(common.ContextKeyParsedForm).(*HTTPSettingsForm)
+
+	domains, err := parseAllowedDomains(form.AllowedDomains)
+	if err != nil {
+		return templateData, web.NewPublicError(err.Error())
+	}
+
+	if form.Enabled && len(domains) == 0 {
+		return templateData, web.NewPublicError("Add at least one allowed domain to enable http requests")
+	}
+
+	err = saveHTTPSettings(ctx, &HTTPSettings{
+		GuildID:        activeGuild.ID,
+		Enabled:        form.Enabled,
+		AllowedDomains: domains,
+	})
+	if err != nil {
+		return templateData, err
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyUpdatedHTTPSettings))
+
+	EvictHTTPSettingsCache(activeGuild.ID)
+	return templateData, nil
+}
@@ -81,6 +81,7 @@ var yagFuncs = {
   reFindAllSubmatches: true,
   reReplace: true,
   reSplit: true,
+  newImage: true,
   onlineCount: true,
   onlineCountBots: true,
   sleep: true,
@@ -201,6 +202,11 @@ var yagFuncs = {
   dbSet: true,
   dbSetExpire: true,
   dbIncr: true,
+  dbCompareAndSet: true,
+  dbSetField: true,
+  dbIncrField: true,
+  dbDelField: true,
+  dbTransfer: true,
   dbGet: true,
   dbGetPattern: true,
   dbGetPatternReverse: true,
@@ -212,5 +218,7 @@ var yagFuncs = {
   dbBottomEntries: true,
   dbCount: true,
   dbRank: true,
+  httpGet: true,
+  httpPost: true,
 
 };
@@ -12,9 +12,23 @@ import (
 var Packs = make(map[string]*CardPack)
 
 func AddPack(pack *CardPack) {
-	// Count picks
+	pack.CountPicks()
+	Packs[pack.Name] = pack
+}
+
+type CardPack struct {
+	Name           string
+	Description    string
+	FamilyFriendly bool
+	Prompts        []*PromptCard
+	Responses      []ResponseCard
+}
+
+// CountPicks sets the number of picks on each prompt, adding a blank at the end of prompts that don't have any
+func (pack *CardPack) CountPicks() {
 	for _, v := range pack.Prompts {
-		numPicks := strings.Count(v.Prompt, "%s")
+		// escaped percent signs are dropped first so "%%s" isn't counted as a blank
+		numPicks := strings.Count(strings.ReplaceAll(v.Prompt, "%%", ""), "%s")
 		if numPicks == 0 {
 			v.Prompt += " %s"
 			v.NumPick = 1
@@ -22,15 +36,11 @@ func AddPack(pack *CardPack) {
 			v.NumPick = numPicks
 		}
 	}
-
-	Packs[pack.Name] = pack
 }
 
-type CardPack struct {
-	Name        string
-	Description string
-	Prompts     []*PromptCard
-	Responses   []ResponseCard
+// PackProvider provides additional packs that are only available in specific guilds
+type PackProvider interface {
+	GuildPacks(guildID int64) ([]*CardPack, error)
 }
 
 type PromptCard struct {
@@ -40,11 +50,13 @@ type PromptCard struct {
 
 var (
 	EscaperReplacer = strings.NewReplacer("*", "\\*", "_", "\\_")
+
+	// "%%" comes first so the "%s" in a escaped "%%s" is left alone
+	placeHolderReplacer = strings.NewReplacer("%%", "%", "%s", "_____")
 )
 
 func (p *PromptCard) PlaceHolder() string {
-	s := strings.Replace(p.Prompt, "%s", "_____", -1)
-	s = strings.Replace(s, "%%", `%`, -1)
+	s := placeHolderReplacer.Replace(p.Prompt)
 
 	s = EscaperReplacer.Replace(s)
 
@@ -2,8 +2,9 @@ package cardsagainstdiscord
 
 func init() {
 	pack := &CardPack{
-		Name:        "family-friendly",
-		Description: "Family friendly version of CAH",
+		Name:           "family-friendly",
+		Description:    "Family friendly version of CAH",
+		FamilyFriendly: true,
 		Prompts: []*PromptCard{
 			{Prompt: `Papa, come quickly! There, in the garden! Do you see %s? Tell me you see it, Papa!`},
 			{Prompt: `This is gonna be the best sleepover ever. Once Mom goes to bed, it’s time for %s!`},
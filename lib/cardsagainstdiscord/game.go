@@ -98,6 +98,7 @@ type Game struct {
 	WinLimit           int
 	VoteMode           bool
 	Packs              []string
+	CustomPacks        []*CardPack
 	availablePrompts   []*PromptCard
 	availableResponses []ResponseCard
 
@@ -178,16 +179,49 @@ func (g *Game) Created() error {
 	return nil
 }
 
+// getPack returns the pack with the provided name, looking at the custom packs of this game first
+func (g *Game) getPack(name string) *CardPack {
+	for _, v := range g.CustomPacks {
+		if v.Name == name {
+			return v
+		}
+	}
+
+	return Packs[name]
+}
+
+// MixesFamilyFriendly returns true if the game uses both family friendly packs and packs that aren't
+func (g *Game) MixesFamilyFriendly() bool {
+	anyFamilyFriendly := false
+	allFamilyFriendly := true
+	for _, v := range g.Packs {
+		pack := g.getPack(v)
+		if pack == nil {
+			continue
+		}
+
+		if pack.FamilyFriendly {
+			anyFamilyFriendly = true
+		} else {
+			allFamilyFriendly = false
+		}
+	}
+
+	return anyFamilyFriendly && !allFamilyFriendly
+}
+
 func (g *Game) loadPackResponses() {
 	for _, v := range g.Packs {
-		pack := Packs[v]
-		g.availableResponses = append(g.availableResponses, pack.Responses...)
+		if pack := g.getPack(v); pack != nil {
+			g.availableResponses = append(g.availableResponses, pack.Responses...)
+		}
 	}
 }
 func (g *Game) loadPackPrompts() {
 	for _, v := range g.Packs {
-		pack := Packs[v]
-		g.availablePrompts = append(g.availablePrompts, pack.Prompts...)
+		if pack := g.getPack(v); pack != nil {
+			g.availablePrompts = append(g.availablePrompts, pack.Prompts...)
+		}
 	}
 }
 
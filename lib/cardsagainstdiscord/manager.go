@@ -1,6 +1,7 @@
 package cardsagainstdiscord
 
 import (
+	"slices"
 	"sync"
 
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
@@ -10,6 +11,7 @@ import (
 type GameManager struct {
 	sync.RWMutex
 	SessionProvider SessionProvider
+	PackProvider    PackProvider
 	ActiveGames     map[int64]*Game
 	NumActiveGames  int
 }
@@ -22,6 +24,23 @@ func NewGameManager(sessionProvider SessionProvider) *GameManager {
 }
 
 func (gm *GameManager) CreateGame(guildID int64, channelID int64, userID int64, username string, voteMode bool, packs ...string) (*Game, error) {
+	var guildPacks []*CardPack
+	if gm.PackProvider != nil {
+		var err error
+		guildPacks, err = gm.PackProvider.GuildPacks(guildID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	available := make(map[string]*CardPack, len(Packs)+len(guildPacks))
+	for _, v := range guildPacks {
+		available[v.Name] = v
+	}
+	for k, v := range Packs {
+		available[k] = v
+	}
+
 	allPacks := false
 	allResponseOnly := true
 	for _, v := range packs {
@@ -31,10 +50,10 @@ func (gm *GameManager) CreateGame(guildID int64, channelID int64, userID int64,
 			break
 		}
 
-		p, ok := Packs[v]
+		p, ok := available[v]
 		if !ok {
-			validPacks := make([]string, 0, len(Packs))
-			for k := range Packs {
+			validPacks := make([]string, 0, len(available))
+			for k := range available {
 				validPacks = append(validPacks, k)
 			}
 			return nil, &ErrUnknownPack{
@@ -55,12 +74,20 @@ func (gm *GameManager) CreateGame(guildID int64, channelID int64, userID int64,
 	}
 
 	if allPacks {
-		packs = make([]string, 0, len(Packs))
-		for k := range Packs {
+		packs = make([]string, 0, len(available))
+		for k := range available {
 			packs = append(packs, k)
 		}
 	}
 
+	// only keep the guild packs used in this game, they're carried with the game through shard migrations
+	var customPacks []*CardPack
+	for _, v := range guildPacks {
+		if _, builtin := Packs[v.Name]; !builtin && slices.Contains(packs, v.Name) {
+			customPacks = append(customPacks, v)
+		}
+	}
+
 	gm.Lock()
 	defer gm.Unlock()
 
@@ -77,6 +104,7 @@ func (gm *GameManager) CreateGame(guildID int64, channelID int64, userID int64,
 		Manager:       gm,
 		GuildID:       guildID,
 		Packs:         packs,
+		CustomPacks:   customPacks,
 		GameMaster:    userID,
 		VoteMode:      voteMode,
 		PlayerLimit:   10,
@@ -2,6 +2,7 @@ package dcmd
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
@@ -80,6 +81,10 @@ func (d *Data) WithContext(ctx context.Context) *Data {
 	cop := new(Data)
 	*cop = *d
 	cop.context = ctx
+	if cop.Session != nil {
+		// so requests made with it are traced as part of ctx
+		cop.Session = cop.Session.WithContext(ctx)
+	}
 	return cop
 }
 
@@ -222,3 +227,17 @@ const (
 	// triggered through slash commands or autocomplete
 	TriggerTypeSlashCommands
 )
+
+// Translate translates msg using the translator of the system, it's returned as is if there is none
+func (d *Data) Translate(msg string) string {
+	if d == nil || d.System == nil || d.System.Translator == nil {
+		return msg
+	}
+
+	return d.System.Translator.Translate(d, msg)
+}
+
+// Translatef translates the format string and then formats it like fmt.Sprintf
+func (d *Data) Translatef(format string, args ...interface{}) string {
+	return fmt.Sprintf(d.Translate(format), args...)
+}
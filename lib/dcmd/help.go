@@ -148,10 +148,10 @@ func GenerateHelp(d *Data, container *Container, formatter HelpFormatter) (embed
 		}
 
 		embed := &discordgo.MessageEmbed{
-			Title: cName + "Help",
+			Title: cName + d.Translate("Help"),
 			Color: set.Color(),
 			Footer: &discordgo.MessageEmbedFooter{
-				Text: "Do `" + invoked + "help cmd/container` for more detailed information on a command/group of commands",
+				Text: d.Translatef("Do `%shelp cmd/container` for more detailed information on a command/group of commands", invoked),
 			},
 		}
 
@@ -196,7 +196,7 @@ func (s *StdHelpFormatter) FullCmdHelp(cmd *RegisteredCommand, container *Contai
 		} else if short != "" {
 			desc = short
 		} else {
-			desc = "No description for this command"
+			desc = data.Translate("No description for this command")
 		}
 	}
 
@@ -1,6 +1,7 @@
 package dcmd
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"runtime/debug"
@@ -16,6 +17,23 @@ type System struct {
 	Prefix         PrefixProvider
 	ResponseSender ResponseSender
 	State          dstate.StateTracker
+
+	// Optional, resolves aliases that are not known when the commands are added, e.g per guild aliases
+	Aliases AliasResolver
+
+	// Optional, translates the help and other messages generated by dcmd
+	Translator Translator
+}
+
+// Translator translates a message to the language used where the command was triggered
+type Translator interface {
+	Translate(data *Data, msg string) string
+}
+
+// AliasResolver resolves a custom alias to the full name of the command it points to
+// it's only asked about the names that don't match any command
+type AliasResolver interface {
+	ResolveAlias(data *Data, alias string) (cmdName string, ok bool)
 }
 
 func NewStandardSystem(staticPrefix string) (system *System) {
@@ -62,35 +80,41 @@ func (sys *System) CheckMessage(s *discordgo.Session, m *discordgo.MessageCreate
 		return nil
 	}
 
+	sys.resolveAlias(data)
+
 	response, err := sys.Root.Run(data)
 	return sys.ResponseSender.SendResponse(data, response, err)
 }
 
-// CheckInteraction checks a interaction and runs a command if found
-func (sys *System) CheckInteraction(s *discordgo.Session, interaction *discordgo.Interaction) error {
+// CheckInteraction checks a interaction and runs a command if found, ctx is passed on to the command
+func (sys *System) CheckInteraction(ctx context.Context, s *discordgo.Session, interaction *discordgo.Interaction) error {
 
-	data, err := sys.FillDataInteraction(s, interaction)
+	data, err := sys.FillDataInteraction(s.WithContext(ctx), interaction)
 	if err != nil {
 		return err
 	}
+	data.context = ctx
 
 	response, err := sys.Root.Run(data)
 	return sys.ResponseSender.SendResponse(data, response, err)
 }
 
-// CheckMessageWtihPrefetchedPrefix is the same as CheckMessage but you pass in a prefetched command prefix
-func (sys *System) CheckMessageWtihPrefetchedPrefix(s *discordgo.Session, m *discordgo.MessageCreate, prefetchedPrefix string) error {
+// CheckMessageWtihPrefetchedPrefix is the same as CheckMessage but you pass in a prefetched command prefix and ctx is passed on to the command
+func (sys *System) CheckMessageWtihPrefetchedPrefix(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, prefetchedPrefix string) error {
 
-	data, err := sys.FillDataLegacyMessage(s, m.Message)
+	data, err := sys.FillDataLegacyMessage(s.WithContext(ctx), m.Message)
 	if err != nil {
 		return err
 	}
+	data.context = ctx
 
 	if !sys.FindPrefixWithPrefetched(data, prefetchedPrefix) {
 		// No prefix found in the message for a command to be triggered
 		return nil
 	}
 
+	sys.resolveAlias(data)
+
 	response, err := sys.Root.Run(data)
 	return sys.ResponseSender.SendResponse(data, response, err)
 }
@@ -153,6 +177,31 @@ func (sys *System) FindPrefixWithPrefetched(data *Data, commandPrefix string) (f
 	return
 }
 
+// resolveAlias replaces a custom alias at the start of the stripped message with the command it points to
+func (sys *System) resolveAlias(data *Data) {
+	if sys.Aliases == nil {
+		return
+	}
+
+	stripped := data.TraditionalTriggerData.MessageStrippedPrefix
+	name, rest, _ := strings.Cut(stripped, " ")
+	if name == "" {
+		return
+	}
+
+	if cmd, _ := sys.Root.FindCommand(stripped); cmd != nil {
+		// the built in names always take priority
+		return
+	}
+
+	cmdName, ok := sys.Aliases.ResolveAlias(data, name)
+	if !ok {
+		return
+	}
+
+	data.TraditionalTriggerData.MessageStrippedPrefix = strings.TrimSpace(cmdName + " " + rest)
+}
+
 func (sys *System) FindMentionPrefix(data *Data) (found bool) {
 	if data.Session.State.User == nil {
 		return false
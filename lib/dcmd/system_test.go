@@ -101,3 +101,34 @@ func TestFindPrefix(t *testing.T) {
 		})
 	}
 }
+
+type testAliasResolver map[string]string
+
+func (t testAliasResolver) ResolveAlias(data *Data, alias string) (string, bool) {
+	v, ok := t[alias]
+	return v, ok
+}
+
+func TestResolveAlias(t *testing.T) {
+	sys := NewStandardSystem("!")
+	sys.Root.AddCommand(&TestCommand{}, NewTrigger("test"))
+	sys.Aliases = testAliasResolver{"t": "test", "test": "other", "sub": "container sub"}
+
+	cases := []struct {
+		stripped string
+		expected string
+	}{
+		{"t", "test"},
+		{"t some args", "test some args"},
+		{"test args", "test args"}, // built in names are not overridden
+		{"sub args", "container sub args"},
+		{"unknown args", "unknown args"},
+		{"", ""},
+	}
+
+	for _, v := range cases {
+		data := &Data{TraditionalTriggerData: &TraditionalTriggerData{MessageStrippedPrefix: v.stripped}}
+		sys.resolveAlias(data)
+		assert.Equal(t, v.expected, data.TraditionalTriggerData.MessageStrippedPrefix, "Should resolve %q correctly", v.stripped)
+	}
+}
@@ -121,6 +121,12 @@ var (
 	EndpointGuildStickers       = func(gID int64) string { return "" }
 	EndpointGuildSticker        = func(gID, sID int64) string { return "" }
 	EndpointGuildTagBadge       = func(gID int64, bID string) string { return "" }
+	EndpointGuildAutoModRules   = func(gID int64) string { return "" }
+	EndpointGuildAutoModRule    = func(gID, rID int64) string { return "" }
+
+	EndpointGuildScheduledEvents     = func(gID int64) string { return "" }
+	EndpointGuildScheduledEvent      = func(gID, eID int64) string { return "" }
+	EndpointGuildScheduledEventUsers = func(gID, eID int64) string { return "" }
 
 	EndpointChannel                             = func(cID int64) string { return "" }
 	EndpointChannelThreads                      = func(cID int64) string { return "" }
@@ -343,6 +349,13 @@ func CreateEndpoints(base string) {
 	EndpointGuildStickers = func(gID int64) string { return EndpointGuilds + StrID(gID) + "/stickers" }
 	EndpointGuildSticker = func(gID, sID int64) string { return EndpointGuilds + StrID(gID) + "/stickers/" + StrID(sID) }
 	EndpointGuildTagBadge = func(gID int64, bID string) string { return EndpointCDNGuildTagBadge + StrID(gID) + "/" + bID + ".png" }
+	EndpointGuildAutoModRules = func(gID int64) string { return EndpointGuilds + StrID(gID) + "/auto-moderation/rules" }
+	EndpointGuildAutoModRule = func(gID, rID int64) string {
+		return EndpointGuilds + StrID(gID) + "/auto-moderation/rules/" + StrID(rID)
+	}
+	EndpointGuildScheduledEvents = func(gID int64) string { return EndpointGuilds + StrID(gID) + "/scheduled-events" }
+	EndpointGuildScheduledEvent = func(gID, eID int64) string { return EndpointGuildScheduledEvents(gID) + "/" + StrID(eID) }
+	EndpointGuildScheduledEventUsers = func(gID, eID int64) string { return EndpointGuildScheduledEvent(gID, eID) + "/users" }
 
 	EndpointChannel = func(cID int64) string { return EndpointChannels + StrID(cID) }
 	EndpointChannelThreads = func(cID int64) string { return EndpointChannel(cID) + "/threads" }
@@ -209,6 +209,15 @@ func (s *Session) HandleEvent(t string, i interface{}) {
 	s.handleEvent(t, i)
 }
 
+// eventSession returns the session holding the event handlers, sessions returned by WithContext use the ones of their parent
+func (s *Session) eventSession() *Session {
+	if s.parent != nil {
+		return s.parent
+	}
+
+	return s
+}
+
 // Handles an event type by calling internal methods, firing handlers and firing the
 // interface{} event.
 func (s *Session) handleEvent(t string, i interface{}) {
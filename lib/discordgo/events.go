@@ -527,13 +527,31 @@ func (e *GuildStickersUpdate) GetGuildID() int64 {
 }
 
 // GuildScheduledEventCreate is the data for a GuildScheduledEventCreate event.
-type GuildScheduledEventCreate struct{}
+type GuildScheduledEventCreate struct {
+	*GuildScheduledEvent
+}
+
+func (e *GuildScheduledEventCreate) GetGuildID() int64 {
+	return e.GuildID
+}
 
 // GuildScheduledEventUpdate is the data for a GuildScheduledEventUpdate event.
-type GuildScheduledEventUpdate struct{}
+type GuildScheduledEventUpdate struct {
+	*GuildScheduledEvent
+}
+
+func (e *GuildScheduledEventUpdate) GetGuildID() int64 {
+	return e.GuildID
+}
 
 // GuildScheduledEventDelete is the data for a GuildScheduledEventDelete event.
-type GuildScheduledEventDelete struct{}
+type GuildScheduledEventDelete struct {
+	*GuildScheduledEvent
+}
+
+func (e *GuildScheduledEventDelete) GetGuildID() int64 {
+	return e.GuildID
+}
 
 // GuildScheduledEventUserAdd is the data for a GuildScheduledEventUserAdd event.
 type GuildScheduledEventUserAdd struct {
@@ -542,6 +560,10 @@ type GuildScheduledEventUserAdd struct {
 	GuildID               int64 `json:"guild_id,string"`
 }
 
+func (e *GuildScheduledEventUserAdd) GetGuildID() int64 {
+	return e.GuildID
+}
+
 // GuildScheduledEventUserRemove is the data for a GuildScheduledEventUserRemove event.
 type GuildScheduledEventUserRemove struct {
 	GuildScheduledEventID int64 `json:"guild_scheduled_event_id,string"`
@@ -549,6 +571,10 @@ type GuildScheduledEventUserRemove struct {
 	GuildID               int64 `json:"guild_id,string"`
 }
 
+func (e *GuildScheduledEventUserRemove) GetGuildID() int64 {
+	return e.GuildID
+}
+
 // stage instance was created
 type StageInstanceCreate struct {
 }
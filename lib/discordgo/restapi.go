@@ -31,8 +31,14 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/botlabs-gg/yagpdb/v2/lib/discordgo")
+
 // All error constants
 var (
 	ErrJSONUnmarshal           = errors.New("json unmarshal")
@@ -82,8 +88,51 @@ func (rwmc *ReaderWithMockClose) Close() error {
 	return nil
 }
 
+// WithContext returns a session making REST requests as part of ctx, their spans are started as children of the span in ctx.
+// It shares the http client, ratelimiter and event handlers with s, s itself is returned if ctx isn't part of a trace.
+func (s *Session) WithContext(ctx context.Context) *Session {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return s
+	}
+
+	return &Session{
+		Token:          s.Token,
+		MFA:            s.MFA,
+		Intents:        s.Intents,
+		Debug:          s.Debug,
+		LogLevel:       s.LogLevel,
+		ShardID:        s.ShardID,
+		ShardCount:     s.ShardCount,
+		StateEnabled:   s.StateEnabled,
+		SyncEvents:     s.SyncEvents,
+		MaxRestRetries: s.MaxRestRetries,
+		State:          s.State,
+		Client:         s.Client,
+		Ratelimiter:    s.Ratelimiter,
+		GatewayManager: s.GatewayManager,
+		tokenInvalid:   s.tokenInvalid,
+		ctx:            ctx,
+		parent:         s.eventSession(),
+	}
+}
+
 // RequestWithLockedBucket makes a request using a bucket that's already been locked
 func (s *Session) RequestWithBucket(method, urlStr, contentType string, b []byte, headers map[string]string, bucket *Bucket) (response []byte, err error) {
+	// only traced as part of a existing trace (see WithContext), otherwise span is a no-op
+	span := trace.SpanFromContext(s.ctx)
+	if span.SpanContext().IsValid() {
+		_, span = tracer.Start(s.ctx, "discord "+method+" "+bucket.Key,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("http.request.method", method), attribute.String("discord.bucket", bucket.Key)))
+	}
+
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
 
 	for i := 0; i < s.MaxRestRetries; i++ {
 		var retry bool
@@ -93,6 +142,12 @@ func (s *Session) RequestWithBucket(method, urlStr, contentType string, b []byte
 			break
 		}
 
+		if ratelimited {
+			span.AddEvent("ratelimited")
+		} else {
+			span.AddEvent("retry")
+		}
+
 		if err != nil {
 			s.log(LogError, "Request error, retrying: %v", err)
 		}
@@ -180,7 +235,7 @@ func (s *Session) doRequest(method, urlStr, contentType string, b []byte, header
 		rl.Bucket = bucket.Key
 
 		s.log(LogInformational, "Rate Limiting %s, retry in %s", urlStr, rl.RetryAfterDur())
-		s.handleEvent(rateLimitEventType, &RateLimit{TooManyRequests: &rl, URL: urlStr})
+		s.eventSession().handleEvent(rateLimitEventType, &RateLimit{TooManyRequests: &rl, URL: urlStr})
 
 		time.Sleep(rl.RetryAfterDur())
 		// we can make the above smarter
@@ -1528,6 +1583,223 @@ func (s *Session) GuildEmojiDelete(guildID, emojiID int64) (err error) {
 	return
 }
 
+// AutoModerationRules returns the native auto moderation rules of a guild.
+// guildID : The ID of a Guild.
+func (s *Session) AutoModerationRules(guildID int64) (st []*AutoModerationRule, err error) {
+
+	body, err := s.RequestWithBucketID("GET", EndpointGuildAutoModRules(guildID), nil, nil, EndpointGuildAutoModRules(guildID))
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+// AutoModerationRule returns a single native auto moderation rule.
+// guildID : The ID of a Guild.
+// ruleID  : The ID of a auto moderation rule.
+func (s *Session) AutoModerationRule(guildID, ruleID int64) (st *AutoModerationRule, err error) {
+
+	body, err := s.RequestWithBucketID("GET", EndpointGuildAutoModRule(guildID, ruleID), nil, nil, EndpointGuildAutoModRules(guildID))
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+// AutoModerationRuleCreate creates a native auto moderation rule.
+// guildID : The ID of a Guild.
+// rule    : The rule to create, ID, GuildID and CreatorID are ignored.
+// reason  : The audit log reason, can be empty.
+func (s *Session) AutoModerationRuleCreate(guildID int64, rule *AutoModerationRule, reason string) (st *AutoModerationRule, err error) {
+
+	headers := make(map[string]string)
+	if reason != "" {
+		headers["X-Audit-Log-Reason"] = url.PathEscape(reason)
+	}
+
+	body, err := s.RequestWithBucketID("POST", EndpointGuildAutoModRules(guildID), rule, headers, EndpointGuildAutoModRules(guildID))
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+// AutoModerationRuleEdit modifies a native auto moderation rule, only the set fields are changed.
+// The trigger type of a rule can't be changed.
+// guildID : The ID of a Guild.
+// ruleID  : The ID of a auto moderation rule.
+// rule    : The fields to change.
+// reason  : The audit log reason, can be empty.
+func (s *Session) AutoModerationRuleEdit(guildID, ruleID int64, rule *AutoModerationRuleEditParams, reason string) (st *AutoModerationRule, err error) {
+
+	headers := make(map[string]string)
+	if reason != "" {
+		headers["X-Audit-Log-Reason"] = url.PathEscape(reason)
+	}
+
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildAutoModRule(guildID, ruleID), rule, headers, EndpointGuildAutoModRules(guildID))
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+// AutoModerationRuleDelete deletes a native auto moderation rule.
+// guildID : The ID of a Guild.
+// ruleID  : The ID of a auto moderation rule.
+// reason  : The audit log reason, can be empty.
+func (s *Session) AutoModerationRuleDelete(guildID, ruleID int64, reason string) (err error) {
+
+	headers := make(map[string]string)
+	if reason != "" {
+		headers["X-Audit-Log-Reason"] = url.PathEscape(reason)
+	}
+
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildAutoModRule(guildID, ruleID), nil, headers, EndpointGuildAutoModRules(guildID))
+	return
+}
+
+// GuildScheduledEvents returns the scheduled events of a guild.
+// guildID       : The ID of a Guild.
+// withUserCount : Whether to include the number of subscribed users in the events.
+func (s *Session) GuildScheduledEvents(guildID int64, withUserCount bool) (st []*GuildScheduledEvent, err error) {
+
+	uri := EndpointGuildScheduledEvents(guildID)
+	if withUserCount {
+		uri += "?with_user_count=true"
+	}
+
+	body, err := s.RequestWithBucketID("GET", uri, nil, nil, EndpointGuildScheduledEvents(guildID))
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+// GuildScheduledEvent returns a single scheduled event.
+// guildID       : The ID of a Guild.
+// eventID       : The ID of a scheduled event.
+// withUserCount : Whether to include the number of subscribed users in the event.
+func (s *Session) GuildScheduledEvent(guildID, eventID int64, withUserCount bool) (st *GuildScheduledEvent, err error) {
+
+	uri := EndpointGuildScheduledEvent(guildID, eventID)
+	if withUserCount {
+		uri += "?with_user_count=true"
+	}
+
+	body, err := s.RequestWithBucketID("GET", uri, nil, nil, EndpointGuildScheduledEvents(guildID))
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+// GuildScheduledEventCreate creates a scheduled event.
+// guildID : The ID of a Guild.
+// params  : The event to create.
+// reason  : The audit log reason, can be empty.
+func (s *Session) GuildScheduledEventCreate(guildID int64, params *GuildScheduledEventParams, reason string) (st *GuildScheduledEvent, err error) {
+
+	headers := make(map[string]string)
+	if reason != "" {
+		headers["X-Audit-Log-Reason"] = url.PathEscape(reason)
+	}
+
+	body, err := s.RequestWithBucketID("POST", EndpointGuildScheduledEvents(guildID), params, headers, EndpointGuildScheduledEvents(guildID))
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+// GuildScheduledEventEdit modifies a scheduled event, only the set fields are changed.
+// guildID : The ID of a Guild.
+// eventID : The ID of a scheduled event.
+// params  : The fields to change.
+// reason  : The audit log reason, can be empty.
+func (s *Session) GuildScheduledEventEdit(guildID, eventID int64, params *GuildScheduledEventParams, reason string) (st *GuildScheduledEvent, err error) {
+
+	headers := make(map[string]string)
+	if reason != "" {
+		headers["X-Audit-Log-Reason"] = url.PathEscape(reason)
+	}
+
+	body, err := s.RequestWithBucketID("PATCH", EndpointGuildScheduledEvent(guildID, eventID), params, headers, EndpointGuildScheduledEvents(guildID))
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
+// GuildScheduledEventDelete deletes a scheduled event.
+// guildID : The ID of a Guild.
+// eventID : The ID of a scheduled event.
+// reason  : The audit log reason, can be empty.
+func (s *Session) GuildScheduledEventDelete(guildID, eventID int64, reason string) (err error) {
+
+	headers := make(map[string]string)
+	if reason != "" {
+		headers["X-Audit-Log-Reason"] = url.PathEscape(reason)
+	}
+
+	_, err = s.RequestWithBucketID("DELETE", EndpointGuildScheduledEvent(guildID, eventID), nil, headers, EndpointGuildScheduledEvents(guildID))
+	return
+}
+
+// GuildScheduledEventUsers returns the users subscribed to a scheduled event.
+// guildID    : The ID of a Guild.
+// eventID    : The ID of a scheduled event.
+// limit      : The max number of users to return (max 100).
+// withMember : Whether to include the member data of the users.
+// before     : If provided only users before this ID are returned.
+// after      : If provided only users after this ID are returned.
+func (s *Session) GuildScheduledEventUsers(guildID, eventID int64, limit int, withMember bool, before, after int64) (st []*GuildScheduledEventUser, err error) {
+
+	uri := EndpointGuildScheduledEventUsers(guildID, eventID)
+
+	v := url.Values{}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+	if withMember {
+		v.Set("with_member", "true")
+	}
+	if before != 0 {
+		v.Set("before", StrID(before))
+	}
+	if after != 0 {
+		v.Set("after", StrID(after))
+	}
+
+	if len(v) > 0 {
+		uri = fmt.Sprintf("%s?%s", uri, v.Encode())
+	}
+
+	body, err := s.RequestWithBucketID("GET", uri, nil, nil, EndpointGuildScheduledEventUsers(guildID, 0))
+	if err != nil {
+		return
+	}
+
+	err = unmarshal(body, &st)
+	return
+}
+
 // ------------------------------------------------------------------------------------------------
 // Functions specific to Discord Channels
 // ------------------------------------------------------------------------------------------------
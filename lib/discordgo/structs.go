@@ -12,6 +12,7 @@
 package discordgo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -82,6 +83,10 @@ type Session struct {
 	handlersMu   sync.RWMutex
 	handlers     map[string][]*eventHandlerInstance
 	onceHandlers map[string][]*eventHandlerInstance
+
+	// Set on sessions returned by WithContext
+	ctx    context.Context
+	parent *Session
 }
 
 // UserConnection is a Connection returned from the UserConnections endpoint
@@ -1553,6 +1558,8 @@ const (
 	ErrCodeUnknownEmoji       = 10014
 	ErrCodeUnknownWebhook     = 10015
 
+	ErrCodeUnknownGuildScheduledEvent = 10070
+
 	ErrCodeBotsCannotUseEndpoint  = 20001
 	ErrCodeOnlyBotsCanUseEndpoint = 20002
 
@@ -1599,12 +1606,14 @@ type InviteUser struct {
 }
 
 type CreateApplicationCommandRequest struct {
-	Name              string                      `json:"name"`                         // 1-32 character name matching ^[\w-]{1,32}$
-	Type              ApplicationCommandType      `json:"type,omitempty"`               // defaults to CHAT_INPUT (1); 2=USER, 3=MESSAGE context menu
-	Description       string                      `json:"description,omitempty"`        // 1-100 character description (must be empty for context menu commands)
-	Options           []*ApplicationCommandOption `json:"options,omitempty"`            // the parameters for the command
-	DefaultPermission *bool                       `json:"default_permission,omitempty"` // (default true)	whether the command is enabled by default when the app is added to a guild
-	NSFW              bool                        `json:"nsfw,omitempty"`               // marks a command as age-restricted
+	Name                     string                      `json:"name"`                                // 1-32 character name matching ^[\w-]{1,32}$
+	Type                     ApplicationCommandType      `json:"type,omitempty"`                      // defaults to CHAT_INPUT (1); 2=USER, 3=MESSAGE context menu
+	Description              string                      `json:"description,omitempty"`               // 1-100 character description (must be empty for context menu commands)
+	NameLocalizations        map[Locale]string           `json:"name_localizations,omitempty"`        // localized names, keyed by locale
+	DescriptionLocalizations map[Locale]string           `json:"description_localizations,omitempty"` // localized descriptions, keyed by locale
+	Options                  []*ApplicationCommandOption `json:"options,omitempty"`                   // the parameters for the command
+	DefaultPermission        *bool                       `json:"default_permission,omitempty"`        // (default true)	whether the command is enabled by default when the app is added to a guild
+	NSFW                     bool                        `json:"nsfw,omitempty"`                      // marks a command as age-restricted
 }
 
 func (a *ApplicationCommandInteractionDataResolved) UnmarshalJSON(b []byte) error {
@@ -1797,6 +1806,7 @@ const (
 	AutoModerationEventTriggerHarmfulLink   AutoModerationRuleTriggerType = 2
 	AutoModerationEventTriggerSpam          AutoModerationRuleTriggerType = 3
 	AutoModerationEventTriggerKeywordPreset AutoModerationRuleTriggerType = 4
+	AutoModerationEventTriggerMentionSpam   AutoModerationRuleTriggerType = 5
 )
 
 // AutoModerationKeywordPreset represents an internally pre-defined wordset.
@@ -1829,6 +1839,34 @@ type AutoModerationTriggerMetadata struct {
 	// Total number of unique role and user mentions allowed per message.
 	// NOTE: should be only used with mention spam trigger type.
 	MentionTotalLimit int `json:"mention_total_limit,omitempty"`
+
+	// Whether to automatically detect mention raids.
+	// NOTE: should be only used with mention spam trigger type.
+	MentionRaidProtectionEnabled bool `json:"mention_raid_protection_enabled,omitempty"`
+}
+
+// AutoModerationRuleEditParams are the fields of a auto moderation rule that can be changed.
+// Unlike AutoModerationRule the exempt lists are always sent, so they can be cleared.
+type AutoModerationRuleEditParams struct {
+	Name            string                                   `json:"name,omitempty"`
+	EventType       AutoModerationRuleEventType              `json:"event_type,omitempty"`
+	TriggerMetadata *AutoModerationTriggerMetadataEditParams `json:"trigger_metadata,omitempty"`
+	Actions         []AutoModerationAction                   `json:"actions,omitempty"`
+	Enabled         *bool                                    `json:"enabled,omitempty"`
+	ExemptRoles     IDSlice                                  `json:"exempt_roles"`
+	ExemptChannels  IDSlice                                  `json:"exempt_channels"`
+}
+
+// AutoModerationTriggerMetadataEditParams is the trigger metadata of a rule edit.
+// The lists are sent whenever they're set, even if empty, so they can be cleared
+// while leaving out the ones that don't belong to the trigger type of the rule.
+type AutoModerationTriggerMetadataEditParams struct {
+	KeywordFilter                *[]string                     `json:"keyword_filter,omitempty"`
+	RegexPatterns                *[]string                     `json:"regex_patterns,omitempty"`
+	Presets                      []AutoModerationKeywordPreset `json:"presets,omitempty"`
+	AllowList                    *[]string                     `json:"allow_list,omitempty"`
+	MentionTotalLimit            int                           `json:"mention_total_limit,omitempty"`
+	MentionRaidProtectionEnabled *bool                         `json:"mention_raid_protection_enabled,omitempty"`
 }
 
 // AutoModerationActionType represents an action which will execute whenever a rule is triggered.
@@ -1850,6 +1888,10 @@ type AutoModerationActionMetadata struct {
 	// Timeout duration in seconds (maximum of 2419200 - 4 weeks).
 	// NOTE: should be only used with timeout action type.
 	Duration int `json:"duration_seconds,omitempty"`
+
+	// Additional explanation that will be shown to members whenever their message is blocked (maximum of 150 characters).
+	// NOTE: should be only used with block message action type.
+	CustomMessage string `json:"custom_message,omitempty"`
 }
 
 // AutoModerationAction stores data for an auto moderation action.
@@ -1858,6 +1900,91 @@ type AutoModerationAction struct {
 	Metadata *AutoModerationActionMetadata `json:"metadata,omitempty"`
 }
 
+// GuildScheduledEvent represents a scheduled event in a guild, shown in the guild's events tab.
+type GuildScheduledEvent struct {
+	ID        int64 `json:"id,string"`
+	GuildID   int64 `json:"guild_id,string"`
+	ChannelID int64 `json:"channel_id,string"`
+	// The user that created the event, not set for events created before october 25th 2021.
+	CreatorID   int64  `json:"creator_id,string"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	ScheduledStartTime time.Time  `json:"scheduled_start_time"`
+	ScheduledEndTime   *time.Time `json:"scheduled_end_time"`
+
+	PrivacyLevel   GuildScheduledEventPrivacyLevel    `json:"privacy_level"`
+	Status         GuildScheduledEventStatus          `json:"status"`
+	EntityType     GuildScheduledEventEntityType      `json:"entity_type"`
+	EntityID       int64                              `json:"entity_id,string"`
+	EntityMetadata *GuildScheduledEventEntityMetadata `json:"entity_metadata"`
+
+	Creator *User `json:"creator"`
+	// Only set when requested with the user count
+	UserCount int    `json:"user_count"`
+	Image     string `json:"image"`
+}
+
+// GuildScheduledEventPrivacyLevel is the privacy level of a scheduled event.
+type GuildScheduledEventPrivacyLevel int
+
+const (
+	// GuildScheduledEventPrivacyLevelGuildOnly makes the event only accessible to guild members
+	GuildScheduledEventPrivacyLevelGuildOnly GuildScheduledEventPrivacyLevel = 2
+)
+
+// GuildScheduledEventStatus is the status of a scheduled event.
+// Scheduled events can go from scheduled to active or canceled, and from active to completed.
+type GuildScheduledEventStatus int
+
+const (
+	GuildScheduledEventStatusScheduled GuildScheduledEventStatus = 1
+	GuildScheduledEventStatusActive    GuildScheduledEventStatus = 2
+	GuildScheduledEventStatusCompleted GuildScheduledEventStatus = 3
+	GuildScheduledEventStatusCanceled  GuildScheduledEventStatus = 4
+)
+
+// GuildScheduledEventEntityType is the type of location a scheduled event takes place in.
+type GuildScheduledEventEntityType int
+
+const (
+	GuildScheduledEventEntityTypeStageInstance GuildScheduledEventEntityType = 1
+	GuildScheduledEventEntityTypeVoice         GuildScheduledEventEntityType = 2
+	// External events have no channel, but require a location and a scheduled end time
+	GuildScheduledEventEntityTypeExternal GuildScheduledEventEntityType = 3
+)
+
+// GuildScheduledEventEntityMetadata holds additional data for external scheduled events.
+type GuildScheduledEventEntityMetadata struct {
+	// Location of the event (1-100 characters)
+	Location string `json:"location,omitempty"`
+}
+
+// GuildScheduledEventParams are the parameters used when creating or editing a scheduled event,
+// only the set fields are changed when editing.
+type GuildScheduledEventParams struct {
+	// The channel of the event, must not be set for external events.
+	ChannelID          int64                              `json:"channel_id,string,omitempty"`
+	EntityMetadata     *GuildScheduledEventEntityMetadata `json:"entity_metadata,omitempty"`
+	Name               string                             `json:"name,omitempty"`
+	PrivacyLevel       GuildScheduledEventPrivacyLevel    `json:"privacy_level,omitempty"`
+	ScheduledStartTime *time.Time                         `json:"scheduled_start_time,omitempty"`
+	ScheduledEndTime   *time.Time                         `json:"scheduled_end_time,omitempty"`
+	Description        string                             `json:"description,omitempty"`
+	EntityType         GuildScheduledEventEntityType      `json:"entity_type,omitempty"`
+	Status             GuildScheduledEventStatus          `json:"status,omitempty"`
+	// base64 data uri
+	Image string `json:"image,omitempty"`
+}
+
+// GuildScheduledEventUser is a user subscribed to a scheduled event.
+type GuildScheduledEventUser struct {
+	GuildScheduledEventID int64 `json:"guild_scheduled_event_id,string"`
+	User                  *User `json:"user"`
+	// Only set when requested with member data
+	Member *Member `json:"member"`
+}
+
 type SKUType int
 
 // Valid SKUType values
@@ -0,0 +1,670 @@
+// dstatetest is a conformance test suite for dstate.StateTracker implementations,
+// it feeds gateway events to a tracker and checks that what you read back matches the reference in memory tracker.
+package dstatetest
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+)
+
+// Tracker is a state tracker that is fed gateway events
+type Tracker interface {
+	dstate.StateTracker
+	HandleEvent(s *discordgo.Session, evt interface{})
+}
+
+// NewTrackerFunc should return a new tracker with no state for totalShards shards, with botMemberID as the bot's member
+type NewTrackerFunc func(t *testing.T, totalShards int64, botMemberID int64) Tracker
+
+const (
+	testGuildID   = 1 << 22 // lands on shard 1 with 2 shards
+	testChannelID = 10
+	testRoleID    = 100
+	testMemberID  = 1000
+	testBotID     = 1001
+	testThreadID  = 10000
+)
+
+var testSession = &discordgo.Session{ShardID: 0, ShardCount: 1}
+
+// RunConformanceTests runs the suite against the tracker created by newTracker, each test gets its own tracker
+func RunConformanceTests(t *testing.T, newTracker NewTrackerFunc) {
+	tests := []struct {
+		name string
+		f    func(t *testing.T, tracker Tracker)
+	}{
+		{"GuildCreate", testGuildCreate},
+		{"GuildUpdate", testGuildUpdate},
+		{"GuildDelete", testGuildDelete},
+		{"Members", testMembers},
+		{"Presences", testPresences},
+		{"Channels", testChannels},
+		{"Roles", testRoles},
+		{"Messages", testMessages},
+		{"GuildMessages", testGuildMessages},
+		{"VoiceStates", testVoiceStates},
+		{"EmojisStickers", testEmojisStickers},
+		{"Threads", testThreads},
+		{"ThreadParentPerms", testThreadParentPerms},
+		{"IterateMembers", testIterateMembers},
+	}
+
+	for _, v := range tests {
+		test := v
+		t.Run(test.name, func(t *testing.T) {
+			tracker := newTracker(t, 1, testBotID)
+			createTestGuild(tracker, 1)
+			test.f(t, tracker)
+		})
+	}
+
+	t.Run("ShardGuilds", func(t *testing.T) {
+		testShardGuilds(t, newTracker(t, 2, testBotID))
+	})
+}
+
+func createTestUser(id int64) *discordgo.User {
+	return &discordgo.User{
+		ID:            id,
+		Username:      "test member-" + strconv.FormatInt(id, 10),
+		Discriminator: "0000",
+	}
+}
+
+func createTestMember(guildID int64, id int64, roles []int64) *discordgo.Member {
+	return &discordgo.Member{
+		GuildID: guildID,
+		Roles:   roles,
+		User:    createTestUser(id),
+	}
+}
+
+func createTestChannel(channelID int64, overwrites []*discordgo.PermissionOverwrite) *discordgo.Channel {
+	return &discordgo.Channel{
+		ID:                   channelID,
+		GuildID:              testGuildID,
+		Name:                 "test channel-" + strconv.FormatInt(channelID, 10),
+		Type:                 discordgo.ChannelTypeGuildText,
+		PermissionOverwrites: overwrites,
+	}
+}
+
+func createTestMessage(id int64, channelID int64, content string) *discordgo.Message {
+	return &discordgo.Message{
+		ID:        id,
+		ChannelID: channelID,
+		GuildID:   testGuildID,
+		Content:   content,
+		Author:    createTestUser(testMemberID),
+	}
+}
+
+func createTestGuild(tracker Tracker, totalShards int) {
+	tracker.HandleEvent(sessionFor(testGuildID, totalShards), &discordgo.GuildCreate{
+		Guild: &discordgo.Guild{
+			ID:          testGuildID,
+			Name:        "test guild",
+			OwnerID:     testMemberID,
+			MemberCount: 1,
+			Members: []*discordgo.Member{
+				createTestMember(0, testMemberID, []int64{testRoleID}),
+			},
+			Presences: []*discordgo.Presence{
+				{User: createTestUser(testMemberID), Status: discordgo.StatusOnline},
+			},
+			Channels: []*discordgo.Channel{
+				createTestChannel(testChannelID, nil),
+			},
+			Roles: []*discordgo.Role{
+				{ID: testRoleID, Name: "test role"},
+			},
+			Threads: []*discordgo.Channel{
+				{
+					ID:             testThreadID,
+					Name:           "test thread",
+					Type:           discordgo.ChannelTypeGuildPublicThread,
+					ParentID:       testChannelID,
+					ThreadMetadata: &discordgo.ThreadMetadata{},
+				},
+			},
+		},
+	})
+}
+
+func sessionFor(guildID int64, totalShards int) *discordgo.Session {
+	return &discordgo.Session{ShardID: int((guildID >> 22) % int64(totalShards)), ShardCount: totalShards}
+}
+
+func mustGetGuild(t *testing.T, tracker Tracker) *dstate.GuildSet {
+	t.Helper()
+
+	gs := tracker.GetGuild(testGuildID)
+	if gs == nil {
+		t.Fatal("guild not found")
+	}
+
+	return gs
+}
+
+func testGuildCreate(t *testing.T, tracker Tracker) {
+	gs := mustGetGuild(t, tracker)
+	if gs.Name != "test guild" || gs.OwnerID != testMemberID || gs.MemberCount != 1 {
+		t.Errorf("unexpected guild state: %#v", gs.GuildState)
+	}
+
+	if r := gs.GetRole(testRoleID); r == nil || r.Name != "test role" {
+		t.Error("role not found")
+	}
+
+	if c := gs.GetChannel(testChannelID); c == nil || c.GuildID != testGuildID {
+		t.Error("channel not found or missing guild id")
+	}
+
+	if gs.GetThread(testThreadID) == nil {
+		t.Error("thread not found")
+	}
+
+	ms := tracker.GetMember(testGuildID, testMemberID)
+	if ms == nil {
+		t.Fatal("member not found")
+	}
+
+	if ms.Member == nil || len(ms.Member.Roles) != 1 || ms.Member.Roles[0] != testRoleID {
+		t.Errorf("unexpected member fields: %#v", ms.Member)
+	}
+
+	if ms.Presence == nil || ms.Presence.Status != dstate.StatusOnline {
+		t.Errorf("unexpected presence: %#v", ms.Presence)
+	}
+
+	if ms.User.Username != "test member-1000" {
+		t.Errorf("unexpected user: %#v", ms.User)
+	}
+
+	if tracker.GetGuild(testGuildID+1) != nil {
+		t.Error("got a guild that does not exist")
+	}
+
+	if tracker.GetMember(testGuildID, testMemberID+100) != nil {
+		t.Error("got a member that does not exist")
+	}
+}
+
+func testGuildUpdate(t *testing.T, tracker Tracker) {
+	tracker.HandleEvent(testSession, &discordgo.GuildUpdate{
+		Guild: &discordgo.Guild{
+			ID:      testGuildID,
+			Name:    "new name",
+			OwnerID: testMemberID,
+		},
+	})
+
+	gs := mustGetGuild(t, tracker)
+	if gs.Name != "new name" {
+		t.Errorf("name not updated: %q", gs.Name)
+	}
+
+	if gs.MemberCount != 1 {
+		t.Errorf("member count should be carried over, got %d", gs.MemberCount)
+	}
+
+	if gs.GetChannel(testChannelID) == nil || gs.GetRole(testRoleID) == nil {
+		t.Error("channels and roles should be kept on guild update")
+	}
+}
+
+func testGuildDelete(t *testing.T, tracker Tracker) {
+	tracker.HandleEvent(testSession, &discordgo.GuildDelete{
+		Guild: &discordgo.Guild{ID: testGuildID, Unavailable: true},
+	})
+
+	gs := mustGetGuild(t, tracker)
+	if gs.Available {
+		t.Error("guild should be marked as unavailable")
+	}
+
+	tracker.HandleEvent(testSession, &discordgo.GuildDelete{
+		Guild: &discordgo.Guild{ID: testGuildID},
+	})
+
+	if tracker.GetGuild(testGuildID) != nil {
+		t.Error("guild should have been removed")
+	}
+
+	if tracker.GetMember(testGuildID, testMemberID) != nil {
+		t.Error("members should have been removed with the guild")
+	}
+}
+
+func testMembers(t *testing.T, tracker Tracker) {
+	tracker.HandleEvent(testSession, &discordgo.GuildMemberAdd{
+		Member: createTestMember(testGuildID, 2000, nil),
+	})
+
+	ms := tracker.GetMember(testGuildID, 2000)
+	if ms == nil || ms.Member == nil {
+		t.Fatal("added member not found")
+	}
+
+	if gs := mustGetGuild(t, tracker); gs.MemberCount != 2 {
+		t.Errorf("member count not increased: %d", gs.MemberCount)
+	}
+
+	updated := createTestMember(testGuildID, 2000, []int64{testRoleID})
+	updated.Nick = "nick"
+	tracker.HandleEvent(testSession, &discordgo.GuildMemberUpdate{Member: updated})
+
+	ms = tracker.GetMember(testGuildID, 2000)
+	if ms == nil || ms.Member == nil || ms.Member.Nick != "nick" || len(ms.Member.Roles) != 1 {
+		t.Fatalf("member not updated: %#v", ms)
+	}
+
+	// presence should be kept on member updates
+	tracker.HandleEvent(testSession, &discordgo.GuildMemberUpdate{Member: createTestMember(testGuildID, testMemberID, nil)})
+	ms = tracker.GetMember(testGuildID, testMemberID)
+	if ms == nil || ms.Presence == nil {
+		t.Fatal("presence was lost on member update")
+	}
+
+	tracker.HandleEvent(testSession, &discordgo.GuildMemberRemove{
+		Member: createTestMember(testGuildID, 2000, nil),
+	})
+
+	if tracker.GetMember(testGuildID, 2000) != nil {
+		t.Error("member should have been removed")
+	}
+
+	if gs := mustGetGuild(t, tracker); gs.MemberCount != 1 {
+		t.Errorf("member count not decreased: %d", gs.MemberCount)
+	}
+}
+
+func testPresences(t *testing.T, tracker Tracker) {
+	// partial user on a member we have, the user should be carried over
+	tracker.HandleEvent(testSession, &discordgo.PresenceUpdate{
+		GuildID: testGuildID,
+		Presence: discordgo.Presence{
+			User:   &discordgo.User{ID: testMemberID},
+			Status: discordgo.StatusIdle,
+		},
+	})
+
+	ms := tracker.GetMember(testGuildID, testMemberID)
+	if ms == nil || ms.Presence == nil || ms.Presence.Status != dstate.StatusIdle {
+		t.Fatalf("presence not updated: %#v", ms)
+	}
+
+	if ms.Member == nil || ms.User.Username == "" {
+		t.Error("member and user should be carried over on presence updates")
+	}
+
+	// partial user on a member we don't have, not enough to add it
+	tracker.HandleEvent(testSession, &discordgo.PresenceUpdate{
+		GuildID: testGuildID,
+		Presence: discordgo.Presence{
+			User:   &discordgo.User{ID: 3000},
+			Status: discordgo.StatusOnline,
+		},
+	})
+
+	if tracker.GetMember(testGuildID, 3000) != nil {
+		t.Error("member with a partial user should not be added")
+	}
+
+	// full user on a member we don't have
+	tracker.HandleEvent(testSession, &discordgo.PresenceUpdate{
+		GuildID: testGuildID,
+		Presence: discordgo.Presence{
+			User:   createTestUser(3001),
+			Status: discordgo.StatusOnline,
+		},
+	})
+
+	ms = tracker.GetMember(testGuildID, 3001)
+	if ms == nil || ms.Presence == nil || ms.Member != nil {
+		t.Errorf("expected a presence only member: %#v", ms)
+	}
+}
+
+func testChannels(t *testing.T, tracker Tracker) {
+	tracker.HandleEvent(testSession, &discordgo.ChannelCreate{Channel: createTestChannel(20, nil)})
+
+	if mustGetGuild(t, tracker).GetChannel(20) == nil {
+		t.Fatal("created channel not found")
+	}
+
+	updt := createTestChannel(20, nil)
+	updt.Name = "this is a new name!"
+	tracker.HandleEvent(testSession, &discordgo.ChannelUpdate{Channel: updt})
+
+	if c := mustGetGuild(t, tracker).GetChannel(20); c == nil || c.Name != updt.Name {
+		t.Fatalf("channel not updated: %#v", c)
+	}
+
+	tracker.HandleEvent(testSession, &discordgo.ChannelDelete{Channel: createTestChannel(20, nil)})
+
+	gs := mustGetGuild(t, tracker)
+	if gs.GetChannel(20) != nil {
+		t.Error("channel should have been removed")
+	}
+
+	if gs.GetChannel(testChannelID) == nil {
+		t.Error("the other channel should be kept")
+	}
+}
+
+func testRoles(t *testing.T, tracker Tracker) {
+	tracker.HandleEvent(testSession, &discordgo.GuildRoleCreate{GuildRole: &discordgo.GuildRole{
+		GuildID: testGuildID,
+		Role:    &discordgo.Role{ID: 200, Name: "created"},
+	}})
+
+	if mustGetGuild(t, tracker).GetRole(200) == nil {
+		t.Fatal("created role not found")
+	}
+
+	tracker.HandleEvent(testSession, &discordgo.GuildRoleUpdate{GuildRole: &discordgo.GuildRole{
+		GuildID: testGuildID,
+		Role:    &discordgo.Role{ID: 200, Name: "updated"},
+	}})
+
+	if r := mustGetGuild(t, tracker).GetRole(200); r == nil || r.Name != "updated" {
+		t.Fatalf("role not updated: %#v", r)
+	}
+
+	tracker.HandleEvent(testSession, &discordgo.GuildRoleDelete{GuildID: testGuildID, RoleID: 200})
+
+	gs := mustGetGuild(t, tracker)
+	if gs.GetRole(200) != nil {
+		t.Error("role should have been removed")
+	}
+
+	if gs.GetRole(testRoleID) == nil {
+		t.Error("the other role should be kept")
+	}
+}
+
+func messageIDs(messages []*dstate.MessageState) []int64 {
+	result := make([]int64, len(messages))
+	for i, v := range messages {
+		result[i] = v.ID
+	}
+
+	return result
+}
+
+func assertMessageIDs(t *testing.T, messages []*dstate.MessageState, expected ...int64) {
+	t.Helper()
+
+	got := messageIDs(messages)
+	if len(got) != len(expected) {
+		t.Fatalf("got messages %v, expected %v", got, expected)
+	}
+
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("got messages %v, expected %v", got, expected)
+		}
+	}
+}
+
+func testMessages(t *testing.T, tracker Tracker) {
+	for i := int64(1); i <= 5; i++ {
+		tracker.HandleEvent(testSession, &discordgo.MessageCreate{Message: createTestMessage(i, testChannelID, "message "+strconv.FormatInt(i, 10))})
+	}
+
+	// newest first
+	assertMessageIDs(t, tracker.GetMessages(testGuildID, testChannelID, &dstate.MessagesQuery{}), 5, 4, 3, 2, 1)
+	assertMessageIDs(t, tracker.GetMessages(testGuildID, testChannelID, &dstate.MessagesQuery{Limit: 2}), 5, 4)
+	assertMessageIDs(t, tracker.GetMessages(testGuildID, testChannelID, &dstate.MessagesQuery{Before: 4}), 3, 2, 1)
+	assertMessageIDs(t, tracker.GetMessages(testGuildID, testChannelID, &dstate.MessagesQuery{After: 3}), 5, 4)
+	assertMessageIDs(t, tracker.GetMessages(testGuildID, testChannelID, &dstate.MessagesQuery{Before: 5, After: 1, Limit: 2}), 4, 3)
+
+	updt := createTestMessage(3, testChannelID, "edited")
+	tracker.HandleEvent(testSession, &discordgo.MessageUpdate{Message: updt})
+
+	messages := tracker.GetMessages(testGuildID, testChannelID, &dstate.MessagesQuery{Before: 4, Limit: 1})
+	assertMessageIDs(t, messages, 3)
+	if messages[0].Content != "edited" {
+		t.Errorf("message not updated: %q", messages[0].Content)
+	}
+
+	if messages[0].Author.ID != testMemberID {
+		t.Errorf("unexpected author: %#v", messages[0].Author)
+	}
+
+	tracker.HandleEvent(testSession, &discordgo.MessageDelete{Message: &discordgo.Message{ID: 5, ChannelID: testChannelID, GuildID: testGuildID}})
+	tracker.HandleEvent(testSession, &discordgo.MessageDeleteBulk{ChannelID: testChannelID, GuildID: testGuildID, Messages: []int64{1, 2}})
+
+	assertMessageIDs(t, tracker.GetMessages(testGuildID, testChannelID, &dstate.MessagesQuery{}), 4, 3)
+
+	messages = tracker.GetMessages(testGuildID, testChannelID, &dstate.MessagesQuery{IncludeDeleted: true})
+	assertMessageIDs(t, messages, 5, 4, 3, 2, 1)
+	if !messages[0].Deleted || messages[1].Deleted {
+		t.Error("deleted flag not set properly")
+	}
+
+	if got := tracker.GetMessages(testGuildID, testChannelID+1, &dstate.MessagesQuery{}); len(got) != 0 {
+		t.Errorf("got messages for a channel without any: %v", messageIDs(got))
+	}
+
+	// dm's are not tracked
+	tracker.HandleEvent(testSession, &discordgo.MessageCreate{Message: &discordgo.Message{ID: 6, ChannelID: 99, Author: createTestUser(testMemberID)}})
+	if got := tracker.GetMessages(0, 99, &dstate.MessagesQuery{}); len(got) != 0 {
+		t.Errorf("dm messages should not be tracked, got: %v", messageIDs(got))
+	}
+}
+
+func testGuildMessages(t *testing.T, tracker Tracker) {
+	tracker.HandleEvent(testSession, &discordgo.ChannelCreate{Channel: createTestChannel(20, nil)})
+
+	tracker.HandleEvent(testSession, &discordgo.MessageCreate{Message: createTestMessage(1, testChannelID, "a")})
+	tracker.HandleEvent(testSession, &discordgo.MessageCreate{Message: createTestMessage(2, 20, "b")})
+	tracker.HandleEvent(testSession, &discordgo.MessageCreate{Message: createTestMessage(3, testChannelID, "c")})
+
+	assertMessageIDs(t, tracker.GetMessages(testGuildID, 0, &dstate.MessagesQuery{}), 3, 2, 1)
+	assertMessageIDs(t, tracker.GetMessages(testGuildID, 20, &dstate.MessagesQuery{}), 2)
+
+	// updates and deletes are reflected in the guild wide view
+	tracker.HandleEvent(testSession, &discordgo.MessageUpdate{Message: createTestMessage(2, 20, "edited")})
+	tracker.HandleEvent(testSession, &discordgo.MessageDelete{Message: &discordgo.Message{ID: 3, ChannelID: testChannelID, GuildID: testGuildID}})
+
+	messages := tracker.GetMessages(testGuildID, 0, &dstate.MessagesQuery{})
+	assertMessageIDs(t, messages, 2, 1)
+	if messages[0].Content != "edited" {
+		t.Errorf("message not updated in the guild wide view: %q", messages[0].Content)
+	}
+}
+
+func testVoiceStates(t *testing.T, tracker Tracker) {
+	tracker.HandleEvent(testSession, &discordgo.VoiceStateUpdate{VoiceState: &discordgo.VoiceState{
+		GuildID: testGuildID, UserID: testMemberID, ChannelID: 30,
+	}})
+
+	vs := mustGetGuild(t, tracker).GetVoiceState(testMemberID)
+	if vs == nil || vs.ChannelID != 30 {
+		t.Fatalf("voice state not added: %#v", vs)
+	}
+
+	tracker.HandleEvent(testSession, &discordgo.VoiceStateUpdate{VoiceState: &discordgo.VoiceState{
+		GuildID: testGuildID, UserID: testMemberID, ChannelID: 31, SelfMute: true,
+	}})
+
+	vs = mustGetGuild(t, tracker).GetVoiceState(testMemberID)
+	if vs == nil || vs.ChannelID != 31 || !vs.SelfMute {
+		t.Fatalf("voice state not updated: %#v", vs)
+	}
+
+	tracker.HandleEvent(testSession, &discordgo.VoiceStateUpdate{VoiceState: &discordgo.VoiceState{
+		GuildID: testGuildID, UserID: testMemberID,
+	}})
+
+	if vs = mustGetGuild(t, tracker).GetVoiceState(testMemberID); vs != nil {
+		t.Errorf("voice state should have been removed: %#v", vs)
+	}
+}
+
+func testEmojisStickers(t *testing.T, tracker Tracker) {
+	tracker.HandleEvent(testSession, &discordgo.GuildEmojisUpdate{
+		GuildID: testGuildID,
+		Emojis:  []*discordgo.Emoji{{ID: 50, Name: "emoji"}},
+	})
+
+	tracker.HandleEvent(testSession, &discordgo.GuildStickersUpdate{
+		GuildID:  testGuildID,
+		Stickers: []*discordgo.Sticker{{ID: 60, Name: "sticker"}},
+	})
+
+	gs := mustGetGuild(t, tracker)
+	if e := gs.GetEmoji(50); e == nil || e.Name != "emoji" {
+		t.Errorf("emoji not found: %#v", e)
+	}
+
+	if s := gs.GetSticker(60); s == nil || s.Name != "sticker" {
+		t.Errorf("sticker not found: %#v", s)
+	}
+}
+
+func testThreads(t *testing.T, tracker Tracker) {
+	thread := discordgo.Channel{
+		GuildID:        testGuildID,
+		ParentID:       testChannelID,
+		ID:             testThreadID + 1,
+		Type:           discordgo.ChannelTypeGuildPublicThread,
+		ThreadMetadata: &discordgo.ThreadMetadata{},
+		Name:           "test",
+	}
+
+	tracker.HandleEvent(testSession, &discordgo.ThreadCreate{Channel: thread})
+	if c := mustGetGuild(t, tracker).GetThread(thread.ID); c == nil || c.Name != "test" {
+		t.Fatalf("thread not found: %#v", c)
+	}
+
+	thread.Name = "renamed"
+	tracker.HandleEvent(testSession, &discordgo.ThreadUpdate{Channel: thread})
+	if c := mustGetGuild(t, tracker).GetChannelOrThread(thread.ID); c == nil || c.Name != "renamed" {
+		t.Fatalf("thread not updated: %#v", c)
+	}
+
+	// archived threads are not tracked
+	archived := thread
+	archived.ThreadMetadata = &discordgo.ThreadMetadata{Archived: true}
+	tracker.HandleEvent(testSession, &discordgo.ThreadUpdate{Channel: archived})
+	if mustGetGuild(t, tracker).GetThread(thread.ID) != nil {
+		t.Fatal("archived thread should have been removed")
+	}
+
+	tracker.HandleEvent(testSession, &discordgo.ThreadDelete{ID: testThreadID, GuildID: testGuildID, ParentID: testChannelID})
+	if mustGetGuild(t, tracker).GetThread(testThreadID) != nil {
+		t.Fatal("deleted thread should have been removed")
+	}
+}
+
+func testThreadParentPerms(t *testing.T, tracker Tracker) {
+	tracker.HandleEvent(testSession, &discordgo.ChannelUpdate{
+		Channel: createTestChannel(testChannelID, []*discordgo.PermissionOverwrite{
+			{Type: discordgo.PermissionOverwriteTypeMember, ID: testBotID, Allow: discordgo.PermissionViewChannel},
+		}),
+	})
+
+	tracker.HandleEvent(testSession, &discordgo.GuildMemberAdd{
+		Member: &discordgo.Member{GuildID: testGuildID, User: &discordgo.User{ID: testBotID}},
+	})
+
+	if mustGetGuild(t, tracker).GetThread(testThreadID) == nil {
+		t.Fatal("thread not found")
+	}
+
+	// the bot lost access to the parent channel
+	tracker.HandleEvent(testSession, &discordgo.ChannelUpdate{
+		Channel: createTestChannel(testChannelID, []*discordgo.PermissionOverwrite{
+			{Type: discordgo.PermissionOverwriteTypeMember, ID: testBotID, Deny: discordgo.PermissionViewChannel},
+		}),
+	})
+
+	if mustGetGuild(t, tracker).GetThread(testThreadID) != nil {
+		t.Fatal("thread should have been removed")
+	}
+}
+
+func testIterateMembers(t *testing.T, tracker Tracker) {
+	const n = 250
+	for i := int64(0); i < n; i++ {
+		tracker.HandleEvent(testSession, &discordgo.GuildMemberAdd{Member: createTestMember(testGuildID, 5000+i, nil)})
+	}
+
+	var seen []int64
+	tracker.IterateMembers(testGuildID, func(chunk []*dstate.MemberState) bool {
+		for _, v := range chunk {
+			seen = append(seen, v.User.ID)
+		}
+		return true
+	})
+
+	sort.Slice(seen, func(i, j int) bool { return seen[i] < seen[j] })
+	if len(seen) != n+1 {
+		t.Fatalf("expected %d members, got %d", n+1, len(seen))
+	}
+
+	for i := 1; i < len(seen); i++ {
+		if seen[i] == seen[i-1] {
+			t.Fatalf("member %d was seen twice", seen[i])
+		}
+	}
+
+	calls := 0
+	tracker.IterateMembers(testGuildID, func(chunk []*dstate.MemberState) bool {
+		calls++
+		return false
+	})
+
+	if calls != 1 {
+		t.Errorf("iteration should stop when f returns false, got %d calls", calls)
+	}
+
+	tracker.IterateMembers(testGuildID+1, func(chunk []*dstate.MemberState) bool {
+		t.Error("f called for a guild that does not exist")
+		return true
+	})
+}
+
+func testShardGuilds(t *testing.T, tracker Tracker) {
+	// testGuildID is on shard 1, otherGuild on shard 0
+	const otherGuild = 2 << 22
+
+	createTestGuild(tracker, 2)
+	tracker.HandleEvent(sessionFor(otherGuild, 2), &discordgo.GuildCreate{
+		Guild: &discordgo.Guild{ID: otherGuild, Name: "other guild"},
+	})
+
+	shard0 := tracker.GetShardGuilds(0)
+	if len(shard0) != 1 || shard0[0].ID != otherGuild {
+		t.Fatalf("unexpected guilds on shard 0: %v", shard0)
+	}
+
+	shard1 := tracker.GetShardGuilds(1)
+	if len(shard1) != 1 || shard1[0].ID != testGuildID {
+		t.Fatalf("unexpected guilds on shard 1: %v", shard1)
+	}
+
+	// a ready resets the shard to the guilds in it
+	tracker.HandleEvent(sessionFor(testGuildID, 2), &discordgo.Ready{
+		Guilds: []*discordgo.Guild{{ID: 3 << 22, Unavailable: true}},
+	})
+
+	if tracker.GetGuild(testGuildID) != nil || tracker.GetMember(testGuildID, testMemberID) != nil {
+		t.Error("the shard should have been reset on ready")
+	}
+
+	if tracker.GetGuild(3<<22) == nil {
+		t.Error("guild from the ready not found")
+	}
+
+	if tracker.GetGuild(otherGuild) == nil {
+		t.Error("guilds on other shards should not be touched by a ready")
+	}
+}
@@ -0,0 +1,13 @@
+package inmemorytracker
+
+import (
+	"testing"
+
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate/dstatetest"
+)
+
+func TestConformance(t *testing.T) {
+	dstatetest.RunConformanceTests(t, func(t *testing.T, totalShards int64, botMemberID int64) dstatetest.Tracker {
+		return NewInMemoryTracker(TrackerConfig{BotMemberID: botMemberID}, totalShards)
+	})
+}
@@ -0,0 +1,193 @@
+package inmemorytracker
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+)
+
+// SnapshotVersion is bumped whenever the snapshot format changes in a incompatible way
+const SnapshotVersion = 1
+
+var (
+	ErrSnapshotVersion     = errors.New("snapshot version mismatch")
+	ErrSnapshotTotalShards = errors.New("snapshot is from a different total shard count")
+)
+
+// ShardSnapshot is the serialized state of a single shard, messages are not included
+//
+// The guilds and members are the same objects sent during shard migrations, so either can be built from the other.
+type ShardSnapshot struct {
+	Version     int       `json:"version"`
+	ShardID     int64     `json:"shard_id"`
+	TotalShards int64     `json:"total_shards"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Gateway session the state belongs to, if the session can't be resumed the state should be thrown away
+	SessionID        string `json:"session_id"`
+	Sequence         int64  `json:"sequence"`
+	ResumeGatewayURL string `json:"resume_gateway_url"`
+
+	Guilds  []*dstate.GuildSet    `json:"guilds"`
+	Members []*dstate.MemberState `json:"members"`
+}
+
+// SnapshotShard returns a copy of the current state of the shard
+func (tracker *InMemoryTracker) SnapshotShard(shardID int64) *ShardSnapshot {
+	shard := tracker.getShard(shardID)
+	if shard == nil {
+		panic("unknown shard")
+	}
+
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	snap := &ShardSnapshot{
+		Version:     SnapshotVersion,
+		ShardID:     shardID,
+		TotalShards: tracker.totalShards,
+		CreatedAt:   time.Now(),
+		Guilds:      make([]*dstate.GuildSet, 0, len(shard.guilds)),
+	}
+
+	for _, v := range shard.guilds {
+		snap.Guilds = append(snap.Guilds, &dstate.GuildSet{
+			GuildState:  *v.Guild,
+			Channels:    v.Channels,
+			Roles:       v.Roles,
+			Emojis:      v.Emojis,
+			Stickers:    v.Stickers,
+			VoiceStates: v.VoiceStates,
+			Threads:     v.Threads,
+		})
+	}
+
+	for _, members := range shard.members {
+		for _, v := range members {
+			ms := v.MemberState
+			snap.Members = append(snap.Members, &ms)
+		}
+	}
+
+	return snap
+}
+
+// RestoreShard replaces the state of the shard with the one in the snapshot
+func (tracker *InMemoryTracker) RestoreShard(snap *ShardSnapshot) error {
+	if snap.Version != SnapshotVersion {
+		return ErrSnapshotVersion
+	}
+
+	if snap.TotalShards != tracker.totalShards {
+		return ErrSnapshotTotalShards
+	}
+
+	shard := tracker.getShard(snap.ShardID)
+	if shard == nil {
+		return fmt.Errorf("unknown shard %d", snap.ShardID)
+	}
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.reset()
+
+	for _, v := range snap.Guilds {
+		shard.guilds[v.ID] = SparseGuildStateFromDstate(v)
+	}
+
+	now := time.Now()
+	for _, v := range snap.Members {
+		members, ok := shard.members[v.GuildID]
+		if !ok {
+			members = make(map[int64]*WrappedMember)
+			shard.members[v.GuildID] = members
+		}
+
+		members[v.User.ID] = &WrappedMember{
+			lastUpdated: now,
+			MemberState: *v,
+		}
+	}
+
+	return nil
+}
+
+// WriteSnapshot encodes the snapshot as gzipped json
+func WriteSnapshot(w io.Writer, snap *ShardSnapshot) error {
+	gw := gzip.NewWriter(w)
+	err := json.NewEncoder(gw).Encode(snap)
+	if err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+// ReadSnapshot decodes a snapshot written by WriteSnapshot
+func ReadSnapshot(r io.Reader) (*ShardSnapshot, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	var snap ShardSnapshot
+	err = json.NewDecoder(gr).Decode(&snap)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snap, nil
+}
+
+// SnapshotPath returns the path of the snapshot file for the shard in dir
+func SnapshotPath(dir string, shardID int64) string {
+	return filepath.Join(dir, fmt.Sprintf("shard_%d.json.gz", shardID))
+}
+
+// SaveSnapshotFile writes the snapshot to dir, replacing any previous one for the shard
+func SaveSnapshotFile(dir string, snap *ShardSnapshot) error {
+	f, err := os.CreateTemp(dir, "shard_*.tmp")
+	if err != nil {
+		return err
+	}
+
+	err = WriteSnapshot(f, snap)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), SnapshotPath(dir, snap.ShardID))
+}
+
+// LoadSnapshotFile reads and removes the snapshot for the shard in dir, so that it's never restored twice
+// returns nil and no error if there is none
+func LoadSnapshotFile(dir string, shardID int64) (*ShardSnapshot, error) {
+	path := SnapshotPath(dir, shardID)
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer os.Remove(path)
+	defer f.Close()
+
+	return ReadSnapshot(f)
+}
@@ -0,0 +1,63 @@
+package inmemorytracker
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSnapshotRoundtrip(t *testing.T) {
+	state := createTestState(TrackerConfig{})
+
+	snap := state.SnapshotShard(0)
+	snap.SessionID = "abc"
+	snap.Sequence = 10
+
+	var buf bytes.Buffer
+	err := WriteSnapshot(&buf, snap)
+	if err != nil {
+		t.Fatal("failed writing snapshot: ", err)
+	}
+
+	decoded, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatal("failed reading snapshot: ", err)
+	}
+
+	if decoded.SessionID != "abc" || decoded.Sequence != 10 {
+		t.Errorf("session info not preserved: %q %d", decoded.SessionID, decoded.Sequence)
+	}
+
+	restored := NewInMemoryTracker(TrackerConfig{}, 1)
+	err = restored.RestoreShard(decoded)
+	if err != nil {
+		t.Fatal("failed restoring snapshot: ", err)
+	}
+
+	gs := restored.GetGuild(initialTestGuildID)
+	if gs == nil {
+		t.Fatal("guild not restored")
+	}
+
+	if gs.GetChannel(initialTestChannelID) == nil {
+		t.Error("channel not restored")
+	}
+
+	if gs.GetRole(initialTestRoleID) == nil {
+		t.Error("role not restored")
+	}
+
+	if gs.GetThread(intialTestThreadID) == nil {
+		t.Error("thread not restored")
+	}
+
+	assertMemberExists(t, restored, initialTestGuildID, initialTestMemberID, true, true)
+}
+
+func TestSnapshotTotalShardsMismatch(t *testing.T) {
+	snap := createTestState(TrackerConfig{}).SnapshotShard(0)
+
+	restored := NewInMemoryTracker(TrackerConfig{}, 2)
+	if err := restored.RestoreShard(snap); err != ErrSnapshotTotalShards {
+		t.Errorf("expected ErrSnapshotTotalShards, got %v", err)
+	}
+}
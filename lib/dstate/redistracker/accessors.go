@@ -0,0 +1,214 @@
+package redistracker
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/mediocregopher/radix/v3"
+)
+
+var _ dstate.StateTracker = (*RedisTracker)(nil)
+
+func (t *RedisTracker) GetGuild(guildID int64) *dstate.GuildSet {
+	return t.getGuildSet(guildID)
+}
+
+func (t *RedisTracker) GetMember(guildID int64, memberID int64) *dstate.MemberState {
+	return t.getMember(guildID, memberID)
+}
+
+func (t *RedisTracker) GetShardGuilds(shardID int64) []*dstate.GuildSet {
+	var guildIDs []int64
+	if !t.do(radix.Cmd(&guildIDs, "SMEMBERS", t.shardGuildsKey(shardID))) || len(guildIDs) < 1 {
+		return nil
+	}
+
+	keys := make([]string, len(guildIDs))
+	for i, v := range guildIDs {
+		keys[i] = t.guildKey(v)
+	}
+
+	var raw [][]byte
+	if !t.do(radix.Cmd(&raw, "MGET", keys...)) {
+		return nil
+	}
+
+	result := make([]*dstate.GuildSet, 0, len(raw))
+	for _, v := range raw {
+		if len(v) < 1 {
+			continue
+		}
+
+		var gs dstate.GuildSet
+		err := json.Unmarshal(v, &gs)
+		if err != nil {
+			t.conf.OnError(err)
+			continue
+		}
+
+		result = append(result, &gs)
+	}
+
+	return result
+}
+
+// IterateMembers calls f with chunks of roughly MembersChunkSize members as they're scanned from redis
+func (t *RedisTracker) IterateMembers(guildID int64, f func(chunk []*dstate.MemberState) bool) {
+	scanner := radix.NewScanner(t.client, radix.ScanOpts{
+		Command: "HSCAN",
+		Key:     t.membersKey(guildID),
+		Count:   t.conf.MembersChunkSize,
+	})
+
+	// a scan can return the same element more than once
+	seen := make(map[int64]bool)
+	chunk := make([]*dstate.MemberState, 0, t.conf.MembersChunkSize)
+
+	// the scan returns the field followed by the value
+	var field, value string
+	for scanner.Next(&field) && scanner.Next(&value) {
+		id, _ := strconv.ParseInt(field, 10, 64)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		var ms dstate.MemberState
+		err := json.Unmarshal([]byte(value), &ms)
+		if err != nil {
+			t.conf.OnError(err)
+			continue
+		}
+
+		chunk = append(chunk, &ms)
+		if len(chunk) >= t.conf.MembersChunkSize {
+			if !f(chunk) {
+				scanner.Close()
+				return
+			}
+			chunk = make([]*dstate.MemberState, 0, t.conf.MembersChunkSize)
+		}
+	}
+
+	if err := scanner.Close(); err != nil {
+		t.conf.OnError(err)
+	}
+
+	if len(chunk) > 0 {
+		f(chunk)
+	}
+}
+
+// how many message ids are read from the lists at a time
+const messagesBatchSize = 100
+
+func (t *RedisTracker) GetMessages(guildID int64, channelID int64, query *dstate.MessagesQuery) []*dstate.MessageState {
+	key := t.channelMessagesKey(channelID)
+	if channelID == 0 {
+		key = t.guildMessagesKey(guildID)
+	}
+
+	limit := query.Limit
+
+	buf := query.Buf[:0]
+	if limit > 0 && cap(buf) < limit {
+		buf = make([]*dstate.MessageState, 0, limit)
+	}
+
+	// walk the list from the newest message in batches
+	for offset := 0; ; offset += messagesBatchSize {
+		var ids []int64
+		if !t.do(radix.FlatCmd(&ids, "LRANGE", key, -offset-messagesBatchSize, -offset-1)) || len(ids) < 1 {
+			break
+		}
+
+		keys := make([]string, len(ids))
+		for i, v := range ids {
+			keys[i] = t.messageKey(v)
+		}
+
+		var raw [][]byte
+		if !t.do(radix.Cmd(&raw, "MGET", keys...)) {
+			break
+		}
+
+		for i := len(raw) - 1; i >= 0; i-- {
+			if len(raw[i]) < 1 {
+				// expired
+				continue
+			}
+
+			var m dstate.MessageState
+			err := json.Unmarshal(raw[i], &m)
+			if err != nil {
+				t.conf.OnError(err)
+				continue
+			}
+
+			include, cont := checkMessage(query, &m)
+			if include {
+				buf = append(buf, &m)
+				if limit > 0 && len(buf) >= limit {
+					return buf
+				}
+			}
+
+			if !cont {
+				return buf
+			}
+		}
+
+		if len(ids) < messagesBatchSize {
+			break
+		}
+	}
+
+	return buf
+}
+
+func checkMessage(q *dstate.MessagesQuery, m *dstate.MessageState) (include bool, continueIter bool) {
+	if q.Before != 0 && m.ID >= q.Before {
+		return false, true
+	}
+
+	if q.After != 0 && m.ID <= q.After {
+		return false, false
+	}
+
+	if !q.IncludeDeleted && m.Deleted {
+		return false, true
+	}
+
+	return true, true
+}
+
+// SetGuild allows you to manually add guilds to the state tracker, for example when recovering state
+func (t *RedisTracker) SetGuild(gs *dstate.GuildSet) {
+	defer t.lockGuild(gs.ID)()
+
+	t.setGuildSet(gs)
+}
+
+// SetMember allows you to manually add members to the state tracker, for example for caching reasons
+func (t *RedisTracker) SetMember(ms *dstate.MemberState) {
+	defer t.lockGuild(ms.GuildID)()
+
+	// carry over presence
+	if existing := t.getMember(ms.GuildID, ms.User.ID); existing != nil {
+		cop := *ms
+		cop.Presence = existing.Presence
+		ms = &cop
+	}
+
+	t.setMember(ms)
+}
+
+// DelShard removes all the guilds on the shard from the state
+func (t *RedisTracker) DelShard(shardID int64) {
+	l := &t.shardLocks[shardID]
+	l.Lock()
+	defer l.Unlock()
+
+	t.resetShard(shardID)
+}
@@ -0,0 +1,64 @@
+package redistracker
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate/dstatetest"
+	"github.com/mediocregopher/radix/v3"
+)
+
+// the tests run against the redis in YAGPDB_REDIS (or localhost), on db 2 like the rest of the unit tests
+func testPool(t *testing.T) *radix.Pool {
+	addr := os.Getenv("YAGPDB_REDIS")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+
+	pool, err := radix.NewPool("tcp", addr, 2, radix.PoolConnFunc(func(network, addr string) (radix.Conn, error) {
+		return radix.Dial(network, addr, radix.DialSelectDB(2))
+	}))
+	if err != nil {
+		t.Skip("redis not available: ", err)
+	}
+
+	t.Cleanup(func() { pool.Close() })
+	return pool
+}
+
+func TestConformance(t *testing.T) {
+	pool := testPool(t)
+
+	n := 0
+	dstatetest.RunConformanceTests(t, func(t *testing.T, totalShards int64, botMemberID int64) dstatetest.Tracker {
+		n++
+		prefix := "dstatetest:" + strconv.FormatInt(time.Now().UnixNano(), 10) + ":" + strconv.Itoa(n)
+		t.Cleanup(func() { deleteKeys(t, pool, prefix) })
+
+		return NewRedisTracker(pool, TrackerConfig{
+			KeyPrefix:        prefix,
+			BotMemberID:      botMemberID,
+			MembersChunkSize: 100,
+			OnError: func(err error) {
+				t.Error("redis error: ", err)
+			},
+		}, totalShards)
+	})
+}
+
+func deleteKeys(t *testing.T, pool *radix.Pool, prefix string) {
+	scanner := radix.NewScanner(pool, radix.ScanOpts{Command: "SCAN", Pattern: prefix + ":*"})
+
+	var key string
+	for scanner.Next(&key) {
+		if err := pool.Do(radix.Cmd(nil, "DEL", key)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if err := scanner.Close(); err != nil {
+		t.Error(err)
+	}
+}
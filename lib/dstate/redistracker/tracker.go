@@ -0,0 +1,930 @@
+// redistracker is a dstate.StateTracker that keeps the state in redis,
+// so it survives restarts and can be read by other processes (the web server, botrest and so on) without going through the bot.
+//
+// It's fed events like the in memory tracker, and there should only be one process feeding events per shard
+// as updates to the guild sets are read-modify-write.
+package redistracker
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/mediocregopher/radix/v3"
+)
+
+type TrackerConfig struct {
+	// KeyPrefix is prepended to all the keys, defaults to "dstate"
+	KeyPrefix string
+
+	ChannelMessageLen int
+	ChannelMessageDur time.Duration
+
+	ChannelMessageLimitsF func(guildID int64) (int, time.Duration)
+
+	// MembersChunkSize is roughly how many members IterateMembers fetches at a time, defaults to 1000
+	MembersChunkSize int
+
+	// Set this to have threads removed from state when the bot loses access to them
+	BotMemberID int64
+
+	// OnError is called with errors talking to redis, as they can't be returned through the StateTracker interface,
+	// defaults to logging them
+	OnError func(err error)
+}
+
+type RedisTracker struct {
+	client      radix.Client
+	conf        TrackerConfig
+	totalShards int64
+
+	// guards the read-modify-write updates in this process, per shard
+	shardLocks []sync.Mutex
+}
+
+// NewRedisTracker returns a new tracker using client, a tracker that is never fed events can be used to read the state
+func NewRedisTracker(client radix.Client, conf TrackerConfig, totalShards int64) *RedisTracker {
+	if conf.KeyPrefix == "" {
+		conf.KeyPrefix = "dstate"
+	}
+
+	if conf.MembersChunkSize < 1 {
+		conf.MembersChunkSize = 1000
+	}
+
+	if conf.OnError == nil {
+		conf.OnError = func(err error) {
+			log.Println("[dstate/redistracker] ", err)
+		}
+	}
+
+	return &RedisTracker{
+		client:      client,
+		conf:        conf,
+		totalShards: totalShards,
+		shardLocks:  make([]sync.Mutex, totalShards),
+	}
+}
+
+///////////////////
+// Keys
+///////////////////
+
+func (t *RedisTracker) guildKey(guildID int64) string {
+	return t.conf.KeyPrefix + ":guild:" + strconv.FormatInt(guildID, 10)
+}
+
+// set of the guild ids on the shard
+func (t *RedisTracker) shardGuildsKey(shardID int64) string {
+	return t.conf.KeyPrefix + ":shard_guilds:" + strconv.FormatInt(shardID, 10)
+}
+
+// hash of user id -> member state
+func (t *RedisTracker) membersKey(guildID int64) string {
+	return t.conf.KeyPrefix + ":members:" + strconv.FormatInt(guildID, 10)
+}
+
+// list of message ids, oldest first
+func (t *RedisTracker) channelMessagesKey(channelID int64) string {
+	return t.conf.KeyPrefix + ":channel_messages:" + strconv.FormatInt(channelID, 10)
+}
+
+// list of message ids in all the channels of the guild, oldest first
+func (t *RedisTracker) guildMessagesKey(guildID int64) string {
+	return t.conf.KeyPrefix + ":guild_messages:" + strconv.FormatInt(guildID, 10)
+}
+
+func (t *RedisTracker) messageKey(messageID int64) string {
+	return t.conf.KeyPrefix + ":message:" + strconv.FormatInt(messageID, 10)
+}
+
+func (t *RedisTracker) guildShard(guildID int64) int64 {
+	return (guildID >> 22) % t.totalShards
+}
+
+func (t *RedisTracker) lockGuild(guildID int64) func() {
+	l := &t.shardLocks[t.guildShard(guildID)]
+	l.Lock()
+	return l.Unlock
+}
+
+func (t *RedisTracker) do(a radix.Action) bool {
+	err := t.client.Do(a)
+	if err != nil {
+		t.conf.OnError(err)
+		return false
+	}
+
+	return true
+}
+
+///////////////////
+// Storage
+///////////////////
+
+// getGuildSet returns the stored guild set, or nil if there is none
+func (t *RedisTracker) getGuildSet(guildID int64) *dstate.GuildSet {
+	var raw []byte
+	if !t.do(radix.Cmd(&raw, "GET", t.guildKey(guildID))) || len(raw) < 1 {
+		return nil
+	}
+
+	var gs dstate.GuildSet
+	err := json.Unmarshal(raw, &gs)
+	if err != nil {
+		t.conf.OnError(err)
+		return nil
+	}
+
+	return &gs
+}
+
+func (t *RedisTracker) setGuildSet(gs *dstate.GuildSet) {
+	raw, err := json.Marshal(gs)
+	if err != nil {
+		t.conf.OnError(err)
+		return
+	}
+
+	t.do(radix.Pipeline(
+		radix.Cmd(nil, "SET", t.guildKey(gs.ID), string(raw)),
+		radix.FlatCmd(nil, "SADD", t.shardGuildsKey(t.guildShard(gs.ID)), gs.ID),
+	))
+}
+
+// delGuild removes the guild and everything in it
+func (t *RedisTracker) delGuild(guildID int64) {
+	keys := []string{t.guildKey(guildID), t.membersKey(guildID), t.guildMessagesKey(guildID)}
+	if gs := t.getGuildSet(guildID); gs != nil {
+		for _, v := range gs.Channels {
+			keys = append(keys, t.channelMessagesKey(v.ID))
+		}
+		for _, v := range gs.Threads {
+			keys = append(keys, t.channelMessagesKey(v.ID))
+		}
+	}
+
+	t.do(radix.Pipeline(
+		radix.Cmd(nil, "DEL", keys...),
+		radix.FlatCmd(nil, "SREM", t.shardGuildsKey(t.guildShard(guildID)), guildID),
+	))
+}
+
+func (t *RedisTracker) getMember(guildID int64, userID int64) *dstate.MemberState {
+	var raw []byte
+	if !t.do(radix.FlatCmd(&raw, "HGET", t.membersKey(guildID), userID)) || len(raw) < 1 {
+		return nil
+	}
+
+	var ms dstate.MemberState
+	err := json.Unmarshal(raw, &ms)
+	if err != nil {
+		t.conf.OnError(err)
+		return nil
+	}
+
+	return &ms
+}
+
+func (t *RedisTracker) setMember(ms *dstate.MemberState) {
+	raw, err := json.Marshal(ms)
+	if err != nil {
+		t.conf.OnError(err)
+		return
+	}
+
+	t.do(radix.FlatCmd(nil, "HSET", t.membersKey(ms.GuildID), ms.User.ID, raw))
+}
+
+func (t *RedisTracker) getMessage(messageID int64) *dstate.MessageState {
+	var raw []byte
+	if !t.do(radix.Cmd(&raw, "GET", t.messageKey(messageID))) || len(raw) < 1 {
+		return nil
+	}
+
+	var m dstate.MessageState
+	err := json.Unmarshal(raw, &m)
+	if err != nil {
+		t.conf.OnError(err)
+		return nil
+	}
+
+	return &m
+}
+
+// replaceMessage updates a message that is already in state, keeping its expiry
+func (t *RedisTracker) replaceMessage(m *dstate.MessageState) {
+	raw, err := json.Marshal(m)
+	if err != nil {
+		t.conf.OnError(err)
+		return
+	}
+
+	t.do(radix.Cmd(nil, "SET", t.messageKey(m.ID), string(raw), "XX", "KEEPTTL"))
+}
+
+func (t *RedisTracker) messageLimits(guildID int64) (int, time.Duration) {
+	if t.conf.ChannelMessageLimitsF != nil {
+		return t.conf.ChannelMessageLimitsF(guildID)
+	}
+
+	return t.conf.ChannelMessageLen, t.conf.ChannelMessageDur
+}
+
+///////////////////
+// Events
+///////////////////
+
+func (t *RedisTracker) HandleEvent(s *discordgo.Session, i interface{}) {
+	switch evt := i.(type) {
+	// Guild events
+	case *discordgo.GuildCreate:
+		t.handleGuildCreate(evt)
+	case *discordgo.GuildUpdate:
+		t.handleGuildUpdate(evt)
+	case *discordgo.GuildDelete:
+		t.handleGuildDelete(evt)
+
+	// Member events
+	case *discordgo.GuildMemberAdd:
+		t.handleMemberCreate(evt)
+	case *discordgo.GuildMemberUpdate:
+		t.handleMemberUpdate(evt.Member)
+	case *discordgo.GuildMemberRemove:
+		t.handleMemberDelete(evt)
+
+	// Channel events
+	case *discordgo.ChannelCreate:
+		t.handleChannelCreateUpdate(evt.Channel, false)
+	case *discordgo.ChannelUpdate:
+		t.handleChannelCreateUpdate(evt.Channel, true)
+	case *discordgo.ChannelDelete:
+		t.handleChannelDelete(evt)
+
+	// Role events
+	case *discordgo.GuildRoleCreate:
+		t.handleRoleCreateUpdate(evt.GuildID, evt.Role, false)
+	case *discordgo.GuildRoleUpdate:
+		t.handleRoleCreateUpdate(evt.GuildID, evt.Role, true)
+	case *discordgo.GuildRoleDelete:
+		t.handleRoleDelete(evt)
+
+	// Message events
+	case *discordgo.MessageCreate:
+		t.handleMessageCreate(evt)
+	case *discordgo.MessageUpdate:
+		t.handleMessageUpdate(evt)
+	case *discordgo.MessageDelete:
+		t.handleMessageDelete(evt.GuildID, []int64{evt.ID})
+	case *discordgo.MessageDeleteBulk:
+		t.handleMessageDelete(evt.GuildID, evt.Messages)
+
+	// Threads
+	case *discordgo.ThreadCreate:
+		t.handleThreadCreateUpdate(&evt.Channel)
+	case *discordgo.ThreadUpdate:
+		t.handleThreadCreateUpdate(&evt.Channel)
+	case *discordgo.ThreadDelete:
+		t.handleThreadDelete(evt)
+	case *discordgo.ThreadListSync:
+		t.handleThreadListSync(evt)
+
+	// Other
+	case *discordgo.PresenceUpdate:
+		t.handlePresenceUpdate(evt)
+	case *discordgo.VoiceStateUpdate:
+		t.handleVoiceStateUpdate(evt)
+	case *discordgo.Ready:
+		t.handleReady(int64(s.ShardID), evt)
+	case *discordgo.GuildEmojisUpdate:
+		t.handleEmojis(evt)
+	case *discordgo.GuildStickersUpdate:
+		t.handleStickers(evt)
+	}
+}
+
+///////////////////
+// Guild events
+///////////////////
+
+func (t *RedisTracker) handleGuildCreate(gc *discordgo.GuildCreate) {
+	defer t.lockGuild(gc.ID)()
+
+	gs := &dstate.GuildSet{
+		GuildState:  *dstate.GuildStateFromDgo(gc.Guild),
+		Channels:    make([]dstate.ChannelState, len(gc.Channels)),
+		Threads:     make([]dstate.ChannelState, len(gc.Threads)),
+		Roles:       make([]discordgo.Role, len(gc.Roles)),
+		Emojis:      make([]discordgo.Emoji, len(gc.Emojis)),
+		Stickers:    make([]discordgo.Sticker, len(gc.Stickers)),
+		VoiceStates: make([]discordgo.VoiceState, len(gc.VoiceStates)),
+	}
+
+	for i, v := range gc.Channels {
+		gs.Channels[i] = dstate.ChannelStateFromDgo(v)
+		gs.Channels[i].GuildID = gc.ID
+	}
+	sort.Sort(dstate.Channels(gs.Channels))
+
+	for i, v := range gc.Threads {
+		gs.Threads[i] = dstate.ChannelStateFromDgo(v)
+		gs.Threads[i].GuildID = gc.ID
+	}
+
+	for i := range gc.Roles {
+		gs.Roles[i] = *gc.Roles[i]
+	}
+	sort.Sort(dstate.Roles(gs.Roles))
+
+	for i := range gc.Emojis {
+		gs.Emojis[i] = *gc.Emojis[i]
+	}
+
+	for i := range gc.Stickers {
+		gs.Stickers[i] = *gc.Stickers[i]
+	}
+
+	for i := range gc.VoiceStates {
+		gs.VoiceStates[i] = *gc.VoiceStates[i]
+	}
+
+	t.setGuildSet(gs)
+
+	if len(gc.Members) < 1 {
+		return
+	}
+
+	// the presences in the guild does not include a full user object,
+	// so only the presences that also have a corresponding member object are loaded
+	presences := make(map[int64]*discordgo.Presence, len(gc.Presences))
+	for _, v := range gc.Presences {
+		if v.User != nil {
+			presences[v.User.ID] = v
+		}
+	}
+
+	args := make([]interface{}, 0, len(gc.Members)*2)
+	for _, v := range gc.Members {
+		ms := dstate.MemberStateFromMember(v)
+		ms.GuildID = gc.ID
+
+		if p, ok := presences[ms.User.ID]; ok {
+			ms.Presence = dstate.MemberStateFromPresence(&discordgo.PresenceUpdate{Presence: *p, GuildID: gc.ID}).Presence
+		}
+
+		raw, err := json.Marshal(ms)
+		if err != nil {
+			t.conf.OnError(err)
+			continue
+		}
+
+		args = append(args, ms.User.ID, raw)
+	}
+
+	t.do(radix.FlatCmd(nil, "HSET", t.membersKey(gc.ID), args...))
+}
+
+func (t *RedisTracker) handleGuildUpdate(gu *discordgo.GuildUpdate) {
+	defer t.lockGuild(gu.ID)()
+
+	newInnerGuild := dstate.GuildStateFromDgo(gu.Guild)
+
+	gs := t.getGuildSet(gu.ID)
+	if gs == nil {
+		gs = &dstate.GuildSet{}
+	} else {
+		newInnerGuild.MemberCount = gs.MemberCount
+	}
+
+	gs.GuildState = *newInnerGuild
+	t.setGuildSet(gs)
+}
+
+func (t *RedisTracker) handleGuildDelete(gd *discordgo.GuildDelete) {
+	defer t.lockGuild(gd.ID)()
+
+	if !gd.Unavailable {
+		t.delGuild(gd.ID)
+		return
+	}
+
+	if gs := t.getGuildSet(gd.ID); gs != nil {
+		gs.Available = false
+		t.setGuildSet(gs)
+	}
+}
+
+///////////////////
+// Channel events
+///////////////////
+
+func (t *RedisTracker) handleChannelCreateUpdate(c *discordgo.Channel, checkThreads bool) {
+	defer t.lockGuild(c.GuildID)()
+
+	gs := t.getGuildSet(c.GuildID)
+	if gs == nil {
+		return
+	}
+
+	cs := dstate.ChannelStateFromDgo(c)
+	if existing := gs.GetChannel(c.ID); existing != nil {
+		*existing = cs
+	} else {
+		gs.Channels = append(gs.Channels, cs)
+	}
+	sort.Sort(dstate.Channels(gs.Channels))
+
+	if checkThreads {
+		// remove threads in the channel from state if we lost access to it
+		if ms := t.getBotMember(gs.ID); ms != nil {
+			updateChannelThreadsAccess(gs, ms, gs.GetChannel(c.ID))
+		}
+	}
+
+	t.setGuildSet(gs)
+}
+
+func (t *RedisTracker) handleChannelDelete(c *discordgo.ChannelDelete) {
+	defer t.lockGuild(c.GuildID)()
+
+	t.do(radix.Cmd(nil, "DEL", t.channelMessagesKey(c.ID)))
+
+	gs := t.getGuildSet(c.GuildID)
+	if gs == nil {
+		return
+	}
+
+	for i, v := range gs.Channels {
+		if v.ID == c.ID {
+			gs.Channels = append(gs.Channels[:i], gs.Channels[i+1:]...)
+			t.setGuildSet(gs)
+			return
+		}
+	}
+}
+
+///////////////////
+// Role events
+///////////////////
+
+func (t *RedisTracker) handleRoleCreateUpdate(guildID int64, r *discordgo.Role, checkThreads bool) {
+	defer t.lockGuild(guildID)()
+
+	gs := t.getGuildSet(guildID)
+	if gs == nil {
+		return
+	}
+
+	if existing := gs.GetRole(r.ID); existing != nil {
+		*existing = *r
+	} else {
+		gs.Roles = append(gs.Roles, *r)
+	}
+	sort.Sort(dstate.Roles(gs.Roles))
+
+	if checkThreads {
+		// remove threads from state we lost access to
+		if ms := t.getBotMember(guildID); ms != nil && containsInt64(ms.Member.Roles, r.ID) {
+			updateAllThreadsAccess(gs, ms)
+		}
+	}
+
+	t.setGuildSet(gs)
+}
+
+func (t *RedisTracker) handleRoleDelete(r *discordgo.GuildRoleDelete) {
+	defer t.lockGuild(r.GuildID)()
+
+	gs := t.getGuildSet(r.GuildID)
+	if gs == nil {
+		return
+	}
+
+	for i, v := range gs.Roles {
+		if v.ID == r.RoleID {
+			gs.Roles = append(gs.Roles[:i], gs.Roles[i+1:]...)
+			t.setGuildSet(gs)
+			return
+		}
+	}
+}
+
+///////////////////
+// Member events
+///////////////////
+
+func (t *RedisTracker) handleMemberCreate(m *discordgo.GuildMemberAdd) {
+	defer t.lockGuild(m.GuildID)()
+
+	gs := t.getGuildSet(m.GuildID)
+	if gs == nil {
+		return
+	}
+
+	gs.MemberCount++
+	t.setGuildSet(gs)
+
+	t.innerHandleMemberUpdate(gs, dstate.MemberStateFromMember(m.Member))
+}
+
+func (t *RedisTracker) handleMemberUpdate(m *discordgo.Member) {
+	defer t.lockGuild(m.GuildID)()
+
+	t.innerHandleMemberUpdate(nil, dstate.MemberStateFromMember(m))
+}
+
+// gs is loaded if needed and nil
+func (t *RedisTracker) innerHandleMemberUpdate(gs *dstate.GuildSet, ms *dstate.MemberState) {
+	existing := t.getMember(ms.GuildID, ms.User.ID)
+	if existing != nil {
+		// carry over presence
+		ms.Presence = existing.Presence
+	}
+
+	t.setMember(ms)
+
+	if t.conf.BotMemberID == 0 || ms.User.ID != t.conf.BotMemberID {
+		return
+	}
+
+	if existing == nil || existing.Member == nil || hasRemovedRole(existing.Member.Roles, ms.Member.Roles) {
+		if gs == nil {
+			gs = t.getGuildSet(ms.GuildID)
+		}
+
+		if gs != nil && updateAllThreadsAccess(gs, ms) {
+			t.setGuildSet(gs)
+		}
+	}
+}
+
+func (t *RedisTracker) handleMemberDelete(mr *discordgo.GuildMemberRemove) {
+	defer t.lockGuild(mr.GuildID)()
+
+	gs := t.getGuildSet(mr.GuildID)
+	if gs == nil {
+		return
+	}
+
+	gs.MemberCount--
+	t.setGuildSet(gs)
+
+	t.do(radix.FlatCmd(nil, "HDEL", t.membersKey(mr.GuildID), mr.User.ID))
+}
+
+func (t *RedisTracker) getBotMember(guildID int64) *dstate.MemberState {
+	if t.conf.BotMemberID == 0 {
+		return nil
+	}
+
+	ms := t.getMember(guildID, t.conf.BotMemberID)
+	if ms == nil || ms.Member == nil {
+		return nil
+	}
+
+	return ms
+}
+
+///////////////////
+// Message events
+///////////////////
+
+func (t *RedisTracker) handleMessageCreate(m *discordgo.MessageCreate) {
+	if m.GuildID == 0 {
+		return
+	}
+
+	raw, err := json.Marshal(dstate.MessageStateFromDgo(m.Message))
+	if err != nil {
+		t.conf.OnError(err)
+		return
+	}
+
+	maxLen, maxAge := t.messageLimits(m.GuildID)
+
+	channelKey := t.channelMessagesKey(m.ChannelID)
+	guildKey := t.guildMessagesKey(m.GuildID)
+
+	setArgs := []string{t.messageKey(m.ID), string(raw)}
+	if maxAge > 0 {
+		setArgs = append(setArgs, "PX", strconv.FormatInt(maxAge.Milliseconds(), 10))
+	}
+
+	cmds := []radix.CmdAction{
+		radix.Cmd(nil, "SET", setArgs...),
+		radix.FlatCmd(nil, "RPUSH", channelKey, m.ID),
+		radix.FlatCmd(nil, "RPUSH", guildKey, m.ID),
+	}
+
+	if maxLen > 0 {
+		cmds = append(cmds,
+			radix.FlatCmd(nil, "LTRIM", channelKey, -maxLen, -1),
+			radix.FlatCmd(nil, "LTRIM", guildKey, -maxLen, -1))
+	}
+
+	if maxAge > 0 {
+		// the lists of channels without activity expire along with the messages in them,
+		// ids of expired messages left in active lists are skipped when reading
+		cmds = append(cmds,
+			radix.FlatCmd(nil, "PEXPIRE", channelKey, maxAge.Milliseconds()),
+			radix.FlatCmd(nil, "PEXPIRE", guildKey, maxAge.Milliseconds()))
+	}
+
+	t.do(radix.Pipeline(cmds...))
+}
+
+func (t *RedisTracker) handleMessageUpdate(m *discordgo.MessageUpdate) {
+	if m.GuildID == 0 {
+		return
+	}
+
+	t.replaceMessage(dstate.MessageStateFromDgo(m.Message))
+}
+
+func (t *RedisTracker) handleMessageDelete(guildID int64, messageIDs []int64) {
+	if guildID == 0 {
+		return
+	}
+
+	for _, v := range messageIDs {
+		m := t.getMessage(v)
+		if m == nil {
+			continue
+		}
+
+		m.Deleted = true
+		t.replaceMessage(m)
+	}
+}
+
+///////////////////
+// MISC events
+///////////////////
+
+func (t *RedisTracker) handlePresenceUpdate(p *discordgo.PresenceUpdate) {
+	if p.User == nil {
+		return
+	}
+
+	defer t.lockGuild(p.GuildID)()
+
+	ms := dstate.MemberStateFromPresence(p)
+
+	if existing := t.getMember(ms.GuildID, ms.User.ID); existing != nil {
+		// carry over the member object, and the user object if needed
+		ms.Member = existing.Member
+		if ms.User.Username == "" {
+			ms.User = existing.User
+		}
+	} else if ms.User.Username == "" {
+		// not enough info to add to state
+		return
+	}
+
+	t.setMember(ms)
+}
+
+func (t *RedisTracker) handleVoiceStateUpdate(p *discordgo.VoiceStateUpdate) {
+	defer t.lockGuild(p.GuildID)()
+
+	gs := t.getGuildSet(p.GuildID)
+	if gs == nil {
+		return
+	}
+
+	for i, v := range gs.VoiceStates {
+		if v.UserID == p.UserID {
+			if p.ChannelID == 0 {
+				// Left voice chat entirely, remove us
+				gs.VoiceStates = append(gs.VoiceStates[:i], gs.VoiceStates[i+1:]...)
+			} else {
+				// just changed state
+				gs.VoiceStates[i] = *p.VoiceState
+			}
+
+			t.setGuildSet(gs)
+			return
+		}
+	}
+
+	if p.ChannelID != 0 {
+		// joined a voice channel
+		gs.VoiceStates = append(gs.VoiceStates, *p.VoiceState)
+		t.setGuildSet(gs)
+	}
+}
+
+func (t *RedisTracker) handleReady(shardID int64, r *discordgo.Ready) {
+	l := &t.shardLocks[shardID]
+	l.Lock()
+	defer l.Unlock()
+
+	t.resetShard(shardID)
+
+	for _, v := range r.Guilds {
+		t.setGuildSet(&dstate.GuildSet{
+			GuildState: *dstate.GuildStateFromDgo(v),
+		})
+	}
+}
+
+// assumes the shard is locked
+func (t *RedisTracker) resetShard(shardID int64) {
+	var guilds []int64
+	if !t.do(radix.Cmd(&guilds, "SMEMBERS", t.shardGuildsKey(shardID))) {
+		return
+	}
+
+	for _, v := range guilds {
+		t.delGuild(v)
+	}
+}
+
+func (t *RedisTracker) handleEmojis(e *discordgo.GuildEmojisUpdate) {
+	defer t.lockGuild(e.GuildID)()
+
+	gs := t.getGuildSet(e.GuildID)
+	if gs == nil {
+		return
+	}
+
+	gs.Emojis = make([]discordgo.Emoji, len(e.Emojis))
+	for i := range e.Emojis {
+		gs.Emojis[i] = *e.Emojis[i]
+	}
+
+	t.setGuildSet(gs)
+}
+
+func (t *RedisTracker) handleStickers(s *discordgo.GuildStickersUpdate) {
+	defer t.lockGuild(s.GuildID)()
+
+	gs := t.getGuildSet(s.GuildID)
+	if gs == nil {
+		return
+	}
+
+	gs.Stickers = make([]discordgo.Sticker, len(s.Stickers))
+	for i := range s.Stickers {
+		gs.Stickers[i] = *s.Stickers[i]
+	}
+
+	t.setGuildSet(gs)
+}
+
+///////////////////
+// THREAD EVENTS
+///////////////////
+
+func (t *RedisTracker) handleThreadCreateUpdate(c *discordgo.Channel) {
+	defer t.lockGuild(c.GuildID)()
+
+	// we don't cache archived threads
+	if c.ThreadMetadata != nil && c.ThreadMetadata.Archived {
+		t.removeThread(c.GuildID, c.ID)
+		return
+	}
+
+	gs := t.getGuildSet(c.GuildID)
+	if gs == nil {
+		return
+	}
+
+	if existing := gs.GetThread(c.ID); existing != nil {
+		*existing = dstate.ChannelStateFromDgo(c)
+	} else {
+		gs.Threads = append(gs.Threads, dstate.ChannelStateFromDgo(c))
+	}
+
+	t.setGuildSet(gs)
+}
+
+func (t *RedisTracker) handleThreadDelete(td *discordgo.ThreadDelete) {
+	defer t.lockGuild(td.GuildID)()
+
+	t.removeThread(td.GuildID, td.ID)
+}
+
+func (t *RedisTracker) removeThread(guildID int64, threadID int64) {
+	t.do(radix.Cmd(nil, "DEL", t.channelMessagesKey(threadID)))
+
+	gs := t.getGuildSet(guildID)
+	if gs == nil {
+		return
+	}
+
+	for i := range gs.Threads {
+		if gs.Threads[i].ID == threadID {
+			gs.Threads = append(gs.Threads[:i], gs.Threads[i+1:]...)
+			t.setGuildSet(gs)
+			return
+		}
+	}
+}
+
+func (t *RedisTracker) handleThreadListSync(evt *discordgo.ThreadListSync) {
+	defer t.lockGuild(evt.GuildID)()
+
+	gs := t.getGuildSet(evt.GuildID)
+	if gs == nil {
+		return
+	}
+
+	// keep the threads in the channels that were not synced
+	threads := make([]dstate.ChannelState, 0, len(gs.Threads)+len(evt.Threads))
+	for _, v := range gs.Threads {
+		if !containsInt64(evt.Channels, v.ParentID) {
+			threads = append(threads, v)
+		}
+	}
+
+	for _, v := range evt.Threads {
+		threads = append(threads, dstate.ChannelStateFromDgo(v))
+	}
+
+	gs.Threads = threads
+	t.setGuildSet(gs)
+}
+
+// updateAllThreadsAccess removes the threads in channels the bot can't view from gs, returns true if any were removed
+func updateAllThreadsAccess(gs *dstate.GuildSet, bot *dstate.MemberState) bool {
+	removeChannels := make([]int64, 0)
+
+	guildPerms := dstate.CalculateBasePermissions(gs.ID, gs.OwnerID, gs.Roles, bot.User.ID, bot.Member.Roles)
+
+	for i := range gs.Threads {
+		parent := gs.Threads[i].ParentID
+		if containsInt64(removeChannels, parent) {
+			continue
+		}
+
+		if cs := gs.GetChannel(parent); cs == nil || !botHasAccessToChannel(gs, bot, cs, guildPerms) {
+			removeChannels = append(removeChannels, parent)
+		}
+	}
+
+	if len(removeChannels) < 1 {
+		return false
+	}
+
+	threads := make([]dstate.ChannelState, 0, len(gs.Threads))
+	for _, thread := range gs.Threads {
+		if !containsInt64(removeChannels, thread.ParentID) {
+			threads = append(threads, thread)
+		}
+	}
+
+	gs.Threads = threads
+	return true
+}
+
+// updateChannelThreadsAccess removes the threads in cs from gs if the bot can't view it
+func updateChannelThreadsAccess(gs *dstate.GuildSet, bot *dstate.MemberState, cs *dstate.ChannelState) {
+	guildPerms := dstate.CalculateBasePermissions(gs.ID, gs.OwnerID, gs.Roles, bot.User.ID, bot.Member.Roles)
+	if botHasAccessToChannel(gs, bot, cs, guildPerms) {
+		return
+	}
+
+	threads := make([]dstate.ChannelState, 0, len(gs.Threads))
+	for _, thread := range gs.Threads {
+		if thread.ParentID != cs.ID {
+			threads = append(threads, thread)
+		}
+	}
+
+	gs.Threads = threads
+}
+
+func botHasAccessToChannel(gs *dstate.GuildSet, bot *dstate.MemberState, cs *dstate.ChannelState, guildPerms int64) bool {
+	if guildPerms&discordgo.PermissionAdministrator == discordgo.PermissionAdministrator {
+		return true
+	}
+
+	fullPerms := dstate.ApplyChannelPermissions(guildPerms, gs.ID, cs.PermissionOverwrites, bot.User.ID, bot.Member.Roles)
+	return fullPerms&discordgo.PermissionViewChannel == discordgo.PermissionViewChannel
+}
+
+func hasRemovedRole(oldRoles []int64, newRoles []int64) bool {
+	for _, v := range oldRoles {
+		if !containsInt64(newRoles, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsInt64(slice []int64, i int64) bool {
+	for _, v := range slice {
+		if v == i {
+			return true
+		}
+	}
+
+	return false
+}
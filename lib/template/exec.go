@@ -254,10 +254,18 @@ func (t *Template) ExecuteTemplate(wr io.Writer, name string, data interface{})
 // If data is a reflect.Value, the template applies to the concrete
 // value that the reflect.Value holds, as in fmt.Print.
 func (t *Template) Execute(wr io.Writer, data interface{}) error {
-	return t.execute(wr, data)
+	return t.execute(wr, data, nil)
 }
 
-func (t *Template) execute(wr io.Writer, data interface{}) (err error) {
+// ExecuteWithOps is like Execute but also returns the number of operations
+// the execution used, counted the same way as for the MaxOps limit.
+func (t *Template) ExecuteWithOps(wr io.Writer, data interface{}) (int, error) {
+	var ops int
+	err := t.execute(wr, data, &ops)
+	return ops, err
+}
+
+func (t *Template) execute(wr io.Writer, data interface{}, ops *int) (err error) {
 	defer errRecover(&err)
 	value, ok := data.(reflect.Value)
 	if !ok {
@@ -268,6 +276,9 @@ func (t *Template) execute(wr io.Writer, data interface{}) (err error) {
 		wr:   wr,
 		vars: []variable{{"$", value}},
 	}
+	if ops != nil {
+		defer func() { *ops = state.operations }()
+	}
 	if t.Tree == nil || t.Root == nil {
 		state.errorf("%q is an incomplete or empty template", t.Name())
 	}
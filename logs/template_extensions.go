@@ -9,6 +9,9 @@ import (
 )
 
 func init() {
+	templates.RegisterCallLimit("pastUsernames", templates.CallLimit{Normal: 2, Premium: 2})
+	templates.RegisterCallLimit("pastNicknames", templates.CallLimit{Normal: 2, Premium: 2})
+
 	templates.RegisterSetupFunc(func(ctx *templates.Context) {
 		ctx.ContextFuncs["pastUsernames"] = tmplUsernames(ctx)
 		ctx.ContextFuncs["pastNicknames"] = tmplNicknames(ctx)
@@ -25,7 +28,7 @@ func tmplUsernames(tmplCtx *templates.Context) interface{} {
 		if !confEnableUsernameTracking.GetBool() {
 			return nil, errors.New("pastUsernames has been disabled globally")
 		}
-		if tmplCtx.IncreaseCheckCallCounter("pastUsernames", 2) {
+		if tmplCtx.IncreaseCheckCallLimit("pastUsernames") {
 			return nil, errors.New("Max calls to pastUsernames (2) reached")
 		}
 
@@ -53,7 +56,7 @@ func tmplNicknames(tmplCtx *templates.Context) interface{} {
 		if !confEnableUsernameTracking.GetBool() {
 			return nil, errors.New("pastNicknames has been disabled globally")
 		}
-		if tmplCtx.IncreaseCheckCallCounter("pastNicknames", 2) {
+		if tmplCtx.IncreaseCheckCallLimit("pastNicknames") {
 			return nil, errors.New("Max calls to pastNicknames (2) reached")
 		}
 
@@ -134,21 +134,22 @@ func SafeArgString(data *dcmd.Data, arg int) string {
 	return data.Args[arg].Str()
 }
 
-func GenericCmdResp(action ModlogAction, target *discordgo.User, duration time.Duration, zeroDurPermanent bool, noDur bool) string {
-	durStr := " indefinitely"
-	if duration > 0 || !zeroDurPermanent {
-		durStr = " for `" + common.HumanizeDuration(common.DurationPrecisionMinutes, duration) + "`"
+func GenericCmdResp(data *dcmd.Data, action ModlogAction, target *discordgo.User, duration time.Duration, zeroDurPermanent bool, noDur bool) string {
+	userStr := target.String()
+	if target.Discriminator == "????" {
+		userStr = strconv.FormatInt(target.ID, 10)
 	}
+
+	prefix := data.Translate(action.Prefix)
 	if noDur {
-		durStr = ""
+		return fmt.Sprintf("%s %s `%s`", action.Emoji, prefix, userStr)
 	}
 
-	userStr := target.String()
-	if target.Discriminator == "????" {
-		userStr = strconv.FormatInt(target.ID, 10)
+	if duration > 0 || !zeroDurPermanent {
+		return data.Translatef("%s %s `%s` for `%s`", action.Emoji, prefix, userStr, common.HumanizeDuration(common.DurationPrecisionMinutes, duration))
 	}
 
-	return fmt.Sprintf("%s %s `%s`%s", action.Emoji, action.Prefix, userStr, durStr)
+	return data.Translatef("%s %s `%s` indefinitely", action.Emoji, prefix, userStr)
 }
 
 var ModerationCommands = []*commands.YAGCommand{
@@ -213,7 +214,7 @@ var ModerationCommands = []*commands.YAGCommand{
 				return nil, err
 			}
 
-			return GenericCmdResp(MABanned, target, banDuration, true, false), nil
+			return GenericCmdResp(parsed, MABanned, target, banDuration, true, false), nil
 		},
 	},
 	{
@@ -263,7 +264,7 @@ var ModerationCommands = []*commands.YAGCommand{
 				return "User is not banned!", nil
 			}
 
-			return GenericCmdResp(MAUnbanned, target, 0, true, true), nil
+			return GenericCmdResp(parsed, MAUnbanned, target, 0, true, true), nil
 		},
 	},
 	{
@@ -328,7 +329,7 @@ var ModerationCommands = []*commands.YAGCommand{
 				return nil, err
 			}
 
-			return GenericCmdResp(MAKick, target, 0, true, true), nil
+			return GenericCmdResp(parsed, MAKick, target, 0, true, true), nil
 		},
 	},
 	{
@@ -397,7 +398,7 @@ var ModerationCommands = []*commands.YAGCommand{
 			}
 
 			common.BotSession.GuildMemberMove(parsed.GuildData.GS.ID, target.ID, 0)
-			return GenericCmdResp(MAMute, target, d, true, false), nil
+			return GenericCmdResp(parsed, MAMute, target, d, true, false), nil
 		},
 	},
 	{
@@ -454,7 +455,7 @@ var ModerationCommands = []*commands.YAGCommand{
 				return nil, err
 			}
 
-			return GenericCmdResp(MAUnmute, target, 0, false, true), nil
+			return GenericCmdResp(parsed, MAUnmute, target, 0, false, true), nil
 		},
 	},
 	{
@@ -518,7 +519,7 @@ var ModerationCommands = []*commands.YAGCommand{
 				return nil, err
 			}
 
-			return GenericCmdResp(MATimeoutAdded, target, d, true, false), nil
+			return GenericCmdResp(parsed, MATimeoutAdded, target, d, true, false), nil
 		},
 	}, {
 		CustomEnabled: true,
@@ -568,7 +569,7 @@ var ModerationCommands = []*commands.YAGCommand{
 				return nil, err
 			}
 
-			return GenericCmdResp(MATimeoutRemoved, target, 0, false, true), nil
+			return GenericCmdResp(parsed, MATimeoutRemoved, target, 0, false, true), nil
 		},
 	},
 	{
@@ -902,7 +903,7 @@ var ModerationCommands = []*commands.YAGCommand{
 				return nil, err
 			}
 
-			return GenericCmdResp(MAWarned, target, 0, false, true), nil
+			return GenericCmdResp(parsed, MAWarned, target, 0, false, true), nil
 		},
 	},
 	{
@@ -1260,7 +1261,7 @@ var ModerationCommands = []*commands.YAGCommand{
 				CreateModlogEmbed(config, parsed.Author, action, target, "", "")
 			}
 
-			return GenericCmdResp(action, target, dur, true, dur <= 0), nil
+			return GenericCmdResp(parsed, action, target, dur, true, dur <= 0), nil
 		},
 	},
 	{
@@ -1318,7 +1319,7 @@ var ModerationCommands = []*commands.YAGCommand{
 				CreateModlogEmbed(config, parsed.Author, action, target, "", "")
 			}
 
-			return GenericCmdResp(action, target, 0, true, true), nil
+			return GenericCmdResp(parsed, action, target, 0, true, true), nil
 		},
 	},
 }
@@ -1,14 +1,20 @@
 package moderation
 
 import (
+	"embed"
+
 	"emperror.dev/errors"
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 )
 
 //go:generate sqlboiler --no-hooks psql
 
+//go:embed locales
+var locales embed.FS
+
 const (
 	ActionMuted    = "Muted"
 	ActionUnMuted  = "Unmuted"
@@ -47,6 +53,7 @@ func RegisterPlugin() {
 	common.RegisterPlugin(plugin)
 
 	common.InitSchemas("moderation", DBSchemas...)
+	i18n.RegisterCatalogs(locales, "locales")
 }
 
 var _ featureflags.PluginWithFeatureFlags = (*Plugin)(nil)
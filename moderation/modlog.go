@@ -7,6 +7,7 @@ import (
 
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/outboundwebhooks"
 )
 
 type ModlogAction struct {
@@ -42,6 +43,14 @@ var (
 )
 
 func CreateModlogEmbed(config *Config, author *discordgo.User, action ModlogAction, target *discordgo.User, reason, logLink string) error {
+	outboundwebhooks.Emit(config.GuildID, outboundwebhooks.EventModerationAction, &outboundwebhooks.ModerationActionData{
+		Action:    action.Prefix,
+		Reason:    reason,
+		Target:    outboundwebhooks.UserData(target),
+		Moderator: outboundwebhooks.UserData(author),
+		LogsLink:  logLink,
+	})
+
 	channelID := config.ActionChannel
 	if channelID == 0 {
 		return nil
@@ -106,6 +106,18 @@ func HandleClearServerWarnings(w http.ResponseWriter, r *http.Request) (web.Temp
 	return templateData, nil
 }
 
+var _ web.PluginWithPublicAPI = (*Plugin)(nil)
+
+func (p *Plugin) PublicAPIResources() []*web.APIResource {
+	return []*web.APIResource{
+		{
+			Path:     "/moderation",
+			Scope:    "moderation",
+			DataKeys: []string{"ModConfig"},
+		},
+	}
+}
+
 var _ web.PluginWithServerHomeWidget = (*Plugin)(nil)
 
 func (p *Plugin) LoadServerHomeWidget(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
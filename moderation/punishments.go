@@ -14,6 +14,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
 	seventsmodels "github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2/models"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	"github.com/botlabs-gg/yagpdb/v2/common/templates"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
@@ -201,8 +202,9 @@ var ActionMap = map[string]string{
 }
 
 func sendPunishDM(config *Config, dmMsg string, action ModlogAction, gs *dstate.GuildSet, channel *dstate.ChannelState, message *discordgo.Message, author *discordgo.User, member *dstate.MemberState, duration time.Duration, reason string, warningID int, executedFromCommandTemplate bool) {
+	lang := i18n.GuildLanguage(gs.ID)
 	if dmMsg == "" {
-		dmMsg = DefaultDMMessage
+		dmMsg = i18n.T(lang, DefaultDMMessage)
 	}
 
 	// Execute and send the DM message template
@@ -218,10 +220,13 @@ func sendPunishDM(config *Config, dmMsg string, action ModlogAction, gs *dstate.
 		ctx.Data["HumanDuration"] = common.HumanizeDuration(common.DurationPrecisionMinutes, duration)
 	} else {
 		ctx.Data["Duration"] = 0
-		ctx.Data["HumanDuration"] = "never"
+		ctx.Data["HumanDuration"] = i18n.T(lang, "never")
 	}
 	ctx.Data["Author"] = author
-	ctx.Data["ModAction"] = action
+
+	dmAction := action
+	dmAction.Prefix = i18n.T(lang, action.Prefix)
+	ctx.Data["ModAction"] = dmAction
 	ctx.Data["Message"] = message
 
 	if warningID != -1 {
@@ -229,13 +234,13 @@ func sendPunishDM(config *Config, dmMsg string, action ModlogAction, gs *dstate.
 	}
 
 	if duration < 1 {
-		ctx.Data["HumanDuration"] = "permanently"
+		ctx.Data["HumanDuration"] = i18n.T(lang, "permanently")
 	}
 
 	executed, err := ctx.Execute(dmMsg)
 	if err != nil {
 		logger.WithError(err).WithField("guild", gs.ID).Warn("Failed executing punishment DM")
-		executed = "Failed executing template."
+		executed = i18n.T(lang, "Failed executing template.")
 		sendFailedDMError(gs.ID, config.ErrorChannel, fmt.Sprintf("Failed executing punishment DM (Action: `%s`).\nError: `%v`", ActionMap[action.Prefix], err))
 	}
 
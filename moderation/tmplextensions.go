@@ -14,6 +14,8 @@ import (
 )
 
 func init() {
+	templates.RegisterCallLimit("cc_moderation", templates.CallLimit{Normal: 5, Premium: 10})
+
 	templates.RegisterSetupFunc(func(ctx *templates.Context) {
 		ctx.ContextFuncs["getWarnings"] = tmplGetWarnings(ctx)
 	})
@@ -63,7 +65,7 @@ func templatesWarningFromModel(model *models.ModerationWarning) *TemplatesWarnin
 // getWarnings returns a slice of all warnings the target user has.
 func tmplGetWarnings(ctx *templates.Context) interface{} {
 	return func(target interface{}) ([]*TemplatesWarning, error) {
-		if ctx.IncreaseCheckCallCounterPremium("cc_moderation", 5, 10) {
+		if ctx.IncreaseCheckCallLimit("cc_moderation") {
 			return nil, templates.ErrTooManyCalls
 		}
 
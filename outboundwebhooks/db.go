@@ -0,0 +1,214 @@
+package outboundwebhooks
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/lib/pq"
+)
+
+// Webhook is an url events are sent to
+type Webhook struct {
+	ID         int64
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	GuildID    int64
+	URL        string
+	Secret     string
+	EventTypes []string
+	Enabled    bool
+}
+
+func (w *Webhook) SubscribedTo(eventType string) bool {
+	for _, v := range w.EventTypes {
+		if v == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	DeliveryStatusPending  = "pending"
+	DeliveryStatusRetrying = "retrying"
+	DeliveryStatusSuccess  = "success"
+	DeliveryStatusFailed   = "failed"
+)
+
+// Delivery is a single event sent to a webhook, retried until it succeeds or runs out of attempts
+type Delivery struct {
+	ID             int64
+	CreatedAt      time.Time
+	GuildID        int64
+	WebhookID      int64
+	EventType      string
+	Payload        string
+	Status         string
+	Attempts       int
+	LastAttemptAt  sql.NullTime
+	LastStatusCode int
+	LastError      string
+}
+
+const webhookColumns = "id, created_at, updated_at, guild_id, url, secret, event_types, enabled"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(row rowScanner) (*Webhook, error) {
+	w := &Webhook{}
+	err := row.Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt, &w.GuildID, &w.URL, &w.Secret, pq.Array(&w.EventTypes), &w.Enabled)
+	return w, err
+}
+
+func queryWebhooks(ctx context.Context, q string, args ...interface{}) ([]*Webhook, error) {
+	rows, err := common.PQ.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+
+	return result, rows.Err()
+}
+
+// GetWebhooks returns all the webhooks of a guild
+func GetWebhooks(ctx context.Context, guildID int64) ([]*Webhook, error) {
+	return queryWebhooks(ctx, "SELECT "+webhookColumns+" FROM outbound_webhooks WHERE guild_id = $1 ORDER BY id ASC", guildID)
+}
+
+// GetSubscribedWebhooks returns the enabled webhooks of a guild subscribed to the event
+func GetSubscribedWebhooks(ctx context.Context, guildID int64, eventType string) ([]*Webhook, error) {
+	return queryWebhooks(ctx, "SELECT "+webhookColumns+" FROM outbound_webhooks WHERE guild_id = $1 AND enabled AND $2 = ANY(event_types)", guildID, eventType)
+}
+
+// GetWebhook returns the webhook, or sql.ErrNoRows if there's no such webhook on the guild
+func GetWebhook(ctx context.Context, guildID, id int64) (*Webhook, error) {
+	return scanWebhook(common.PQ.QueryRowContext(ctx, "SELECT "+webhookColumns+" FROM outbound_webhooks WHERE guild_id = $1 AND id = $2", guildID, id))
+}
+
+func CountWebhooks(ctx context.Context, guildID int64, onlyEnabled bool) (int, error) {
+	var count int
+	err := common.PQ.QueryRowContext(ctx, "SELECT count(*) FROM outbound_webhooks WHERE guild_id = $1 AND (enabled OR NOT $2)", guildID, onlyEnabled).Scan(&count)
+	return count, err
+}
+
+func InsertWebhook(ctx context.Context, w *Webhook) error {
+	w.CreatedAt = time.Now()
+	w.UpdatedAt = w.CreatedAt
+
+	const q = `INSERT INTO outbound_webhooks (created_at, updated_at, guild_id, url, secret, event_types, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id`
+
+	return common.PQ.QueryRowContext(ctx, q, w.CreatedAt, w.UpdatedAt, w.GuildID, w.URL, w.Secret, pq.Array(w.EventTypes), w.Enabled).Scan(&w.ID)
+}
+
+func UpdateWebhook(ctx context.Context, w *Webhook) error {
+	w.UpdatedAt = time.Now()
+
+	const q = `UPDATE outbound_webhooks SET updated_at = $3, url = $4, secret = $5, event_types = $6, enabled = $7
+WHERE guild_id = $1 AND id = $2`
+
+	_, err := common.PQ.ExecContext(ctx, q, w.GuildID, w.ID, w.UpdatedAt, w.URL, w.Secret, pq.Array(w.EventTypes), w.Enabled)
+	return err
+}
+
+// DeleteWebhook deletes the webhook along with its deliveries
+func DeleteWebhook(ctx context.Context, guildID, id int64) error {
+	_, err := common.PQ.ExecContext(ctx, "DELETE FROM outbound_webhooks WHERE guild_id = $1 AND id = $2", guildID, id)
+	return err
+}
+
+const deliveryColumns = "id, created_at, guild_id, webhook_id, event_type, payload, status, attempts, last_attempt_at, last_status_code, last_error"
+
+func scanDelivery(row rowScanner) (*Delivery, error) {
+	d := &Delivery{}
+	err := row.Scan(&d.ID, &d.CreatedAt, &d.GuildID, &d.WebhookID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.LastAttemptAt, &d.LastStatusCode, &d.LastError)
+	return d, err
+}
+
+// GetDeliveries returns the latest deliveries of a guild, newest first
+func GetDeliveries(ctx context.Context, guildID int64, limit int) ([]*Delivery, error) {
+	rows, err := common.PQ.QueryContext(ctx, "SELECT "+deliveryColumns+" FROM outbound_webhook_deliveries WHERE guild_id = $1 ORDER BY id DESC LIMIT $2", guildID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, d)
+	}
+
+	return result, rows.Err()
+}
+
+// GetDelivery returns the delivery, or sql.ErrNoRows if there's no such delivery on the guild
+func GetDelivery(ctx context.Context, guildID, id int64) (*Delivery, error) {
+	return scanDelivery(common.PQ.QueryRowContext(ctx, "SELECT "+deliveryColumns+" FROM outbound_webhook_deliveries WHERE guild_id = $1 AND id = $2", guildID, id))
+}
+
+func InsertDelivery(ctx context.Context, d *Delivery) error {
+	d.CreatedAt = time.Now()
+
+	const q = `INSERT INTO outbound_webhook_deliveries (created_at, guild_id, webhook_id, event_type, payload, status)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id`
+
+	err := common.PQ.QueryRowContext(ctx, q, d.CreatedAt, d.GuildID, d.WebhookID, d.EventType, d.Payload, d.Status).Scan(&d.ID)
+	if err != nil {
+		return err
+	}
+
+	// only keep the latest deliveries around
+	const qTrim = `DELETE FROM outbound_webhook_deliveries
+WHERE guild_id = $1 AND id IN (SELECT id FROM outbound_webhook_deliveries WHERE guild_id = $1 ORDER BY id DESC OFFSET $2)`
+
+	_, err = common.PQ.ExecContext(ctx, qTrim, d.GuildID, maxLoggedDeliveries)
+	return err
+}
+
+// resetDelivery marks the delivery as pending again with no attempts, returning false if it's still being attempted
+func resetDelivery(ctx context.Context, d *Delivery) (bool, error) {
+	const q = `UPDATE outbound_webhook_deliveries SET status = $3, attempts = 0
+WHERE guild_id = $1 AND id = $2 AND status NOT IN ($3, $4)`
+
+	res, err := common.PQ.ExecContext(ctx, q, d.GuildID, d.ID, DeliveryStatusPending, DeliveryStatusRetrying)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n < 1 {
+		return false, err
+	}
+
+	d.Status = DeliveryStatusPending
+	d.Attempts = 0
+	return true, nil
+}
+
+func UpdateDelivery(ctx context.Context, d *Delivery) error {
+	const q = `UPDATE outbound_webhook_deliveries SET status = $3, attempts = $4, last_attempt_at = $5, last_status_code = $6, last_error = $7
+WHERE guild_id = $1 AND id = $2`
+
+	_, err := common.PQ.ExecContext(ctx, q, d.GuildID, d.ID, d.Status, d.Attempts, d.LastAttemptAt, d.LastStatusCode, d.LastError)
+	return err
+}
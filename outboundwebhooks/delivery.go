@@ -0,0 +1,263 @@
+package outboundwebhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
+	seventsmodels "github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2/models"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+const (
+	HeaderEvent     = "X-Yagpdb-Event"
+	HeaderDelivery  = "X-Yagpdb-Delivery"
+	HeaderTimestamp = "X-Yagpdb-Timestamp"
+
+	// HeaderSignature is "sha256=" followed by the hex encoded HMAC-SHA256 of "<timestamp>.<body>" using the webhook secret
+	HeaderSignature = "X-Yagpdb-Signature"
+)
+
+const scheduledEventDelivery = "outbound_webhook_delivery"
+
+var _ bot.BotInitHandler = (*Plugin)(nil)
+
+// ErrDeliveryInProgress is returned when redelivering a delivery that's still being attempted
+var ErrDeliveryInProgress = errors.New("delivery is still being attempted")
+
+func (p *Plugin) BotInit() {
+	scheduledevents2.RegisterHandler(scheduledEventDelivery, int64(0), handleDeliveryScheduledEvent)
+}
+
+var httpClient = &http.Client{
+	Timeout: time.Second * 10,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: time.Second * 5,
+			Control: denyPrivateAddresses,
+		}).DialContext,
+		TLSHandshakeTimeout: time.Second * 5,
+		MaxIdleConnsPerHost: 2,
+	},
+	// a redirect could point anywhere, so they're treated as failures
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// denyPrivateAddresses stops webhooks from being used to reach our internal services,
+// it's checked on the resolved address so dns can't be used to get around it
+func denyPrivateAddresses(network, address string, c syscall.RawConn) error {
+	if confAllowPrivateAddresses.GetBool() {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return errors.New("refusing to connect to non public address " + host)
+	}
+
+	return nil
+}
+
+// sharedAddressSpace is the carrier grade NAT range (RFC 6598), it's not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || sharedAddressSpace.Contains(ip))
+}
+
+// Sign returns the signature of the body sent at timestamp, the value of the signature header without the "sha256=" prefix
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns a new random secret for signing
+func GenerateSecret() string {
+	b := make([]byte, 24)
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+// retryDelay returns how long to wait before the next attempt after attempt failed,
+// doubling from 30 seconds up to an hour
+func retryDelay(attempt int) time.Duration {
+	if attempt > 8 {
+		return time.Hour
+	}
+
+	delay := time.Second * 30 << (attempt - 1)
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+
+	return delay
+}
+
+// QueueDelivery logs a delivery of the event to the webhook and schedules it to be sent right away
+func QueueDelivery(ctx context.Context, hook *Webhook, eventType string, data interface{}) (*Delivery, error) {
+	payload, err := json.Marshal(&Payload{
+		Type:      eventType,
+		GuildID:   hook.GuildID,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "marshal")
+	}
+
+	d := &Delivery{
+		GuildID:   hook.GuildID,
+		WebhookID: hook.ID,
+		EventType: eventType,
+		Payload:   string(payload),
+		Status:    DeliveryStatusPending,
+	}
+
+	err = InsertDelivery(ctx, d)
+	if err != nil {
+		return nil, errors.WithMessage(err, "insert")
+	}
+
+	err = scheduledevents2.ScheduleEvent(scheduledEventDelivery, d.GuildID, time.Now(), d.ID)
+	return d, errors.WithMessage(err, "schedule")
+}
+
+// Redeliver resets the attempts of the delivery and sends it again right away
+func Redeliver(ctx context.Context, d *Delivery) error {
+	// only one attempt can be outstanding at a time, otherwise the delivery could be sent twice
+	reset, err := resetDelivery(ctx, d)
+	if err != nil {
+		return err
+	}
+
+	if !reset {
+		return ErrDeliveryInProgress
+	}
+
+	// clear retries left behind, for example by a failed delivery of a disabled webhook
+	_, err = seventsmodels.ScheduledEvents(
+		qm.Where("event_name = ?", scheduledEventDelivery),
+		qm.Where("guild_id = ?", d.GuildID),
+		qm.Where("(data#>>'{}')::bigint = ?", d.ID),
+		qm.Where("processed = false")).DeleteAll(ctx, common.PQ)
+	if err != nil {
+		return errors.WithStackIf(err)
+	}
+
+	return scheduledevents2.ScheduleEvent(scheduledEventDelivery, d.GuildID, time.Now(), d.ID)
+}
+
+func handleDeliveryScheduledEvent(evt *seventsmodels.ScheduledEvent, data interface{}) (retry bool, err error) {
+	ctx := scheduledevents2.Context(evt)
+	id := *data.(*int64)
+
+	d, err := GetDelivery(ctx, evt.GuildID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// trimmed from the log or the webhook was deleted
+			return false, nil
+		}
+		return true, errors.WithStackIf(err)
+	}
+
+	if d.Status == DeliveryStatusSuccess || d.Status == DeliveryStatusFailed {
+		return false, nil
+	}
+
+	hook, err := GetWebhook(ctx, d.GuildID, d.WebhookID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return true, errors.WithStackIf(err)
+	}
+
+	statusCode, deliverErr := deliver(ctx, hook, d)
+
+	d.Attempts++
+	d.LastAttemptAt = sql.NullTime{Time: time.Now(), Valid: true}
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+
+	if deliverErr == nil {
+		d.Status = DeliveryStatusSuccess
+	} else {
+		d.LastError = deliverErr.Error()
+		if d.Attempts >= MaxAttempts || !hook.Enabled {
+			d.Status = DeliveryStatusFailed
+		} else {
+			d.Status = DeliveryStatusRetrying
+			err = scheduledevents2.ScheduleEvent(scheduledEventDelivery, d.GuildID, time.Now().Add(retryDelay(d.Attempts)), d.ID)
+			if err != nil {
+				logger.WithError(err).WithField("guild", d.GuildID).Error("failed scheduling outbound webhook retry")
+				d.Status = DeliveryStatusFailed
+			}
+		}
+	}
+
+	err = UpdateDelivery(ctx, d)
+	return false, errors.WithStackIf(err)
+}
+
+// deliver sends the delivery, returning the status code of the response if there was one
+func deliver(ctx context.Context, hook *Webhook, d *Delivery) (int, error) {
+	body := []byte(d.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "YAGPDB outbound webhooks")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, "sha256="+Sign(hook.Secret, timestamp, body))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// read some of it so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.New("received status " + resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
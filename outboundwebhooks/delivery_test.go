@@ -0,0 +1,71 @@
+package outboundwebhooks
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"type":"ping"}' | openssl dgst -sha256 -hmac "secret"
+	const expected = "5a2a8f7d964e86f8fb6f3a65e439891e4154c53e76bca44002f5200ba270fdf6"
+
+	got := Sign("secret", 1700000000, []byte(`{"type":"ping"}`))
+	if got != expected {
+		t.Fatalf("Sign() = %q, expected %q", got, expected)
+	}
+
+	if got == Sign("secret", 1700000001, []byte(`{"type":"ping"}`)) {
+		t.Error("expected the timestamp to be part of the signature")
+	}
+
+	if got == Sign("other", 1700000000, []byte(`{"type":"ping"}`)) {
+		t.Error("expected the secret to be part of the signature")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	testcases := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, time.Second * 30},
+		{2, time.Minute},
+		{3, time.Minute * 2},
+		{7, time.Minute * 32},
+		{8, time.Hour},
+		{100, time.Hour},
+	}
+
+	for _, v := range testcases {
+		if result := retryDelay(v.attempt); result != v.expected {
+			t.Errorf("retryDelay(%d) = %s, expected %s", v.attempt, result, v.expected)
+		}
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	testcases := []struct {
+		ip       string
+		expected bool
+	}{
+		{"1.1.1.1", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.0.0.5", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"100.128.0.1", true},
+	}
+
+	for _, v := range testcases {
+		if result := isPublicIP(net.ParseIP(v.ip)); result != v.expected {
+			t.Errorf("isPublicIP(%s) = %t, expected %t", v.ip, result, v.expected)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// outboundwebhooks sends bot side events (moderation actions, automod triggers and so on)
+// to urls configured by the guild, signed so the receiver can verify they came from us
+package outboundwebhooks
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/config"
+	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+)
+
+type Plugin struct{}
+
+func (p *Plugin) PluginInfo() *common.PluginInfo {
+	return &common.PluginInfo{
+		Name:     "Outbound Webhooks",
+		SysName:  "outbound_webhooks",
+		Category: common.PluginCategoryMisc,
+	}
+}
+
+var logger = common.GetPluginLogger(&Plugin{})
+
+var (
+	_ common.Plugin                       = (*Plugin)(nil)
+	_ featureflags.PluginWithFeatureFlags = (*Plugin)(nil)
+)
+
+const (
+	MaxWebhooks = 5
+
+	// MaxAttempts is the number of times a delivery is attempted before giving up on it
+	MaxAttempts = 8
+
+	// how many deliveries are kept in the log per guild
+	maxLoggedDeliveries = 500
+)
+
+var confAllowPrivateAddresses = config.RegisterOption("yagpdb.outbound_webhooks.allow_private_addresses", "Allow outbound webhooks to private and loopback addresses, only meant for self hosting", false)
+
+const (
+	EventModerationAction   = "moderation_action"
+	EventAutomodTriggered   = "automod_triggered"
+	EventTicketOpened       = "ticket_opened"
+	EventTicketClosed       = "ticket_closed"
+	EventReputationChanged  = "reputation_changed"
+	EventCustomCommandError = "custom_command_error"
+
+	// EventPing is sent with the test button, to any webhook regardless of the events it's subscribed to
+	EventPing = "ping"
+)
+
+type EventType struct {
+	Name        string
+	Description string
+}
+
+// EventTypes are the events webhooks can subscribe to
+var EventTypes = []*EventType{
+	{EventModerationAction, "A moderation action was taken (ban, kick, mute, warn and so on)"},
+	{EventAutomodTriggered, "An automod rule was triggered"},
+	{EventTicketOpened, "A ticket was opened"},
+	{EventTicketClosed, "A ticket was closed"},
+	{EventReputationChanged, "The reputation of a member was changed"},
+	{EventCustomCommandError, "A custom command failed with an error"},
+}
+
+func isEventType(name string) bool {
+	for _, v := range EventTypes {
+		if v.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func RegisterPlugin() {
+	common.RegisterPlugin(&Plugin{})
+
+	common.InitSchemas("outbound_webhooks", DBSchemas...)
+}
+
+const featureFlagEnabled = "outbound_webhooks_enabled"
+
+func (p *Plugin) UpdateFeatureFlags(guildID int64) ([]string, error) {
+	n, err := CountWebhooks(context.Background(), guildID, true)
+	if err != nil {
+		return nil, errors.WithStackIf(err)
+	}
+
+	var flags []string
+	if n > 0 {
+		flags = append(flags, featureFlagEnabled)
+	}
+
+	return flags, nil
+}
+
+func (p *Plugin) AllFeatureFlags() []string {
+	return []string{
+		featureFlagEnabled, // set if the guild has atleast one enabled webhook
+	}
+}
+
+// Payload is the body of the requests sent to the webhooks
+type Payload struct {
+	Type      string      `json:"type"`
+	GuildID   int64       `json:"guild_id,string"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type User struct {
+	ID       int64  `json:"id,string"`
+	Username string `json:"username"`
+}
+
+// UserData returns the webhook representation of a user, nil if u is nil
+func UserData(u *discordgo.User) *User {
+	if u == nil {
+		return nil
+	}
+
+	return &User{
+		ID:       u.ID,
+		Username: u.String(),
+	}
+}
+
+type ModerationActionData struct {
+	Action    string `json:"action"`
+	Reason    string `json:"reason"`
+	Target    *User  `json:"target"`
+	Moderator *User  `json:"moderator"`
+	LogsLink  string `json:"logs_link,omitempty"`
+}
+
+type AutomodTriggeredData struct {
+	RulesetName string `json:"ruleset_name"`
+	RuleID      int64  `json:"rule_id,string"`
+	RuleName    string `json:"rule_name"`
+	User        *User  `json:"user"`
+	ChannelID   int64  `json:"channel_id,string"`
+	ChannelName string `json:"channel_name"`
+}
+
+type TicketData struct {
+	TicketID  int64  `json:"ticket_id"`
+	ChannelID int64  `json:"channel_id,string"`
+	Title     string `json:"title"`
+	Author    *User  `json:"author"`
+
+	// only set when closed
+	ClosedBy *User  `json:"closed_by,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type ReputationChangedData struct {
+	User *User `json:"user"`
+
+	// who changed it, nil if it was reset from the dashboard
+	Sender *User `json:"sender"`
+
+	// Change is the amount given or taken, if Set is true the reputation was set to NewReputation instead
+	Change        int64 `json:"change"`
+	Set           bool  `json:"set"`
+	NewReputation int64 `json:"new_reputation"`
+}
+
+type CustomCommandErrorData struct {
+	CCID  int64  `json:"cc_id"`
+	Error string `json:"error"`
+}
+
+type PingData struct {
+	Message string `json:"message"`
+}
+
+// Emit queues a delivery of the event to all the enabled webhooks of the guild subscribed to it,
+// it's cheap to call on guilds without webhooks as it only checks a feature flag
+func Emit(guildID int64, eventType string, data interface{}) {
+	if guildID == 0 || !featureflags.GuildHasFlagOrLogError(guildID, featureFlagEnabled) {
+		return
+	}
+
+	go func() {
+		err := emit(context.Background(), guildID, eventType, data)
+		if err != nil {
+			logger.WithError(err).WithField("guild", guildID).WithField("event", eventType).Error("failed queueing outbound webhook deliveries")
+		}
+	}()
+}
+
+func emit(ctx context.Context, guildID int64, eventType string, data interface{}) error {
+	hooks, err := GetSubscribedWebhooks(ctx, guildID, eventType)
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		_, err = QueueDelivery(ctx, hook, eventType, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package outboundwebhooks
+
+var DBSchemas = []string{`
+CREATE TABLE IF NOT EXISTS outbound_webhooks (
+	id bigserial NOT NULL PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL,
+	guild_id bigint NOT NULL,
+	url text NOT NULL,
+	secret text NOT NULL,
+	event_types text[] NOT NULL,
+	enabled boolean NOT NULL DEFAULT true
+);
+`, `
+CREATE INDEX IF NOT EXISTS outbound_webhooks_guild_idx ON outbound_webhooks(guild_id);
+`, `
+CREATE TABLE IF NOT EXISTS outbound_webhook_deliveries (
+	id bigserial NOT NULL PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	guild_id bigint NOT NULL,
+	webhook_id bigint NOT NULL REFERENCES outbound_webhooks(id) ON DELETE CASCADE,
+	event_type text NOT NULL,
+	payload text NOT NULL,
+	status text NOT NULL,
+	attempts int NOT NULL DEFAULT 0,
+	last_attempt_at timestamptz,
+	last_status_code int NOT NULL DEFAULT 0,
+	last_error text NOT NULL DEFAULT ''
+);
+`, `
+CREATE INDEX IF NOT EXISTS outbound_webhook_deliveries_guild_idx ON outbound_webhook_deliveries(guild_id, id);
+`, `
+CREATE INDEX IF NOT EXISTS outbound_webhook_deliveries_webhook_idx ON outbound_webhook_deliveries(webhook_id);
+`}
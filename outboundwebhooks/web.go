@@ -0,0 +1,332 @@
+package outboundwebhooks
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/cplogs"
+	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
+	"github.com/botlabs-gg/yagpdb/v2/web"
+	"goji.io"
+	"goji.io/pat"
+)
+
+//go:embed assets/outboundwebhooks.html
+var PageHTML string
+
+var _ web.Plugin = (*Plugin)(nil)
+
+type FormWebhook struct {
+	URL        string `valid:",1,500"`
+	EventTypes []string
+	Enabled    bool
+}
+
+func (f *FormWebhook) Validate(tmpl web.TemplateData, guildID int64) bool {
+	f.URL = strings.TrimSpace(f.URL)
+	if msg := checkWebhookURL(f.URL); msg != "" {
+		tmpl.AddAlerts(web.ErrorAlert(msg))
+		return false
+	}
+
+	for _, v := range f.EventTypes {
+		if !isEventType(v) {
+			tmpl.AddAlerts(web.ErrorAlert("Unknown event type: ", v))
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkWebhookURL returns an alert message if the url can't be used for a webhook
+func checkWebhookURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "Invalid URL"
+	}
+
+	if parsed.Scheme != "https" && !(parsed.Scheme == "http" && confAllowPrivateAddresses.GetBool()) {
+		return "The URL has to use https"
+	}
+
+	return ""
+}
+
+func (f *FormWebhook) apply(w *Webhook) {
+	w.URL = f.URL
+	w.EventTypes = f.EventTypes
+	w.Enabled = f.Enabled
+}
+
+var (
+	panelLogKeyNewWebhook     = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "outbound_webhooks_new", FormatString: "Created outbound webhook %d"})
+	panelLogKeyUpdatedWebhook = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "outbound_webhooks_updated", FormatString: "Updated outbound webhook %d"})
+	panelLogKeyRemovedWebhook = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "outbound_webhooks_removed", FormatString: "Removed outbound webhook %d"})
+	panelLogKeyRotatedSecret  = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "outbound_webhooks_rotated_secret", FormatString: "Rotated the secret of outbound webhook %d"})
+)
+
+func (p *Plugin) InitWeb() {
+	web.AddHTMLTemplate("outboundwebhooks/assets/outboundwebhooks.html", PageHTML)
+
+	web.AddSidebarItem(web.SidebarCategoryTools, &web.SidebarItem{
+		Name: "Outbound Webhooks",
+		URL:  "outbound_webhooks",
+		Icon: "fas fa-paper-plane",
+	})
+
+	muxer := goji.SubMux()
+
+	web.CPMux.Handle(pat.New("/outbound_webhooks"), muxer)
+	web.CPMux.Handle(pat.New("/outbound_webhooks/*"), muxer)
+
+	getHandler := web.RenderHandler(handleGetWebhooks, "cp_outbound_webhooks")
+
+	muxer.Handle(pat.Get(""), getHandler)
+	muxer.Handle(pat.Get("/"), getHandler)
+
+	muxer.Handle(pat.Post("/new"), web.ControllerPostHandler(handleNewWebhook, getHandler, FormWebhook{}))
+	muxer.Handle(pat.Post("/:id/update"), web.ControllerPostHandler(handleUpdateWebhook, getHandler, FormWebhook{}))
+	muxer.Handle(pat.Post("/:id/delete"), web.ControllerPostHandler(handleDeleteWebhook, getHandler, nil))
+	muxer.Handle(pat.Post("/:id/rotate_secret"), web.ControllerPostHandler(handleRotateSecret, getHandler, nil))
+	muxer.Handle(pat.Post("/:id/test"), web.ControllerPostHandler(handleTestWebhook, getHandler, nil))
+	muxer.Handle(pat.Post("/deliveries/:id/redeliver"), web.ControllerPostHandler(handleRedeliver, getHandler, nil))
+}
+
+func handleGetWebhooks(w http.ResponseWriter, r *http.Request) interface{} {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	hooks, err := GetWebhooks(ctx, activeGuild.ID)
+	web.CheckErr(tmpl, err, "Failed retrieving webhooks", logger.Error)
+
+	deliveries, err := GetDeliveries(ctx, activeGuild.ID, 50)
+	web.CheckErr(tmpl, err, "Failed retrieving the delivery log", logger.Error)
+
+	// anyone with the secret can sign payloads, so read only users don't get to see it
+	if web.GetIsReadOnly(ctx) {
+		for _, hook := range hooks {
+			hook.Secret = ""
+		}
+	}
+
+	tmpl["Webhooks"] = hooks
+	tmpl["Deliveries"] = deliveries
+	tmpl["EventTypes"] = EventTypes
+	tmpl["CanAddMore"] = len(hooks) < MaxWebhooks
+	tmpl["MaxWebhooks"] = MaxWebhooks
+	tmpl["MaxAttempts"] = MaxAttempts
+
+	return tmpl
+}
+
+func parseIDParam(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(pat.Param(r, "id"), 10, 64)
+	if err != nil {
+		return 0, web.NewPublicError("Invalid ID")
+	}
+
+	return id, nil
+}
+
+func handleNewWebhook(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	form := ctx.Value(common.ContextKeyParsedForm).(*FormWebhook)
+
+	count, err := CountWebhooks(ctx, activeGuild.ID, false)
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed counting webhooks")
+	}
+
+	if count >= MaxWebhooks {
+		return tmpl.AddAlerts(web.ErrorAlert(fmt.Sprintf("Maximum of %d webhooks reached", MaxWebhooks))), nil
+	}
+
+	hook := &Webhook{
+		GuildID: activeGuild.ID,
+		Secret:  GenerateSecret(),
+	}
+	form.apply(hook)
+	hook.Enabled = true
+
+	err = InsertWebhook(ctx, hook)
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed creating webhook")
+	}
+
+	featureflags.MarkGuildDirty(activeGuild.ID)
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(ctx, panelLogKeyNewWebhook, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: hook.ID}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Webhook created!")), nil
+}
+
+// getWebhookFromParam returns the webhook in the id param, or a public error if it was not found
+func getWebhookFromParam(r *http.Request) (*Webhook, error) {
+	activeGuild, _ := web.GetBaseCPContextData(r.Context())
+
+	id, err := parseIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	hook, err := GetWebhook(r.Context(), activeGuild.ID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, web.NewPublicError("Webhook not found")
+		}
+		return nil, errors.WithMessage(err, "failed retrieving webhook")
+	}
+
+	return hook, nil
+}
+
+func handleUpdateWebhook(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	form := ctx.Value(common.ContextKeyParsedForm).(*FormWebhook)
+
+	hook, err := getWebhookFromParam(r)
+	if err != nil {
+		return tmpl, err
+	}
+
+	form.apply(hook)
+	err = UpdateWebhook(ctx, hook)
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed updating webhook")
+	}
+
+	featureflags.MarkGuildDirty(activeGuild.ID)
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(ctx, panelLogKeyUpdatedWebhook, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: hook.ID}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Webhook updated!")), nil
+}
+
+func handleDeleteWebhook(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	id, err := parseIDParam(r)
+	if err != nil {
+		return tmpl, err
+	}
+
+	err = DeleteWebhook(ctx, activeGuild.ID, id)
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed deleting webhook")
+	}
+
+	featureflags.MarkGuildDirty(activeGuild.ID)
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(ctx, panelLogKeyRemovedWebhook, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: id}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Webhook deleted!")), nil
+}
+
+func handleRotateSecret(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	_, tmpl := web.GetBaseCPContextData(ctx)
+
+	hook, err := getWebhookFromParam(r)
+	if err != nil {
+		return tmpl, err
+	}
+
+	hook.Secret = GenerateSecret()
+	err = UpdateWebhook(ctx, hook)
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed updating webhook")
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(ctx, panelLogKeyRotatedSecret, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: hook.ID}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Secret rotated, deliveries are signed with the new secret from now on")), nil
+}
+
+func handleTestWebhook(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	_, tmpl := web.GetBaseCPContextData(ctx)
+
+	hook, err := getWebhookFromParam(r)
+	if err != nil {
+		return tmpl, err
+	}
+
+	_, err = QueueDelivery(ctx, hook, EventPing, &PingData{Message: "Test delivery from the control panel"})
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed queueing test delivery")
+	}
+
+	return tmpl.AddAlerts(web.SucessAlert("Test delivery queued, refresh the page to see the result in the delivery log")), nil
+}
+
+func handleRedeliver(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	id, err := parseIDParam(r)
+	if err != nil {
+		return tmpl, err
+	}
+
+	d, err := GetDelivery(ctx, activeGuild.ID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return tmpl, web.NewPublicError("Delivery not found")
+		}
+		return tmpl, errors.WithMessage(err, "failed retrieving delivery")
+	}
+
+	if d.Status == DeliveryStatusPending || d.Status == DeliveryStatusRetrying {
+		return tmpl, web.NewPublicError("That delivery is still being attempted")
+	}
+
+	err = Redeliver(ctx, d)
+	if err == ErrDeliveryInProgress {
+		return tmpl, web.NewPublicError("That delivery is still being attempted")
+	}
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed redelivering")
+	}
+
+	return tmpl.AddAlerts(web.SucessAlert("Redelivery queued")), nil
+}
+
+var _ web.PluginWithServerHomeWidget = (*Plugin)(nil)
+
+func (p *Plugin) LoadServerHomeWidget(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ag, templateData := web.GetBaseCPContextData(r.Context())
+
+	templateData["WidgetTitle"] = "Outbound Webhooks"
+	templateData["SettingsPath"] = "/outbound_webhooks"
+
+	enabled, err := CountWebhooks(r.Context(), ag.ID, true)
+	if err != nil {
+		return templateData, err
+	}
+
+	if enabled > 0 {
+		templateData["WidgetEnabled"] = true
+	} else {
+		templateData["WidgetDisabled"] = true
+	}
+
+	format := `<ul>
+	<li>Status: %s</li>
+	<li>Enabled webhooks: <code>%d</code></li>
+</ul>`
+
+	templateData["WidgetBody"] = template.HTML(fmt.Sprintf(format, web.EnabledDisabledSpanStatus(enabled > 0), enabled))
+
+	return templateData, nil
+}
@@ -0,0 +1,237 @@
+package polls
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/lib/pq"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+type Poll struct {
+	MessageID int64
+	GuildID   int64
+	ChannelID int64
+	LocalID   int64
+	AuthorID  int64
+
+	CreatedAt time.Time
+	EndsAt    null.Time
+
+	Question     string
+	Options      []string
+	MaxChoices   int
+	Anonymous    bool
+	SelectMenu   bool
+	AllowedRoles []int64
+
+	Closed bool
+}
+
+const pollColumns = "message_id, guild_id, channel_id, local_id, author_id, created_at, ends_at, question, options, max_choices, anonymous, select_menu, allowed_roles, closed"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPoll(row rowScanner) (*Poll, error) {
+	p := &Poll{}
+	err := row.Scan(&p.MessageID, &p.GuildID, &p.ChannelID, &p.LocalID, &p.AuthorID, &p.CreatedAt, &p.EndsAt,
+		&p.Question, pq.Array(&p.Options), &p.MaxChoices, &p.Anonymous, &p.SelectMenu, pq.Array(&p.AllowedRoles), &p.Closed)
+	return p, err
+}
+
+func GetPoll(ctx context.Context, messageID int64) (*Poll, error) {
+	return scanPoll(common.PQ.QueryRowContext(ctx, "SELECT "+pollColumns+" FROM polls WHERE message_id = $1", messageID))
+}
+
+func GetPollByLocalID(ctx context.Context, guildID, localID int64) (*Poll, error) {
+	return scanPoll(common.PQ.QueryRowContext(ctx, "SELECT "+pollColumns+" FROM polls WHERE guild_id = $1 AND local_id = $2", guildID, localID))
+}
+
+func CountActivePolls(ctx context.Context, guildID int64) (int, error) {
+	var count int
+	err := common.PQ.QueryRowContext(ctx, "SELECT count(*) FROM polls WHERE guild_id = $1 AND closed = false", guildID).Scan(&count)
+	return count, err
+}
+
+func InsertPoll(ctx context.Context, p *Poll) error {
+	const q = `INSERT INTO polls (` + pollColumns + `)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err := common.PQ.ExecContext(ctx, q, p.MessageID, p.GuildID, p.ChannelID, p.LocalID, p.AuthorID, p.CreatedAt, p.EndsAt,
+		p.Question, pq.Array(p.Options), p.MaxChoices, p.Anonymous, p.SelectMenu, pq.Array(p.AllowedRoles), p.Closed)
+	return err
+}
+
+// DeletePolls deletes the polls on the provided messages along with their votes
+func DeletePolls(ctx context.Context, messageIDs []int64) error {
+	_, err := common.PQ.ExecContext(ctx, "DELETE FROM polls WHERE message_id = ANY($1)", pq.Array(messageIDs))
+	return err
+}
+
+// MarkPollClosed marks the poll as closed, returning false if it was already closed
+func MarkPollClosed(ctx context.Context, messageID int64) (bool, error) {
+	res, err := common.PQ.ExecContext(ctx, "UPDATE polls SET closed = true WHERE message_id = $1 AND closed = false", messageID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// PollResults holds the votes of a poll, Voters is sorted by the time of the vote
+type PollResults struct {
+	Counts      []int
+	Voters      [][]int64
+	TotalVoters int
+}
+
+func GetPollResults(ctx context.Context, p *Poll) (*PollResults, error) {
+	rows, err := common.PQ.QueryContext(ctx, "SELECT user_id, option_index FROM poll_votes WHERE poll_message_id = $1 ORDER BY voted_at ASC", p.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := &PollResults{
+		Counts: make([]int, len(p.Options)),
+		Voters: make([][]int64, len(p.Options)),
+	}
+
+	seen := make(map[int64]bool)
+	for rows.Next() {
+		var userID int64
+		var option int
+		err = rows.Scan(&userID, &option)
+		if err != nil {
+			return nil, err
+		}
+
+		if option < 0 || option >= len(p.Options) {
+			continue
+		}
+
+		results.Counts[option]++
+		results.Voters[option] = append(results.Voters[option], userID)
+		if !seen[userID] {
+			seen[userID] = true
+			results.TotalVoters++
+		}
+	}
+
+	return results, rows.Err()
+}
+
+func getUserVotes(ctx context.Context, exec boil.ContextExecutor, messageID, userID int64) ([]int, error) {
+	rows, err := exec.QueryContext(ctx, "SELECT option_index FROM poll_votes WHERE poll_message_id = $1 AND user_id = $2 ORDER BY option_index ASC", messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []int
+	for rows.Next() {
+		var option int
+		if err = rows.Scan(&option); err != nil {
+			return nil, err
+		}
+		result = append(result, option)
+	}
+
+	return result, rows.Err()
+}
+
+// lockPollVotes locks the poll row until the transaction ends, so concurrent vote changes are applied one at a time
+func lockPollVotes(ctx context.Context, tx *sql.Tx, messageID int64) error {
+	_, err := tx.ExecContext(ctx, "SELECT 1 FROM polls WHERE message_id = $1 FOR NO KEY UPDATE", messageID)
+	return err
+}
+
+// ToggleUserVote removes the vote of the user on option if they had one, otherwise it adds it,
+// replacing their current vote on single choice polls.
+// ok is false if the user already voted on maxChoices options, in which case nothing is changed.
+func ToggleUserVote(ctx context.Context, messageID, userID int64, option, maxChoices int) (votes []int, ok bool, err error) {
+	tx, err := common.PQ.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	err = lockPollVotes(ctx, tx, messageID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM poll_votes WHERE poll_message_id = $1 AND user_id = $2 AND option_index = $3", messageID, userID, option)
+	if err != nil {
+		return nil, false, err
+	}
+
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+
+	ok = true
+	if removed < 1 {
+		if maxChoices <= 1 {
+			_, err = tx.ExecContext(ctx, "DELETE FROM poll_votes WHERE poll_message_id = $1 AND user_id = $2", messageID, userID)
+			if err != nil {
+				return nil, false, err
+			}
+		}
+
+		res, err = tx.ExecContext(ctx, `INSERT INTO poll_votes (poll_message_id, user_id, option_index, voted_at)
+SELECT $1, $2, $3, $4 WHERE (SELECT count(*) FROM poll_votes WHERE poll_message_id = $1 AND user_id = $2) < $5`, messageID, userID, option, time.Now(), max(maxChoices, 1))
+		if err != nil {
+			return nil, false, err
+		}
+
+		added, err := res.RowsAffected()
+		if err != nil {
+			return nil, false, err
+		}
+		ok = added > 0
+	}
+
+	votes, err = getUserVotes(ctx, tx, messageID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return votes, ok, tx.Commit()
+}
+
+// SetUserVotes replaces the votes of the user with the provided options
+func SetUserVotes(ctx context.Context, messageID, userID int64, options []int) error {
+	tx, err := common.PQ.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = lockPollVotes(ctx, tx, messageID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM poll_votes WHERE poll_message_id = $1 AND user_id = $2", messageID, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, v := range options {
+		_, err = tx.ExecContext(ctx, "INSERT INTO poll_votes (poll_message_id, user_id, option_index, voted_at) VALUES ($1, $2, $3, $4)", messageID, userID, v, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
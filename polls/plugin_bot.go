@@ -0,0 +1,318 @@
+package polls
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/bot/eventsystem"
+	"github.com/botlabs-gg/yagpdb/v2/commands"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
+	seventsmodels "github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2/models"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+)
+
+var (
+	_ bot.BotInitHandler       = (*Plugin)(nil)
+	_ commands.CommandProvider = (*Plugin)(nil)
+)
+
+func (p *Plugin) BotInit() {
+	eventsystem.AddHandlerAsyncLastLegacy(p, p.handleInteractionCreate, eventsystem.EventInteractionCreate)
+	eventsystem.AddHandlerAsyncLastLegacy(p, handleMessageRemove, eventsystem.EventMessageDelete, eventsystem.EventMessageDeleteBulk)
+	scheduledevents2.RegisterHandler("polls_close", int64(0), handleScheduledClose)
+}
+
+func (p *Plugin) AddCommands() {
+	cmdCreate := &commands.YAGCommand{
+		CmdCategory:  commands.CategoryTool,
+		Name:         "Create",
+		Aliases:      []string{"new"},
+		Description:  "Creates a poll voted on with buttons. Example: `polls create \"favorite color?\" \"blue | red | pink\" -duration 1d`",
+		RequiredArgs: 2,
+		Arguments: []*dcmd.ArgDef{
+			{Name: "Question", Type: dcmd.String, Help: "The question of the poll"},
+			{Name: "Options", Type: dcmd.String, Help: fmt.Sprintf("Between 2 and %d options separated by |", MaxOptions)},
+		},
+		ArgSwitches: []*dcmd.ArgDef{
+			{Name: "duration", Type: &commands.DurationArg{}, Help: "Closes the poll after this duration"},
+			{Name: "choices", Type: &dcmd.IntArg{Min: 1, Max: MaxOptions}, Help: "Max number of options each user can pick", Default: 1},
+			{Name: "anonymous", Help: "Don't show who voted for what in the results"},
+			{Name: "select", Help: "Use a select menu instead of buttons"},
+			{Name: "role", Type: &commands.RoleArg{}, Help: "Only allow members with this role to vote"},
+		},
+		SlashCommandEnabled: true,
+		DefaultEnabled:      true,
+		Plugin:              p,
+		RunFunc: func(parsed *dcmd.Data) (interface{}, error) {
+			opts := &CreatePollOptions{
+				GuildID:    parsed.GuildData.GS.ID,
+				ChannelID:  parsed.ChannelID,
+				AuthorID:   parsed.Author.ID,
+				Question:   parsed.Args[0].Str(),
+				Options:    strings.Split(parsed.Args[1].Str(), "|"),
+				MaxChoices: parsed.Switch("choices").Int(),
+				Anonymous:  parsed.Switch("anonymous").Bool(),
+				SelectMenu: parsed.Switch("select").Bool(),
+			}
+
+			if d := parsed.Switch("duration").Value; d != nil {
+				opts.Duration = d.(time.Duration)
+			}
+
+			if r := parsed.Switch("role").Value; r != nil {
+				opts.AllowedRoles = []int64{r.(*discordgo.Role).ID}
+			}
+
+			poll, err := CreatePoll(parsed.Context(), opts)
+			if err != nil {
+				var invalid ErrInvalidPoll
+				if errors.As(err, &invalid) {
+					return invalid.Error(), nil
+				}
+				return nil, err
+			}
+
+			if parsed.TraditionalTriggerData != nil {
+				return nil, nil
+			}
+
+			return fmt.Sprintf("Created poll #%d", poll.LocalID), nil
+		},
+	}
+
+	cmdEnd := &commands.YAGCommand{
+		CmdCategory:  commands.CategoryTool,
+		Name:         "End",
+		Aliases:      []string{"close", "stop"},
+		Description:  "Closes a poll and posts the results, only the creator of the poll or members with manage messages can do this",
+		RequiredArgs: 1,
+		Arguments: []*dcmd.ArgDef{
+			{Name: "ID", Type: dcmd.Int},
+		},
+		SlashCommandEnabled: true,
+		DefaultEnabled:      true,
+		Plugin:              p,
+		RunFunc: func(parsed *dcmd.Data) (interface{}, error) {
+			poll, err := GetPollByLocalID(parsed.Context(), parsed.GuildData.GS.ID, parsed.Args[0].Int64())
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return "Unknown poll", nil
+				}
+				return nil, err
+			}
+
+			if poll.AuthorID != parsed.Author.ID {
+				ok, err := bot.AdminOrPermMS(parsed.GuildData.GS.ID, poll.ChannelID, parsed.GuildData.MS, discordgo.PermissionManageMessages)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					return "Only the creator of the poll or members with manage messages can close it", nil
+				}
+			}
+
+			if poll.Closed {
+				return "That poll is already closed", nil
+			}
+
+			err = ClosePoll(parsed.Context(), poll)
+			if err != nil {
+				return nil, err
+			}
+
+			return fmt.Sprintf("Closed poll #%d", poll.LocalID), nil
+		},
+	}
+
+	cmdResults := &commands.YAGCommand{
+		CmdCategory:  commands.CategoryTool,
+		Name:         "Results",
+		Description:  "Shows the current results of a poll",
+		RequiredArgs: 1,
+		Arguments: []*dcmd.ArgDef{
+			{Name: "ID", Type: dcmd.Int},
+		},
+		SlashCommandEnabled: true,
+		DefaultEnabled:      true,
+		Plugin:              p,
+		RunFunc: func(parsed *dcmd.Data) (interface{}, error) {
+			poll, err := GetPollByLocalID(parsed.Context(), parsed.GuildData.GS.ID, parsed.Args[0].Int64())
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return "Unknown poll", nil
+				}
+				return nil, err
+			}
+
+			results, err := GetPollResults(parsed.Context(), poll)
+			if err != nil {
+				return nil, err
+			}
+
+			return poll.ResultsEmbed(results), nil
+		},
+	}
+
+	container, _ := commands.CommandSystem.Root.Sub("polls")
+	container.NotFound = commands.CommonContainerNotFoundHandler(container, "")
+	container.Description = "Create and manage polls"
+
+	container.AddCommand(cmdCreate, cmdCreate.GetTrigger())
+	container.AddCommand(cmdEnd, cmdEnd.GetTrigger())
+	container.AddCommand(cmdResults, cmdResults.GetTrigger())
+	commands.RegisterSlashCommandsContainer(container, true, func(gs *dstate.GuildSet) ([]int64, error) {
+		return nil, nil
+	})
+}
+
+func handleScheduledClose(evt *seventsmodels.ScheduledEvent, data interface{}) (retry bool, err error) {
+	messageID := *data.(*int64)
+
+	poll, err := GetPoll(context.Background(), messageID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return true, err
+	}
+
+	err = ClosePoll(context.Background(), poll)
+	if err != nil {
+		return scheduledevents2.CheckDiscordErrRetry(err), err
+	}
+
+	return false, nil
+}
+
+func (p *Plugin) handleInteractionCreate(evt *eventsystem.EventData) {
+	ic := evt.InteractionCreate()
+	if ic.Type != discordgo.InteractionMessageComponent || ic.GuildID == 0 || ic.Member == nil || ic.Message == nil {
+		return
+	}
+
+	data := ic.MessageComponentData()
+	if data.CustomID != CustomIDSelect && !strings.HasPrefix(data.CustomID, CustomIDVotePrefix) {
+		return
+	}
+
+	ctx := context.Background()
+	poll, err := GetPoll(ctx, ic.Message.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondEphemeral(ic, "This poll no longer exists")
+			return
+		}
+		logger.WithError(err).WithField("guild", ic.GuildID).Error("failed retrieving poll")
+		return
+	}
+
+	if poll.Closed {
+		respondEphemeral(ic, "This poll is closed")
+		return
+	}
+
+	if len(poll.AllowedRoles) > 0 && !common.ContainsInt64SliceOneOf(ic.Member.Roles, poll.AllowedRoles) {
+		respondEphemeral(ic, "You're not allowed to vote in this poll")
+		return
+	}
+
+	var votes []int
+	if data.CustomID == CustomIDSelect {
+		for _, v := range data.Values {
+			option, err := strconv.Atoi(v)
+			if err != nil || option < 0 || option >= len(poll.Options) {
+				continue
+			}
+			votes = append(votes, option)
+		}
+
+		if len(votes) > poll.MaxChoices {
+			votes = votes[:poll.MaxChoices]
+		}
+
+		err = SetUserVotes(ctx, poll.MessageID, ic.Member.User.ID, votes)
+		if err != nil {
+			logger.WithError(err).WithField("guild", ic.GuildID).Error("failed saving poll votes")
+			return
+		}
+	} else {
+		option, err := strconv.Atoi(strings.TrimPrefix(data.CustomID, CustomIDVotePrefix))
+		if err != nil || option < 0 || option >= len(poll.Options) {
+			return
+		}
+
+		var ok bool
+		votes, ok, err = ToggleUserVote(ctx, poll.MessageID, ic.Member.User.ID, option, poll.MaxChoices)
+		if err != nil {
+			logger.WithError(err).WithField("guild", ic.GuildID).Error("failed saving poll votes")
+			return
+		}
+
+		if !ok {
+			respondEphemeral(ic, fmt.Sprintf("You can pick max %d choices, remove one of your votes first by clicking it again", poll.MaxChoices))
+			return
+		}
+	}
+
+	if len(votes) < 1 {
+		respondEphemeral(ic, "Removed your vote")
+	} else {
+		picked := make([]string, 0, len(votes))
+		for _, v := range votes {
+			picked = append(picked, "`"+poll.Options[v]+"`")
+		}
+		respondEphemeral(ic, "You voted for "+strings.Join(picked, ", "))
+	}
+
+	results, err := GetPollResults(ctx, poll)
+	if err != nil {
+		logger.WithError(err).WithField("guild", ic.GuildID).Error("failed retrieving poll results")
+		return
+	}
+
+	_, err = common.BotSession.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    poll.ChannelID,
+		ID:         poll.MessageID,
+		Embeds:     []*discordgo.MessageEmbed{poll.Embed(results)},
+		Components: poll.Components(),
+	})
+	if err != nil {
+		logger.WithError(err).WithField("guild", ic.GuildID).Warn("failed updating poll message")
+	}
+}
+
+func handleMessageRemove(evt *eventsystem.EventData) {
+	var messageIDs []int64
+	if evt.Type == eventsystem.EventMessageDelete {
+		messageIDs = []int64{evt.MessageDelete().Message.ID}
+	} else {
+		messageIDs = evt.MessageDeleteBulk().Messages
+	}
+
+	err := DeletePolls(evt.Context(), messageIDs)
+	if err != nil {
+		logger.WithError(err).WithField("guild", evt.GS.ID).Error("failed deleting polls of removed messages")
+	}
+}
+
+func respondEphemeral(ic *discordgo.InteractionCreate, msg string) {
+	err := common.BotSession.CreateInteractionResponse(ic.ID, ic.Token, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: msg,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		logger.WithError(err).WithField("guild", ic.GuildID).Warn("failed responding to poll interaction")
+	}
+}
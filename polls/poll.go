@@ -0,0 +1,359 @@
+package polls
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/premium"
+	"github.com/volatiletech/null/v8"
+)
+
+const (
+	CustomIDVotePrefix = "polls_vote_"
+	CustomIDSelect     = "polls_select"
+
+	chartBarWidth = 20
+)
+
+var optionEmojis = [...]string{"1⃣", "2⃣", "3⃣", "4⃣", "5⃣", "6⃣", "7⃣", "8⃣", "9⃣", "🔟"}
+
+// ErrInvalidPoll is returned for user errors when creating a poll, the message is safe to show to users
+type ErrInvalidPoll string
+
+func (e ErrInvalidPoll) Error() string {
+	return string(e)
+}
+
+type CreatePollOptions struct {
+	GuildID   int64
+	ChannelID int64
+	AuthorID  int64
+
+	Question string
+	Options  []string
+
+	// Zero means the poll stays open until closed manually
+	Duration time.Duration
+
+	// Zero or one means single choice
+	MaxChoices   int
+	Anonymous    bool
+	SelectMenu   bool
+	AllowedRoles []int64
+}
+
+func (o *CreatePollOptions) validate() error {
+	o.Question = strings.TrimSpace(o.Question)
+	if o.Question == "" {
+		return ErrInvalidPoll("The poll needs a question")
+	}
+
+	if utf8.RuneCountInString(o.Question) > MaxQuestionLength {
+		return ErrInvalidPoll(fmt.Sprintf("The question can be max %d characters long", MaxQuestionLength))
+	}
+
+	options := make([]string, 0, len(o.Options))
+	for _, v := range o.Options {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(v) > MaxOptionLength {
+			return ErrInvalidPoll(fmt.Sprintf("Options can be max %d characters long", MaxOptionLength))
+		}
+		options = append(options, v)
+	}
+	o.Options = options
+
+	if len(o.Options) < 2 || len(o.Options) > MaxOptions {
+		return ErrInvalidPoll(fmt.Sprintf("A poll needs between 2 and %d options", MaxOptions))
+	}
+
+	if o.MaxChoices < 1 {
+		o.MaxChoices = 1
+	}
+	if o.MaxChoices > len(o.Options) {
+		o.MaxChoices = len(o.Options)
+	}
+
+	if o.Duration < 0 || o.Duration > MaxPollDuration {
+		return ErrInvalidPoll("Polls can stay open for max 31 days")
+	}
+
+	return nil
+}
+
+// CreatePoll sends the poll message, saves the poll and schedules it to be closed
+func CreatePoll(ctx context.Context, opts *CreatePollOptions) (*Poll, error) {
+	err := opts.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := CountActivePolls(ctx, opts.GuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := MaxActivePolls
+	if isPremium, _ := premium.IsGuildPremium(opts.GuildID); isPremium {
+		limit = MaxActivePollsPrem
+	}
+	if count >= limit {
+		return nil, ErrInvalidPoll(fmt.Sprintf("Max %d active polls at a time", limit))
+	}
+
+	localID, err := common.GenLocalIncrID(opts.GuildID, "poll")
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Poll{
+		GuildID:      opts.GuildID,
+		ChannelID:    opts.ChannelID,
+		LocalID:      localID,
+		AuthorID:     opts.AuthorID,
+		CreatedAt:    time.Now(),
+		Question:     opts.Question,
+		Options:      opts.Options,
+		MaxChoices:   opts.MaxChoices,
+		Anonymous:    opts.Anonymous,
+		SelectMenu:   opts.SelectMenu,
+		AllowedRoles: opts.AllowedRoles,
+	}
+
+	if p.AllowedRoles == nil {
+		p.AllowedRoles = []int64{}
+	}
+
+	if opts.Duration > 0 {
+		p.EndsAt = null.TimeFrom(p.CreatedAt.Add(opts.Duration))
+	}
+
+	emptyResults := &PollResults{Counts: make([]int, len(p.Options)), Voters: make([][]int64, len(p.Options))}
+	msg, err := common.BotSession.ChannelMessageSendComplex(p.ChannelID, &discordgo.MessageSend{
+		Embeds:          []*discordgo.MessageEmbed{p.Embed(emptyResults)},
+		Components:      p.Components(),
+		AllowedMentions: discordgo.AllowedMentions{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.MessageID = msg.ID
+	err = InsertPoll(ctx, p)
+	if err != nil {
+		common.BotSession.ChannelMessageDelete(p.ChannelID, p.MessageID)
+		return nil, err
+	}
+
+	if p.EndsAt.Valid {
+		err = scheduledevents2.ScheduleEvent("polls_close", p.GuildID, p.EndsAt.Time, p.MessageID)
+		if err != nil {
+			logger.WithError(err).WithField("guild", p.GuildID).Error("failed scheduling poll close")
+		}
+	}
+
+	return p, nil
+}
+
+// ClosePoll closes the poll, disabling voting and posting the results
+func ClosePoll(ctx context.Context, p *Poll) error {
+	closed, err := MarkPollClosed(ctx, p.MessageID)
+	if err != nil || !closed {
+		return err
+	}
+	p.Closed = true
+
+	results, err := GetPollResults(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	_, err = common.BotSession.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    p.ChannelID,
+		ID:         p.MessageID,
+		Embeds:     []*discordgo.MessageEmbed{p.Embed(results)},
+		Components: p.Components(),
+	})
+	if err != nil {
+		logger.WithError(err).WithField("guild", p.GuildID).Warn("failed updating closed poll message")
+	}
+
+	_, err = common.BotSession.ChannelMessageSendComplex(p.ChannelID, &discordgo.MessageSend{
+		Embeds:          []*discordgo.MessageEmbed{p.ResultsEmbed(results)},
+		Reference:       &discordgo.MessageReference{MessageID: p.MessageID, ChannelID: p.ChannelID, GuildID: p.GuildID},
+		AllowedMentions: discordgo.AllowedMentions{},
+	})
+	return err
+}
+
+func (p *Poll) choiceDescription() string {
+	if p.MaxChoices <= 1 {
+		return "Single choice"
+	}
+	return fmt.Sprintf("Up to %d choices", p.MaxChoices)
+}
+
+// Embed returns the embed of the poll message with the current vote counts
+func (p *Poll) Embed(results *PollResults) *discordgo.MessageEmbed {
+	var description strings.Builder
+	for i, v := range p.Options {
+		fmt.Fprintf(&description, "%s %s **(%d)**\n", optionEmojis[i], v, results.Counts[i])
+	}
+
+	if len(p.AllowedRoles) > 0 {
+		mentions := make([]string, 0, len(p.AllowedRoles))
+		for _, v := range p.AllowedRoles {
+			mentions = append(mentions, "<@&"+strconv.FormatInt(v, 10)+">")
+		}
+		fmt.Fprintf(&description, "\nOnly %s can vote", strings.Join(mentions, ", "))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       p.Question,
+		Description: description.String(),
+		Color:       0x65f442,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Poll #%d • %s", p.LocalID, p.choiceDescription()),
+		},
+	}
+
+	if p.Anonymous {
+		embed.Footer.Text += " • Anonymous"
+	}
+
+	status := "Open until closed with `polls end " + strconv.FormatInt(p.LocalID, 10) + "`"
+	if p.Closed {
+		status = "Closed"
+		embed.Color = 0x808080
+	} else if p.EndsAt.Valid {
+		status = fmt.Sprintf("Ends <t:%d:R>", p.EndsAt.Time.Unix())
+	}
+
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  fmt.Sprintf("%d voters", results.TotalVoters),
+		Value: status,
+	})
+
+	return embed
+}
+
+// ResultsEmbed returns the embed with the results bar chart posted when the poll closes
+func (p *Poll) ResultsEmbed(results *PollResults) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "Results: " + p.Question,
+		Description: ResultsChart(p.Options, results.Counts),
+		Color:       0x65f442,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Poll #%d • %d voters", p.LocalID, results.TotalVoters),
+		},
+	}
+
+	if p.Anonymous {
+		return embed
+	}
+
+	for i, voters := range results.Voters {
+		if len(voters) < 1 {
+			continue
+		}
+
+		mentions := make([]string, 0, 10)
+		for j, v := range voters {
+			if j >= 10 {
+				mentions = append(mentions, fmt.Sprintf("and %d more", len(voters)-j))
+				break
+			}
+			mentions = append(mentions, "<@"+strconv.FormatInt(v, 10)+">")
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  optionEmojis[i] + " " + p.Options[i],
+			Value: strings.Join(mentions, ", "),
+		})
+	}
+
+	return embed
+}
+
+// ResultsChart renders a text bar chart of the results
+func ResultsChart(options []string, counts []int) string {
+	total := 0
+	for _, v := range counts {
+		total += v
+	}
+
+	var b strings.Builder
+	for i, option := range options {
+		percent := 0
+		filled := 0
+		if total > 0 {
+			percent = counts[i] * 100 / total
+			filled = counts[i] * chartBarWidth / total
+		}
+
+		fmt.Fprintf(&b, "%s %s\n`%s%s` %d%% (%d)\n", optionEmojis[i], option,
+			strings.Repeat("█", filled), strings.Repeat("░", chartBarWidth-filled), percent, counts[i])
+	}
+
+	return b.String()
+}
+
+// Components returns the vote buttons or select menu of the poll, disabled if the poll is closed
+func (p *Poll) Components() []discordgo.TopLevelComponent {
+	if p.SelectMenu {
+		minValues := 0
+		menu := discordgo.SelectMenu{
+			CustomID:    CustomIDSelect,
+			Placeholder: "Pick your choice",
+			MinValues:   &minValues,
+			MaxValues:   p.MaxChoices,
+			Disabled:    p.Closed,
+		}
+		if p.MaxChoices > 1 {
+			menu.Placeholder = fmt.Sprintf("Pick up to %d choices", p.MaxChoices)
+		}
+
+		for i, v := range p.Options {
+			menu.Options = append(menu.Options, discordgo.SelectMenuOption{
+				Label: v,
+				Value: strconv.Itoa(i),
+				Emoji: &discordgo.ComponentEmoji{Name: optionEmojis[i]},
+			})
+		}
+
+		return []discordgo.TopLevelComponent{discordgo.ActionsRow{Components: []discordgo.InteractiveComponent{menu}}}
+	}
+
+	var rows []discordgo.TopLevelComponent
+	var current []discordgo.InteractiveComponent
+	for i, v := range p.Options {
+		current = append(current, discordgo.Button{
+			Label:    v,
+			Style:    discordgo.SecondaryButton,
+			Emoji:    &discordgo.ComponentEmoji{Name: optionEmojis[i]},
+			CustomID: CustomIDVotePrefix + strconv.Itoa(i),
+			Disabled: p.Closed,
+		})
+
+		if len(current) == 5 {
+			rows = append(rows, discordgo.ActionsRow{Components: current})
+			current = nil
+		}
+	}
+
+	if len(current) > 0 {
+		rows = append(rows, discordgo.ActionsRow{Components: current})
+	}
+
+	return rows
+}
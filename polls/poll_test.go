@@ -0,0 +1,27 @@
+package polls
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResultsChart(t *testing.T) {
+	chart := ResultsChart([]string{"red", "blue"}, []int{3, 1})
+	lines := strings.Split(strings.TrimSpace(chart), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d: %q", len(lines), chart)
+	}
+
+	if !strings.HasSuffix(lines[1], "75% (3)") || strings.Count(lines[1], "█") != 15 {
+		t.Errorf("unexpected bar for red: %q", lines[1])
+	}
+
+	if !strings.HasSuffix(lines[3], "25% (1)") || strings.Count(lines[3], "█") != 5 {
+		t.Errorf("unexpected bar for blue: %q", lines[3])
+	}
+
+	empty := ResultsChart([]string{"red", "blue"}, []int{0, 0})
+	if strings.Contains(empty, "█") {
+		t.Errorf("expected empty bars without votes: %q", empty)
+	}
+}
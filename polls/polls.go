@@ -0,0 +1,38 @@
+// polls is a plugin for persisted interactive polls, voted on with buttons or select menus
+package polls
+
+import (
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+)
+
+type Plugin struct{}
+
+func (p *Plugin) PluginInfo() *common.PluginInfo {
+	return &common.PluginInfo{
+		Name:     "Polls",
+		SysName:  "polls",
+		Category: common.PluginCategoryMisc,
+	}
+}
+
+var logger = common.GetPluginLogger(&Plugin{})
+
+const (
+	MaxOptions         = 10
+	MaxOptionLength    = 80
+	MaxQuestionLength  = 256
+	MaxActivePolls     = 25
+	MaxActivePollsPrem = 100
+
+	// Longest time a poll can stay open for
+	MaxPollDuration = time.Hour * 24 * 31
+)
+
+func RegisterPlugin() {
+	p := &Plugin{}
+
+	common.InitSchemas("polls", DBSchemas...)
+	common.RegisterPlugin(p)
+}
@@ -0,0 +1,36 @@
+package polls
+
+var DBSchemas = []string{`
+CREATE TABLE IF NOT EXISTS polls (
+	message_id BIGINT PRIMARY KEY,
+
+	guild_id BIGINT NOT NULL,
+	channel_id BIGINT NOT NULL,
+	local_id BIGINT NOT NULL,
+	author_id BIGINT NOT NULL,
+
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	ends_at TIMESTAMP WITH TIME ZONE,
+
+	question TEXT NOT NULL,
+	options TEXT[] NOT NULL,
+	max_choices INT NOT NULL,
+	anonymous BOOLEAN NOT NULL,
+	select_menu BOOLEAN NOT NULL,
+	allowed_roles BIGINT[] NOT NULL,
+
+	closed BOOLEAN NOT NULL DEFAULT false
+);
+`, `
+CREATE UNIQUE INDEX IF NOT EXISTS polls_guild_local_id_idx ON polls(guild_id, local_id);
+`, `
+CREATE TABLE IF NOT EXISTS poll_votes (
+	poll_message_id BIGINT NOT NULL REFERENCES polls(message_id) ON DELETE CASCADE,
+	user_id BIGINT NOT NULL,
+	option_index INT NOT NULL,
+
+	voted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+	PRIMARY KEY(poll_message_id, user_id, option_index)
+);
+`}
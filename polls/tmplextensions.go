@@ -0,0 +1,196 @@
+package polls
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common/templates"
+)
+
+func init() {
+	templates.RegisterCallLimit("create_poll", templates.CallLimit{Normal: 1, Premium: 3})
+	templates.RegisterCallLimit("get_poll", templates.CallLimit{Normal: 5, Premium: 5})
+	templates.RegisterCallLimit("end_poll", templates.CallLimit{Normal: 2, Premium: 2})
+
+	templates.RegisterSetupFunc(func(ctx *templates.Context) {
+		ctx.ContextFuncs["createPoll"] = tmplCreatePoll(ctx)
+		ctx.ContextFuncs["getPoll"] = tmplGetPoll(ctx)
+		ctx.ContextFuncs["endPoll"] = tmplEndPoll(ctx)
+	})
+}
+
+// TemplatePoll is the representation of a poll exposed to custom commands
+type TemplatePoll struct {
+	ID        int64
+	MessageID int64
+	ChannelID int64
+	AuthorID  int64
+
+	Question   string
+	Options    []string
+	MaxChoices int
+	Anonymous  bool
+	EndsAt     *time.Time
+	Closed     bool
+
+	Counts      []int
+	TotalVoters int
+
+	// Nil for anonymous polls
+	Voters [][]int64
+}
+
+func newTemplatePoll(p *Poll, results *PollResults) *TemplatePoll {
+	tp := &TemplatePoll{
+		ID:         p.LocalID,
+		MessageID:  p.MessageID,
+		ChannelID:  p.ChannelID,
+		AuthorID:   p.AuthorID,
+		Question:   p.Question,
+		Options:    p.Options,
+		MaxChoices: p.MaxChoices,
+		Anonymous:  p.Anonymous,
+		Closed:     p.Closed,
+	}
+
+	if p.EndsAt.Valid {
+		tp.EndsAt = &p.EndsAt.Time
+	}
+
+	if results != nil {
+		tp.Counts = results.Counts
+		tp.TotalVoters = results.TotalVoters
+		if !p.Anonymous {
+			tp.Voters = results.Voters
+		}
+	}
+
+	return tp
+}
+
+// tmplCreatePoll creates a poll in the provided channel, optional settings are passed as a sdict
+// or key-value pairs: duration (seconds or duration string), choices, anonymous, selectMenu and roles
+func tmplCreatePoll(ctx *templates.Context) interface{} {
+	return func(channel interface{}, question string, options interface{}, optionalArgs ...interface{}) (*TemplatePoll, error) {
+		if ctx.IncreaseCheckCallLimit("create_poll") {
+			return nil, templates.ErrTooManyCalls
+		}
+
+		cID := ctx.ChannelArgNoDM(channel)
+		if cID == 0 {
+			return nil, errors.New("unknown channel")
+		}
+
+		opts := &CreatePollOptions{
+			GuildID:   ctx.GS.ID,
+			ChannelID: cID,
+			Question:  question,
+		}
+
+		if ctx.MS != nil {
+			opts.AuthorID = ctx.MS.User.ID
+		}
+
+		optionsVal := reflect.ValueOf(options)
+		if optionsVal.Kind() != reflect.Slice {
+			return nil, errors.New("options has to be a slice")
+		}
+		for i := 0; i < optionsVal.Len(); i++ {
+			opts.Options = append(opts.Options, templates.ToString(optionsVal.Index(i).Interface()))
+		}
+
+		if len(optionalArgs) > 0 {
+			settings, err := templates.StringKeyDictionary(optionalArgs...)
+			if err != nil {
+				return nil, err
+			}
+
+			for k, v := range settings {
+				switch k {
+				case "duration":
+					opts.Duration = tmplDuration(v)
+				case "choices":
+					opts.MaxChoices = int(templates.ToInt64(v))
+				case "anonymous":
+					opts.Anonymous = v == true
+				case "selectMenu":
+					opts.SelectMenu = v == true
+				case "roles":
+					rolesVal := reflect.ValueOf(v)
+					if rolesVal.Kind() != reflect.Slice {
+						return nil, errors.New("roles has to be a slice of role IDs")
+					}
+					for i := 0; i < rolesVal.Len(); i++ {
+						roleID := templates.ToInt64(rolesVal.Index(i).Interface())
+						if ctx.GS.GetRole(roleID) == nil {
+							return nil, errors.New("unknown role")
+						}
+						opts.AllowedRoles = append(opts.AllowedRoles, roleID)
+					}
+				default:
+					return nil, errors.New("unknown poll setting: " + k)
+				}
+			}
+		}
+
+		p, err := CreatePoll(context.Background(), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return newTemplatePoll(p, nil), nil
+	}
+}
+
+func tmplDuration(v interface{}) time.Duration {
+	switch t := v.(type) {
+	case string, time.Duration:
+		return templates.ToDuration(t)
+	default:
+		return time.Duration(templates.ToInt64(t)) * time.Second
+	}
+}
+
+func tmplGetPoll(ctx *templates.Context) interface{} {
+	return func(id interface{}) (*TemplatePoll, error) {
+		if ctx.IncreaseCheckCallLimit("get_poll") {
+			return nil, templates.ErrTooManyCalls
+		}
+
+		p, err := GetPollByLocalID(context.Background(), ctx.GS.ID, templates.ToInt64(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		results, err := GetPollResults(context.Background(), p)
+		if err != nil {
+			return nil, err
+		}
+
+		return newTemplatePoll(p, results), nil
+	}
+}
+
+func tmplEndPoll(ctx *templates.Context) interface{} {
+	return func(id interface{}) (string, error) {
+		if ctx.IncreaseCheckCallLimit("end_poll") {
+			return "", templates.ErrTooManyCalls
+		}
+
+		p, err := GetPollByLocalID(context.Background(), ctx.GS.ID, templates.ToInt64(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return "", errors.New("unknown poll")
+			}
+			return "", err
+		}
+
+		return "", ClosePoll(context.Background(), p)
+	}
+}
@@ -0,0 +1,148 @@
+package premium
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/config"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/premium/models"
+)
+
+var (
+	confExpiryWarnings = config.RegisterOption("yagpdb.premium.expiry_warnings", "Comma separated durations before a timed premium slot expires to DM the owner at, empty to disable", "168h,24h,1h")
+)
+
+// ExpiryWarningIntervals returns the configured warning intervals, sorted from longest to shortest
+func ExpiryWarningIntervals() []time.Duration {
+	return parseExpiryWarningIntervals(confExpiryWarnings.GetString())
+}
+
+func parseExpiryWarningIntervals(s string) []time.Duration {
+	var result []time.Duration
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			logger.Warnf("Invalid premium expiry warning interval: %q", v)
+			continue
+		}
+
+		if !slices.Contains(result, d) {
+			result = append(result, d)
+		}
+	}
+
+	slices.Sort(result)
+	slices.Reverse(result)
+	return result
+}
+
+// dueExpiryWarnings returns the warning intervals the slot has passed without being warned about,
+// only the last (shortest) one should be sent as the others are outdated
+func dueExpiryWarnings(left time.Duration, intervals []time.Duration, sent []time.Duration) []time.Duration {
+	var due []time.Duration
+	for _, v := range intervals {
+		if left <= v && !slices.Contains(sent, v) {
+			due = append(due, v)
+		}
+	}
+
+	return due
+}
+
+func slotSentExpiryWarnings(ctx context.Context, slotID int64) ([]time.Duration, error) {
+	rows, err := common.PQ.QueryContext(ctx, "SELECT warning FROM premium_slot_expiry_warnings WHERE slot_id = $1", slotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []time.Duration
+	for rows.Next() {
+		var d int64
+		if err = rows.Scan(&d); err != nil {
+			return nil, err
+		}
+		result = append(result, time.Duration(d))
+	}
+
+	return result, rows.Err()
+}
+
+func markExpiryWarningsSent(ctx context.Context, slotID int64, warnings []time.Duration) error {
+	now := time.Now()
+	for _, v := range warnings {
+		_, err := common.PQ.ExecContext(ctx, "INSERT INTO premium_slot_expiry_warnings (slot_id, warning, sent_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING", slotID, int64(v), now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkExpiryWarnings DMs the owners of the timed slots that are about to expire
+func checkExpiryWarnings(ctx context.Context, slots []*models.PremiumSlot) error {
+	intervals := ExpiryWarningIntervals()
+	if len(intervals) < 1 {
+		return nil
+	}
+
+	for _, slot := range slots {
+		left := SlotDurationLeft(slot)
+		// slots only run out while attached to a server
+		if slot.Permanent || !slot.GuildID.Valid || left <= 0 || left > intervals[0] {
+			continue
+		}
+
+		sent, err := slotSentExpiryWarnings(ctx, slot.ID)
+		if err != nil {
+			return errors.WithMessage(err, "slotSentExpiryWarnings")
+		}
+
+		due := dueExpiryWarnings(left, intervals, sent)
+		if len(due) < 1 {
+			continue
+		}
+
+		// mark them before sending so a failing DM doesn't get retried every minute
+		err = markExpiryWarningsSent(ctx, slot.ID, due)
+		if err != nil {
+			return errors.WithMessage(err, "markExpiryWarningsSent")
+		}
+
+		go SendPremiumExpiryDM(slot, left)
+	}
+
+	return nil
+}
+
+// SendPremiumExpiryDM warns the owner of the slot that it expires soon, or that it has expired if left is 0 or below
+func SendPremiumExpiryDM(slot *models.PremiumSlot, left time.Duration) {
+	embed := &discordgo.MessageEmbed{Color: 0xf1c40f}
+	if left <= 0 {
+		embed.Title = "Your Premium Slot expired"
+		embed.Description = fmt.Sprintf("Your premium slot **%s** has run out and was removed.", slot.Title)
+	} else {
+		embed.Title = "Your Premium Slot expires soon"
+		embed.Description = fmt.Sprintf("Your premium slot **%s** attached to the server with the ID `%d` expires in %s.", slot.Title, slot.GuildID.Int64, common.HumanizeDuration(common.DurationPrecisionMinutes, left))
+	}
+	embed.Description += fmt.Sprintf("\n\n[Manage your premium slots here.](https://%s/premium)", common.ConfHost.GetString())
+
+	logger.Infof("Sending premium expiry DM to user: %d for slot #%d, %s left", slot.UserID, slot.ID, left)
+	err := bot.SendDMEmbed(slot.UserID, embed)
+	if err != nil {
+		logger.WithError(err).Error("Failed sending premium expiry DM")
+	}
+}
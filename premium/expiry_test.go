@@ -0,0 +1,45 @@
+package premium
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseExpiryWarningIntervals(t *testing.T) {
+	result := parseExpiryWarningIntervals("1h, 168h,24h,,1h,-5m,abc")
+	expected := []time.Duration{168 * time.Hour, 24 * time.Hour, time.Hour}
+	if !slices.Equal(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+
+	if result := parseExpiryWarningIntervals(""); len(result) != 0 {
+		t.Errorf("expected no intervals, got %v", result)
+	}
+}
+
+func TestDueExpiryWarnings(t *testing.T) {
+	intervals := []time.Duration{168 * time.Hour, 24 * time.Hour, time.Hour}
+
+	testcases := []struct {
+		name     string
+		left     time.Duration
+		sent     []time.Duration
+		expected []time.Duration
+	}{
+		{name: "not yet", left: 200 * time.Hour, expected: nil},
+		{name: "first warning", left: 100 * time.Hour, expected: []time.Duration{168 * time.Hour}},
+		{name: "already sent", left: 100 * time.Hour, sent: []time.Duration{168 * time.Hour}, expected: nil},
+		{name: "second warning", left: 23 * time.Hour, sent: []time.Duration{168 * time.Hour}, expected: []time.Duration{24 * time.Hour}},
+		{name: "skipped warnings", left: 30 * time.Minute, expected: []time.Duration{168 * time.Hour, 24 * time.Hour, time.Hour}},
+	}
+
+	for _, v := range testcases {
+		t.Run(v.name, func(t *testing.T) {
+			result := dueExpiryWarnings(v.left, intervals, v.sent)
+			if !slices.Equal(result, v.expected) {
+				t.Errorf("expected %v, got %v", v.expected, result)
+			}
+		})
+	}
+}
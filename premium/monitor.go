@@ -159,6 +159,11 @@ func checkExpiredSlots(ctx context.Context) error {
 		return errors.WithMessage(err, "models.PremiumSlots")
 	}
 
+	err = checkExpiryWarnings(ctx, timedSlots)
+	if err != nil {
+		logger.WithError(err).Error("Failed sending premium slot expiry warnings")
+	}
+
 	for _, v := range timedSlots {
 		if SlotDurationLeft(v) <= 0 {
 			tx, err := common.PQ.BeginTx(ctx, nil)
@@ -174,6 +179,10 @@ func checkExpiredSlots(ctx context.Context) error {
 			if err != nil {
 				return errors.WithMessage(err, "Commit")
 			}
+
+			if len(ExpiryWarningIntervals()) > 0 {
+				go SendPremiumExpiryDM(v, 0)
+			}
 		}
 	}
 
@@ -2,9 +2,12 @@ package premium
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/bot/eventsystem"
 	"github.com/botlabs-gg/yagpdb/v2/commands"
 	"github.com/botlabs-gg/yagpdb/v2/common"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
@@ -108,11 +111,124 @@ func premiumActionsRow(discordURL, patreonURL, perksURL, manageURL string) disco
 	}}
 }
 
+var cmdTransferSlot = &commands.YAGCommand{
+	CmdCategory:  commands.CategoryGeneral,
+	Name:         "TransferPremiumSlot",
+	Aliases:      []string{"giftpremium", "giftslot"},
+	Description:  "Gives one of your premium slots from a redeemed code to another user, the slot is detached from its server. You can find the slot IDs on the premium page.",
+	RequiredArgs: 2,
+	RunInDM:      true,
+	Arguments: []*dcmd.ArgDef{
+		{Name: "SlotID", Type: dcmd.Int},
+		{Name: "User", Type: dcmd.UserID},
+	},
+	RunFunc: func(data *dcmd.Data) (interface{}, error) {
+		slotID := data.Args[0].Int64()
+		toUserID := data.Args[1].Int64()
+		if toUserID == data.Author.ID {
+			return "You can't transfer a slot to yourself", nil
+		}
+
+		slot, err := models.PremiumSlots(qm.Where("id = ? AND user_id = ?", slotID, data.Author.ID)).OneG(data.Context())
+		if err != nil {
+			return "You don't have a premium slot with that ID", nil
+		}
+
+		if slot.Source != string(PremiumSourceTypeCode) || slot.DeletesAt.Valid {
+			return "Only slots from redeemed codes can be transferred", nil
+		}
+
+		confirmID := fmt.Sprintf("%s%d:%d:%d", transferConfirmPrefix, data.Author.ID, slot.ID, toUserID)
+		return &discordgo.MessageSend{
+			Content: fmt.Sprintf("Are you sure you want to give the premium slot **%s** to <@%d>? This can't be undone.", slot.Title, toUserID),
+			Components: []discordgo.TopLevelComponent{discordgo.ActionsRow{Components: []discordgo.InteractiveComponent{
+				discordgo.Button{Style: discordgo.DangerButton, Label: "Transfer slot", CustomID: confirmID},
+				discordgo.Button{Style: discordgo.SecondaryButton, Label: "Cancel", CustomID: transferCancelPrefix + strconv.FormatInt(data.Author.ID, 10)},
+			}}},
+			AllowedMentions: discordgo.AllowedMentions{},
+		}, nil
+	},
+}
+
+const (
+	transferConfirmPrefix = "premium_transfer_confirm:"
+	transferCancelPrefix  = "premium_transfer_cancel:"
+)
+
 func (p *Plugin) BotInit() {
+	eventsystem.AddHandlerAsyncLastLegacy(p, handleTransferInteraction, eventsystem.EventInteractionCreate)
 }
 
 func (p *Plugin) AddCommands() {
-	commands.AddRootCommands(p, cmdGenerateCode, cmdPremium)
+	commands.AddRootCommands(p, cmdGenerateCode, cmdPremium, cmdTransferSlot)
+}
+
+func handleTransferInteraction(evt *eventsystem.EventData) {
+	ic := evt.InteractionCreate()
+	if ic.Type != discordgo.InteractionMessageComponent || ic.Message == nil {
+		return
+	}
+
+	customID := ic.MessageComponentData().CustomID
+	var split []string
+	switch {
+	case strings.HasPrefix(customID, transferConfirmPrefix):
+		split = strings.Split(strings.TrimPrefix(customID, transferConfirmPrefix), ":")
+	case strings.HasPrefix(customID, transferCancelPrefix):
+		split = strings.Split(strings.TrimPrefix(customID, transferCancelPrefix), ":")
+	default:
+		return
+	}
+
+	user := ic.User
+	if ic.Member != nil {
+		user = ic.Member.User
+	}
+
+	// only the user that ran the command can confirm or cancel it
+	if ownerID, _ := strconv.ParseInt(split[0], 10, 64); ownerID != user.ID {
+		respondTransferInteraction(ic, discordgo.InteractionResponseChannelMessageWithSource, "This isn't your premium slot transfer")
+		return
+	}
+
+	if len(split) != 3 {
+		respondTransferInteraction(ic, discordgo.InteractionResponseUpdateMessage, "Cancelled the premium slot transfer")
+		return
+	}
+
+	slotID, _ := strconv.ParseInt(split[1], 10, 64)
+	toUserID, _ := strconv.ParseInt(split[2], 10, 64)
+
+	slot, err := TransferPremiumSlot(evt.Context(), slotID, user.ID, toUserID)
+	switch err {
+	case nil:
+		respondTransferInteraction(ic, discordgo.InteractionResponseUpdateMessage, fmt.Sprintf("Transferred the premium slot **%s** to <@%d>", slot.Title, toUserID))
+	case ErrSlotNotFound:
+		respondTransferInteraction(ic, discordgo.InteractionResponseUpdateMessage, "You don't have that premium slot anymore")
+	case ErrSlotNotTransferable, ErrTransferToSelf:
+		respondTransferInteraction(ic, discordgo.InteractionResponseUpdateMessage, err.Error())
+	default:
+		logger.WithError(err).WithField("slot", slotID).Error("Failed transferring premium slot")
+		respondTransferInteraction(ic, discordgo.InteractionResponseChannelMessageWithSource, "Something went wrong transferring the slot, try again later")
+	}
+}
+
+func respondTransferInteraction(ic *discordgo.InteractionCreate, typ discordgo.InteractionResponseType, msg string) {
+	data := &discordgo.InteractionResponseData{
+		Content:         msg,
+		AllowedMentions: &discordgo.AllowedMentions{},
+	}
+
+	if typ == discordgo.InteractionResponseUpdateMessage {
+		data.Components = []discordgo.TopLevelComponent{}
+	} else {
+		data.Flags = discordgo.MessageFlagsEphemeral
+	}
+
+	err := common.BotSession.CreateInteractionResponse(ic.ID, ic.Token, &discordgo.InteractionResponse{Type: typ, Data: data})
+	if err != nil {
+		logger.WithError(err).Error("Failed responding to premium transfer interaction")
+	}
 }
 
 const (
@@ -65,6 +65,7 @@ func (p *Plugin) InitWeb() {
 	submux.Handle(pat.Post("/lookupcode"), tollbooth.LimitHandler(limiter, web.ControllerPostHandler(HandlePostLookupCode, mainHandler, nil)))
 	submux.Handle(pat.Post("/redeemcode"), tollbooth.LimitHandler(limiter, web.ControllerPostHandler(HandlePostRedeemCode, mainHandler, nil)))
 	submux.Handle(pat.Post("/updateslot/:slotID"), web.ControllerPostHandler(HandlePostUpdateSlot, mainHandler, UpdateData{}))
+	submux.Handle(pat.Post("/transferslot/:slotID"), web.ControllerPostHandler(HandlePostTransferSlot, mainHandler, TransferData{}))
 
 	web.CPMux.Handle(pat.Post("/premium/detach"), web.ControllerPostHandler(HandlePostDetachGuildSlot, web.RenderHandler(nil, "cp_premium_detach"), nil))
 }
@@ -195,6 +196,31 @@ func HandlePostUpdateSlot(w http.ResponseWriter, r *http.Request) (tmpl web.Temp
 	return tmpl, err
 }
 
+type TransferData struct {
+	UserID int64
+}
+
+func HandlePostTransferSlot(w http.ResponseWriter, r *http.Request) (tmpl web.TemplateData, err error) {
+	_, tmpl = web.GetCreateTemplateData(r.Context())
+	data := r.Context().Value(common.ContextKeyParsedForm).(*TransferData)
+	user := web.ContextUser(r.Context())
+
+	if data.UserID == 0 {
+		return tmpl.AddAlerts(web.ErrorAlert("No user ID provided")), nil
+	}
+
+	parsedSlotID, _ := strconv.ParseInt(pat.Param(r, "slotID"), 10, 64)
+	_, err = TransferPremiumSlot(r.Context(), parsedSlotID, user.ID, data.UserID)
+	switch err {
+	case nil:
+		return tmpl.AddAlerts(web.SucessAlert(fmt.Sprintf("Transferred the premium slot to user %d", data.UserID))), nil
+	case ErrSlotNotFound, ErrSlotNotTransferable, ErrTransferToSelf:
+		return tmpl.AddAlerts(web.ErrorAlert(err.Error())), nil
+	}
+
+	return tmpl, err
+}
+
 func ContextPremium(ctx context.Context) bool {
 	if confAllGuildsPremium.GetBool() {
 		return true
@@ -39,6 +39,10 @@ const (
 	PremiumSourceTypeDiscord PremiumSourceType = "discord"
 	PremiumSourceTypePatreon PremiumSourceType = "patreon"
 	PremiumSourceTypeCode    PremiumSourceType = "code"
+
+	// PremiumSourceTypeGift is only used for DMs about slots transferred from another user,
+	// the slots themselves keep their original source
+	PremiumSourceTypeGift PremiumSourceType = "gift"
 )
 
 func (p PremiumTier) String() string {
@@ -308,7 +312,11 @@ func SendPremiumDM(userID int64, source PremiumSourceType, numSlots int) {
 	logger.Infof("Sending premium DM to user: %d for %d slots via %s subscription", userID, numSlots, string(source))
 	embed := &discordgo.MessageEmbed{}
 	embed.Title = "You have new Premium Slots!"
-	embed.Description = fmt.Sprintf("You have received %d new premium slots from a %s subscription!\n\n[Assign them to a server here.](https://%s/premium)", numSlots, string(source), common.ConfHost.GetString())
+	if source == PremiumSourceTypeGift {
+		embed.Description = fmt.Sprintf("You have been gifted %d premium slots by another user!\n\n[Assign them to a server here.](https://%s/premium)", numSlots, common.ConfHost.GetString())
+	} else {
+		embed.Description = fmt.Sprintf("You have received %d new premium slots from a %s subscription!\n\n[Assign them to a server here.](https://%s/premium)", numSlots, string(source), common.ConfHost.GetString())
+	}
 	err := bot.SendDMEmbed(userID, embed)
 	if err != nil {
 		logger.WithError(err).Error("Failed sending premium DM")
@@ -51,5 +51,14 @@ CREATE TABLE IF NOT EXISTS discord_skus ( sku_id BIGINT PRIMARY KEY, slots INTEG
 `,
 	`
 CREATE TABLE IF NOT EXISTS patreon_tiers ( tier_id BIGINT PRIMARY KEY, slots INTEGER NOT NULL);
+`,
+	`
+CREATE TABLE IF NOT EXISTS premium_slot_expiry_warnings (
+	slot_id BIGINT NOT NULL REFERENCES premium_slots(id) ON DELETE CASCADE,
+	warning BIGINT NOT NULL,
+	sent_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+	PRIMARY KEY(slot_id, warning)
+);
 `,
 }
@@ -0,0 +1,92 @@
+package premium
+
+import (
+	"context"
+	"database/sql"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/premium/models"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+var (
+	ErrSlotNotTransferable = errors.New("only slots from redeemed codes can be transferred")
+	ErrTransferToSelf      = errors.New("can't transfer a slot to yourself")
+)
+
+// TransferPremiumSlot gives the slot to another user, detaching it from the guild it's currently attached to.
+// Slots from patreon and discord are synced to the subscription of the user and can't be transferred.
+func TransferPremiumSlot(ctx context.Context, slotID int64, fromUserID int64, toUserID int64) (*models.PremiumSlot, error) {
+	if fromUserID == toUserID {
+		return nil, ErrTransferToSelf
+	}
+
+	tx, err := common.PQ.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "BeginTX")
+	}
+
+	slot, err := transferPremiumSlot(ctx, tx, slotID, fromUserID, toUserID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WithMessage(err, "Commit")
+	}
+
+	logger.Infof("Transferred premium slot #%d from user %d to user %d", slotID, fromUserID, toUserID)
+	go SendPremiumDM(toUserID, PremiumSourceTypeGift, 1)
+
+	return slot, nil
+}
+
+func transferPremiumSlot(ctx context.Context, exec boil.ContextExecutor, slotID int64, fromUserID int64, toUserID int64) (*models.PremiumSlot, error) {
+	slot, err := models.PremiumSlots(qm.Where("id = ? AND user_id = ?", slotID, fromUserID), qm.For("UPDATE")).One(ctx, exec)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSlotNotFound
+		}
+
+		return nil, errors.WithMessage(err, "PremiumSlots.One")
+	}
+
+	if slot.Source != string(PremiumSourceTypeCode) || slot.DeletesAt.Valid {
+		return nil, ErrSlotNotTransferable
+	}
+
+	if !slot.Permanent && SlotDurationLeft(slot) <= 0 {
+		return nil, ErrSlotNotFound
+	}
+
+	if slot.GuildID.Valid {
+		err = DetachSlotFromGuild(ctx, exec, slot.ID, fromUserID)
+		if err != nil {
+			return nil, errors.WithMessage(err, "DetachSlotFromGuild")
+		}
+
+		// reload the remaining duration after detaching
+		err = slot.Reload(ctx, exec)
+		if err != nil {
+			return nil, errors.WithMessage(err, "Reload")
+		}
+	}
+
+	slot.UserID = toUserID
+	_, err = slot.Update(ctx, exec, boil.Whitelist(models.PremiumSlotColumns.UserID))
+	if err != nil {
+		return nil, errors.WithMessage(err, "Update")
+	}
+
+	// the new owner should get all the expiry warnings again
+	_, err = exec.ExecContext(ctx, "DELETE FROM premium_slot_expiry_warnings WHERE slot_id = $1", slot.ID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "delete expiry warnings")
+	}
+
+	return slot, nil
+}
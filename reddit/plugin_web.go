@@ -287,6 +287,18 @@ func FindFeed(feeds []*models.RedditFeed, id int64) *models.RedditFeed {
 	return nil
 }
 
+var _ web.PluginWithPublicAPI = (*Plugin)(nil)
+
+func (p *Plugin) PublicAPIResources() []*web.APIResource {
+	return []*web.APIResource{
+		{
+			Path:     "/reddit",
+			Scope:    "feeds",
+			DataKeys: []string{"RedditConfig"},
+		},
+	}
+}
+
 var _ web.PluginWithServerHomeWidget = (*Plugin)(nil)
 
 func (p *Plugin) LoadServerHomeWidget(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 	"slices"
 	"strings"
 	"time"
@@ -63,7 +62,7 @@ var cmds = []*commands.YAGCommand{
 			uid := discordgo.StrID(parsed.Author.ID)
 			count, _ := models.Reminders(models.ReminderWhere.UserID.EQ(uid)).CountG(parsed.Context())
 			if count >= MaxReminders {
-				return fmt.Sprintf("You can have a maximum of %d active reminders; list all your reminders with the `reminders` command in DM, doing it in a server will only show reminders set in the server", MaxReminders), nil
+				return parsed.Translatef("You can have a maximum of %d active reminders; list all your reminders with the `reminders` command in DM, doing it in a server will only show reminders set in the server", MaxReminders), nil
 			}
 
 			if parsed.Author.Bot {
@@ -72,7 +71,7 @@ var cmds = []*commands.YAGCommand{
 
 			offsetFromNow := parsed.Args[0].Value.(time.Duration)
 			if offsetFromNow > MaxReminderOffset {
-				return MaxReminderOffsetExceededMsg, nil
+				return parsed.Translate(MaxReminderOffsetExceededMsg), nil
 			}
 
 			id := parsed.ChannelID
@@ -83,11 +82,11 @@ var cmds = []*commands.YAGCommand{
 
 				hasPerms, err := bot.AdminOrPermMS(parsed.GuildData.GS.ID, cs.ID, parsed.GuildData.MS, discordgo.PermissionSendMessages|discordgo.PermissionViewChannel)
 				if err != nil {
-					return "Failed checking permissions; please try again or join the support server.", err
+					return parsed.Translate("Failed checking permissions; please try again or join the support server."), err
 				}
 
 				if !hasPerms {
-					return fmt.Sprintf("You do not have permissions to send messages in %s", mention), nil
+					return parsed.Translatef("You do not have permissions to send messages in %s", mention), nil
 				}
 
 				// Ensure the member can run the `remindme` command in the
@@ -96,11 +95,11 @@ var cmds = []*commands.YAGCommand{
 				yc := parsed.Cmd.Command.(*commands.YAGCommand)
 				settings, err := yc.GetSettings(parsed.ContainerChain, cs, parsed.GuildData.GS)
 				if err != nil {
-					return "Failed fetching command settings", err
+					return parsed.Translate("Failed fetching command settings"), err
 				}
 
 				if !settings.Enabled {
-					return fmt.Sprintf("The `remindme` command is disabled in %s", mention), nil
+					return parsed.Translatef("The `remindme` command is disabled in %s", mention), nil
 				}
 
 				ms := parsed.GuildData.MS
@@ -108,7 +107,7 @@ var cmds = []*commands.YAGCommand{
 				hasRequiredRoles := len(settings.RequiredRoles) == 0 || memberHasAnyRole(ms, settings.RequiredRoles)
 				hasIgnoredRoles := memberHasAnyRole(ms, settings.IgnoreRoles)
 				if !hasRequiredRoles || hasIgnoredRoles {
-					return fmt.Sprintf("You cannot use the `remindme` command in %s", mention), nil
+					return parsed.Translatef("You cannot use the `remindme` command in %s", mention), nil
 				}
 			}
 
@@ -119,7 +118,7 @@ var cmds = []*commands.YAGCommand{
 			}
 
 			durString := common.HumanizeDuration(common.DurationPrecisionSeconds, offsetFromNow)
-			return fmt.Sprintf("Set a reminder in %s from now (<t:%d:f>)\nView reminders with the `reminders` command", durString, when.Unix()), nil
+			return parsed.Translatef("Set a reminder in %s from now (<t:%d:f>)\nView reminders with the `reminders` command", durString, when.Unix()), nil
 		},
 	},
 	{
@@ -135,10 +134,8 @@ var cmds = []*commands.YAGCommand{
 			qms := []qm.QueryMod{models.ReminderWhere.UserID.EQ(uid)}
 
 			// if command used in server, only show reminders in that server
-			var inServerSuffix string
-			if inServer := parsed.GuildData != nil; inServer {
-				inServerSuffix = " in this server"
-
+			inServer := parsed.GuildData != nil
+			if inServer {
 				guildID := parsed.GuildData.GS.ID
 				qms = append(qms, models.ReminderWhere.GuildID.EQ(guildID))
 			}
@@ -149,12 +146,18 @@ var cmds = []*commands.YAGCommand{
 			}
 
 			if len(currentReminders) == 0 {
-				return fmt.Sprintf("You have no reminders%s. Create reminders with the `remindme` command", inServerSuffix), nil
+				if inServer {
+					return parsed.Translate("You have no reminders in this server. Create reminders with the `remindme` command"), nil
+				}
+				return parsed.Translate("You have no reminders. Create reminders with the `remindme` command"), nil
 			}
 
-			out := fmt.Sprintf("Your reminders%s:\n", inServerSuffix)
+			out := parsed.Translate("Your reminders:") + "\n"
+			if inServer {
+				out = parsed.Translate("Your reminders in this server:") + "\n"
+			}
 			out += DisplayReminders(currentReminders, ModeDisplayUserReminders)
-			out += "\nRemove a reminder with `delreminder/rmreminder (id)` where id is the first number for each reminder above.\nTo clear all reminders, use `delreminder` with the `-a` switch."
+			out += "\n" + parsed.Translate("Remove a reminder with `delreminder/rmreminder (id)` where id is the first number for each reminder above.\nTo clear all reminders, use `delreminder` with the `-a` switch.")
 			return out, nil
 		},
 	},
@@ -175,12 +178,12 @@ var cmds = []*commands.YAGCommand{
 			}
 
 			if len(currentReminders) == 0 {
-				return "There are no reminders in this channel.", nil
+				return parsed.Translate("There are no reminders in this channel."), nil
 			}
 
-			out := "Reminders in this channel:\n"
+			out := parsed.Translate("Reminders in this channel:") + "\n"
 			out += DisplayReminders(currentReminders, ModeDisplayChannelReminders)
-			out += "\nRemove a reminder with `delreminder/rmreminder (id)` where id is the first number for each reminder above"
+			out += "\n" + parsed.Translate("Remove a reminder with `delreminder/rmreminder (id)` where id is the first number for each reminder above")
 			return out, nil
 		},
 	},
@@ -205,31 +208,31 @@ var cmds = []*commands.YAGCommand{
 				uid := discordgo.StrID(parsed.Author.ID)
 				count, err := models.Reminders(models.ReminderWhere.UserID.EQ(uid)).DeleteAllG(parsed.Context(), false /* hardDelete */)
 				if err != nil {
-					return "Error clearing reminders", err
+					return parsed.Translate("Error clearing reminders"), err
 				}
 
 				if count == 0 {
-					return "No reminders to clear", nil
+					return parsed.Translate("No reminders to clear"), nil
 				}
-				return fmt.Sprintf("Cleared %d reminders", count), nil
+				return parsed.Translatef("Cleared %d reminders", count), nil
 			}
 
 			if len(parsed.Args) == 0 || parsed.Args[0].Value == nil {
-				return "No reminder ID provided", nil
+				return parsed.Translate("No reminder ID provided"), nil
 			}
 
 			reminder, err := models.FindReminderG(parsed.Context(), parsed.Args[0].Int())
 			if err != nil {
 				if err == sql.ErrNoRows {
-					return "No reminder by that ID found", nil
+					return parsed.Translate("No reminder by that ID found"), nil
 				}
-				return "Error retrieving reminder", err
+				return parsed.Translate("Error retrieving reminder"), err
 			}
 
 			// check perms
 			if reminder.UserID != discordgo.StrID(parsed.Author.ID) {
 				if reminder.GuildID != parsed.GuildData.GS.ID {
-					return "You can only delete reminders that are not your own in the guild the reminder was originally created", nil
+					return parsed.Translate("You can only delete reminders that are not your own in the guild the reminder was originally created"), nil
 				}
 
 				cid, _ := discordgo.ParseID(reminder.ChannelID)
@@ -238,7 +241,7 @@ var cmds = []*commands.YAGCommand{
 					return nil, err
 				}
 				if !ok {
-					return "You need manage channel permission in the channel the reminder is in to delete reminders that are not your own", nil
+					return parsed.Translate("You need manage channel permission in the channel the reminder is in to delete reminders that are not your own"), nil
 				}
 			}
 
@@ -249,7 +252,7 @@ var cmds = []*commands.YAGCommand{
 				return nil, err
 			}
 
-			return fmt.Sprintf("Deleted reminder **#%d**: '%s'", reminder.ID, CutReminderShort(reminder.Message)), nil
+			return parsed.Translatef("Deleted reminder **#%d**: '%s'", reminder.ID, CutReminderShort(reminder.Message)), nil
 		},
 	},
 }
@@ -277,7 +280,7 @@ func checkUserScheduledEvent(evt *seventsmodels.ScheduledEvent, data interface{}
 	nowUnix := time.Now().Unix()
 	for _, r := range reminders {
 		if r.When <= nowUnix {
-			err := TriggerReminder(r)
+			err := TriggerReminder(scheduledevents2.Context(evt), r)
 			if err != nil {
 				// possibly try again
 				return scheduledevents2.CheckDiscordErrRetry(err), err
@@ -2,12 +2,14 @@ package reminders
 
 import (
 	"context"
+	"embed"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/botlabs-gg/yagpdb/v2/bot"
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/i18n"
 	"github.com/botlabs-gg/yagpdb/v2/common/mqueue"
 	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
@@ -18,6 +20,9 @@ import (
 
 //go:generate sqlboiler --no-hooks --add-soft-deletes psql
 
+//go:embed locales
+var locales embed.FS
+
 type Plugin struct{}
 
 func RegisterPlugin() {
@@ -25,6 +30,7 @@ func RegisterPlugin() {
 	common.RegisterPlugin(p)
 
 	common.InitSchemas("reminders", DBSchemas...)
+	i18n.RegisterCatalogs(locales, "locales")
 }
 
 func (p *Plugin) PluginInfo() *common.PluginInfo {
@@ -35,7 +41,7 @@ func (p *Plugin) PluginInfo() *common.PluginInfo {
 	}
 }
 
-func TriggerReminder(r *models.Reminder) error {
+func TriggerReminder(ctx context.Context, r *models.Reminder) error {
 	r.DeleteG(context.Background(), false /* hardDelete */)
 
 	logger.WithFields(logrus.Fields{"channel": r.ChannelID, "user": r.UserID, "message": r.Message, "id": r.ID}).Info("Triggered reminder")
@@ -46,7 +52,7 @@ func TriggerReminder(r *models.Reminder) error {
 
 	channelID, _ := discordgo.ParseID(r.ChannelID)
 	userID, _ := discordgo.ParseID(r.UserID)
-	return mqueue.QueueMessage(&mqueue.QueuedElement{
+	return mqueue.QueueMessageContext(ctx, &mqueue.QueuedElement{
 		Source:       "reminder",
 		SourceItemID: "",
 
@@ -17,6 +17,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/common/featureflags"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/botlabs-gg/yagpdb/v2/outboundwebhooks"
 	"github.com/botlabs-gg/yagpdb/v2/premium"
 	"github.com/botlabs-gg/yagpdb/v2/reputation/models"
 	"github.com/mediocregopher/radix/v3"
@@ -183,6 +184,13 @@ func ModifyRep(ctx context.Context, conf *models.ReputationConfig, gs *dstate.Gu
 		return
 	}
 
+	outboundwebhooks.Emit(gs.ID, outboundwebhooks.EventReputationChanged, &outboundwebhooks.ReputationChangedData{
+		User:          outboundwebhooks.UserData(&receiver.User),
+		Sender:        outboundwebhooks.UserData(&sender.User),
+		Change:        amount,
+		NewReputation: newRep,
+	})
+
 	if err := UpdateRepRoles(gs, receiver, newRep); err != nil {
 		logger.WithField("guild_id", gs.ID).Errorf("failed updating rep roles: %s", err)
 		return ErrUpdatingRepRoles
@@ -374,6 +382,13 @@ func SetRep(ctx context.Context, gs *dstate.GuildSet, sender, receiver *dstate.M
 		return err
 	}
 
+	outboundwebhooks.Emit(gs.ID, outboundwebhooks.EventReputationChanged, &outboundwebhooks.ReputationChangedData{
+		User:          outboundwebhooks.UserData(&receiver.User),
+		Sender:        outboundwebhooks.UserData(&sender.User),
+		Set:           true,
+		NewReputation: points,
+	})
+
 	if err := UpdateRepRoles(gs, receiver, points); err != nil {
 		logger.WithField("guild_id", gs.ID).Errorf("failed updating rep roles: %s", err)
 		return ErrUpdatingRepRoles
@@ -398,6 +413,11 @@ func DelRep(ctx context.Context, gs *dstate.GuildSet, userID int64) error {
 		return err
 	}
 
+	outboundwebhooks.Emit(gs.ID, outboundwebhooks.EventReputationChanged, &outboundwebhooks.ReputationChangedData{
+		User: &outboundwebhooks.User{ID: userID},
+		Set:  true,
+	})
+
 	// Try to update the ms's rep roles if we can find them.
 	ms, err := bot.GetMember(gs.ID, userID)
 	if err != nil {
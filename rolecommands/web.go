@@ -506,6 +506,18 @@ func HandleRemoveGroup(w http.ResponseWriter, r *http.Request) (web.TemplateData
 	return nil, err
 }
 
+var _ web.PluginWithPublicAPI = (*Plugin)(nil)
+
+func (p *Plugin) PublicAPIResources() []*web.APIResource {
+	return []*web.APIResource{
+		{
+			Path:     "/rolecommands",
+			Scope:    "rolecommands",
+			DataKeys: []string{"Groups", "LoneCommands", "CurrentGroup", "Commands"},
+		},
+	}
+}
+
 var _ web.PluginWithServerHomeWidget = (*Plugin)(nil)
 
 func (p *Plugin) LoadServerHomeWidget(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
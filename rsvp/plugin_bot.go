@@ -30,6 +30,10 @@ var _ bot.BotInitHandler = (*Plugin)(nil)
 func (p *Plugin) BotInit() {
 	eventsystem.AddHandlerAsyncLastLegacy(p, p.handleMessageCreate, eventsystem.EventMessageCreate)
 	eventsystem.AddHandlerAsyncLastLegacy(p, p.handleInteractionCreate, eventsystem.EventInteractionCreate)
+	eventsystem.AddHandlerAsyncLastLegacy(p, p.handleScheduledEventUserAdd, eventsystem.EventGuildScheduledEventUserAdd)
+	eventsystem.AddHandlerAsyncLastLegacy(p, p.handleScheduledEventUserRemove, eventsystem.EventGuildScheduledEventUserRemove)
+	eventsystem.AddHandlerAsyncLastLegacy(p, p.handleScheduledEventUpdate, eventsystem.EventGuildScheduledEventUpdate)
+	eventsystem.AddHandlerAsyncLastLegacy(p, p.handleScheduledEventDelete, eventsystem.EventGuildScheduledEventDelete)
 	scheduledevents2.RegisterHandler("rsvp_update_session", int64(0), p.handleScheduledUpdate)
 }
 
@@ -46,12 +50,15 @@ func (p *Plugin) AddCommands() {
 	container.NotFound = commands.CommonContainerNotFoundHandler(container, "")
 
 	cmdCreateEvent := &commands.YAGCommand{
-		CmdCategory: catEvents,
-		Name:        "Create",
-		Aliases:     []string{"new", "make"},
-		Description: "Creates an event, You will be led through an interactive setup",
+		CmdCategory:         catEvents,
+		Name:                "Create",
+		Aliases:             []string{"new", "make"},
+		Description:         "Creates an event, You will be led through an interactive setup",
 		RequireDiscordPerms: []int64{discordgo.PermissionManageGuild, discordgo.PermissionManageEvents},
-		Plugin:      p,
+		ArgSwitches: []*dcmd.ArgDef{
+			{Name: "discordevent", Help: "Also create the event in the server's events tab, users interested in it are added as participants"},
+		},
+		Plugin: p,
 		RunFunc: func(parsed *dcmd.Data) (interface{}, error) {
 
 			count, err := models.RSVPSessions(models.RSVPSessionWhere.GuildID.EQ(parsed.GuildData.GS.ID)).CountG(parsed.Context())
@@ -85,6 +92,8 @@ func (p *Plugin) AddCommands() {
 				plugin:             p,
 				setupMessages:      setupMessages,
 
+				CreateScheduledEvent: parsed.Switch("discordevent").Bool(),
+
 				stopCH: make(chan bool),
 			}
 			go setupSession.loopCheckActive()
@@ -173,6 +182,11 @@ func (p *Plugin) AddCommands() {
 
 			UpdateEventEmbed(m)
 
+			err = updateScheduledEvent(m)
+			if err != nil {
+				logger.WithError(err).WithField("guild", m.GuildID).Error("failed updating scheduled event")
+			}
+
 			return fmt.Sprintf("Updated #%d to '%s' - with max %d participants, starting at: %s", m.LocalID, m.Title, m.MaxParticipants, m.StartsAt.Format("02 Jan 2006 15:04 MST")), nil
 		},
 	}
@@ -233,6 +247,11 @@ func (p *Plugin) AddCommands() {
 				return nil, err
 			}
 
+			err = deleteScheduledEvent(m)
+			if err != nil {
+				logger.WithError(err).WithField("guild", m.GuildID).Error("failed deleting scheduled event")
+			}
+
 			_, err = m.DeleteG(parsed.Context())
 			if err != nil {
 				return nil, err
@@ -565,6 +584,7 @@ func (p *Plugin) handleScheduledUpdate(evt *eventModels.ScheduledEvent, data int
 	if err != nil {
 		code, _ := common.DiscordError(err)
 		if code == discordgo.ErrCodeUnknownMessage || code == discordgo.ErrCodeUnknownChannel {
+			deleteScheduledEvent(m)
 			m.DeleteG(context.Background())
 			return false, nil
 		}
@@ -602,7 +622,12 @@ func (p *Plugin) startEvent(m *models.RSVPSession) error {
 
 	p.sendReminders(m, "Event starting now!", "The event you signed up for: **"+m.Title+"** is starting now!")
 
-	_, err := m.DeleteG(context.Background())
+	err := startScheduledEvent(m)
+	if err != nil {
+		logger.WithError(err).WithField("guild", m.GuildID).Error("failed starting scheduled event")
+	}
+
+	_, err = m.DeleteG(context.Background())
 	return err
 }
 
@@ -728,24 +753,28 @@ func (p *Plugin) handleInteractionCreate(evt *eventsystem.EventData) {
 		logger.WithError(err).WithField("guild", ic.GuildID).Error("failed updating rsvp participant")
 	}
 
+	queueEmbedUpdate(m.MessageID, m.GuildID)
+}
+
+// queueEmbedUpdate updates the embed of the session, waiting a bit between updates if there's a lot of changes
+func queueEmbedUpdate(messageID, guildID int64) {
 	updatingSessiosMU.Lock()
+	defer updatingSessiosMU.Unlock()
+
 	for _, v := range updatingSessionEmbeds {
-		if v.ID == m.MessageID {
+		if v.ID == messageID {
 			v.lastModelUpdate = time.Now()
-			updatingSessiosMU.Unlock()
 			return
 		}
 	}
 
 	s := &UpdatingSession{
-		ID:              m.MessageID,
-		GuildID:         m.GuildID,
+		ID:              messageID,
+		GuildID:         guildID,
 		lastModelUpdate: time.Now(),
 	}
 	updatingSessionEmbeds = append(updatingSessionEmbeds, s)
 	go s.run()
-	updatingSessiosMU.Unlock()
-
 }
 
 var (
@@ -801,4 +830,9 @@ func (u *UpdatingSession) update() {
 	if err != nil {
 		logger.WithError(err).WithField("guild", u.GuildID).Error("failed retreiving rsvp")
 	}
+
+	err = updateScheduledEvent(m)
+	if err != nil {
+		logger.WithError(err).WithField("guild", u.GuildID).Error("failed updating scheduled event")
+	}
 }
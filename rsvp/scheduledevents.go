@@ -0,0 +1,269 @@
+package rsvp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/bot/eventsystem"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/rsvp/models"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// Events can be mirrored as a discord scheduled event in the servers events tab.
+//
+// Users marking themselves as interested in the scheduled event are added as participants,
+// and removing the interest marks them as not joining. Discord doesn't let bots mark users as interested,
+// so the other way around is mirrored through the participant count in the scheduled event description.
+
+// rsvp events don't have a end time, but external scheduled events require one
+const scheduledEventDuration = time.Hour
+
+func getLinkedScheduledEvent(messageID int64) (int64, error) {
+	var eventID int64
+	err := common.PQ.QueryRow("SELECT scheduled_event_id FROM rsvp_scheduled_events WHERE rsvp_sessions_message_id = $1", messageID).Scan(&eventID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+
+	return eventID, err
+}
+
+func getSessionByScheduledEvent(scheduledEventID int64) (int64, error) {
+	var messageID int64
+	err := common.PQ.QueryRow("SELECT rsvp_sessions_message_id FROM rsvp_scheduled_events WHERE scheduled_event_id = $1", scheduledEventID).Scan(&messageID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+
+	return messageID, err
+}
+
+func unlinkScheduledEvent(scheduledEventID int64) error {
+	_, err := common.PQ.Exec("DELETE FROM rsvp_scheduled_events WHERE scheduled_event_id = $1", scheduledEventID)
+	return err
+}
+
+// createScheduledEvent creates the scheduled event for the session and links the two
+func createScheduledEvent(m *models.RSVPSession) error {
+	location := "Discord"
+	if gs := bot.State.GetGuild(m.GuildID); gs != nil {
+		if cs := gs.GetChannel(m.ChannelID); cs != nil {
+			location = "#" + cs.Name
+		}
+	}
+
+	start := m.StartsAt
+	end := start.Add(scheduledEventDuration)
+	evt, err := common.BotSession.GuildScheduledEventCreate(m.GuildID, &discordgo.GuildScheduledEventParams{
+		Name:               common.CutStringShort(m.Title, 100),
+		Description:        scheduledEventDescription(m),
+		PrivacyLevel:       discordgo.GuildScheduledEventPrivacyLevelGuildOnly,
+		EntityType:         discordgo.GuildScheduledEventEntityTypeExternal,
+		EntityMetadata:     &discordgo.GuildScheduledEventEntityMetadata{Location: common.CutStringShort(location, 100)},
+		ScheduledStartTime: &start,
+		ScheduledEndTime:   &end,
+	}, "RSVP event #"+fmt.Sprint(m.LocalID))
+	if err != nil {
+		return err
+	}
+
+	_, err = common.PQ.Exec("INSERT INTO rsvp_scheduled_events (rsvp_sessions_message_id, guild_id, scheduled_event_id) VALUES ($1, $2, $3)", m.MessageID, m.GuildID, evt.ID)
+	if err != nil {
+		common.BotSession.GuildScheduledEventDelete(m.GuildID, evt.ID, "")
+	}
+
+	return err
+}
+
+// updateScheduledEvent updates the name, time and participant count of the linked scheduled event, if there is one
+func updateScheduledEvent(m *models.RSVPSession) error {
+	eventID, err := getLinkedScheduledEvent(m.MessageID)
+	if err != nil || eventID == 0 {
+		return err
+	}
+
+	start := m.StartsAt
+	end := start.Add(scheduledEventDuration)
+	_, err = common.BotSession.GuildScheduledEventEdit(m.GuildID, eventID, &discordgo.GuildScheduledEventParams{
+		Name:               common.CutStringShort(m.Title, 100),
+		Description:        scheduledEventDescription(m),
+		ScheduledStartTime: &start,
+		ScheduledEndTime:   &end,
+	}, "")
+
+	return checkUnknownScheduledEvent(eventID, err)
+}
+
+// startScheduledEvent marks the linked scheduled event as active, the session and link is deleted right after
+func startScheduledEvent(m *models.RSVPSession) error {
+	eventID, err := getLinkedScheduledEvent(m.MessageID)
+	if err != nil || eventID == 0 {
+		return err
+	}
+
+	_, err = common.BotSession.GuildScheduledEventEdit(m.GuildID, eventID, &discordgo.GuildScheduledEventParams{
+		Status: discordgo.GuildScheduledEventStatusActive,
+	}, "")
+
+	return checkUnknownScheduledEvent(eventID, err)
+}
+
+// deleteScheduledEvent deletes the linked scheduled event, if there is one
+func deleteScheduledEvent(m *models.RSVPSession) error {
+	eventID, err := getLinkedScheduledEvent(m.MessageID)
+	if err != nil || eventID == 0 {
+		return err
+	}
+
+	err = common.BotSession.GuildScheduledEventDelete(m.GuildID, eventID, "RSVP event #"+fmt.Sprint(m.LocalID)+" deleted")
+	if err == nil {
+		return unlinkScheduledEvent(eventID)
+	}
+
+	return checkUnknownScheduledEvent(eventID, err)
+}
+
+// checkUnknownScheduledEvent removes the link if the scheduled event was deleted in the meantime
+func checkUnknownScheduledEvent(eventID int64, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if code, _ := common.DiscordError(err); code == discordgo.ErrCodeUnknownGuildScheduledEvent {
+		return unlinkScheduledEvent(eventID)
+	}
+
+	return err
+}
+
+func scheduledEventDescription(m *models.RSVPSession) string {
+	var participants []*models.RSVPParticipant
+	if m.R != nil {
+		participants = m.R.RSVPSessionsMessageRSVPParticipants
+	}
+
+	joining := 0
+	waitlist := 0
+	for _, v := range participants {
+		switch v.JoinState {
+		case int16(ParticipantStateJoining):
+			if m.MaxParticipants > 0 && joining >= m.MaxParticipants {
+				waitlist++
+			} else {
+				joining++
+			}
+		case int16(ParticipantStateWaitlist):
+			waitlist++
+		}
+	}
+
+	desc := fmt.Sprintf("Sign up at https://discord.com/channels/%d/%d/%d\n\n", m.GuildID, m.ChannelID, m.MessageID)
+	if m.MaxParticipants > 0 {
+		desc += fmt.Sprintf("Participants: %d / %d, waiting list: %d", joining, m.MaxParticipants, waitlist)
+	} else {
+		desc += fmt.Sprintf("Participants: %d", joining)
+	}
+
+	return desc
+}
+
+func (p *Plugin) handleScheduledEventUserAdd(evt *eventsystem.EventData) {
+	data := evt.GuildScheduledEventUserAdd()
+	p.syncScheduledEventUser(data.GuildID, data.GuildScheduledEventID, data.UserID, true)
+}
+
+func (p *Plugin) handleScheduledEventUserRemove(evt *eventsystem.EventData) {
+	data := evt.GuildScheduledEventUserRemove()
+	p.syncScheduledEventUser(data.GuildID, data.GuildScheduledEventID, data.UserID, false)
+}
+
+// syncScheduledEventUser updates the participant state of a user that added or removed their interest in a linked scheduled event
+func (p *Plugin) syncScheduledEventUser(guildID, scheduledEventID, userID int64, interested bool) {
+	messageID, err := getSessionByScheduledEvent(scheduledEventID)
+	if err != nil {
+		logger.WithError(err).WithField("guild", guildID).Error("failed retrieving linked rsvp session")
+		return
+	}
+
+	if messageID == 0 {
+		return
+	}
+
+	m, err := models.RSVPSessions(models.RSVPSessionWhere.MessageID.EQ(messageID), qm.Load("RSVPSessionsMessageRSVPParticipants")).OneG(context.Background())
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.WithError(err).WithField("guild", guildID).Error("failed retrieving RSVP session")
+		}
+		return
+	}
+
+	var participant *models.RSVPParticipant
+	for _, v := range m.R.RSVPSessionsMessageRSVPParticipants {
+		if v.UserID == userID {
+			participant = v
+			break
+		}
+	}
+
+	if interested {
+		if participant != nil && (participant.JoinState == int16(ParticipantStateJoining) || participant.JoinState == int16(ParticipantStateWaitlist)) {
+			// already joining
+			return
+		}
+
+		if participant == nil {
+			participant = &models.RSVPParticipant{
+				RSVPSessionsMessageID:   m.MessageID,
+				UserID:                  userID,
+				GuildID:                 guildID,
+				JoinState:               int16(ParticipantStateJoining),
+				MarkedAsParticipatingAt: time.Now(),
+			}
+			err = m.AddRSVPSessionsMessageRSVPParticipantsG(context.Background(), true, participant)
+		} else {
+			participant.JoinState = int16(ParticipantStateJoining)
+			participant.MarkedAsParticipatingAt = time.Now()
+			_, err = participant.UpdateG(context.Background(), boil.Infer())
+		}
+	} else {
+		if participant == nil || participant.JoinState != int16(ParticipantStateJoining) {
+			return
+		}
+
+		participant.JoinState = int16(ParticipantStateNotJoining)
+		_, err = participant.UpdateG(context.Background(), boil.Infer())
+	}
+
+	if err != nil {
+		logger.WithError(err).WithField("guild", guildID).Error("failed updating rsvp participant")
+		return
+	}
+
+	queueEmbedUpdate(m.MessageID, m.GuildID)
+}
+
+func (p *Plugin) handleScheduledEventUpdate(evt *eventsystem.EventData) {
+	data := evt.GuildScheduledEventUpdate()
+	if data.Status != discordgo.GuildScheduledEventStatusCompleted && data.Status != discordgo.GuildScheduledEventStatusCanceled {
+		return
+	}
+
+	err := unlinkScheduledEvent(data.ID)
+	if err != nil {
+		logger.WithError(err).WithField("guild", data.GuildID).Error("failed unlinking scheduled event")
+	}
+}
+
+func (p *Plugin) handleScheduledEventDelete(evt *eventsystem.EventData) {
+	data := evt.GuildScheduledEventDelete()
+	err := unlinkScheduledEvent(data.ID)
+	if err != nil {
+		logger.WithError(err).WithField("guild", data.GuildID).Error("failed unlinking scheduled event")
+	}
+}
@@ -0,0 +1,30 @@
+package rsvp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/botlabs-gg/yagpdb/v2/rsvp/models"
+)
+
+func TestScheduledEventDescription(t *testing.T) {
+	m := &models.RSVPSession{GuildID: 1, ChannelID: 2, MessageID: 3, MaxParticipants: 2}
+	m.R = m.R.NewStruct()
+	for _, state := range []ParticipantState{ParticipantStateJoining, ParticipantStateMaybe, ParticipantStateJoining, ParticipantStateJoining, ParticipantStateWaitlist} {
+		m.R.RSVPSessionsMessageRSVPParticipants = append(m.R.RSVPSessionsMessageRSVPParticipants, &models.RSVPParticipant{JoinState: int16(state)})
+	}
+
+	desc := scheduledEventDescription(m)
+	if !strings.HasPrefix(desc, "Sign up at https://discord.com/channels/1/2/3") {
+		t.Errorf("missing message link: %q", desc)
+	}
+
+	if !strings.HasSuffix(desc, "Participants: 2 / 2, waiting list: 2") {
+		t.Errorf("unexpected participant counts: %q", desc)
+	}
+
+	m.MaxParticipants = 0
+	if desc = scheduledEventDescription(m); !strings.HasSuffix(desc, "Participants: 3") {
+		t.Errorf("unexpected participant counts without a limit: %q", desc)
+	}
+}
@@ -32,4 +32,11 @@ CREATE TABLE IF NOT EXISTS rsvp_participants (
 
 	PRIMARY KEY(rsvp_sessions_message_id, user_id)
 );
+`, `
+CREATE TABLE IF NOT EXISTS rsvp_scheduled_events (
+	rsvp_sessions_message_id BIGINT PRIMARY KEY REFERENCES rsvp_sessions(message_id) ON DELETE CASCADE,
+
+	guild_id BIGINT NOT NULL,
+	scheduled_event_id BIGINT NOT NULL UNIQUE
+);
 `}
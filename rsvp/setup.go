@@ -49,6 +49,9 @@ type SetupSession struct {
 	Channel         int64
 	When            time.Time
 
+	// also create a discord scheduled event for it
+	CreateScheduledEvent bool
+
 	LastAction time.Time
 	stopCH     chan bool
 	stopped    bool
@@ -283,6 +286,18 @@ func (s *SetupSession) Finish() {
 		return
 	}
 
+	if s.CreateScheduledEvent {
+		err = createScheduledEvent(m)
+		if err != nil {
+			logger.WithError(err).WithField("guild", s.GuildID).Error("failed creating scheduled event")
+			msg := "Created the event, but failed creating the event in the server's events tab"
+			if code, _ := common.DiscordError(err); code == discordgo.ErrCodeMissingPermissions {
+				msg += ", the bot needs the `Manage Events` permission for it"
+			}
+			common.BotSession.ChannelMessageSend(s.SetupChannel, "[RSVP Event Setup]: "+msg)
+		}
+	}
+
 	go s.remove()
 
 	// finish by deleting the setup messages
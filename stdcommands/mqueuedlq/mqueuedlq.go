@@ -0,0 +1,72 @@
+package mqueuedlq
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/commands"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/mqueue"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
+	"github.com/botlabs-gg/yagpdb/v2/stdcommands/util"
+)
+
+var Command = &commands.YAGCommand{
+	Cooldown:    2,
+	CmdCategory: commands.CategoryDebug,
+	Name:        "MqueueDeadLetters",
+	Aliases:     []string{"dlq"},
+	Description: "Lists, replays or deletes mqueue items that failed to send. Bot Admin Only",
+	Arguments: []*dcmd.ArgDef{
+		{Name: "Action", Help: "list, replay or delete", Type: dcmd.String, Default: "list"},
+		{Name: "ID", Help: "The ID of the item, or the number of items to skip when listing", Type: dcmd.BigInt, Default: int64(0)},
+	},
+	HideFromHelp:         true,
+	HideFromCommandsPage: true,
+	RunFunc: util.RequireBotAdmin(func(data *dcmd.Data) (interface{}, error) {
+		id := data.Args[1].Int64()
+
+		switch strings.ToLower(data.Args[0].Str()) {
+		case "list":
+			return listDeadLetters(int(id))
+		case "replay":
+			dl, err := mqueue.ReplayDeadLetter(id)
+			if err == mqueue.ErrDeadLetterNotFound {
+				return "Unknown dead letter", nil
+			} else if err != nil {
+				return nil, err
+			}
+
+			return fmt.Sprintf("Queued #%d from %s again", dl.Elem.ID, dl.Elem.Source), nil
+		case "delete", "del":
+			err := mqueue.DeleteDeadLetter(id)
+			if err != nil {
+				return nil, err
+			}
+
+			return fmt.Sprintf("Deleted #%d", id), nil
+		}
+
+		return "Unknown action, use list, replay or delete", nil
+	}),
+}
+
+func listDeadLetters(skip int) (interface{}, error) {
+	letters, total, err := mqueue.GetDeadLetters(skip, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(letters) < 1 {
+		return fmt.Sprintf("No dead letters (%d total)", total), nil
+	}
+
+	out := fmt.Sprintf("Dead letters %d-%d of %d:\n```", skip+1, skip+len(letters), total)
+	for _, v := range letters {
+		out += fmt.Sprintf("\n#%-8d %-12s g:%d c:%d attempts:%d %s ago\n  %s", v.Elem.ID, v.Elem.Source, v.Elem.GuildID, v.Elem.ChannelID, v.Attempts,
+			common.HumanizeDuration(common.DurationPrecisionMinutes, time.Since(v.FailedAt)), common.CutStringShort(v.Error, 150))
+	}
+
+	return out + "\n```", nil
+}
@@ -13,7 +13,7 @@ var (
 	Command       = &commands.YAGCommand{
 		CmdCategory:         commands.CategoryTool,
 		Name:                "Poll",
-		Description:         "Create very simple reaction poll. Example: `poll \"favorite color?\" blue red pink`",
+		Description:         "Create very simple reaction poll. Example: `poll \"favorite color?\" blue red pink`, see `polls create` for polls with buttons, expiry and results",
 		RequiredArgs:        3,
 		SlashCommandEnabled: true,
 		Arguments: []*dcmd.ArgDef{
@@ -34,6 +34,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/stdcommands/listflags"
 	"github.com/botlabs-gg/yagpdb/v2/stdcommands/listroles"
 	"github.com/botlabs-gg/yagpdb/v2/stdcommands/memstats"
+	"github.com/botlabs-gg/yagpdb/v2/stdcommands/mqueuedlq"
 	"github.com/botlabs-gg/yagpdb/v2/stdcommands/ping"
 	"github.com/botlabs-gg/yagpdb/v2/stdcommands/poll"
 	"github.com/botlabs-gg/yagpdb/v2/stdcommands/roast"
@@ -131,6 +132,7 @@ func (p *Plugin) AddCommands() {
 		toggledbg.Command,
 		globalrl.Command,
 		listflags.Command,
+		mqueuedlq.Command,
 	)
 
 	statedbg.Commands()
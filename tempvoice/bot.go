@@ -0,0 +1,305 @@
+package tempvoice
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/bot/eventsystem"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/botlabs-gg/yagpdb/v2/premium"
+)
+
+var _ premium.RemovedPremiumGuildListener = (*Plugin)(nil)
+
+func (p *Plugin) BotInit() {
+	// Use OrderSyncPreState to get the state before it's updated
+	eventsystem.AddHandlerFirst(p, handleVoiceStateUpdate, eventsystem.EventVoiceStateUpdate)
+	eventsystem.AddHandlerAsyncLastLegacy(p, handleGuildCreate, eventsystem.EventGuildCreate)
+	eventsystem.AddHandlerAsyncLastLegacy(p, handleChannelDelete, eventsystem.EventChannelDelete)
+}
+
+func handleVoiceStateUpdate(evt *eventsystem.EventData) (retry bool, err error) {
+	vs := evt.VoiceStateUpdate()
+	if vs.UserID == 0 {
+		return false, nil
+	}
+
+	gs := bot.State.GetGuild(vs.GuildID)
+	if gs == nil {
+		return false, nil
+	}
+
+	beforeChannelID := int64(0)
+	if oldVs := gs.GetVoiceState(vs.UserID); oldVs != nil {
+		beforeChannelID = oldVs.ChannelID
+	}
+
+	afterChannelID := vs.ChannelID
+	if beforeChannelID == afterChannelID {
+		return false, nil
+	}
+
+	// The state is not updated yet, so the channel is empty if the user was the only one in it
+	if beforeChannelID != 0 && countChannelMembers(gs, beforeChannelID, vs.UserID) == 0 {
+		go deleteIfEmptyTempChannel(gs.ID, beforeChannelID)
+	}
+
+	if afterChannelID != 0 {
+		go handleJoinChannel(gs.ID, vs.UserID, afterChannelID)
+	}
+
+	return false, nil
+}
+
+// countChannelMembers returns the number of members in the voice channel, excluding the provided user
+func countChannelMembers(gs *dstate.GuildSet, channelID int64, excludeUserID int64) int {
+	count := 0
+	for _, v := range gs.VoiceStates {
+		if v.ChannelID == channelID && v.UserID != excludeUserID {
+			count++
+		}
+	}
+
+	return count
+}
+
+var (
+	creatingLock sync.Mutex
+	creating     = make(map[int64]bool)
+)
+
+func handleJoinChannel(guildID int64, userID int64, channelID int64) {
+	ctx := context.Background()
+
+	hub, err := GetEnabledHubByChannel(ctx, channelID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.WithError(err).WithField("guild", guildID).Error("Failed retrieving temp voice hub")
+		}
+		return
+	}
+
+	// Don't create several channels if the user joins the hub again while we're still creating one
+	creatingLock.Lock()
+	if creating[userID] {
+		creatingLock.Unlock()
+		return
+	}
+	creating[userID] = true
+	creatingLock.Unlock()
+
+	defer func() {
+		creatingLock.Lock()
+		delete(creating, userID)
+		creatingLock.Unlock()
+	}()
+
+	err = createTempChannel(ctx, hub, userID)
+	if err != nil {
+		code, _ := common.DiscordError(err)
+		if code == discordgo.ErrCodeMissingPermissions {
+			logger.WithError(err).WithField("guild", guildID).Warn("Failed creating temp voice channel (permissions issue), disabling hub")
+			hub.Enabled = false
+			UpdateHub(ctx, hub)
+			return
+		}
+
+		logger.WithError(err).WithField("guild", guildID).WithField("user", userID).Error("Failed creating temp voice channel")
+	}
+}
+
+func createTempChannel(ctx context.Context, hub *Hub, userID int64) error {
+	gs := bot.State.GetGuild(hub.GuildID)
+	if gs == nil {
+		return nil
+	}
+
+	// Move the user back to their channel if they already have one
+	existing, err := GetOwnedTempChannel(ctx, hub.GuildID, userID)
+	if err == nil && gs.GetChannel(existing.ChannelID) != nil {
+		return common.BotSession.GuildMemberMove(hub.GuildID, userID, existing.ChannelID)
+	} else if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	ms, err := bot.GetMember(hub.GuildID, userID)
+	if err != nil {
+		return err
+	}
+
+	hubChannel := gs.GetChannel(hub.ChannelID)
+	if hubChannel == nil {
+		return nil
+	}
+
+	parentID := hub.CategoryID
+	if parentID == 0 || gs.GetChannel(parentID) == nil {
+		parentID = hubChannel.ParentID
+	}
+
+	// Keep the permissions of the category, and give the owner access even if the channel gets locked
+	var overwrites []*discordgo.PermissionOverwrite
+	if parent := gs.GetChannel(parentID); parent != nil {
+		for _, v := range parent.PermissionOverwrites {
+			v := v
+			overwrites = append(overwrites, &v)
+		}
+	}
+	overwrites = append(overwrites, &discordgo.PermissionOverwrite{
+		ID:    userID,
+		Type:  discordgo.PermissionOverwriteTypeMember,
+		Allow: discordgo.PermissionVoiceConnect,
+	})
+
+	name := channelName(hub.NameTemplate, memberDisplayName(ms))
+	channel, err := common.BotSession.GuildChannelCreateWithOverwrites(hub.GuildID, name, discordgo.ChannelTypeGuildVoice, parentID, overwrites)
+	if err != nil {
+		return err
+	}
+
+	err = InsertTempChannel(ctx, &TempChannel{
+		ChannelID: channel.ID,
+		GuildID:   hub.GuildID,
+		HubID:     hub.ID,
+		OwnerID:   userID,
+	})
+	if err != nil {
+		common.BotSession.ChannelDelete(channel.ID)
+		return err
+	}
+
+	if hub.UserLimit > 0 {
+		_, err = common.BotSession.ChannelEditComplex(channel.ID, &discordgo.ChannelEdit{UserLimit: hub.UserLimit})
+		if err != nil {
+			logger.WithError(err).WithField("guild", hub.GuildID).Warn("Failed setting temp voice channel user limit")
+		}
+	}
+
+	err = common.BotSession.GuildMemberMove(hub.GuildID, userID, channel.ID)
+	if err != nil {
+		// Most likely they left the hub before we could move them
+		deleteTempChannel(hub.GuildID, channel.ID)
+		return nil
+	}
+
+	return nil
+}
+
+func memberDisplayName(ms *dstate.MemberState) string {
+	if ms.Member != nil && ms.Member.Nick != "" {
+		return ms.Member.Nick
+	}
+
+	if ms.User.Globalname != "" {
+		return ms.User.Globalname
+	}
+
+	return ms.User.Username
+}
+
+// channelName returns the name of a new temporary channel from the template of the hub
+func channelName(template string, displayName string) string {
+	if strings.TrimSpace(template) == "" {
+		template = DefaultNameTemplate
+	}
+
+	name := strings.TrimSpace(strings.ReplaceAll(template, "{user}", displayName))
+	if utf8.RuneCountInString(name) > MaxChannelNameLength {
+		name = string([]rune(name)[:MaxChannelNameLength])
+	}
+
+	return name
+}
+
+func deleteIfEmptyTempChannel(guildID int64, channelID int64) {
+	_, err := GetTempChannel(context.Background(), channelID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.WithError(err).WithField("guild", guildID).Error("Failed retrieving temp voice channel")
+		}
+		return
+	}
+
+	// Someone might have joined in the meantime
+	if gs := bot.State.GetGuild(guildID); gs != nil && countChannelMembers(gs, channelID, 0) > 0 {
+		return
+	}
+
+	deleteTempChannel(guildID, channelID)
+}
+
+func deleteTempChannel(guildID int64, channelID int64) {
+	_, err := common.BotSession.ChannelDelete(channelID)
+	if err != nil && !common.IsDiscordErr(err, discordgo.ErrCodeUnknownChannel) {
+		logger.WithError(err).WithField("guild", guildID).Error("Failed deleting temp voice channel")
+		return
+	}
+
+	err = DeleteTempChannel(context.Background(), channelID)
+	if err != nil {
+		logger.WithError(err).WithField("guild", guildID).Error("Failed removing temp voice channel")
+	}
+}
+
+// handleGuildCreate cleans up the temporary channels that were emptied or deleted while the bot was down
+func handleGuildCreate(evt *eventsystem.EventData) {
+	g := evt.GuildCreate()
+	if g.Unavailable {
+		return
+	}
+
+	channels, err := GetGuildTempChannels(evt.Context(), g.ID)
+	if err != nil {
+		logger.WithError(err).WithField("guild", g.ID).Error("Failed retrieving temp voice channels")
+		return
+	}
+
+	if len(channels) < 1 {
+		return
+	}
+
+	gs := bot.State.GetGuild(g.ID)
+	if gs == nil {
+		return
+	}
+
+	for _, v := range channels {
+		if gs.GetChannel(v.ChannelID) == nil {
+			err = DeleteTempChannel(evt.Context(), v.ChannelID)
+			if err != nil {
+				logger.WithError(err).WithField("guild", g.ID).Error("Failed removing deleted temp voice channel")
+			}
+			continue
+		}
+
+		if countChannelMembers(gs, v.ChannelID, 0) == 0 {
+			deleteTempChannel(g.ID, v.ChannelID)
+		}
+	}
+}
+
+func handleChannelDelete(evt *eventsystem.EventData) {
+	cd := evt.ChannelDelete()
+	if cd.GuildID == 0 || (cd.Type != discordgo.ChannelTypeGuildVoice && cd.Type != discordgo.ChannelTypeGuildStageVoice) {
+		return
+	}
+
+	err := DeleteTempChannel(evt.Context(), cd.ID)
+	if err != nil {
+		logger.WithError(err).WithField("guild", cd.GuildID).Error("Failed removing deleted temp voice channel")
+	}
+}
+
+func (p *Plugin) OnRemovedPremiumGuild(guildID int64) error {
+	err := DisableHubs(context.Background(), guildID, MaxHubs)
+	if err != nil {
+		logger.WithError(err).WithField("guild", guildID).Error("failed disabling temp voice hubs for limit enforcement")
+	}
+	return err
+}
@@ -0,0 +1,31 @@
+package tempvoice
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChannelName(t *testing.T) {
+	testcases := []struct {
+		template string
+		user     string
+		expected string
+	}{
+		{template: "", user: "bob", expected: "bob's channel"},
+		{template: "  ", user: "bob", expected: "bob's channel"},
+		{template: "🔊 {user}", user: "alice", expected: "🔊 alice"},
+		{template: "gaming", user: "alice", expected: "gaming"},
+		{template: "{user} & {user}", user: "x", expected: "x & x"},
+	}
+
+	for _, v := range testcases {
+		if result := channelName(v.template, v.user); result != v.expected {
+			t.Errorf("channelName(%q, %q) = %q, expected %q", v.template, v.user, result, v.expected)
+		}
+	}
+
+	long := channelName("{user}", strings.Repeat("é", 150))
+	if n := len([]rune(long)); n != MaxChannelNameLength {
+		t.Errorf("expected long names to be cut to %d characters, got %d", MaxChannelNameLength, n)
+	}
+}
@@ -0,0 +1,251 @@
+package tempvoice
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/botlabs-gg/yagpdb/v2/commands"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dcmd"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+)
+
+func (p *Plugin) AddCommands() {
+	cmdRename := &commands.YAGCommand{
+		CmdCategory:  commands.CategoryTool,
+		Name:         "Rename",
+		Description:  "Renames your temporary voice channel",
+		RequiredArgs: 1,
+		Arguments: []*dcmd.ArgDef{
+			{Name: "Name", Type: dcmd.String},
+		},
+		SlashCommandEnabled: true,
+		DefaultEnabled:      true,
+		Plugin:              p,
+		RunFunc: requireOwner(func(parsed *dcmd.Data, tc *TempChannel, cs *dstate.ChannelState) (interface{}, error) {
+			if strings.TrimSpace(parsed.Args[0].Str()) == "" {
+				return "The channel name can't be empty", nil
+			}
+
+			name := channelName(parsed.Args[0].Str(), "")
+			_, err := common.BotSession.ChannelEditComplex(tc.ChannelID, &discordgo.ChannelEdit{Name: name})
+			if err != nil {
+				return nil, err
+			}
+
+			return fmt.Sprintf("Renamed your channel to `%s`", name), nil
+		}),
+	}
+
+	cmdLock := &commands.YAGCommand{
+		CmdCategory:         commands.CategoryTool,
+		Name:                "Lock",
+		Description:         "Locks your temporary voice channel so no one else can join it",
+		SlashCommandEnabled: true,
+		DefaultEnabled:      true,
+		Plugin:              p,
+		RunFunc: requireOwner(func(parsed *dcmd.Data, tc *TempChannel, cs *dstate.ChannelState) (interface{}, error) {
+			allow, deny := everyoneOverwrite(cs)
+			err := common.BotSession.ChannelPermissionSet(tc.ChannelID, tc.GuildID, discordgo.PermissionOverwriteTypeRole, allow&^discordgo.PermissionVoiceConnect, deny|discordgo.PermissionVoiceConnect)
+			if err != nil {
+				return nil, err
+			}
+
+			return "Locked your channel", nil
+		}),
+	}
+
+	cmdUnlock := &commands.YAGCommand{
+		CmdCategory:         commands.CategoryTool,
+		Name:                "Unlock",
+		Description:         "Unlocks your temporary voice channel",
+		SlashCommandEnabled: true,
+		DefaultEnabled:      true,
+		Plugin:              p,
+		RunFunc: requireOwner(func(parsed *dcmd.Data, tc *TempChannel, cs *dstate.ChannelState) (interface{}, error) {
+			allow, deny := everyoneOverwrite(cs)
+			err := common.BotSession.ChannelPermissionSet(tc.ChannelID, tc.GuildID, discordgo.PermissionOverwriteTypeRole, allow, deny&^discordgo.PermissionVoiceConnect)
+			if err != nil {
+				return nil, err
+			}
+
+			return "Unlocked your channel", nil
+		}),
+	}
+
+	cmdLimit := &commands.YAGCommand{
+		CmdCategory:  commands.CategoryTool,
+		Name:         "Limit",
+		Description:  "Sets the max number of members in your temporary voice channel, 0 to remove the limit",
+		RequiredArgs: 1,
+		Arguments: []*dcmd.ArgDef{
+			{Name: "Limit", Type: &dcmd.IntArg{Min: 0, Max: 99}},
+		},
+		SlashCommandEnabled: true,
+		DefaultEnabled:      true,
+		Plugin:              p,
+		RunFunc: requireOwner(func(parsed *dcmd.Data, tc *TempChannel, cs *dstate.ChannelState) (interface{}, error) {
+			limit := parsed.Args[0].Int()
+
+			err := setUserLimit(tc.ChannelID, limit)
+			if err != nil {
+				return nil, err
+			}
+
+			if limit == 0 {
+				return "Removed the member limit of your channel", nil
+			}
+			return fmt.Sprintf("Your channel is now limited to %d members", limit), nil
+		}),
+	}
+
+	cmdTransfer := &commands.YAGCommand{
+		CmdCategory:  commands.CategoryTool,
+		Name:         "Transfer",
+		Description:  "Gives the ownership of your temporary voice channel to another member in it",
+		RequiredArgs: 1,
+		Arguments: []*dcmd.ArgDef{
+			{Name: "Member", Type: dcmd.UserID},
+		},
+		SlashCommandEnabled: true,
+		DefaultEnabled:      true,
+		Plugin:              p,
+		RunFunc: requireOwner(func(parsed *dcmd.Data, tc *TempChannel, cs *dstate.ChannelState) (interface{}, error) {
+			target := parsed.Args[0].Int64()
+			if target == tc.OwnerID {
+				return "You already own this channel", nil
+			}
+
+			vs := parsed.GuildData.GS.GetVoiceState(target)
+			if vs == nil || vs.ChannelID != tc.ChannelID {
+				return "That member has to be in your channel", nil
+			}
+
+			err := setOwner(parsed, tc, target)
+			if err != nil {
+				return nil, err
+			}
+
+			return fmt.Sprintf("Transferred your channel to <@%d>", target), nil
+		}),
+	}
+
+	cmdClaim := &commands.YAGCommand{
+		CmdCategory:         commands.CategoryTool,
+		Name:                "Claim",
+		Description:         "Takes the ownership of the temporary voice channel you're in if the owner left it",
+		SlashCommandEnabled: true,
+		DefaultEnabled:      true,
+		Plugin:              p,
+		RunFunc: func(parsed *dcmd.Data) (interface{}, error) {
+			tc, _, msg, err := currentTempChannel(parsed)
+			if tc == nil || err != nil {
+				return msg, err
+			}
+
+			if tc.OwnerID == parsed.Author.ID {
+				return "You already own this channel", nil
+			}
+
+			if vs := parsed.GuildData.GS.GetVoiceState(tc.OwnerID); vs != nil && vs.ChannelID == tc.ChannelID {
+				return "The owner is still in the channel", nil
+			}
+
+			err = setOwner(parsed, tc, parsed.Author.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			return "You now own this channel", nil
+		},
+	}
+
+	container, _ := commands.CommandSystem.Root.Sub("tempvoice", "tvc")
+	container.NotFound = commands.CommonContainerNotFoundHandler(container, "")
+	container.Description = "Manage your temporary voice channel"
+
+	container.AddCommand(cmdRename, cmdRename.GetTrigger())
+	container.AddCommand(cmdLock, cmdLock.GetTrigger())
+	container.AddCommand(cmdUnlock, cmdUnlock.GetTrigger())
+	container.AddCommand(cmdLimit, cmdLimit.GetTrigger())
+	container.AddCommand(cmdTransfer, cmdTransfer.GetTrigger())
+	container.AddCommand(cmdClaim, cmdClaim.GetTrigger())
+	commands.RegisterSlashCommandsContainer(container, true, func(gs *dstate.GuildSet) ([]int64, error) {
+		return nil, nil
+	})
+}
+
+// currentTempChannel returns the temporary channel the author is in, msg is set if they're not in one
+func currentTempChannel(parsed *dcmd.Data) (tc *TempChannel, cs *dstate.ChannelState, msg string, err error) {
+	vs := parsed.GuildData.GS.GetVoiceState(parsed.Author.ID)
+	if vs == nil || vs.ChannelID == 0 {
+		return nil, nil, "You're not in a voice channel", nil
+	}
+
+	cs = parsed.GuildData.GS.GetChannel(vs.ChannelID)
+	if cs == nil {
+		return nil, nil, "You're not in a temporary voice channel", nil
+	}
+
+	tc, err = GetTempChannel(parsed.Context(), vs.ChannelID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, "You're not in a temporary voice channel", nil
+		}
+		return nil, nil, "", err
+	}
+
+	return tc, cs, "", nil
+}
+
+func requireOwner(inner func(parsed *dcmd.Data, tc *TempChannel, cs *dstate.ChannelState) (interface{}, error)) dcmd.RunFunc {
+	return func(parsed *dcmd.Data) (interface{}, error) {
+		tc, cs, msg, err := currentTempChannel(parsed)
+		if tc == nil || err != nil {
+			return msg, err
+		}
+
+		if tc.OwnerID != parsed.Author.ID {
+			return "Only the owner of this channel can do that", nil
+		}
+
+		return inner(parsed, tc, cs)
+	}
+}
+
+// everyoneOverwrite returns the current permission overwrite of the everyone role in the channel
+func everyoneOverwrite(cs *dstate.ChannelState) (allow, deny int64) {
+	for _, v := range cs.PermissionOverwrites {
+		if v.Type == discordgo.PermissionOverwriteTypeRole && v.ID == cs.GuildID {
+			return v.Allow, v.Deny
+		}
+	}
+
+	return 0, 0
+}
+
+// setUserLimit sets the user limit of the voice channel, ChannelEdit omits the limit when it's 0 so this uses a raw request
+func setUserLimit(channelID int64, limit int) error {
+	data := struct {
+		UserLimit int `json:"user_limit"`
+	}{limit}
+
+	_, err := common.BotSession.RequestWithBucketID("PATCH", discordgo.EndpointChannel(channelID), data, nil, discordgo.EndpointChannel(channelID))
+	return err
+}
+
+func setOwner(parsed *dcmd.Data, tc *TempChannel, newOwner int64) error {
+	err := common.BotSession.ChannelPermissionSet(tc.ChannelID, newOwner, discordgo.PermissionOverwriteTypeMember, discordgo.PermissionVoiceConnect, 0)
+	if err != nil {
+		return err
+	}
+
+	err = common.BotSession.ChannelPermissionDelete(tc.ChannelID, tc.OwnerID)
+	if err != nil && !common.IsDiscordErr(err, discordgo.ErrCodeUnknownOverwrite) {
+		logger.WithError(err).WithField("guild", tc.GuildID).Warn("Failed removing old temp voice channel owner overwrite")
+	}
+
+	return SetTempChannelOwner(parsed.Context(), tc.ChannelID, newOwner)
+}
@@ -0,0 +1,168 @@
+package tempvoice
+
+import (
+	"context"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+)
+
+// Hub is a voice channel that creates a temporary channel for members joining it
+type Hub struct {
+	ID        int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	GuildID   int64
+	ChannelID int64
+
+	// Category to create the channels in, 0 to use the category of the hub
+	CategoryID   int64
+	NameTemplate string
+	UserLimit    int
+	Enabled      bool
+}
+
+// TempChannel is a channel created from a hub, deleted when it's empty
+type TempChannel struct {
+	ChannelID int64
+	GuildID   int64
+	HubID     int64
+	OwnerID   int64
+	CreatedAt time.Time
+}
+
+const hubColumns = "id, created_at, updated_at, guild_id, channel_id, category_id, name_template, user_limit, enabled"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanHub(row rowScanner) (*Hub, error) {
+	h := &Hub{}
+	err := row.Scan(&h.ID, &h.CreatedAt, &h.UpdatedAt, &h.GuildID, &h.ChannelID, &h.CategoryID, &h.NameTemplate, &h.UserLimit, &h.Enabled)
+	return h, err
+}
+
+// GetHubs returns all the hubs of a guild
+func GetHubs(ctx context.Context, guildID int64) ([]*Hub, error) {
+	rows, err := common.PQ.QueryContext(ctx, "SELECT "+hubColumns+" FROM tempvoice_hubs WHERE guild_id = $1 ORDER BY id ASC", guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Hub
+	for rows.Next() {
+		h, err := scanHub(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, h)
+	}
+
+	return result, rows.Err()
+}
+
+// GetEnabledHubByChannel returns the enabled hub for the channel, or sql.ErrNoRows if there is none
+func GetEnabledHubByChannel(ctx context.Context, channelID int64) (*Hub, error) {
+	return scanHub(common.PQ.QueryRowContext(ctx, "SELECT "+hubColumns+" FROM tempvoice_hubs WHERE channel_id = $1 AND enabled = true", channelID))
+}
+
+func GetHub(ctx context.Context, guildID, id int64) (*Hub, error) {
+	return scanHub(common.PQ.QueryRowContext(ctx, "SELECT "+hubColumns+" FROM tempvoice_hubs WHERE guild_id = $1 AND id = $2", guildID, id))
+}
+
+func CountHubs(ctx context.Context, guildID int64, onlyEnabled bool) (int, error) {
+	var count int
+	err := common.PQ.QueryRowContext(ctx, "SELECT count(*) FROM tempvoice_hubs WHERE guild_id = $1 AND (enabled OR NOT $2)", guildID, onlyEnabled).Scan(&count)
+	return count, err
+}
+
+func InsertHub(ctx context.Context, h *Hub) error {
+	h.CreatedAt = time.Now()
+	h.UpdatedAt = h.CreatedAt
+
+	const q = `INSERT INTO tempvoice_hubs (created_at, updated_at, guild_id, channel_id, category_id, name_template, user_limit, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id`
+
+	return common.PQ.QueryRowContext(ctx, q, h.CreatedAt, h.UpdatedAt, h.GuildID, h.ChannelID, h.CategoryID, h.NameTemplate, h.UserLimit, h.Enabled).Scan(&h.ID)
+}
+
+func UpdateHub(ctx context.Context, h *Hub) error {
+	h.UpdatedAt = time.Now()
+
+	const q = `UPDATE tempvoice_hubs SET updated_at = $3, channel_id = $4, category_id = $5, name_template = $6, user_limit = $7, enabled = $8
+WHERE guild_id = $1 AND id = $2`
+
+	_, err := common.PQ.ExecContext(ctx, q, h.GuildID, h.ID, h.UpdatedAt, h.ChannelID, h.CategoryID, h.NameTemplate, h.UserLimit, h.Enabled)
+	return err
+}
+
+func DeleteHub(ctx context.Context, guildID, id int64) error {
+	_, err := common.PQ.ExecContext(ctx, "DELETE FROM tempvoice_hubs WHERE guild_id = $1 AND id = $2", guildID, id)
+	return err
+}
+
+// DisableHubs disables all but the first limit hubs, used when a guild loses premium
+func DisableHubs(ctx context.Context, guildID int64, limit int) error {
+	const q = `UPDATE tempvoice_hubs SET enabled = false
+WHERE guild_id = $1 AND id NOT IN (SELECT id FROM tempvoice_hubs WHERE guild_id = $1 AND enabled ORDER BY id ASC LIMIT $2)`
+
+	_, err := common.PQ.ExecContext(ctx, q, guildID, limit)
+	return err
+}
+
+const tempChannelColumns = "channel_id, guild_id, hub_id, owner_id, created_at"
+
+func scanTempChannel(row rowScanner) (*TempChannel, error) {
+	c := &TempChannel{}
+	err := row.Scan(&c.ChannelID, &c.GuildID, &c.HubID, &c.OwnerID, &c.CreatedAt)
+	return c, err
+}
+
+// GetTempChannel returns the temporary channel, or sql.ErrNoRows if the channel isn't one
+func GetTempChannel(ctx context.Context, channelID int64) (*TempChannel, error) {
+	return scanTempChannel(common.PQ.QueryRowContext(ctx, "SELECT "+tempChannelColumns+" FROM tempvoice_channels WHERE channel_id = $1", channelID))
+}
+
+// GetOwnedTempChannel returns the temporary channel owned by the user in the guild, or sql.ErrNoRows if they don't own one
+func GetOwnedTempChannel(ctx context.Context, guildID, ownerID int64) (*TempChannel, error) {
+	return scanTempChannel(common.PQ.QueryRowContext(ctx, "SELECT "+tempChannelColumns+" FROM tempvoice_channels WHERE guild_id = $1 AND owner_id = $2 LIMIT 1", guildID, ownerID))
+}
+
+func GetGuildTempChannels(ctx context.Context, guildID int64) ([]*TempChannel, error) {
+	rows, err := common.PQ.QueryContext(ctx, "SELECT "+tempChannelColumns+" FROM tempvoice_channels WHERE guild_id = $1", guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*TempChannel
+	for rows.Next() {
+		c, err := scanTempChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+
+	return result, rows.Err()
+}
+
+func InsertTempChannel(ctx context.Context, c *TempChannel) error {
+	c.CreatedAt = time.Now()
+	_, err := common.PQ.ExecContext(ctx, "INSERT INTO tempvoice_channels ("+tempChannelColumns+") VALUES ($1, $2, $3, $4, $5)",
+		c.ChannelID, c.GuildID, c.HubID, c.OwnerID, c.CreatedAt)
+	return err
+}
+
+func SetTempChannelOwner(ctx context.Context, channelID, ownerID int64) error {
+	_, err := common.PQ.ExecContext(ctx, "UPDATE tempvoice_channels SET owner_id = $2 WHERE channel_id = $1", channelID, ownerID)
+	return err
+}
+
+func DeleteTempChannel(ctx context.Context, channelID int64) error {
+	_, err := common.PQ.ExecContext(ctx, "DELETE FROM tempvoice_channels WHERE channel_id = $1", channelID)
+	return err
+}
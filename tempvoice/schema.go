@@ -0,0 +1,29 @@
+package tempvoice
+
+var DBSchemas = []string{`
+CREATE TABLE IF NOT EXISTS tempvoice_hubs (
+	id bigserial NOT NULL PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL,
+	guild_id bigint NOT NULL,
+	channel_id bigint NOT NULL,
+	category_id bigint NOT NULL DEFAULT 0,
+	name_template text NOT NULL,
+	user_limit int NOT NULL DEFAULT 0,
+	enabled boolean NOT NULL DEFAULT true
+);
+`, `
+CREATE UNIQUE INDEX IF NOT EXISTS tempvoice_hubs_channel_idx ON tempvoice_hubs(channel_id);
+`, `
+CREATE INDEX IF NOT EXISTS tempvoice_hubs_guild_idx ON tempvoice_hubs(guild_id);
+`, `
+CREATE TABLE IF NOT EXISTS tempvoice_channels (
+	channel_id bigint NOT NULL PRIMARY KEY,
+	guild_id bigint NOT NULL,
+	hub_id bigint NOT NULL,
+	owner_id bigint NOT NULL,
+	created_at timestamptz NOT NULL
+);
+`, `
+CREATE INDEX IF NOT EXISTS tempvoice_channels_guild_idx ON tempvoice_channels(guild_id);
+`}
@@ -0,0 +1,53 @@
+// tempvoice is a plugin that creates temporary voice channels when users join a hub channel ("join to create")
+package tempvoice
+
+import (
+	"context"
+
+	"github.com/botlabs-gg/yagpdb/v2/bot"
+	"github.com/botlabs-gg/yagpdb/v2/commands"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/premium"
+	"github.com/botlabs-gg/yagpdb/v2/web"
+)
+
+type Plugin struct{}
+
+func (p *Plugin) PluginInfo() *common.PluginInfo {
+	return &common.PluginInfo{
+		Name:     "Temporary Voice Channels",
+		SysName:  "tempvoice",
+		Category: common.PluginCategoryMisc,
+	}
+}
+
+var logger = common.GetPluginLogger(&Plugin{})
+
+var (
+	_ common.Plugin            = (*Plugin)(nil)
+	_ web.Plugin               = (*Plugin)(nil)
+	_ bot.BotInitHandler       = (*Plugin)(nil)
+	_ commands.CommandProvider = (*Plugin)(nil)
+)
+
+const (
+	MaxHubs        = 1
+	MaxHubsPremium = 5
+
+	MaxChannelNameLength = 100
+	DefaultNameTemplate  = "{user}'s channel"
+)
+
+func MaxHubsForContext(ctx context.Context) int {
+	if premium.ContextPremium(ctx) {
+		return MaxHubsPremium
+	}
+	return MaxHubs
+}
+
+func RegisterPlugin() {
+	p := &Plugin{}
+	common.RegisterPlugin(p)
+
+	common.InitSchemas("tempvoice", DBSchemas...)
+}
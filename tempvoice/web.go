@@ -0,0 +1,260 @@
+package tempvoice
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/cplogs"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/botlabs-gg/yagpdb/v2/premium"
+	"github.com/botlabs-gg/yagpdb/v2/web"
+	"goji.io"
+	"goji.io/pat"
+)
+
+//go:embed assets/tempvoice.html
+var PageHTML string
+
+type FormHub struct {
+	ChannelID    int64  `valid:"channel,false"`
+	CategoryID   int64  `valid:"channel,true"`
+	NameTemplate string `valid:",100"`
+	UserLimit    int    `valid:"0,99"`
+	Enabled      bool
+}
+
+func (f *FormHub) Validate(tmpl web.TemplateData, guildID int64) bool {
+	f.NameTemplate = strings.TrimSpace(f.NameTemplate)
+	if f.NameTemplate == "" {
+		f.NameTemplate = DefaultNameTemplate
+	}
+
+	return true
+}
+
+func (f *FormHub) apply(h *Hub) {
+	h.ChannelID = f.ChannelID
+	h.CategoryID = f.CategoryID
+	h.NameTemplate = f.NameTemplate
+	h.UserLimit = f.UserLimit
+	h.Enabled = f.Enabled
+}
+
+var (
+	panelLogKeyNewHub     = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "tempvoice_new_hub", FormatString: "Created temp voice hub for channel %d"})
+	panelLogKeyUpdatedHub = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "tempvoice_updated_hub", FormatString: "Updated temp voice hub %d"})
+	panelLogKeyRemovedHub = cplogs.RegisterActionFormat(&cplogs.ActionFormat{Key: "tempvoice_removed_hub", FormatString: "Removed temp voice hub %d"})
+)
+
+func (p *Plugin) InitWeb() {
+	web.AddHTMLTemplate("tempvoice/assets/tempvoice.html", PageHTML)
+
+	web.AddSidebarItem(web.SidebarCategoryTools, &web.SidebarItem{
+		Name: "Temp Voice Channels",
+		URL:  "tempvoice",
+		Icon: "fas fa-headset",
+	})
+
+	muxer := goji.SubMux()
+
+	web.CPMux.Handle(pat.New("/tempvoice"), muxer)
+	web.CPMux.Handle(pat.New("/tempvoice/*"), muxer)
+
+	muxer.Use(web.RequireBotMemberMW)
+	muxer.Use(premium.PremiumGuildMW)
+	muxer.Use(web.RequirePermMW(discordgo.PermissionManageChannels, discordgo.PermissionVoiceMoveMembers))
+
+	getHandler := web.RenderHandler(handleGetHubs, "cp_tempvoice")
+
+	muxer.Handle(pat.Get(""), getHandler)
+	muxer.Handle(pat.Get("/"), getHandler)
+
+	muxer.Handle(pat.Post("/new"), web.ControllerPostHandler(handleNewHub, getHandler, FormHub{}))
+	muxer.Handle(pat.Post("/:id/update"), web.ControllerPostHandler(handleUpdateHub, getHandler, FormHub{}))
+	muxer.Handle(pat.Post("/:id/delete"), web.ControllerPostHandler(handleDeleteHub, getHandler, nil))
+}
+
+func handleGetHubs(w http.ResponseWriter, r *http.Request) interface{} {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	hubs, err := GetHubs(ctx, activeGuild.ID)
+	web.CheckErr(tmpl, err, "Failed retrieving temp voice hubs", logger.Error)
+
+	tempChannels, err := GetGuildTempChannels(ctx, activeGuild.ID)
+	web.CheckErr(tmpl, err, "Failed retrieving temp voice channels", logger.Error)
+
+	maxAllowed := MaxHubsForContext(ctx)
+	tmpl["Hubs"] = hubs
+	tmpl["ActiveChannels"] = len(tempChannels)
+	tmpl["CanAddMore"] = len(hubs) < maxAllowed
+	tmpl["MaxHubs"] = maxAllowed
+	tmpl["PremiumLimit"] = MaxHubsPremium
+	tmpl["FreeLimit"] = MaxHubs
+	tmpl["DefaultNameTemplate"] = DefaultNameTemplate
+
+	return tmpl
+}
+
+// checkHubChannel returns an alert message if the selected channels can't be used for a hub
+func checkHubChannel(activeGuild *dstate.GuildSet, form *FormHub) string {
+	channel := activeGuild.GetChannel(form.ChannelID)
+	if channel == nil {
+		return "Channel not found"
+	}
+
+	if channel.Type != discordgo.ChannelTypeGuildVoice {
+		return "Selected hub channel must be a voice channel"
+	}
+
+	if form.CategoryID != 0 {
+		category := activeGuild.GetChannel(form.CategoryID)
+		if category == nil || category.Type != discordgo.ChannelTypeGuildCategory {
+			return "Selected category must be a category"
+		}
+	}
+
+	return ""
+}
+
+func handleNewHub(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	form := ctx.Value(common.ContextKeyParsedForm).(*FormHub)
+	if msg := checkHubChannel(activeGuild, form); msg != "" {
+		return tmpl.AddAlerts(web.ErrorAlert(msg)), nil
+	}
+
+	count, err := CountHubs(ctx, activeGuild.ID, false)
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed counting hubs")
+	}
+
+	maxAllowed := MaxHubsForContext(ctx)
+	if count >= maxAllowed {
+		return tmpl.AddAlerts(web.ErrorAlert(fmt.Sprintf("Maximum of %d temp voice hubs reached", maxAllowed))), nil
+	}
+
+	hub := &Hub{GuildID: activeGuild.ID}
+	form.apply(hub)
+	hub.Enabled = true
+
+	err = InsertHub(ctx, hub)
+	if err != nil {
+		if common.ErrPQIsUniqueViolation(err) {
+			return tmpl.AddAlerts(web.ErrorAlert("That channel is already a hub")), nil
+		}
+		return tmpl, errors.WithMessage(err, "failed creating temp voice hub")
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyNewHub, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: form.ChannelID}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Temp voice hub created!")), nil
+}
+
+func handleUpdateHub(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	form := ctx.Value(common.ContextKeyParsedForm).(*FormHub)
+
+	id, err := strconv.ParseInt(pat.Param(r, "id"), 10, 64)
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert("Invalid ID")), nil
+	}
+
+	if msg := checkHubChannel(activeGuild, form); msg != "" {
+		return tmpl.AddAlerts(web.ErrorAlert(msg)), nil
+	}
+
+	hub, err := GetHub(ctx, activeGuild.ID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return tmpl.AddAlerts(web.ErrorAlert("Hub not found")), nil
+		}
+		return tmpl, errors.WithMessage(err, "failed retrieving temp voice hub")
+	}
+
+	if form.Enabled && !hub.Enabled {
+		enabledCount, err := CountHubs(ctx, activeGuild.ID, true)
+		if err != nil {
+			return tmpl, errors.WithMessage(err, "failed counting enabled hubs")
+		}
+
+		limit := MaxHubsForContext(ctx)
+		if enabledCount >= limit {
+			return tmpl.AddAlerts(web.ErrorAlert(fmt.Sprintf("Max %d enabled temp voice hubs allowed", limit))), nil
+		}
+	}
+
+	form.apply(hub)
+	err = UpdateHub(ctx, hub)
+	if err != nil {
+		if common.ErrPQIsUniqueViolation(err) {
+			return tmpl.AddAlerts(web.ErrorAlert("That channel is already a hub")), nil
+		}
+		return tmpl, errors.WithMessage(err, "failed updating temp voice hub")
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyUpdatedHub, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: id}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Temp voice hub updated!")), nil
+}
+
+func handleDeleteHub(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ctx := r.Context()
+	activeGuild, tmpl := web.GetBaseCPContextData(ctx)
+
+	id, err := strconv.ParseInt(pat.Param(r, "id"), 10, 64)
+	if err != nil {
+		return tmpl.AddAlerts(web.ErrorAlert("Invalid ID")), nil
+	}
+
+	// Channels already created from the hub are still deleted once they're empty
+	err = DeleteHub(ctx, activeGuild.ID, id)
+	if err != nil {
+		return tmpl, errors.WithMessage(err, "failed deleting temp voice hub")
+	}
+
+	go cplogs.RetryAddEntry(web.NewLogEntryFromContext(r.Context(), panelLogKeyRemovedHub, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: id}))
+
+	return tmpl.AddAlerts(web.SucessAlert("Temp voice hub deleted!")), nil
+}
+
+var _ web.PluginWithServerHomeWidget = (*Plugin)(nil)
+
+func (p *Plugin) LoadServerHomeWidget(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
+	ag, templateData := web.GetBaseCPContextData(r.Context())
+
+	templateData["WidgetTitle"] = "Temp Voice Channels"
+	templateData["SettingsPath"] = "/tempvoice"
+
+	enabled, err := CountHubs(r.Context(), ag.ID, true)
+	if err != nil {
+		return templateData, err
+	}
+
+	if enabled > 0 {
+		templateData["WidgetEnabled"] = true
+	} else {
+		templateData["WidgetDisabled"] = true
+	}
+
+	format := `<ul>
+	<li>Status: %s</li>
+	<li>Enabled hubs: <code>%d</code></li>
+</ul>`
+
+	templateData["WidgetBody"] = template.HTML(fmt.Sprintf(format, web.EnabledDisabledSpanStatus(enabled > 0), enabled))
+
+	return templateData, nil
+}
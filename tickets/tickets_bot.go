@@ -17,6 +17,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/common/templates"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 	"github.com/botlabs-gg/yagpdb/v2/lib/dstate"
+	"github.com/botlabs-gg/yagpdb/v2/outboundwebhooks"
 	"github.com/botlabs-gg/yagpdb/v2/premium"
 	"github.com/botlabs-gg/yagpdb/v2/tickets/models"
 	"github.com/volatiletech/sqlboiler/v4/boil"
@@ -207,6 +208,13 @@ func CreateTicket(ctx context.Context, gs *dstate.GuildSet, ms *dstate.MemberSta
 		Color:       0x5df948,
 	})
 
+	outboundwebhooks.Emit(gs.ID, outboundwebhooks.EventTicketOpened, &outboundwebhooks.TicketData{
+		TicketID:  dbModel.LocalID,
+		ChannelID: dbModel.ChannelID,
+		Title:     dbModel.Title,
+		Author:    outboundwebhooks.UserData(&ms.User),
+	})
+
 	// And done setting up the ticket
 	return gs, dbModel, nil
 }
@@ -268,6 +276,15 @@ func closeTicket(gs *dstate.GuildSet, currentTicket *Ticket, ticketCS *dstate.Ch
 		Color:       0xf23c3c,
 	})
 
+	outboundwebhooks.Emit(gs.ID, outboundwebhooks.EventTicketClosed, &outboundwebhooks.TicketData{
+		TicketID:  currentTicket.Ticket.LocalID,
+		ChannelID: currentTicket.Ticket.ChannelID,
+		Title:     currentTicket.Ticket.Title,
+		Author:    &outboundwebhooks.User{ID: currentTicket.Ticket.AuthorID, Username: currentTicket.Ticket.AuthorUsernameDiscrim},
+		ClosedBy:  outboundwebhooks.UserData(member),
+		Reason:    reason,
+	})
+
 	cs := gs.GetChannelOrThread(currentTicket.Ticket.ChannelID)
 	if cs == nil {
 		return "", nil
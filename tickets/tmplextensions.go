@@ -16,6 +16,8 @@ import (
 )
 
 func init() {
+	templates.RegisterCallLimit("ticket", templates.CallLimit{Normal: 1, Premium: 1})
+
 	templates.RegisterSetupFunc(func(ctx *templates.Context) {
 		ctx.ContextFuncs["createTicket"] = tmplCreateTicket(ctx)
 	})
@@ -29,7 +31,7 @@ func tmplCreateTicket(ctx *templates.Context) interface{} {
 			return nil, errors.New("cannot nest exec/execAdmin/ticket creation")
 		}
 
-		if ctx.IncreaseCheckCallCounterPremium("ticket", 1, 1) {
+		if ctx.IncreaseCheckCallLimit("ticket") {
 			return nil, templates.ErrTooManyCalls
 		}
 
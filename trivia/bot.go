@@ -11,6 +11,7 @@ import (
 	"github.com/botlabs-gg/yagpdb/v2/bot"
 	"github.com/botlabs-gg/yagpdb/v2/bot/eventsystem"
 	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
 	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
 )
 
@@ -18,6 +19,7 @@ var TriviaDuration = time.Second * 30
 
 func (p *Plugin) BotInit() {
 	eventsystem.AddHandlerAsyncLastLegacy(p, p.handleInteractionCreate, eventsystem.EventInteractionCreate)
+	scheduledevents2.RegisterHandler("trivia_season_end", nil, p.handleSeasonEndScheduledEvent)
 }
 
 func (p *Plugin) handleInteractionCreate(evt *eventsystem.EventData) {
@@ -32,8 +34,9 @@ func (p *Plugin) handleInteractionCreate(evt *eventsystem.EventData) {
 var manager = &triviaSessionManager{}
 
 type triviaSessionManager struct {
-	sessions []*triviaSession
-	mu       sync.Mutex
+	sessions    []*triviaSession
+	tournaments []*triviaTournament
+	mu          sync.Mutex
 }
 
 type pickedOption struct {
@@ -47,6 +50,7 @@ type triviaSession struct {
 	ChannelID       int64
 	MessageID       int64
 	Question        *TriviaQuestion
+	Tournament      *triviaTournament
 	SelectedOptions []*pickedOption
 	createdAt       time.Time
 	startedAt       time.Time
@@ -56,15 +60,16 @@ type triviaSession struct {
 	mu sync.Mutex
 }
 
-var ErrSessionInChannel = errors.New("a trivia session already exists in this channel")
+var (
+	ErrSessionInChannel = errors.New("a trivia session already exists in this channel")
+	ErrNoQuestions      = errors.New("no trivia questions available")
+)
 
 func (tm *triviaSessionManager) NewTrivia(guildID int64, channelID int64, difficulty string) error {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
-	for _, v := range tm.sessions {
-		if v.ChannelID == channelID {
-			return ErrSessionInChannel
-		}
+	if tm.channelBusy(channelID) {
+		return ErrSessionInChannel
 	}
 
 	triviaQuestions, err := FetchQuestions(1, difficulty)
@@ -72,7 +77,31 @@ func (tm *triviaSessionManager) NewTrivia(guildID int64, channelID int64, diffic
 		return err
 	}
 
-	question := triviaQuestions[0]
+	tm.startSession(guildID, channelID, triviaQuestions[0], nil)
+	return nil
+}
+
+// channelBusy returns true if there's a trivia session or tournament running in the channel
+// tm.mu has to be held by the caller
+func (tm *triviaSessionManager) channelBusy(channelID int64) bool {
+	for _, v := range tm.sessions {
+		if v.ChannelID == channelID {
+			return true
+		}
+	}
+
+	for _, v := range tm.tournaments {
+		if v.ChannelID == channelID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startSession starts a new trivia session with the provided question
+// tm.mu has to be held by the caller
+func (tm *triviaSessionManager) startSession(guildID int64, channelID int64, question *TriviaQuestion, tournament *triviaTournament) {
 	var optionEmojis []string
 	if question.Type == "boolean" {
 		optionEmojis = []string{
@@ -94,14 +123,13 @@ func (tm *triviaSessionManager) NewTrivia(guildID int64, channelID int64, diffic
 		GuildID:      guildID,
 		ChannelID:    channelID,
 		Question:     question,
+		Tournament:   tournament,
 		optionEmojis: optionEmojis,
 	}
 
 	tm.sessions = append(tm.sessions, session)
 
 	go session.tickLoop()
-
-	return nil
 }
 
 func (t *triviaSessionManager) removeSession(session *triviaSession) {
@@ -141,6 +169,9 @@ func (t *triviaSession) tickLoop() {
 		ended := t.tick()
 		if ended || time.Since(t.createdAt) > 1*time.Minute {
 			t.Manager.removeSession(t)
+			if t.Tournament != nil {
+				t.Tournament.roundEnded(t)
+			}
 			return
 		}
 		time.Sleep(time.Second)
@@ -254,6 +285,11 @@ func (t *triviaSession) buildButtons() []discordgo.InteractiveComponent {
 func (t *triviaSession) buildEmbed() *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{}
 	embed.Title = fmt.Sprintf("%s difficulty trivia on %s", strings.Title(t.Question.Difficulty), t.Question.Category)
+	if t.Tournament != nil {
+		embed.Author = &discordgo.MessageEmbedAuthor{
+			Name: fmt.Sprintf("Tournament round %d of %d", t.Tournament.currentRound(), len(t.Tournament.Questions)),
+		}
+	}
 	embed.Description += fmt.Sprintf("\n## %s \n\n\n", t.Question.Question)
 
 	embed.Footer = &discordgo.MessageEmbedFooter{
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/botlabs-gg/yagpdb/v2/bot"
 	"github.com/botlabs-gg/yagpdb/v2/bot/paginatedmessages"
@@ -45,6 +46,38 @@ func (p *Plugin) AddCommands() {
 		},
 	}
 
+	cmdTournament := &commands.YAGCommand{
+		Name:        "Tournament",
+		Aliases:     []string{"tour"},
+		Description: "Starts a trivia tournament, running multiple rounds back to back with a final scoreboard",
+		CmdCategory: commands.CategoryFun,
+		Arguments: []*dcmd.ArgDef{
+			{Name: "Rounds", Type: &dcmd.IntArg{Min: MinTournamentRounds, Max: MaxTournamentRounds}, Default: 5, Help: fmt.Sprintf("Number of rounds, between %d and %d", MinTournamentRounds, MaxTournamentRounds)},
+			{
+				Name: "Difficulty", Type: dcmd.String, Default: "none", Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "None", Value: "none"},
+					{Name: "Easy", Value: "easy"},
+					{Name: "Medium", Value: "medium"},
+					{Name: "Hard", Value: "hard"},
+				}, Help: "Difficulty of the trivia, can be none, easy, medium or hard"},
+		},
+		RunFunc: func(parsed *dcmd.Data) (any, error) {
+			difficulty := strings.ToLower(parsed.Args[1].Str())
+			if difficulty != "easy" && difficulty != "medium" && difficulty != "hard" {
+				difficulty = "none"
+			}
+			err := manager.NewTournament(parsed.GuildData.GS.ID, parsed.ChannelID, parsed.Args[0].Int(), difficulty)
+			if err != nil {
+				logger.WithError(err).Error("Failed to create new trivia tournament")
+				if err == ErrSessionInChannel {
+					return "There's already a trivia session in this channel", nil
+				}
+				return "Failed Running Trivia Tournament, unknown error", nil
+			}
+			return nil, nil
+		},
+	}
+
 	cmdRank := &commands.YAGCommand{
 		Name:        "Rank",
 		Description: "Shows your trivia rank",
@@ -219,12 +252,208 @@ func (p *Plugin) AddCommands() {
 		},
 	}
 
+	cmdSeason := &commands.YAGCommand{
+		Name:        "Season",
+		Description: "Shows the current trivia season, or the final leaderboard of a past season",
+		CmdCategory: commands.CategoryFun,
+		ArgSwitches: []*dcmd.ArgDef{
+			{Name: "season", Type: dcmd.Int, Help: "Past season to show the results of", Default: 0},
+		},
+		RunFunc: func(data *dcmd.Data) (any, error) {
+			conf, err := GetSeasonConfig(data.Context(), data.GuildData.GS.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			if !conf.Enabled && conf.CurrentSeason == 1 {
+				return "Trivia seasons are not enabled on this server, an admin can enable them with `trivia seasonsettings -enable`", nil
+			}
+
+			season := data.Switches["season"].Int()
+			if season == 0 {
+				season = conf.CurrentSeason - 1
+			}
+
+			embed := &discordgo.MessageEmbed{
+				Title: fmt.Sprintf("🏆 Trivia Season %d", conf.CurrentSeason),
+				Color: 0xFFD700, // Gold
+			}
+
+			if conf.Enabled {
+				embed.Description = fmt.Sprintf("The current season ends <t:%d:R>", conf.EndsAt().Unix())
+				if conf.RewardRoleID != 0 {
+					embed.Description += fmt.Sprintf("\nThe top %d players will receive <@&%d>", conf.RewardTopN, conf.RewardRoleID)
+				}
+			} else {
+				embed.Description = "Seasons are currently disabled on this server"
+			}
+
+			emojiList := []string{"🥇", "🥈", "🥉"}
+			if conf.Enabled {
+				standings, err := GetSeasonStandings(data.Context(), data.GuildData.GS.ID, SeasonResultsSize)
+				if err != nil {
+					return nil, err
+				}
+
+				field := &discordgo.MessageEmbedField{
+					Name:  "Current Standings",
+					Value: "No one has played this season yet",
+				}
+				if len(standings) > 0 {
+					field.Value = ""
+				}
+
+				for i, s := range standings {
+					rank := fmt.Sprintf("#%d", i+1)
+					if i < len(emojiList) {
+						rank = emojiList[i]
+					}
+					field.Value += fmt.Sprintf("%s <@%d>: Score **%d** | Correct **%d** | Max Streak **%d**\n", rank, s.UserID, s.Score, s.CorrectAnswers, s.MaxStreak)
+				}
+				embed.Fields = append(embed.Fields, field)
+			}
+
+			if season < 1 || season >= conf.CurrentSeason {
+				return embed, nil
+			}
+
+			results, err := GetSeasonResults(data.Context(), data.GuildData.GS.ID, season)
+			if err != nil {
+				return nil, err
+			}
+
+			field := &discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("Season %d Results", season),
+				Value: "No one played that season :(",
+			}
+			if len(results) > 0 {
+				field.Value = ""
+			}
+
+			for _, r := range results {
+				rank := fmt.Sprintf("#%d", r.Rank)
+				if r.Rank <= len(emojiList) {
+					rank = emojiList[r.Rank-1]
+				}
+				field.Value += fmt.Sprintf("%s <@%d>: Score **%d** | Correct **%d** | Max Streak **%d**\n", rank, r.UserID, r.Score, r.CorrectAnswers, r.MaxStreak)
+			}
+			embed.Fields = append(embed.Fields, field)
+
+			return embed, nil
+		},
+	}
+
+	cmdSeasonSettings := &commands.YAGCommand{
+		Name:                "SeasonSettings",
+		Description:         "Configures trivia seasons, which rank players by their score during the season and reset the season scores on a schedule",
+		RequireDiscordPerms: []int64{discordgo.PermissionManageGuild},
+		CmdCategory:         commands.CategoryFun,
+		ArgSwitches: []*dcmd.ArgDef{
+			{Name: "enable", Help: "Enable seasons"},
+			{Name: "disable", Help: "Disable seasons"},
+			{Name: "days", Type: &dcmd.IntArg{Min: MinSeasonLengthDays, Max: MaxSeasonLengthDays}, Help: "Length of a season in days"},
+			{Name: "role", Type: &commands.RoleArg{}, Help: "Role given to the top players of a season"},
+			{Name: "norole", Help: "Don't give a role to the top players of a season"},
+			{Name: "top", Type: &dcmd.IntArg{Min: 1, Max: MaxSeasonRewardTopN}, Help: "Number of top players that receive the role"},
+		},
+		RunFunc: func(data *dcmd.Data) (any, error) {
+			conf, err := GetSeasonConfig(data.Context(), data.GuildData.GS.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			wasEnabled := conf.Enabled
+			oldEnd := conf.EndsAt()
+
+			if data.Switches["enable"].Bool() {
+				conf.Enabled = true
+			}
+			if data.Switches["disable"].Bool() {
+				conf.Enabled = false
+			}
+			if data.Switches["days"].Value != nil {
+				conf.LengthDays = data.Switches["days"].Int()
+			}
+			if data.Switches["role"].Value != nil {
+				conf.RewardRoleID = data.Switches["role"].Value.(*discordgo.Role).ID
+			}
+			if data.Switches["norole"].Bool() {
+				conf.RewardRoleID = 0
+			}
+			if data.Switches["top"].Value != nil {
+				conf.RewardTopN = data.Switches["top"].Int()
+			}
+
+			if conf.Enabled && !wasEnabled {
+				conf.SeasonStartedAt = time.Now()
+			}
+
+			err = SaveSeasonConfig(data.Context(), conf)
+			if err != nil {
+				return nil, err
+			}
+
+			if conf.Enabled && (!wasEnabled || !oldEnd.Equal(conf.EndsAt())) {
+				err = ScheduleSeasonEnd(conf)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if !conf.Enabled {
+				return "Trivia seasons are disabled", nil
+			}
+
+			msg := fmt.Sprintf("Trivia seasons are enabled, season %d ends <t:%d:R> and lasts %d days.", conf.CurrentSeason, conf.EndsAt().Unix(), conf.LengthDays)
+			if conf.RewardRoleID != 0 {
+				msg += fmt.Sprintf(" The top %d players will receive <@&%d>.", conf.RewardTopN, conf.RewardRoleID)
+			}
+			return msg, nil
+		},
+	}
+
+	cmdEndSeason := &commands.YAGCommand{
+		Name:                "EndSeason",
+		Description:         "Ends the current trivia season now, snapshotting and resetting the season scores",
+		RequireDiscordPerms: []int64{discordgo.PermissionManageGuild},
+		CmdCategory:         commands.CategoryFun,
+		RunFunc: func(data *dcmd.Data) (any, error) {
+			conf, err := GetSeasonConfig(data.Context(), data.GuildData.GS.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			if !conf.Enabled {
+				return "Trivia seasons are not enabled on this server", nil
+			}
+
+			endedSeason := conf.CurrentSeason
+			conf, results, err := EndSeason(data.Context(), data.GuildData.GS.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			giveSeasonRewards(data.Context(), conf, endedSeason, results)
+
+			err = ScheduleSeasonEnd(conf)
+			if err != nil {
+				return nil, err
+			}
+
+			return fmt.Sprintf("Season %d ended, use `trivia season` to see the results", endedSeason), nil
+		},
+	}
+
 	container, _ := commands.CommandSystem.Root.Sub("Trivia", "triv")
 	container.Description = "Trivia commands"
 	container.AddCommand(cmdStart, cmdStart.GetTrigger())
+	container.AddCommand(cmdTournament, cmdTournament.GetTrigger())
 	container.AddCommand(cmdRank, cmdRank.GetTrigger())
 	container.AddCommand(cmdLeaderboard, cmdLeaderboard.GetTrigger())
 	container.AddCommand(cmdLbReset, cmdLbReset.GetTrigger())
+	container.AddCommand(cmdSeason, cmdSeason.GetTrigger())
+	container.AddCommand(cmdSeasonSettings, cmdSeasonSettings.GetTrigger())
+	container.AddCommand(cmdEndSeason, cmdEndSeason.GetTrigger())
 
 	commands.RegisterSlashCommandsContainer(container, true, func(gs *dstate.GuildSet) ([]int64, error) {
 		return nil, nil
@@ -20,19 +20,7 @@ func MarkAnswer(ctx context.Context, guildID, userID int64, correct bool, questi
 	}
 	defer tx.Rollback()
 
-	add := 2
-	remove := 1
-	switch question.Difficulty {
-	case "easy":
-		add = 2
-		remove = 1
-	case "medium":
-		add = 3
-		remove = 2
-	case "hard":
-		add = 4
-		remove = 3
-	}
+	add, remove := questionPoints(question)
 
 	u, err := models.FindTriviaUser(ctx, tx, guildID, userID)
 	isNew := false
@@ -75,9 +63,27 @@ func MarkAnswer(ctx context.Context, guildID, userID int64, correct bool, questi
 		return err
 	}
 
+	err = markSeasonAnswer(ctx, tx, guildID, userID, correct, add, remove)
+	if err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
+// questionPoints returns the amount of points gained for a correct answer
+// and lost for an incorrect answer to the question
+func questionPoints(question *TriviaQuestion) (add, remove int) {
+	switch question.Difficulty {
+	case "medium":
+		return 3, 2
+	case "hard":
+		return 4, 3
+	default:
+		return 2, 1
+	}
+}
+
 func GetTopTriviaUsers(guildID int64, limit, offset int, sort string) ([]*models.TriviaUser, error) {
 	mods := []qm.QueryMod{
 		models.TriviaUserWhere.GuildID.EQ(guildID),
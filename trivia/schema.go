@@ -27,4 +27,40 @@ CREATE INDEX IF NOT EXISTS trivia_users_incorrect_answers_idx ON trivia_users(in
 CREATE INDEX IF NOT EXISTS trivia_users_current_streak_idx ON trivia_users(current_streak);
 `, `
 CREATE INDEX IF NOT EXISTS trivia_users_max_streak_idx ON trivia_users(max_streak);
+`, `
+CREATE TABLE IF NOT EXISTS trivia_season_configs (
+	guild_id bigint PRIMARY KEY,
+	enabled boolean NOT NULL DEFAULT false,
+	length_days int NOT NULL DEFAULT 30,
+	reward_role_id bigint NOT NULL DEFAULT 0,
+	reward_top_n int NOT NULL DEFAULT 3,
+	current_season int NOT NULL DEFAULT 1,
+	season_started_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+`, `
+CREATE TABLE IF NOT EXISTS trivia_season_results (
+	guild_id bigint NOT NULL,
+	season int NOT NULL,
+	user_id bigint NOT NULL,
+	rank int NOT NULL,
+	score int NOT NULL,
+	correct_answers int NOT NULL,
+	incorrect_answers int NOT NULL,
+	max_streak int NOT NULL,
+	ended_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+	PRIMARY KEY(guild_id, season, user_id)
+);
+`, `
+CREATE TABLE IF NOT EXISTS trivia_season_scores (
+	guild_id bigint NOT NULL,
+	user_id bigint NOT NULL,
+	score int NOT NULL DEFAULT 0,
+	correct_answers int NOT NULL DEFAULT 0,
+	incorrect_answers int NOT NULL DEFAULT 0,
+	current_streak int NOT NULL DEFAULT 0,
+	max_streak int NOT NULL DEFAULT 0,
+
+	PRIMARY KEY(guild_id, user_id)
+);
 `}
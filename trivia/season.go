@@ -0,0 +1,349 @@
+package trivia
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2"
+	seventsmodels "github.com/botlabs-gg/yagpdb/v2/common/scheduledevents2/models"
+)
+
+const (
+	MinSeasonLengthDays = 1
+	MaxSeasonLengthDays = 365
+	MaxSeasonRewardTopN = 10
+
+	// Number of players stored in the results of each finished season
+	SeasonResultsSize = 10
+)
+
+// SeasonConfig holds the per guild season settings, when enabled the leaderboard
+// is snapshotted and reset every LengthDays days
+type SeasonConfig struct {
+	GuildID         int64
+	Enabled         bool
+	LengthDays      int
+	RewardRoleID    int64
+	RewardTopN      int
+	CurrentSeason   int
+	SeasonStartedAt time.Time
+}
+
+// EndsAt returns the time the current season ends
+func (c *SeasonConfig) EndsAt() time.Time {
+	return c.SeasonStartedAt.Add(time.Hour * 24 * time.Duration(c.LengthDays))
+}
+
+// startNextSeason rolls the config over to the next season, starting at now
+func (c *SeasonConfig) startNextSeason(now time.Time) {
+	c.CurrentSeason++
+	c.SeasonStartedAt = now
+}
+
+// SeasonScore is the score of a user in the current season, it's kept separate from
+// the all time stats in trivia_users and reset when the season ends
+type SeasonScore struct {
+	GuildID          int64
+	UserID           int64
+	Score            int
+	CorrectAnswers   int
+	IncorrectAnswers int
+	CurrentStreak    int
+	MaxStreak        int
+}
+
+func (s *SeasonScore) addAnswer(correct bool, add, remove int) {
+	if correct {
+		s.Score += add
+		s.CorrectAnswers++
+		s.CurrentStreak++
+		if s.CurrentStreak > s.MaxStreak {
+			s.MaxStreak = s.CurrentStreak
+		}
+	} else {
+		s.Score -= remove
+		s.IncorrectAnswers++
+		s.CurrentStreak = 0
+	}
+}
+
+type SeasonResult struct {
+	GuildID          int64
+	Season           int
+	UserID           int64
+	Rank             int
+	Score            int
+	CorrectAnswers   int
+	IncorrectAnswers int
+	MaxStreak        int
+	EndedAt          time.Time
+}
+
+// GetSeasonConfig returns the season config for the guild, or a disabled default config if none has been saved
+func GetSeasonConfig(ctx context.Context, guildID int64) (*SeasonConfig, error) {
+	const q = `SELECT enabled, length_days, reward_role_id, reward_top_n, current_season, season_started_at
+FROM trivia_season_configs WHERE guild_id = $1`
+
+	conf := &SeasonConfig{GuildID: guildID}
+	err := common.PQ.QueryRowContext(ctx, q, guildID).Scan(&conf.Enabled, &conf.LengthDays, &conf.RewardRoleID, &conf.RewardTopN, &conf.CurrentSeason, &conf.SeasonStartedAt)
+	if err == sql.ErrNoRows {
+		return &SeasonConfig{
+			GuildID:         guildID,
+			LengthDays:      30,
+			RewardTopN:      3,
+			CurrentSeason:   1,
+			SeasonStartedAt: time.Now(),
+		}, nil
+	}
+
+	return conf, err
+}
+
+func SaveSeasonConfig(ctx context.Context, conf *SeasonConfig) error {
+	const q = `INSERT INTO trivia_season_configs (guild_id, enabled, length_days, reward_role_id, reward_top_n, current_season, season_started_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (guild_id) DO UPDATE SET
+enabled = $2, length_days = $3, reward_role_id = $4, reward_top_n = $5, current_season = $6, season_started_at = $7`
+
+	_, err := common.PQ.ExecContext(ctx, q, conf.GuildID, conf.Enabled, conf.LengthDays, conf.RewardRoleID, conf.RewardTopN, conf.CurrentSeason, conf.SeasonStartedAt)
+	return err
+}
+
+// markSeasonAnswer adds the answer to the season score of the user, if seasons are enabled on the guild
+func markSeasonAnswer(ctx context.Context, tx *sql.Tx, guildID, userID int64, correct bool, add, remove int) error {
+	var enabled bool
+	err := tx.QueryRowContext(ctx, "SELECT enabled FROM trivia_season_configs WHERE guild_id = $1", guildID).Scan(&enabled)
+	if err == sql.ErrNoRows || (err == nil && !enabled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s := &SeasonScore{GuildID: guildID, UserID: userID}
+	err = tx.QueryRowContext(ctx, `SELECT score, correct_answers, incorrect_answers, current_streak, max_streak
+FROM trivia_season_scores WHERE guild_id = $1 AND user_id = $2 FOR UPDATE`, guildID, userID).Scan(&s.Score, &s.CorrectAnswers, &s.IncorrectAnswers, &s.CurrentStreak, &s.MaxStreak)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	s.addAnswer(correct, add, remove)
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO trivia_season_scores (guild_id, user_id, score, correct_answers, incorrect_answers, current_streak, max_streak)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (guild_id, user_id) DO UPDATE SET
+score = $3, correct_answers = $4, incorrect_answers = $5, current_streak = $6, max_streak = $7`,
+		s.GuildID, s.UserID, s.Score, s.CorrectAnswers, s.IncorrectAnswers, s.CurrentStreak, s.MaxStreak)
+	return err
+}
+
+// GetSeasonStandings returns the top scores of the current season
+func GetSeasonStandings(ctx context.Context, guildID int64, limit int) ([]*SeasonScore, error) {
+	rows, err := common.PQ.QueryContext(ctx, `SELECT user_id, score, correct_answers, incorrect_answers, current_streak, max_streak
+FROM trivia_season_scores WHERE guild_id = $1 ORDER BY score DESC, correct_answers DESC, user_id ASC LIMIT $2`, guildID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*SeasonScore
+	for rows.Next() {
+		s := &SeasonScore{GuildID: guildID}
+		err = rows.Scan(&s.UserID, &s.Score, &s.CorrectAnswers, &s.IncorrectAnswers, &s.CurrentStreak, &s.MaxStreak)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+
+	return result, rows.Err()
+}
+
+// seasonResults ranks the scores of a ended season, the scores have to be sorted already
+func seasonResults(season int, scores []*SeasonScore, endedAt time.Time) []*SeasonResult {
+	results := make([]*SeasonResult, 0, len(scores))
+	for i, s := range scores {
+		results = append(results, &SeasonResult{
+			GuildID:          s.GuildID,
+			Season:           season,
+			UserID:           s.UserID,
+			Rank:             i + 1,
+			Score:            s.Score,
+			CorrectAnswers:   s.CorrectAnswers,
+			IncorrectAnswers: s.IncorrectAnswers,
+			MaxStreak:        s.MaxStreak,
+			EndedAt:          endedAt,
+		})
+	}
+
+	return results
+}
+
+// GetSeasonResults returns the snapshotted leaderboard of a finished season, ordered by rank
+func GetSeasonResults(ctx context.Context, guildID int64, season int) ([]*SeasonResult, error) {
+	const q = `SELECT user_id, rank, score, correct_answers, incorrect_answers, max_streak, ended_at
+FROM trivia_season_results WHERE guild_id = $1 AND season = $2 ORDER BY rank ASC`
+
+	rows, err := common.PQ.QueryContext(ctx, q, guildID, season)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*SeasonResult
+	for rows.Next() {
+		r := &SeasonResult{GuildID: guildID, Season: season}
+		err = rows.Scan(&r.UserID, &r.Rank, &r.Score, &r.CorrectAnswers, &r.IncorrectAnswers, &r.MaxStreak, &r.EndedAt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+
+	return result, rows.Err()
+}
+
+// EndSeason snapshots the season scores into the season results, resets the season scores
+// and starts a new season, returning the updated config and the results of the ended season.
+// The all time stats in trivia_users are left untouched.
+func EndSeason(ctx context.Context, guildID int64) (*SeasonConfig, []*SeasonResult, error) {
+	tx, err := common.PQ.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	conf := &SeasonConfig{GuildID: guildID}
+	err = tx.QueryRowContext(ctx, `SELECT enabled, length_days, reward_role_id, reward_top_n, current_season, season_started_at
+FROM trivia_season_configs WHERE guild_id = $1 FOR UPDATE`, guildID).Scan(&conf.Enabled, &conf.LengthDays, &conf.RewardRoleID, &conf.RewardTopN, &conf.CurrentSeason, &conf.SeasonStartedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT user_id, score, correct_answers, incorrect_answers, current_streak, max_streak
+FROM trivia_season_scores WHERE guild_id = $1 ORDER BY score DESC, correct_answers DESC, user_id ASC LIMIT $2`, guildID, SeasonResultsSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var scores []*SeasonScore
+	for rows.Next() {
+		s := &SeasonScore{GuildID: guildID}
+		err = rows.Scan(&s.UserID, &s.Score, &s.CorrectAnswers, &s.IncorrectAnswers, &s.CurrentStreak, &s.MaxStreak)
+		if err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		scores = append(scores, s)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	results := seasonResults(conf.CurrentSeason, scores, now)
+
+	for _, r := range results {
+		_, err = tx.ExecContext(ctx, `INSERT INTO trivia_season_results (guild_id, season, user_id, rank, score, correct_answers, incorrect_answers, max_streak, ended_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`, r.GuildID, r.Season, r.UserID, r.Rank, r.Score, r.CorrectAnswers, r.IncorrectAnswers, r.MaxStreak, r.EndedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM trivia_season_scores WHERE guild_id = $1", guildID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conf.startNextSeason(now)
+	_, err = tx.ExecContext(ctx, "UPDATE trivia_season_configs SET current_season = $2, season_started_at = $3 WHERE guild_id = $1", guildID, conf.CurrentSeason, conf.SeasonStartedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conf, results, tx.Commit()
+}
+
+func ScheduleSeasonEnd(conf *SeasonConfig) error {
+	return scheduledevents2.ScheduleEvent("trivia_season_end", conf.GuildID, conf.EndsAt(), nil)
+}
+
+func (p *Plugin) handleSeasonEndScheduledEvent(evt *seventsmodels.ScheduledEvent, data interface{}) (retry bool, err error) {
+	ctx := context.Background()
+	conf, err := GetSeasonConfig(ctx, evt.GuildID)
+	if err != nil {
+		return true, err
+	}
+
+	// the season might have been disabled or ended manually since this event was scheduled,
+	// or the season length changed, in which case another event has been scheduled
+	if !conf.Enabled || time.Now().Add(time.Minute).Before(conf.EndsAt()) {
+		return false, nil
+	}
+
+	endedSeason := conf.CurrentSeason
+	conf, results, err := EndSeason(ctx, evt.GuildID)
+	if err != nil {
+		return true, err
+	}
+
+	giveSeasonRewards(ctx, conf, endedSeason, results)
+
+	err = ScheduleSeasonEnd(conf)
+	return false, err
+}
+
+// giveSeasonRewards moves the reward role from the top players of the season before the ended one
+// to the top players of the ended season
+func giveSeasonRewards(ctx context.Context, conf *SeasonConfig, endedSeason int, results []*SeasonResult) {
+	if conf.RewardRoleID == 0 || conf.RewardTopN < 1 {
+		return
+	}
+
+	var previous []*SeasonResult
+	if endedSeason > 1 {
+		var err error
+		previous, err = GetSeasonResults(ctx, conf.GuildID, endedSeason-1)
+		if err != nil {
+			logger.WithError(err).WithField("guild", conf.GuildID).Error("failed retrieving previous trivia season results")
+		}
+	}
+
+	give, remove := seasonRewardChanges(conf.RewardTopN, previous, results)
+	for _, userID := range remove {
+		err := common.BotSession.GuildMemberRoleRemove(conf.GuildID, userID, conf.RewardRoleID)
+		if err != nil {
+			logger.WithError(err).WithField("guild", conf.GuildID).WithField("user", userID).Warn("failed removing trivia season reward role")
+		}
+	}
+
+	for _, userID := range give {
+		err := common.BotSession.GuildMemberRoleAdd(conf.GuildID, userID, conf.RewardRoleID)
+		if err != nil {
+			logger.WithError(err).WithField("guild", conf.GuildID).WithField("user", userID).Warn("failed giving trivia season reward role")
+		}
+	}
+}
+
+// seasonRewardChanges returns the users to give the reward role to, and the previous winners to remove it from
+func seasonRewardChanges(topN int, previous, results []*SeasonResult) (give, remove []int64) {
+	winners := make(map[int64]bool)
+	for _, v := range results {
+		if v.Rank <= topN {
+			winners[v.UserID] = true
+			give = append(give, v.UserID)
+		}
+	}
+
+	for _, v := range previous {
+		if v.Rank <= topN && !winners[v.UserID] {
+			remove = append(remove, v.UserID)
+		}
+	}
+
+	return give, remove
+}
@@ -0,0 +1,68 @@
+package trivia
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeasonScoreAddAnswer(t *testing.T) {
+	s := &SeasonScore{}
+	hard := &TriviaQuestion{Difficulty: "hard"}
+	add, remove := questionPoints(hard)
+
+	s.addAnswer(true, add, remove)
+	s.addAnswer(true, add, remove)
+	s.addAnswer(false, add, remove)
+	s.addAnswer(true, add, remove)
+
+	if s.Score != 4+4-3+4 {
+		t.Errorf("got score %d, expected %d", s.Score, 4+4-3+4)
+	}
+	if s.CorrectAnswers != 3 || s.IncorrectAnswers != 1 {
+		t.Errorf("got %d correct and %d incorrect, expected 3 and 1", s.CorrectAnswers, s.IncorrectAnswers)
+	}
+	if s.CurrentStreak != 1 || s.MaxStreak != 2 {
+		t.Errorf("got current streak %d and max streak %d, expected 1 and 2", s.CurrentStreak, s.MaxStreak)
+	}
+}
+
+func TestSeasonRollover(t *testing.T) {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	conf := &SeasonConfig{LengthDays: 30, CurrentSeason: 3, SeasonStartedAt: started}
+
+	if !conf.EndsAt().Equal(started.Add(30 * 24 * time.Hour)) {
+		t.Fatalf("unexpected season end: %s", conf.EndsAt())
+	}
+
+	endedAt := conf.EndsAt()
+	results := seasonResults(conf.CurrentSeason, []*SeasonScore{
+		{UserID: 10, Score: 20},
+		{UserID: 20, Score: 15},
+		{UserID: 30, Score: 3},
+	}, endedAt)
+	conf.startNextSeason(endedAt)
+
+	if conf.CurrentSeason != 4 || !conf.SeasonStartedAt.Equal(endedAt) {
+		t.Errorf("got season %d started at %s, expected season 4 started at %s", conf.CurrentSeason, conf.SeasonStartedAt, endedAt)
+	}
+
+	for i, r := range results {
+		if r.Rank != i+1 || r.Season != 3 || !r.EndedAt.Equal(endedAt) {
+			t.Errorf("unexpected result %d: %+v", i, r)
+		}
+	}
+
+	previous := []*SeasonResult{
+		{UserID: 20, Rank: 1},
+		{UserID: 40, Rank: 2},
+		{UserID: 50, Rank: 3},
+	}
+
+	give, remove := seasonRewardChanges(2, previous, results)
+	if len(give) != 2 || give[0] != 10 || give[1] != 20 {
+		t.Errorf("got role given to %v, expected [10 20]", give)
+	}
+	if len(remove) != 1 || remove[0] != 40 {
+		t.Errorf("got role removed from %v, expected [40]", remove)
+	}
+}
@@ -0,0 +1,190 @@
+package trivia
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+)
+
+const (
+	MinTournamentRounds = 2
+	MaxTournamentRounds = 20
+
+	// Time between the end of a round and the start of the next one
+	TournamentRoundDelay = time.Second * 5
+)
+
+type tournamentScore struct {
+	UserID   int64
+	Points   int
+	Correct  int
+	Answered int
+}
+
+// triviaTournament runs multiple trivia sessions back to back in a channel
+// and keeps a scoreboard across all the rounds
+type triviaTournament struct {
+	GuildID   int64
+	ChannelID int64
+	Questions []*TriviaQuestion
+	Round     int
+
+	scores map[int64]*tournamentScore
+	mu     sync.Mutex
+}
+
+func (tm *triviaSessionManager) NewTournament(guildID int64, channelID int64, rounds int, difficulty string) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.channelBusy(channelID) {
+		return ErrSessionInChannel
+	}
+
+	questions, err := FetchQuestions(rounds, difficulty)
+	if err != nil {
+		return err
+	}
+
+	if len(questions) < 1 {
+		return ErrNoQuestions
+	}
+
+	tournament := &triviaTournament{
+		GuildID:   guildID,
+		ChannelID: channelID,
+		Questions: questions,
+		Round:     1,
+		scores:    make(map[int64]*tournamentScore),
+	}
+
+	tm.tournaments = append(tm.tournaments, tournament)
+	tm.startSession(guildID, channelID, questions[0], tournament)
+	return nil
+}
+
+func (tm *triviaSessionManager) removeTournament(tournament *triviaTournament) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	for i, v := range tm.tournaments {
+		if v == tournament {
+			tm.tournaments = append(tm.tournaments[:i], tm.tournaments[i+1:]...)
+			break
+		}
+	}
+}
+
+// roundEnded is called once the session for the current round has been removed,
+// it tallies the scores and either starts the next round or posts the final scoreboard
+func (tt *triviaTournament) roundEnded(session *triviaSession) {
+	session.mu.Lock()
+	for _, v := range session.SelectedOptions {
+		tt.addAnswer(v.User.ID, session.Question, session.Question.Options[v.Option] == session.Question.Answer)
+	}
+	session.mu.Unlock()
+
+	if tt.currentRound() >= len(tt.Questions) {
+		session.Manager.removeTournament(tt)
+		tt.sendScoreboard()
+		return
+	}
+
+	time.Sleep(TournamentRoundDelay)
+
+	tm := session.Manager
+	tm.mu.Lock()
+	tt.mu.Lock()
+	tt.Round++
+	question := tt.Questions[tt.Round-1]
+	tt.mu.Unlock()
+	tm.startSession(tt.GuildID, tt.ChannelID, question, tt)
+	tm.mu.Unlock()
+}
+
+func (tt *triviaTournament) currentRound() int {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	return tt.Round
+}
+
+func (tt *triviaTournament) addAnswer(userID int64, question *TriviaQuestion, correct bool) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	score, ok := tt.scores[userID]
+	if !ok {
+		score = &tournamentScore{UserID: userID}
+		tt.scores[userID] = score
+	}
+
+	score.Answered++
+	if correct {
+		add, _ := questionPoints(question)
+		score.Points += add
+		score.Correct++
+	}
+}
+
+// sortedScores returns the scores sorted by points, then by the amount of correct answers
+func (tt *triviaTournament) sortedScores() []*tournamentScore {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	result := make([]*tournamentScore, 0, len(tt.scores))
+	for _, v := range tt.scores {
+		result = append(result, v)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Points != result[j].Points {
+			return result[i].Points > result[j].Points
+		}
+		if result[i].Correct != result[j].Correct {
+			return result[i].Correct > result[j].Correct
+		}
+		return result[i].UserID < result[j].UserID
+	})
+
+	return result
+}
+
+func (tt *triviaTournament) sendScoreboard() {
+	scores := tt.sortedScores()
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("🏆 Trivia Tournament Results (%d rounds)", len(tt.Questions)),
+		Color: 0xFFD700, // Gold
+		Footer: &discordgo.MessageEmbedFooter{
+			Text:    "Powered by OpenTDB | Use /trivia leaderboard to see all scores",
+			IconURL: "https://opentdb.com/images/logo-banner.png",
+		},
+	}
+
+	if len(scores) == 0 {
+		embed.Description = "**No one participated :(**"
+	} else {
+		emojiList := []string{"🥇", "🥈", "🥉"}
+		if len(scores) > 20 {
+			embed.Description = fmt.Sprintf("Showing the top 20 of %d participants\n\n", len(scores))
+			scores = scores[:20]
+		}
+
+		for i, v := range scores {
+			rank := fmt.Sprintf("#%d", i+1)
+			if i < len(emojiList) {
+				rank = emojiList[i]
+			}
+			embed.Description += fmt.Sprintf("%s <@%d>: **%d** points (%d/%d correct)\n", rank, v.UserID, v.Points, v.Correct, v.Answered)
+		}
+	}
+
+	_, err := common.BotSession.ChannelMessageSendEmbed(tt.ChannelID, embed)
+	if err != nil {
+		logger.WithError(err).WithField("guild", tt.GuildID).WithField("channel", tt.ChannelID).Error("failed sending trivia tournament scoreboard")
+	}
+}
@@ -83,7 +83,8 @@ func (p *Plugin) InitWeb() {
 	mux.Handle(pat.Post("/announcement"), web.ControllerPostHandler(p.HandleTwitchAnnouncement, mainGetHandler, TwitchAnnouncementForm{}))
 	mux.Handle(pat.Post("/:item/update"), web.ControllerPostHandler(BaseEditHandler(p.HandleEdit), mainGetHandler, TwitchFeedForm{}))
 	mux.Handle(pat.Post("/:item/delete"), web.ControllerPostHandler(BaseEditHandler(p.HandleRemove), mainGetHandler, nil))
-	mux.Handle(pat.Get("/:item/delete"), web.ControllerPostHandler(BaseEditHandler(p.HandleRemove), mainGetHandler, nil))
+	// feeds used to be deletable with a GET, old links now only show the feeds
+	mux.Handle(pat.Get("/:item/delete"), mainGetHandler)
 }
 
 func (p *Plugin) HandleTwitch(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
@@ -328,6 +329,18 @@ func (p *Plugin) HandleRemove(w http.ResponseWriter, r *http.Request) (templateD
 	return
 }
 
+var _ web.PluginWithPublicAPI = (*Plugin)(nil)
+
+func (p *Plugin) PublicAPIResources() []*web.APIResource {
+	return []*web.APIResource{
+		{
+			Path:     "/twitch",
+			Scope:    "feeds",
+			DataKeys: []string{"TwitchSubs", "TwitchAnnouncement"},
+		},
+	}
+}
+
 var _ web.PluginWithServerHomeWidget = (*Plugin)(nil)
 
 func (p *Plugin) LoadServerHomeWidget(w http.ResponseWriter, r *http.Request) (web.TemplateData, error) {
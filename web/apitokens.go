@@ -0,0 +1,247 @@
+package web
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/common/cplogs"
+	"github.com/lib/pq"
+	"goji.io/pat"
+)
+
+const apiTokensDBSchema = `
+CREATE TABLE IF NOT EXISTS api_tokens (
+	id BIGSERIAL PRIMARY KEY,
+	guild_id BIGINT NOT NULL,
+	name TEXT NOT NULL,
+	token_hash TEXT NOT NULL UNIQUE,
+	scopes TEXT[] NOT NULL,
+	created_by BIGINT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	last_used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS api_tokens_guild_id_idx ON api_tokens(guild_id);
+`
+
+// MaxAPITokensPerGuild is the max number of api tokens a guild can have at once
+const MaxAPITokensPerGuild = 10
+
+// apiTokenPrefix makes the tokens easy to recognize (e.g for secret scanners)
+const apiTokenPrefix = "yag_"
+
+var (
+	panelLogKeyCreatedAPIToken = cplogs.RegisterActionFormat(&cplogs.ActionFormat{
+		Key:          "created_api_token",
+		FormatString: "Created API token %s",
+	})
+	panelLogKeyRevokedAPIToken = cplogs.RegisterActionFormat(&cplogs.ActionFormat{
+		Key:          "revoked_api_token",
+		FormatString: "Revoked API token #%d",
+	})
+)
+
+// APIToken is a token for the public api, it's scoped to a single guild
+// and only the sha256 hash of it is stored
+type APIToken struct {
+	ID         int64
+	GuildID    int64
+	Name       string
+	Scopes     []string
+	CreatedBy  int64
+	CreatedAt  time.Time
+	LastUsedAt sql.NullTime
+}
+
+// HasScope returns true if the token can access resources with the scope,
+// write access also grants read access
+func (t *APIToken) HasScope(scope string, write bool) bool {
+	if slices.Contains(t.Scopes, scope+":write") {
+		return true
+	}
+
+	return !write && slices.Contains(t.Scopes, scope+":read")
+}
+
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+const apiTokenColumns = "id, guild_id, name, scopes, created_by, created_at, last_used_at"
+
+func scanAPIToken(row interface{ Scan(...interface{}) error }) (*APIToken, error) {
+	var t APIToken
+	err := row.Scan(&t.ID, &t.GuildID, &t.Name, pq.Array(&t.Scopes), &t.CreatedBy, &t.CreatedAt, &t.LastUsedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// CreateAPIToken creates a new token and returns it along with the plaintext token,
+// the plaintext token is not stored anywhere so it can only be shown once
+func CreateAPIToken(ctx context.Context, guildID, createdBy int64, name string, scopes []string) (*APIToken, string, error) {
+	plain := apiTokenPrefix + RandBase64(32)
+
+	t := &APIToken{
+		GuildID:   guildID,
+		Name:      name,
+		Scopes:    scopes,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+
+	const q = `INSERT INTO api_tokens (guild_id, name, token_hash, scopes, created_by, created_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	err := common.PQ.QueryRowContext(ctx, q, guildID, name, hashAPIToken(plain), pq.Array(scopes), createdBy, t.CreatedAt).Scan(&t.ID)
+	if err != nil {
+		return nil, "", errors.WithStackIf(err)
+	}
+
+	return t, plain, nil
+}
+
+// GetAPIToken returns the token matching the plaintext token, or nil if there is none
+func GetAPIToken(ctx context.Context, plain string) (*APIToken, error) {
+	if !strings.HasPrefix(plain, apiTokenPrefix) {
+		return nil, nil
+	}
+
+	row := common.PQ.QueryRowContext(ctx, "SELECT "+apiTokenColumns+" FROM api_tokens WHERE token_hash = $1", hashAPIToken(plain))
+	t, err := scanAPIToken(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return t, errors.WithStackIf(err)
+}
+
+// GetGuildAPITokens returns all the tokens of the guild, oldest first
+func GetGuildAPITokens(ctx context.Context, guildID int64) ([]*APIToken, error) {
+	rows, err := common.PQ.QueryContext(ctx, "SELECT "+apiTokenColumns+" FROM api_tokens WHERE guild_id = $1 ORDER BY id ASC", guildID)
+	if err != nil {
+		return nil, errors.WithStackIf(err)
+	}
+	defer rows.Close()
+
+	var result []*APIToken
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, errors.WithStackIf(err)
+		}
+
+		result = append(result, t)
+	}
+
+	return result, errors.WithStackIf(rows.Err())
+}
+
+// DeleteAPIToken deletes the token, returns false if there was no such token on the guild
+func DeleteAPIToken(ctx context.Context, guildID, id int64) (bool, error) {
+	res, err := common.PQ.ExecContext(ctx, "DELETE FROM api_tokens WHERE guild_id = $1 AND id = $2", guildID, id)
+	if err != nil {
+		return false, errors.WithStackIf(err)
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, errors.WithStackIf(err)
+}
+
+func markAPITokenUsed(id int64) {
+	// only update it once a minute to avoid a write for every single request
+	_, err := common.PQ.Exec("UPDATE api_tokens SET last_used_at = now() WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')", id)
+	if err != nil {
+		logger.WithError(err).Error("Failed updating last used time of api token")
+	}
+}
+
+type CreateAPITokenForm struct {
+	Name   string `valid:",1,100"`
+	Scopes []string
+}
+
+func HandleGetAPITokens(w http.ResponseWriter, r *http.Request) (TemplateData, error) {
+	g, templateData := GetBaseCPContextData(r.Context())
+
+	tokens, err := GetGuildAPITokens(r.Context(), g.ID)
+	if err != nil {
+		return templateData, err
+	}
+
+	templateData["APITokens"] = tokens
+	templateData["APIScopes"] = APIScopes()
+	templateData["MaxAPITokens"] = MaxAPITokensPerGuild
+	return templateData, nil
+}
+
+func HandlePostCreateAPIToken(w http.ResponseWriter, r *http.Request) (TemplateData, error) {
+	ctx := r.Context()
+	g, templateData := GetBaseCPContextData(ctx)
+	form := ctx.Value(common.ContextKeyParsedForm).(*CreateAPITokenForm)
+
+	if len(form.Scopes) < 1 {
+		return templateData, NewPublicError("The token needs at least one scope")
+	}
+
+	available := APIScopes()
+	for _, scope := range form.Scopes {
+		name, access, _ := strings.Cut(scope, ":")
+		if !slices.Contains(available, name) || (access != "read" && access != "write") {
+			return templateData, NewPublicError("Unknown scope: ", scope)
+		}
+	}
+
+	existing, err := GetGuildAPITokens(ctx, g.ID)
+	if err != nil {
+		return templateData, err
+	}
+
+	if len(existing) >= MaxAPITokensPerGuild {
+		return templateData, NewPublicError("Max ", MaxAPITokensPerGuild, " API tokens per server, revoke one first")
+	}
+
+	_, plain, err := CreateAPIToken(ctx, g.ID, ContextUser(ctx).ID, form.Name, form.Scopes)
+	if err != nil {
+		return templateData, err
+	}
+
+	templateData["CreatedAPIToken"] = plain
+
+	go cplogs.RetryAddEntry(NewLogEntryFromContext(ctx, panelLogKeyCreatedAPIToken, &cplogs.Param{Type: cplogs.ParamTypeString, Value: form.Name}))
+
+	return templateData, nil
+}
+
+func HandlePostRevokeAPIToken(w http.ResponseWriter, r *http.Request) (TemplateData, error) {
+	ctx := r.Context()
+	g, templateData := GetBaseCPContextData(ctx)
+
+	id, err := strconv.ParseInt(pat.Param(r, "token"), 10, 64)
+	if err != nil {
+		return templateData, NewPublicError("Invalid token id")
+	}
+
+	deleted, err := DeleteAPIToken(ctx, g.ID, id)
+	if err != nil {
+		return templateData, err
+	}
+
+	if !deleted {
+		return templateData, NewPublicError("Unknown token")
+	}
+
+	go cplogs.RetryAddEntry(NewLogEntryFromContext(ctx, panelLogKeyRevokedAPIToken, &cplogs.Param{Type: cplogs.ParamTypeInt, Value: id}))
+
+	return templateData, nil
+}
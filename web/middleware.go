@@ -461,6 +461,16 @@ type CustomHandlerFunc func(w http.ResponseWriter, r *http.Request) interface{}
 // A helper wrapper that renders a template
 func RenderHandler(inner CustomHandlerFunc, tmpl string) http.Handler {
 	mw := func(w http.ResponseWriter, r *http.Request) {
+		if apiReq := ContextAPIRequest(r.Context()); apiReq != nil {
+			var out interface{}
+			if inner != nil {
+				out = inner(w, r)
+			}
+
+			renderAPIResponse(w, r, apiReq, out)
+			return
+		}
+
 		alertsOnly := r.URL.Query().Get("alertsonly") == "1"
 
 		respCode := 200
@@ -819,6 +829,13 @@ func SetGuildMemberMiddleware(inner http.Handler) http.Handler {
 
 		ctx := r.Context()
 
+		if ContextAPIRequest(ctx) != nil {
+			// the scopes of the token have already been checked
+			ctx = SetContextTemplateData(ctx, map[string]interface{}{"IsAdmin": true})
+			r = r.WithContext(context.WithValue(ctx, common.ContextKeyIsAdmin, true))
+			return
+		}
+
 		userI := r.Context().Value(common.ContextKeyUser)
 		if userI != nil {
 			user := userI.(*discordgo.User)
@@ -36,3 +36,8 @@ type PluginWithServerHomeWidgetMiddlewares interface {
 type ServerHomeWidgetWithOrder interface {
 	ServerHomeWidgetOrder() int
 }
+
+// PluginWithPublicAPI is implemented by plugins that expose their control panel pages through the public api
+type PluginWithPublicAPI interface {
+	PublicAPIResources() []*APIResource
+}
@@ -0,0 +1,334 @@
+package web
+
+// The public api lets external tools manage a guild using a token issued from the control panel.
+//
+// Instead of having separate handlers it mirrors the control panel: a request to
+// /publicapi/v1/guilds/:server/<path> is ran through the same handlers as /manage/:server/<path>,
+// with the json body converted to form values, so the validation, limits and control panel logs
+// are exactly the same as on the control panel. The template data the handlers produce is then
+// returned as json instead of rendering the page.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/botlabs-gg/yagpdb/v2/common"
+	"github.com/botlabs-gg/yagpdb/v2/lib/discordgo"
+	"goji.io/pat"
+	"goji.io/pattern"
+)
+
+// maxAPIBodySize is the max size of json bodies sent to the api
+const maxAPIBodySize = 1 << 20
+
+// APIResource exposes control panel pages of a plugin through the public api
+type APIResource struct {
+	// Path of the pages relative to /manage/:server, e.g /customcommands
+	Path string
+
+	// Scope tokens need to access the resource, tokens with <scope>:read can make GET requests
+	// and tokens with <scope>:write can make any request. Several resources can share a scope.
+	Scope string
+
+	// DataKeys are the keys of the template data that's included in the responses
+	DataKeys []string
+}
+
+// APIRequest is set in the context of control panel handlers when they're handling a public api request
+type APIRequest struct {
+	Token    *APIToken
+	Resource *APIResource
+}
+
+// APIResponse is the body of all public api responses
+type APIResponse struct {
+	Data     map[string]interface{} `json:"data,omitempty"`
+	Errors   []string               `json:"errors,omitempty"`
+	Warnings []string               `json:"warnings,omitempty"`
+}
+
+var apiResources []*APIResource
+
+// ContextAPIRequest returns the public api request being handled, or nil if this is a normal control panel request
+func ContextAPIRequest(ctx context.Context) *APIRequest {
+	if v := ctx.Value(common.ContextKeyAPIRequest); v != nil {
+		return v.(*APIRequest)
+	}
+
+	return nil
+}
+
+// APIScopes returns the scopes of all the resources, sorted
+func APIScopes() []string {
+	var scopes []string
+	for _, v := range apiResources {
+		if !slices.Contains(scopes, v.Scope) {
+			scopes = append(scopes, v.Scope)
+		}
+	}
+
+	slices.Sort(scopes)
+	return scopes
+}
+
+func findAPIResource(path string) *APIResource {
+	for _, v := range apiResources {
+		if path == v.Path || strings.HasPrefix(path, v.Path+"/") {
+			return v
+		}
+	}
+
+	return nil
+}
+
+func publicAPIPath(guildID int64) string {
+	return "/publicapi/v1/guilds/" + discordgo.StrID(guildID)
+}
+
+// HandlePublicAPI authenticates the token and runs the request through the matching control panel handler
+func HandlePublicAPI(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	guildID, err := strconv.ParseInt(pat.Param(r, "server"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, "Unknown server")
+		return
+	}
+
+	plain, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		writeAPIError(w, http.StatusUnauthorized, "Missing bearer token")
+		return
+	}
+
+	token, err := GetAPIToken(ctx, plain)
+	if err != nil {
+		CtxLogger(ctx).WithError(err).Error("Failed retrieving api token")
+		writeAPIError(w, http.StatusInternalServerError, "Failed retrieving token")
+		return
+	}
+
+	if token == nil || token.GuildID != guildID {
+		writeAPIError(w, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		writeAPIError(w, http.StatusMethodNotAllowed, "Only GET and POST are supported")
+		return
+	}
+
+	path := pattern.Path(ctx)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	resource := findAPIResource(path)
+	if resource == nil {
+		writeAPIError(w, http.StatusNotFound, "Unknown resource")
+		return
+	}
+
+	write := r.Method != http.MethodGet
+	if !token.HasScope(resource.Scope, write) {
+		access := "read"
+		if write {
+			access = "write"
+		}
+
+		writeAPIError(w, http.StatusForbidden, "Token is missing the "+resource.Scope+":"+access+" scope")
+		return
+	}
+
+	go markAPITokenUsed(token.ID)
+
+	inner, err := newAPIInnerRequest(r, &APIRequest{Token: token, Resource: resource}, guildID, path)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	RootMux.ServeHTTP(&apiResponseWriter{ResponseWriter: w, guildID: guildID}, inner)
+}
+
+// newAPIInnerRequest creates the control panel request for the api request
+func newAPIInnerRequest(r *http.Request, apiReq *APIRequest, guildID int64, path string) (*http.Request, error) {
+	// the cplogs entries are attributed to the token
+	user := &discordgo.User{
+		ID:       apiReq.Token.CreatedBy,
+		Username: "API token: " + apiReq.Token.Name,
+	}
+
+	ctx := context.WithValue(r.Context(), common.ContextKeyAPIRequest, apiReq)
+	ctx = context.WithValue(ctx, common.ContextKeyUser, user)
+
+	inner := r.Clone(ctx)
+	inner.URL.Path = fmt.Sprintf("/manage/%d%s", guildID, path)
+	inner.URL.RawPath = ""
+	inner.RequestURI = inner.URL.RequestURI()
+
+	// the token is all that's used for authentication, a browser session should never be mixed in
+	inner.Header.Del("Authorization")
+	inner.Header.Del("Cookie")
+	inner.Header.Del("Origin")
+
+	if r.Method == http.MethodGet || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return inner, nil
+	}
+
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxAPIBodySize))
+	decoder.UseNumber()
+
+	var body map[string]interface{}
+	if err := decoder.Decode(&body); err != nil && err != io.EOF {
+		return nil, NewPublicError("Invalid json body: ", err)
+	}
+
+	values := make(url.Values)
+	flattenJSON(values, "", body)
+	encoded := values.Encode()
+
+	inner.Body = io.NopCloser(strings.NewReader(encoded))
+	inner.ContentLength = int64(len(encoded))
+	inner.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return inner, nil
+}
+
+// flattenJSON adds a decoded json value to dst the way the control panel forms name their fields,
+// nested objects are joined with dots and arrays of objects are indexed (e.g Triggers.0.Type).
+// Arrays of anything else becomes multiple values of the same field, like a multiselect.
+func flattenJSON(dst url.Values, key string, v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			if key != "" {
+				k = key + "." + k
+			}
+			flattenJSON(dst, k, child)
+		}
+	case []interface{}:
+		for i, child := range t {
+			switch child.(type) {
+			case map[string]interface{}, []interface{}:
+				flattenJSON(dst, key+"."+strconv.Itoa(i), child)
+			default:
+				flattenJSON(dst, key, child)
+			}
+		}
+	case string:
+		dst.Add(key, t)
+	case json.Number:
+		dst.Add(key, t.String())
+	case bool:
+		dst.Add(key, strconv.FormatBool(t))
+	}
+}
+
+// apiResponseWriter points the redirects made by the control panel handlers to the matching api paths
+type apiResponseWriter struct {
+	http.ResponseWriter
+	guildID    int64
+	redirected bool
+}
+
+func (a *apiResponseWriter) WriteHeader(statusCode int) {
+	if a.redirected {
+		return
+	}
+
+	if statusCode < 300 || statusCode >= 400 {
+		a.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	a.redirected = true
+
+	prefix := fmt.Sprintf("/manage/%d/", a.guildID)
+	location := a.Header().Get("Location")
+	if strings.HasPrefix(location, prefix) {
+		// e.g to the page of a newly created custom command
+		a.Header().Set("Location", publicAPIPath(a.guildID)+location[len(prefix)-1:])
+		a.ResponseWriter.WriteHeader(http.StatusSeeOther)
+		return
+	}
+
+	// anywhere else is the handlers giving up, e.g /?err=noaccess
+	a.Header().Del("Location")
+	msg := "Bad request"
+	if parsed, err := url.Parse(location); err == nil {
+		if e := parsed.Query().Get("err"); e != "" {
+			msg = e
+		} else if e := parsed.Query().Get("error"); e != "" {
+			msg = e
+		}
+	}
+
+	writeAPIError(a.ResponseWriter, http.StatusBadRequest, msg)
+}
+
+func (a *apiResponseWriter) Write(b []byte) (int, error) {
+	if a.redirected {
+		// drop the redirect body and anything rendered after it
+		return len(b), nil
+	}
+
+	return a.ResponseWriter.Write(b)
+}
+
+// renderAPIResponse is used by RenderHandler in place of the template for api requests
+func renderAPIResponse(w http.ResponseWriter, r *http.Request, apiReq *APIRequest, out interface{}) {
+	data, _ := out.(TemplateData)
+	if data == nil {
+		data, _ = r.Context().Value(common.ContextKeyTemplateData).(TemplateData)
+	}
+
+	resp := &APIResponse{}
+	for _, alert := range data.Alerts() {
+		switch alert.Style {
+		case AlertDanger:
+			resp.Errors = append(resp.Errors, alert.Message)
+		case AlertWarning:
+			resp.Warnings = append(resp.Warnings, alert.Message)
+		}
+	}
+
+	formOK, ok := r.Context().Value(common.ContextKeyFormOk).(bool)
+	if (ok && !formOK) || len(resp.Errors) > 0 {
+		writeAPIJSON(w, http.StatusBadRequest, resp)
+		return
+	}
+
+	resp.Data = make(map[string]interface{})
+	for _, key := range apiReq.Resource.DataKeys {
+		if v, ok := data[key]; ok {
+			resp.Data[key] = v
+		}
+	}
+
+	writeAPIJSON(w, http.StatusOK, resp)
+}
+
+func writeAPIError(w http.ResponseWriter, statusCode int, msg string) {
+	writeAPIJSON(w, statusCode, &APIResponse{Errors: []string{msg}})
+}
+
+func writeAPIJSON(w http.ResponseWriter, statusCode int, resp *APIResponse) {
+	encoded, err := json.Marshal(resp)
+	if err != nil {
+		logger.WithError(err).Error("Failed encoding api response")
+		statusCode = http.StatusInternalServerError
+		encoded = []byte(`{"errors":["Failed encoding response"]}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	w.Write(encoded)
+}
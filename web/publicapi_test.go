@@ -0,0 +1,93 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlattenJSON(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`{
+		"Name": "hello",
+		"ID": 1234567890123456789,
+		"Enabled": true,
+		"Empty": null,
+		"Roles": [1, 2],
+		"Config": {"Channel": "5"},
+		"Triggers": [{"Type": 1}, {"Type": 2}]
+	}`))
+	decoder.UseNumber()
+
+	var body map[string]interface{}
+	if err := decoder.Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(url.Values)
+	flattenJSON(values, "", body)
+
+	expected := url.Values{
+		"Name":            {"hello"},
+		"ID":              {"1234567890123456789"},
+		"Enabled":         {"true"},
+		"Roles":           {"1", "2"},
+		"Config.Channel":  {"5"},
+		"Triggers.0.Type": {"1"},
+		"Triggers.1.Type": {"2"},
+	}
+
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestAPITokenHasScope(t *testing.T) {
+	token := &APIToken{Scopes: []string{"feeds:read", "automod:write"}}
+
+	cases := []struct {
+		scope    string
+		write    bool
+		expected bool
+	}{
+		{"feeds", false, true},
+		{"feeds", true, false},
+		{"automod", false, true},
+		{"automod", true, true},
+		{"moderation", false, false},
+	}
+
+	for _, c := range cases {
+		if got := token.HasScope(c.scope, c.write); got != c.expected {
+			t.Errorf("HasScope(%q, %t) = %t, expected %t", c.scope, c.write, got, c.expected)
+		}
+	}
+}
+
+func TestAPIResponseWriterRedirects(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := &apiResponseWriter{ResponseWriter: rec, guildID: 10}
+	w.Header().Set("Location", "/manage/10/customcommands/commands/5/")
+	w.WriteHeader(http.StatusSeeOther)
+	w.Write([]byte("see other"))
+
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/publicapi/v1/guilds/10/customcommands/commands/5/" {
+		t.Errorf("unexpected redirect: %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	if rec.Body.Len() != 0 {
+		t.Errorf("expected the redirect body to be dropped, got %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	w = &apiResponseWriter{ResponseWriter: rec, guildID: 10}
+	w.Header().Set("Location", "/?err=noaccess")
+	w.WriteHeader(http.StatusTemporaryRedirect)
+
+	if rec.Code != http.StatusBadRequest || rec.Header().Get("Location") != "" || !strings.Contains(rec.Body.String(), "noaccess") {
+		t.Errorf("unexpected response: %d %q %q", rec.Code, rec.Header().Get("Location"), rec.Body.String())
+	}
+}
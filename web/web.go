@@ -125,6 +125,7 @@ func loadTemplates() {
 		"templates/index.html", "templates/cp_main.html",
 		"templates/cp_nav.html", "templates/cp_selectserver.html", "templates/cp_logs.html",
 		"templates/status.html", "templates/cp_server_home.html", "templates/cp_core_settings.html",
+		"templates/cp_api_tokens.html",
 	}
 
 	for _, v := range coreTemplates {
@@ -164,6 +165,8 @@ func Run() {
 		ListenAddressHTTPS = ":" + confListenAddressHTTPS.GetString()
 	}
 
+	common.InitSchemas("api_tokens", apiTokensDBSchema)
+
 	patreon.Run()
 
 	InitOauth()
@@ -304,6 +307,9 @@ func setupRoutes() *goji.Mux {
 	ServerPublicAPIMux.Handle(pat.Get("/channelperms/:channel"), RequireActiveServer(APIHandler(HandleChannelPermissions)))
 	ServerPublicAPIMux.Handle(pat.Get("/status.json"), RequireActiveServer(APIHandler(HandleGuildStatusJSON)))
 
+	// Public api, authenticated with tokens instead of sessions
+	RootMux.Handle(pat.New("/publicapi/v1/guilds/:server/*"), http.HandlerFunc(HandlePublicAPI))
+
 	// Server selection has its own handler
 	RootMux.Handle(pat.Get("/manage"), SelectServerHomePageHandler)
 	RootMux.Handle(pat.Get("/manage/"), SelectServerHomePageHandler)
@@ -339,6 +345,13 @@ func setupRoutes() *goji.Mux {
 	CPMux.Handle(pat.Get("/core"), coreSettingsHandler)
 	CPMux.Handle(pat.Post("/core"), ControllerPostHandler(HandlePostCoreSettings, coreSettingsHandler, CoreConfigPostForm{}))
 
+	apiTokensHandler := ControllerHandler(HandleGetAPITokens, "cp_api_tokens")
+
+	CPMux.Handle(pat.Get("/apitokens"), apiTokensHandler)
+	CPMux.Handle(pat.Get("/apitokens/"), apiTokensHandler)
+	CPMux.Handle(pat.Post("/apitokens/new"), ControllerPostHandler(HandlePostCreateAPIToken, apiTokensHandler, CreateAPITokenForm{}))
+	CPMux.Handle(pat.Post("/apitokens/:token/revoke"), ControllerPostHandler(HandlePostRevokeAPIToken, apiTokensHandler, nil))
+
 	RootMux.Handle(pat.Get("/guild_selection"), RequireSessionMiddleware(ControllerHandler(HandleGetManagedGuilds, "cp_guild_selection")))
 	CPMux.Handle(pat.Get("/guild_selection"), RequireSessionMiddleware(ControllerHandler(HandleGetManagedGuilds, "cp_guild_selection")))
 
@@ -367,6 +380,12 @@ func setupRoutes() *goji.Mux {
 		Icon: "fas fa-database",
 	})
 
+	AddSidebarItem(SidebarCategoryCore, &SidebarItem{
+		Name: "API tokens",
+		URL:  "apitokens",
+		Icon: "fas fa-key",
+	})
+
 	for _, plugin := range common.Plugins {
 		if webPlugin, ok := plugin.(Plugin); ok {
 			webPlugin.InitWeb()
@@ -374,6 +393,12 @@ func setupRoutes() *goji.Mux {
 		}
 	}
 
+	for _, plugin := range common.Plugins {
+		if apiPlugin, ok := plugin.(PluginWithPublicAPI); ok {
+			apiResources = append(apiResources, apiPlugin.PublicAPIResources()...)
+		}
+	}
+
 	return RootMux
 }
 